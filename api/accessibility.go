@@ -0,0 +1,8 @@
+package api
+
+import "github.com/dop251/goja"
+
+// Accessibility is the interface of the accessibility tree of a page.
+type Accessibility interface {
+	Snapshot(opts goja.Value) map[string]interface{}
+}
@@ -30,6 +30,9 @@ type Browser interface {
 	NewContext(opts goja.Value) BrowserContext
 	NewPage(opts goja.Value) Page
 	On(string) *goja.Promise
+	StartTracing(opts goja.Value)
+	StopTracing() goja.ArrayBuffer
 	UserAgent() string
 	Version() string
+	VersionInfo() map[string]string
 }
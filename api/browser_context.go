@@ -27,19 +27,24 @@ import (
 // BrowserContext is the public interface of a CDP browser context.
 type BrowserContext interface {
 	AddCookies(cookies goja.Value)
+	AddCredential(authenticatorID string, credential goja.Value)
 	AddInitScript(script goja.Value, arg goja.Value)
+	AddVirtualAuthenticator(opts goja.Value) string
 	Browser() Browser
-	ClearCookies()
+	ClearCookies(opts goja.Value)
 	ClearPermissions()
 	Close()
-	Cookies() []goja.Object
+	Cookies(urls ...string) []*Cookie
+	Credentials(authenticatorID string) goja.Value
 	ExposeBinding(name string, callback goja.Callable, opts goja.Value)
 	ExposeFunction(name string, callback goja.Callable)
 	GrantPermissions(permissions []string, opts goja.Value)
-	NewCDPSession() CDPSession
+	NewCDPSession(page Page) CDPSession
 	NewPage() Page
 	Pages() []Page
-	Route(url goja.Value, handler goja.Callable)
+	RemoveVirtualAuthenticator(authenticatorID string)
+	Route(url goja.Value, handler goja.Value)
+	RouteFromHAR(path string, opts goja.Value)
 	SetDefaultNavigationTimeout(timeout int64)
 	SetDefaultTimeout(timeout int64)
 	SetExtraHTTPHeaders(headers map[string]string)
@@ -51,8 +56,9 @@ type BrowserContext interface {
 	// - https://github.com/microsoft/playwright/issues/2196#issuecomment-627134837
 	// - https://github.com/microsoft/playwright/pull/2763
 	SetHTTPCredentials(httpCredentials goja.Value)
+	SetNetworkConditions(conditions goja.Value)
 	SetOffline(offline bool)
-	StorageState(opts goja.Value)
-	Unroute(url goja.Value, handler goja.Callable)
+	StorageState(opts goja.Value) goja.Value
+	Unroute(url goja.Value, handler goja.Value)
 	WaitForEvent(event string, optsOrPredicate goja.Value) interface{}
 }
@@ -23,7 +23,11 @@ package api
 import "github.com/dop251/goja"
 
 // CDPSession is the interface of a raw CDP session.
+// It is for the protocol domains that the API doesn't cover. Using it can
+// desync the state that is managed by the extension, and the behavior of
+// the other APIs isn't guaranteed to be correct afterwards.
 type CDPSession interface {
 	Detach()
+	On(event string, handler goja.Callable)
 	Send(method string, params goja.Value) goja.Value
 }
@@ -0,0 +1,12 @@
+package api
+
+import "github.com/dop251/goja"
+
+// Clock is the interface of the fake clock of a page.
+type Clock interface {
+	FastForward(ms int64)
+	Install(opts goja.Value)
+	PauseAt(time goja.Value)
+	Resume()
+	SetFixedTime(time goja.Value)
+}
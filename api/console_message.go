@@ -0,0 +1,22 @@
+package api
+
+// ConsoleMessage is the interface of a console message logged by a page.
+type ConsoleMessage interface {
+	// Args returns the handles of the arguments passed to the console call.
+	Args() []JSHandle
+	// Location returns where in the page source the message was logged.
+	Location() ConsoleMessageLocation
+	// Page returns the page that logged the message.
+	Page() Page
+	// Text returns the text of the message.
+	Text() string
+	// Type returns the type of the console call, such as log, warning or error.
+	Type() string
+}
+
+// ConsoleMessageLocation is the source location of a console message.
+type ConsoleMessageLocation struct {
+	URL          string `js:"url"`
+	LineNumber   int64  `js:"lineNumber"`
+	ColumnNumber int64  `js:"columnNumber"`
+}
@@ -0,0 +1,18 @@
+package api
+
+// Dialog is the interface of a JavaScript dialog, such as alert, confirm,
+// prompt or beforeunload, opened by a page.
+type Dialog interface {
+	// Accept accepts the dialog. The prompt text is only used by prompt
+	// dialogs.
+	Accept(promptText string)
+	// DefaultValue returns the default value of prompt dialogs.
+	DefaultValue() string
+	// Dismiss dismisses the dialog.
+	Dismiss()
+	// Message returns the message displayed by the dialog.
+	Message() string
+	// Type returns the type of the dialog: alert, beforeunload, confirm or
+	// prompt.
+	Type() string
+}
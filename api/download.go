@@ -0,0 +1,25 @@
+package api
+
+// Download is the interface of a file download started by a page.
+type Download interface {
+	// Delete deletes the downloaded file, waiting for the download to
+	// finish if necessary.
+	Delete()
+	// Failure waits for the download to finish and returns the download
+	// error, or an empty string if the download succeeded.
+	Failure() string
+	// Page returns the page that started the download.
+	Page() Page
+	// Path waits for the download to finish and returns the path of the
+	// downloaded file.
+	Path() string
+	// SaveAs waits for the download to finish and copies the downloaded
+	// file to the given path.
+	SaveAs(path string)
+	// SuggestedFilename returns the file name suggested by the browser,
+	// which is usually taken from the response headers or the download
+	// attribute of the link.
+	SuggestedFilename() string
+	// URL returns the URL of the download.
+	URL() string
+}
@@ -53,6 +53,7 @@ type ElementHandle interface {
 	ScrollIntoViewIfNeeded(opts goja.Value)
 	SelectOption(values goja.Value, opts goja.Value) []string
 	SelectText(opts goja.Value)
+	SetChecked(checked bool, opts goja.Value)
 	SetInputFiles(files goja.Value, opts goja.Value)
 	Tap(opts goja.Value)
 	TextContent() string
@@ -0,0 +1,16 @@
+package api
+
+import "github.com/dop251/goja"
+
+// FileChooser is the interface of a file chooser opened by a page.
+type FileChooser interface {
+	// Element returns the file input element that opened the file chooser.
+	Element() ElementHandle
+	// IsMultiple returns whether the file chooser accepts multiple files.
+	IsMultiple() bool
+	// Page returns the page that opened the file chooser.
+	Page() Page
+	// SetFiles sets the files of the file input element. The files can be
+	// file paths, or objects with name, mimeType and buffer properties.
+	SetFiles(files goja.Value, opts goja.Value)
+}
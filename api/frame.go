@@ -12,12 +12,34 @@ type Frame interface {
 	Content() string
 	Dblclick(selector string, opts goja.Value)
 	DispatchEvent(selector string, typ string, eventInit goja.Value, opts goja.Value)
+	DragAndDrop(source string, target string, opts goja.Value)
+	EvalOnSelector(selector string, pageFunc goja.Value, arg goja.Value) interface{}
+	EvalOnSelectorAll(selector string, pageFunc goja.Value, arg goja.Value) interface{}
 	Evaluate(pageFunc goja.Value, args ...goja.Value) interface{}
 	EvaluateHandle(pageFunc goja.Value, args ...goja.Value) JSHandle
 	Fill(selector string, value string, opts goja.Value)
 	Focus(selector string, opts goja.Value)
 	FrameElement() ElementHandle
+	// FrameLocator returns a frame locator for the iframes matching the
+	// selector in this frame.
+	FrameLocator(selector string) FrameLocator
 	GetAttribute(selector string, name string, opts goja.Value) goja.Value
+	// GetByLabel creates and returns a new locator for the elements with a
+	// matching label in this frame.
+	GetByLabel(text goja.Value, opts goja.Value) Locator
+	// GetByPlaceholder creates and returns a new locator for the elements
+	// with a matching placeholder in this frame.
+	GetByPlaceholder(text goja.Value, opts goja.Value) Locator
+	// GetByRole creates and returns a new locator for the elements with the
+	// given ARIA role in this frame.
+	GetByRole(role string, opts goja.Value) Locator
+	// GetByTestId creates and returns a new locator for the elements with
+	// the test ID in this frame.
+	// It is spelled as getByTestId in scripts.
+	GetByTestId(testID goja.Value) Locator //nolint:revive,stylecheck
+	// GetByText creates and returns a new locator for the elements with a
+	// matching text in this frame.
+	GetByText(text goja.Value, opts goja.Value) Locator
 	Goto(url string, opts goja.Value) Response
 	Hover(selector string, opts goja.Value)
 	InnerHTML(selector string, opts goja.Value) string
@@ -41,6 +63,7 @@ type Frame interface {
 	ParentFrame() Frame
 	Press(selector string, key string, opts goja.Value)
 	SelectOption(selector string, values goja.Value, opts goja.Value) []string
+	SetChecked(selector string, checked bool, opts goja.Value)
 	SetContent(html string, opts goja.Value)
 	SetInputFiles(selector string, files goja.Value, opts goja.Value)
 	Tap(selector string, opts goja.Value)
@@ -54,4 +77,5 @@ type Frame interface {
 	WaitForNavigation(opts goja.Value) Response
 	WaitForSelector(selector string, opts goja.Value) ElementHandle
 	WaitForTimeout(timeout int64)
+	WaitForURL(urlOrPredicate, opts goja.Value)
 }
@@ -0,0 +1,37 @@
+package api
+
+import "github.com/dop251/goja"
+
+// FrameLocator represents a way to find elements in an iframe at any moment.
+type FrameLocator interface {
+	// First returns a new frame locator matching the first iframe that
+	// matches the frame locator's selector.
+	First() FrameLocator
+	// FrameLocator returns a new frame locator for the iframes matching the
+	// selector in the frame locator's iframe.
+	FrameLocator(selector string) FrameLocator
+	// GetByLabel returns a locator for the elements with a matching label
+	// in the frame locator's iframe.
+	GetByLabel(text goja.Value, opts goja.Value) Locator
+	// GetByPlaceholder returns a locator for the input elements with a
+	// matching placeholder in the frame locator's iframe.
+	GetByPlaceholder(text goja.Value, opts goja.Value) Locator
+	// GetByRole returns a locator for the elements with the given ARIA role
+	// in the frame locator's iframe.
+	GetByRole(role string, opts goja.Value) Locator
+	// GetByTestId returns a locator for the elements with the given test ID
+	// in the frame locator's iframe.
+	GetByTestId(testID goja.Value) Locator //nolint:revive,stylecheck
+	// GetByText returns a locator for the elements with a matching text in
+	// the frame locator's iframe.
+	GetByText(text goja.Value, opts goja.Value) Locator
+	// Last returns a new frame locator matching the last iframe that
+	// matches the frame locator's selector.
+	Last() FrameLocator
+	// Locator returns a locator for the elements matching the selector in
+	// the frame locator's iframe.
+	Locator(selector string, opts goja.Value) Locator
+	// Nth returns a new frame locator matching the iframe at the given zero
+	// based index among the iframes matching the frame locator's selector.
+	Nth(index int) FrameLocator
+}
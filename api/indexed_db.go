@@ -0,0 +1,14 @@
+package api
+
+import "github.com/dop251/goja"
+
+// IndexedDB is the interface of the IndexedDB databases of the origin of a
+// page.
+type IndexedDB interface {
+	// Databases returns the databases with their versions, object stores,
+	// and indexes.
+	Databases() goja.Value
+	// Records returns the records of the object store of the database in its
+	// current version, up to the limit option.
+	Records(database string, objectStore string, opts goja.Value) goja.Value
+}
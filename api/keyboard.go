@@ -25,7 +25,7 @@ import "github.com/dop251/goja"
 // Keyboard is the interface of a keyboard input device.
 type Keyboard interface {
 	Down(key string)
-	InsertText(char string)
+	InsertText(text string)
 	Press(key string, opts goja.Value)
 	Type(text string, opts goja.Value)
 	Up(key string)
@@ -10,10 +10,54 @@ import "github.com/dop251/goja"
 
 // Locator represents a way to find element(s) on a page at any moment.
 type Locator interface {
+	// All returns a locator for each of the elements matching the locator's
+	// selector at the time of the call.
+	All() []Locator
+	// AllInnerTexts returns the inner texts of all the elements matching
+	// the locator's selector.
+	AllInnerTexts() []string
+	// AllTextContents returns the text contents of all the elements
+	// matching the locator's selector.
+	AllTextContents() []string
+	// Count returns the number of elements matching the locator's selector.
+	Count() int
+	// Filter returns a new locator narrowing down the locator's elements
+	// to the ones having the given text or descendant.
+	Filter(opts goja.Value) Locator
+	// FrameLocator returns a frame locator for the iframes matching the
+	// selector in the locator's elements.
+	FrameLocator(selector string) FrameLocator
+	// And returns a new locator matching the elements that match both
+	// the locator and the other locator.
+	And(other Locator) Locator
+	// Or returns a new locator matching the elements that match either
+	// the locator or the other locator, in the document order.
+	Or(other Locator) Locator
+	// Highlight highlights the elements matching the locator's selector
+	// for debugging. It does nothing in the headless mode.
+	Highlight()
+	// Nth returns a new locator matching the element at the given
+	// zero based index among the elements matching the locator's selector.
+	Nth(index int) Locator
+	// First returns a new locator matching the first element that matches
+	// the locator's selector.
+	First() Locator
+	// Last returns a new locator matching the last element that matches
+	// the locator's selector.
+	Last() Locator
+	// AriaSnapshot returns the YAML ARIA snapshot of the subtree of the
+	// element matching the locator's selector with strict mode on.
+	AriaSnapshot(opts goja.Value) string
+	// BoundingBox returns the bounding box of the element matching the
+	// locator's selector with strict mode on, or nil if it's not rendered.
+	BoundingBox(opts goja.Value) *Rect
 	// Click on an element using locator's selector with strict mode on.
 	Click(opts goja.Value)
 	// Dblclick double clicks on an element using locator's selector with strict mode on.
 	Dblclick(opts goja.Value)
+	// DragTo drags the element using locator's selector and drops it on
+	// the element using the target locator's selector, with strict mode on.
+	DragTo(target Locator, opts goja.Value)
 	// Check element using locator's selector with strict mode on.
 	Check(opts goja.Value)
 	// Uncheck element using locator's selector with strict mode on.
@@ -36,6 +80,9 @@ type Locator interface {
 	// IsHidden returns true if the element matches the locator's
 	// selector and is hidden. Otherwise, returns false.
 	IsHidden(opts goja.Value) bool
+	// Clear empties the input or textarea element using locator's selector
+	// with strict mode on.
+	Clear(opts goja.Value)
 	// Fill out the element using locator's selector with strict mode on.
 	Fill(value string, opts goja.Value)
 	// Focus on the element using locator's selector with strict mode on.
@@ -58,20 +105,49 @@ type Locator interface {
 	// the locator's selector (with strict mode on), selects the
 	// options, and returns the filtered options.
 	SelectOption(values goja.Value, opts goja.Value) []string
+	// SetChecked checks or unchecks the element found that matches the
+	// locator's selector with strict mode on, depending on checked.
+	SetChecked(checked bool, opts goja.Value)
+	// SetInputFiles sets the files of the file input element found that
+	// matches the locator's selector with strict mode on.
+	SetInputFiles(files goja.Value, opts goja.Value)
 	// Press the given key on the element found that matches the locator's
 	// selector with strict mode on.
 	Press(key string, opts goja.Value)
+	// PressSequentially focuses the element found that matches the
+	// locator's selector with strict mode on, and presses a key for every
+	// character in text.
+	PressSequentially(text string, opts goja.Value)
 	// Type text on the element found that matches the locator's
 	// selector with strict mode on.
 	Type(text string, opts goja.Value)
 	// Hover moves the pointer over the element that matches the locator's
 	// selector with strict mode on.
 	Hover(opts goja.Value)
+	// Screenshot takes a screenshot of the element that matches the
+	// locator's selector with strict mode on.
+	Screenshot(opts goja.Value) goja.ArrayBuffer
+	// ScrollIntoViewIfNeeded scrolls the element matching the locator's
+	// selector with strict mode on into view, if it's not already visible.
+	ScrollIntoViewIfNeeded(opts goja.Value)
 	// Tap the element found that matches the locator's selector with strict mode on.
 	Tap(opts goja.Value)
 	// DispatchEvent dispatches an event for the element matching the
 	// locator's selector with strict mode on.
 	DispatchEvent(typ string, eventInit, opts goja.Value)
+	// ElementHandle waits for the element matching the locator's selector
+	// with strict mode on to be attached, and returns a handle to it.
+	// The caller must dispose the handle.
+	ElementHandle(opts goja.Value) ElementHandle
+	// ElementHandles returns handles to all the elements matching the
+	// locator's selector. The caller must dispose the handles.
+	ElementHandles() []ElementHandle
+	// Evaluate runs the page function with the element matching the
+	// locator's selector as its first argument, with strict mode on.
+	Evaluate(pageFunc goja.Value, arg goja.Value) interface{}
+	// EvaluateAll runs the page function with an array of all the elements
+	// matching the locator's selector as its first argument.
+	EvaluateAll(pageFunc goja.Value, arg goja.Value) interface{}
 	// WaitFor waits for the element matching the locator's selector
 	// with strict mode on.
 	WaitFor(opts goja.Value)
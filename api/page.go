@@ -38,6 +38,8 @@ type Page interface {
 	DragAndDrop(source string, target string, opts goja.Value)
 	EmulateMedia(opts goja.Value)
 	EmulateVisionDeficiency(typ string)
+	EvalOnSelector(selector string, pageFunc goja.Value, arg goja.Value) interface{}
+	EvalOnSelectorAll(selector string, pageFunc goja.Value, arg goja.Value) interface{}
 	Evaluate(pageFunc goja.Value, arg ...goja.Value) interface{}
 	EvaluateHandle(pageFunc goja.Value, arg ...goja.Value) JSHandle
 	ExposeBinding(name string, callback goja.Callable, opts goja.Value)
@@ -46,11 +48,32 @@ type Page interface {
 	Focus(selector string, opts goja.Value)
 	Frame(frameSelector goja.Value) Frame
 	Frames() []Frame
+	// FrameLocator returns a frame locator for the iframes matching the
+	// selector in the main frame.
+	FrameLocator(selector string) FrameLocator
 	GetAttribute(selector string, name string, opts goja.Value) goja.Value
+	// GetByLabel creates and returns a new locator for the elements with a
+	// matching label in this page (main frame).
+	GetByLabel(text goja.Value, opts goja.Value) Locator
+	// GetByPlaceholder creates and returns a new locator for the elements
+	// with a matching placeholder in this page (main frame).
+	GetByPlaceholder(text goja.Value, opts goja.Value) Locator
+	// GetByRole creates and returns a new locator for the elements with the
+	// given ARIA role in this page (main frame).
+	GetByRole(role string, opts goja.Value) Locator
+	// GetByTestId creates and returns a new locator for the elements with
+	// the test ID in this page (main frame).
+	// It is spelled as getByTestId in scripts.
+	GetByTestId(testID goja.Value) Locator //nolint:revive,stylecheck
+	// GetByText creates and returns a new locator for the elements with a
+	// matching text in this page (main frame).
+	GetByText(text goja.Value, opts goja.Value) Locator
 	GoBack(opts goja.Value) Response
 	GoForward(opts goja.Value) Response
 	Goto(url string, opts goja.Value) Response
 	Hover(selector string, opts goja.Value)
+	// IndexedDB returns the IndexedDB databases of the page's origin.
+	IndexedDB() IndexedDB
 	InnerHTML(selector string, opts goja.Value) string
 	InnerText(selector string, opts goja.Value) string
 	InputValue(selector string, opts goja.Value) string
@@ -63,7 +86,14 @@ type Page interface {
 	IsVisible(selector string, opts goja.Value) bool
 	// Locator creates and returns a new locator for this page (main frame).
 	Locator(selector string, opts goja.Value) Locator
+	// LocalStorage returns the local storage of the page's origin.
+	LocalStorage() WebStorage
 	MainFrame() Frame
+	// Metrics returns the run-time metrics of the page.
+	Metrics() map[string]float64
+	// On registers a handler to be called for every page event of the
+	// given type.
+	On(event string, handler goja.Callable)
 	Opener() Page
 	Pause()
 	Pdf(opts goja.Value) goja.ArrayBuffer
@@ -71,9 +101,13 @@ type Page interface {
 	Query(selector string) ElementHandle
 	QueryAll(selector string) []ElementHandle
 	Reload(opts goja.Value) Response
-	Route(url goja.Value, handler goja.Callable)
+	Route(url goja.Value, handler goja.Value)
+	RouteFromHAR(path string, opts goja.Value)
 	Screenshot(opts goja.Value) goja.ArrayBuffer
 	SelectOption(selector string, values goja.Value, opts goja.Value) []string
+	// SessionStorage returns the session storage of the page's origin.
+	SessionStorage() WebStorage
+	SetChecked(selector string, checked bool, opts goja.Value)
 	SetContent(html string, opts goja.Value)
 	SetDefaultNavigationTimeout(timeout int64)
 	SetDefaultTimeout(timeout int64)
@@ -82,10 +116,11 @@ type Page interface {
 	SetViewportSize(viewportSize goja.Value)
 	Tap(selector string, opts goja.Value)
 	TextContent(selector string, opts goja.Value) string
+	ThrottleCPU(rate float64)
 	Title() string
 	Type(selector string, text string, opts goja.Value)
 	Uncheck(selector string, opts goja.Value)
-	Unroute(url goja.Value, handler goja.Callable)
+	Unroute(url goja.Value, handler goja.Value)
 	URL() string
 	Video() Video
 	ViewportSize() map[string]float64
@@ -93,9 +128,11 @@ type Page interface {
 	WaitForFunction(fn, opts goja.Value, args ...goja.Value) *goja.Promise
 	WaitForLoadState(state string, opts goja.Value)
 	WaitForNavigation(opts goja.Value) Response
+	WaitForPopup(opts goja.Value) Page
 	WaitForRequest(urlOrPredicate, opts goja.Value) Request
 	WaitForResponse(urlOrPredicate, opts goja.Value) Response
 	WaitForSelector(selector string, opts goja.Value) ElementHandle
 	WaitForTimeout(timeout int64)
+	WaitForURL(urlOrPredicate, opts goja.Value)
 	Workers() []Worker
 }
@@ -32,9 +32,9 @@ type Request interface {
 	HeadersArray() []HTTPHeader
 	IsNavigationRequest() bool
 	Method() string
-	PostData() string
-	PostDataBuffer() goja.ArrayBuffer
-	PostDataJSON() string
+	PostData() goja.Value
+	PostDataBuffer() goja.Value
+	PostDataJSON() goja.Value
 	RedirectedFrom() Request
 	RedirectedTo() Request
 	ResourceType() string
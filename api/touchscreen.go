@@ -20,7 +20,11 @@
 
 package api
 
+import "github.com/dop251/goja"
+
 // Touchscreen is the interface of a touchscreen.
 type Touchscreen interface {
+	Pinch(center goja.Value, scale float64, opts goja.Value)
+	Swipe(from goja.Value, to goja.Value, opts goja.Value)
 	Tap(x float64, y float64)
 }
@@ -0,0 +1,9 @@
+package api
+
+import "github.com/dop251/goja"
+
+// Tracing is the interface of the tracing of a browser context.
+type Tracing interface {
+	Start(opts goja.Value) error
+	Stop(opts goja.Value) error
+}
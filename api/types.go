@@ -20,6 +20,24 @@
 
 package api
 
+// Cookie is a cookie of a browser context.
+type Cookie struct {
+	Name   string `js:"name" json:"name"`
+	Value  string `js:"value" json:"value"`
+	Domain string `js:"domain" json:"domain"`
+	Path   string `js:"path" json:"path"`
+	// Expires is the expiration time of the cookie in seconds since the
+	// epoch, or -1 for the session cookies.
+	Expires  float64 `js:"expires" json:"expires"`
+	HTTPOnly bool    `js:"httpOnly" json:"httpOnly"`
+	Secure   bool    `js:"secure" json:"secure"`
+	// SameSite is one of Strict, Lax, or None.
+	SameSite string `js:"sameSite" json:"sameSite"`
+	// PartitionKey is the top-level site of the partitioned cookies, and
+	// is empty for the unpartitioned cookies.
+	PartitionKey string `js:"partitionKey" json:"partitionKey,omitempty"`
+}
+
 // HTTPHeader is a single HTTP header.
 type HTTPHeader struct {
 	Name  string `json:"name"`
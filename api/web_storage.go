@@ -0,0 +1,17 @@
+package api
+
+import "github.com/dop251/goja"
+
+// WebStorage is the interface of the local or the session storage of the
+// origin of a page.
+type WebStorage interface {
+	// Clear removes all the items of the storage.
+	Clear()
+	// GetItem returns the value of the item, or null if there's no item
+	// with the name.
+	GetItem(name string) goja.Value
+	// RemoveItem removes the item.
+	RemoveItem(name string)
+	// SetItem sets the value of the item.
+	SetItem(name string, value string)
+}
@@ -467,6 +467,18 @@ func makeLogger(ctx context.Context, launchOpts *common.LaunchOptions) (*log.Log
 			)
 		}
 	}
+	if ec, ok := os.LookupEnv("XK6_BROWSER_LOG_CATEGORIES"); ok {
+		if err := logger.SetCategoryLevels(ec); err != nil {
+			return nil, err
+		}
+	}
+	format := launchOpts.LogFormat
+	if ef, ok := os.LookupEnv("XK6_BROWSER_LOG_FORMAT"); ok {
+		format = ef
+	}
+	if err := logger.SetFormat(format); err != nil {
+		return nil, err
+	}
 	if _, ok := os.LookupEnv("XK6_BROWSER_CALLER"); ok {
 		logger.ReportCaller()
 	}
@@ -0,0 +1,334 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/grafana/xk6-browser/api"
+	"github.com/grafana/xk6-browser/k6ext"
+
+	"github.com/chromedp/cdproto/accessibility"
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/dom"
+	"github.com/dop251/goja"
+)
+
+// Ensure Accessibility implements the api.Accessibility interface.
+var _ api.Accessibility = &Accessibility{}
+
+// Accessibility gives access to the accessibility tree of a page.
+type Accessibility struct {
+	ctx     context.Context
+	session session
+}
+
+// NewAccessibility returns a new Accessibility for the page session.
+func NewAccessibility(ctx context.Context, s session) *Accessibility {
+	return &Accessibility{
+		ctx:     ctx,
+		session: s,
+	}
+}
+
+// Snapshot returns the accessibility tree of the page's main frame, or of
+// the root element if it's given, as nested nodes with the role, name,
+// value, and children of each node. It returns nil if the root element is
+// not in the tree, or if it's pruned by the interestingOnly option.
+func (a *Accessibility) Snapshot(opts goja.Value) map[string]interface{} {
+	popts := NewAccessibilitySnapshotOptions()
+	if err := popts.Parse(a.ctx, opts); err != nil {
+		k6ext.Panic(a.ctx, "parsing accessibility snapshot options: %w", err)
+	}
+	snapshot, err := a.snapshot(popts)
+	if err != nil {
+		k6ext.Panic(a.ctx, "taking accessibility snapshot: %w", err)
+	}
+	return snapshot
+}
+
+func (a *Accessibility) snapshot(opts *AccessibilitySnapshotOptions) (map[string]interface{}, error) {
+	nodes, err := accessibility.GetFullAXTree().Do(cdp.WithExecutor(a.ctx, a.session))
+	if err != nil {
+		return nil, fmt.Errorf("getting accessibility tree: %w", err)
+	}
+	root := newAXTree(nodes)
+	if root == nil {
+		return nil, nil
+	}
+
+	needle := root
+	if opts.Root != nil {
+		action := dom.DescribeNode().WithObjectID(opts.Root.remoteObject.ObjectID)
+		node, err := action.Do(cdp.WithExecutor(a.ctx, opts.Root.session))
+		if err != nil {
+			return nil, fmt.Errorf("describing root element: %w", err)
+		}
+		if needle = root.find(node.BackendNodeID); needle == nil {
+			return nil, nil
+		}
+	}
+
+	var interesting map[*axNode]bool
+	if opts.InterestingOnly {
+		interesting = make(map[*axNode]bool)
+		root.collectInteresting(interesting, false)
+		if !interesting[needle] {
+			return nil, nil
+		}
+	}
+	serialized := needle.serializeTree(interesting)
+	if len(serialized) == 0 {
+		return nil, nil
+	}
+
+	return serialized[0], nil
+}
+
+// axControlRoles are the roles of the nodes that the user can interact with.
+var axControlRoles = map[string]bool{ //nolint:gochecknoglobals
+	"button":             true,
+	"checkbox":           true,
+	"ColorWell":          true,
+	"combobox":           true,
+	"DisclosureTriangle": true,
+	"listbox":            true,
+	"menu":               true,
+	"menubar":            true,
+	"menuitem":           true,
+	"menuitemcheckbox":   true,
+	"menuitemradio":      true,
+	"radio":              true,
+	"scrollbar":          true,
+	"searchbox":          true,
+	"slider":             true,
+	"spinbutton":         true,
+	"switch":             true,
+	"tab":                true,
+	"textbox":            true,
+	"tree":               true,
+	"treeitem":           true,
+}
+
+// axLeafRoles are the roles of the nodes whose descendants are presentational.
+var axLeafRoles = map[string]bool{ //nolint:gochecknoglobals
+	"doc-cover":       true,
+	"graphics-symbol": true,
+	"img":             true,
+	"Meter":           true,
+	"progressbar":     true,
+	"scrollbar":       true,
+	"separator":       true,
+	"slider":          true,
+}
+
+// axNode is a node of the accessibility tree.
+type axNode struct {
+	node       *accessibility.Node
+	children   []*axNode
+	properties map[accessibility.PropertyName]interface{}
+}
+
+// newAXTree links the nodes of the accessibility tree and returns its root.
+// The first node is the root of the tree.
+func newAXTree(nodes []*accessibility.Node) *axNode {
+	if len(nodes) == 0 {
+		return nil
+	}
+	byID := make(map[accessibility.NodeID]*axNode, len(nodes))
+	for _, n := range nodes {
+		an := &axNode{
+			node:       n,
+			properties: make(map[accessibility.PropertyName]interface{}, len(n.Properties)),
+		}
+		for _, p := range n.Properties {
+			an.properties[p.Name] = axValue(p.Value)
+		}
+		byID[n.NodeID] = an
+	}
+	for _, n := range nodes {
+		an := byID[n.NodeID]
+		for _, id := range n.ChildIds {
+			if c, ok := byID[id]; ok {
+				an.children = append(an.children, c)
+			}
+		}
+	}
+	return byID[nodes[0].NodeID]
+}
+
+// axValue decodes the value of a node or a property.
+func axValue(v *accessibility.Value) interface{} {
+	if v == nil || len(v.Value) == 0 {
+		return nil
+	}
+	var value interface{}
+	if err := json.Unmarshal(v.Value, &value); err != nil {
+		return nil
+	}
+	return value
+}
+
+func (n *axNode) role() string {
+	if role, ok := axValue(n.node.Role).(string); ok {
+		return role
+	}
+	return ""
+}
+
+func (n *axNode) name() string {
+	if name, ok := axValue(n.node.Name).(string); ok {
+		return name
+	}
+	return ""
+}
+
+func (n *axNode) find(id cdp.BackendNodeID) *axNode {
+	if n.node.BackendDOMNodeID == id {
+		return n
+	}
+	for _, c := range n.children {
+		if found := c.find(id); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func (n *axNode) isControl() bool {
+	return axControlRoles[n.role()]
+}
+
+// isLeaf reports whether the node's descendants are not part of the
+// snapshot, which is the case for the nodes with only text descendants.
+func (n *axNode) isLeaf() bool {
+	if len(n.children) == 0 || axLeafRoles[n.role()] {
+		return true
+	}
+	for _, c := range n.children {
+		if r := c.role(); r != "StaticText" && r != "InlineTextBox" {
+			return false
+		}
+	}
+	return true
+}
+
+func (n *axNode) isInteresting(insideControl bool) bool {
+	if n.node.Ignored || n.role() == "Ignored" || n.properties[accessibility.PropertyNameHidden] == true {
+		return false
+	}
+	if n.properties[accessibility.PropertyNameFocusable] == true ||
+		n.properties[accessibility.PropertyNameEditable] == "richtext" {
+		return true
+	}
+	if n.isControl() {
+		return true
+	}
+	if insideControl {
+		return false
+	}
+	return n.isLeaf() && n.name() != ""
+}
+
+func (n *axNode) collectInteresting(interesting map[*axNode]bool, insideControl bool) {
+	if n.isInteresting(insideControl) {
+		interesting[n] = true
+	}
+	if n.isLeaf() {
+		return
+	}
+	insideControl = insideControl || n.isControl()
+	for _, c := range n.children {
+		c.collectInteresting(interesting, insideControl)
+	}
+}
+
+// serializeTree serializes the node and its descendants. If interesting is
+// not nil, the nodes that are not in it are left out, and their children
+// take their place.
+func (n *axNode) serializeTree(interesting map[*axNode]bool) []map[string]interface{} {
+	var children []map[string]interface{}
+	if !n.isLeaf() {
+		for _, c := range n.children {
+			children = append(children, c.serializeTree(interesting)...)
+		}
+	}
+	if interesting != nil && !interesting[n] {
+		return children
+	}
+	s := n.serialize()
+	if len(children) > 0 {
+		s["children"] = children
+	}
+	return []map[string]interface{}{s}
+}
+
+func (n *axNode) serialize() map[string]interface{} {
+	s := map[string]interface{}{
+		"role": n.role(),
+	}
+	if name := n.name(); name != "" {
+		s["name"] = name
+	}
+	if v := axValue(n.node.Value); v != nil && v != "" {
+		s["value"] = v
+	}
+	if d, ok := axValue(n.node.Description).(string); ok && d != "" {
+		s["description"] = d
+	}
+
+	// the boolean states are only added when they're set.
+	for _, p := range []accessibility.PropertyName{
+		accessibility.PropertyNameDisabled,
+		accessibility.PropertyNameExpanded,
+		accessibility.PropertyNameFocused,
+		accessibility.PropertyNameModal,
+		accessibility.PropertyNameMultiline,
+		accessibility.PropertyNameMultiselectable,
+		accessibility.PropertyNameReadonly,
+		accessibility.PropertyNameRequired,
+		accessibility.PropertyNameSelected,
+	} {
+		if n.properties[p] == true {
+			s[string(p)] = true
+		}
+	}
+	// the tristate states are true, false, or mixed.
+	for _, p := range []accessibility.PropertyName{
+		accessibility.PropertyNameChecked,
+		accessibility.PropertyNamePressed,
+	} {
+		switch v := n.properties[p]; v {
+		case "true", true:
+			s[string(p)] = true
+		case "false", false:
+			s[string(p)] = false
+		case "mixed":
+			s[string(p)] = "mixed"
+		}
+	}
+	for _, p := range []accessibility.PropertyName{
+		accessibility.PropertyNameLevel,
+		accessibility.PropertyNameValuemax,
+		accessibility.PropertyNameValuemin,
+	} {
+		if v, ok := n.properties[p].(float64); ok {
+			s[string(p)] = v
+		}
+	}
+	for p, key := range map[accessibility.PropertyName]string{
+		accessibility.PropertyNameAutocomplete:    "autocomplete",
+		accessibility.PropertyNameHasPopup:        "haspopup",
+		accessibility.PropertyNameInvalid:         "invalid",
+		accessibility.PropertyNameKeyshortcuts:    "keyshortcuts",
+		accessibility.PropertyNameOrientation:     "orientation",
+		accessibility.PropertyNameRoledescription: "roledescription",
+		accessibility.PropertyNameValuetext:       "valuetext",
+	} {
+		if v, ok := n.properties[p].(string); ok && v != "" && v != "false" {
+			s[key] = v
+		}
+	}
+
+	return s
+}
@@ -0,0 +1,48 @@
+package common
+
+import (
+	"context"
+	"errors"
+
+	"github.com/dop251/goja"
+
+	"github.com/grafana/xk6-browser/k6ext"
+)
+
+// AccessibilitySnapshotOptions are the options of Accessibility.snapshot.
+type AccessibilitySnapshotOptions struct {
+	// InterestingOnly prunes the nodes without useful semantics.
+	InterestingOnly bool `json:"interestingOnly"`
+	// Root is the element to take the snapshot of, instead of the page.
+	Root *ElementHandle `json:"root"`
+}
+
+func NewAccessibilitySnapshotOptions() *AccessibilitySnapshotOptions {
+	return &AccessibilitySnapshotOptions{
+		InterestingOnly: true,
+	}
+}
+
+func (o *AccessibilitySnapshotOptions) Parse(ctx context.Context, opts goja.Value) error {
+	rt := k6ext.Runtime(ctx)
+	if opts != nil && !goja.IsUndefined(opts) && !goja.IsNull(opts) {
+		opts := opts.ToObject(rt)
+		for _, k := range opts.Keys() {
+			switch k {
+			case "interestingOnly":
+				o.InterestingOnly = opts.Get(k).ToBoolean()
+			case "root":
+				v := opts.Get(k)
+				if !gojaValueExists(v) {
+					continue
+				}
+				h, ok := v.Export().(*ElementHandle)
+				if !ok {
+					return errors.New("root must be an element handle")
+				}
+				o.Root = h
+			}
+		}
+	}
+	return nil
+}
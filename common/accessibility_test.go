@@ -0,0 +1,65 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/chromedp/cdproto/accessibility"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAXTreeSnapshot(t *testing.T) {
+	t.Parallel()
+
+	value := func(v string) *accessibility.Value {
+		return &accessibility.Value{Type: accessibility.ValueTypeString, Value: []byte(v)}
+	}
+	property := func(name accessibility.PropertyName, v string) *accessibility.Property {
+		return &accessibility.Property{Name: name, Value: value(v)}
+	}
+	nodes := []*accessibility.Node{
+		{
+			NodeID: "1", Role: value(`"RootWebArea"`), Name: value(`"page"`), ChildIds: []accessibility.NodeID{"2", "5"},
+			Properties: []*accessibility.Property{property(accessibility.PropertyNameFocusable, "true")},
+		},
+		{NodeID: "2", Role: value(`"generic"`), ChildIds: []accessibility.NodeID{"3", "4"}},
+		{
+			NodeID: "3", Role: value(`"button"`), Name: value(`"OK"`), ChildIds: []accessibility.NodeID{"6"},
+			Properties: []*accessibility.Property{property(accessibility.PropertyNameDisabled, "true")},
+		},
+		{
+			NodeID: "4", Role: value(`"checkbox"`), Name: value(`"agree"`), BackendDOMNodeID: 42,
+			Properties: []*accessibility.Property{property(accessibility.PropertyNameChecked, `"mixed"`)},
+		},
+		{
+			NodeID: "5", Role: value(`"heading"`), Name: value(`"Title"`), ChildIds: []accessibility.NodeID{"7"},
+			Properties: []*accessibility.Property{property(accessibility.PropertyNameLevel, "2")},
+		},
+		{NodeID: "6", Role: value(`"StaticText"`), Name: value(`"OK"`)},
+		{NodeID: "7", Role: value(`"StaticText"`), Name: value(`"Title"`)},
+	}
+	root := newAXTree(nodes)
+	require.NotNil(t, root)
+
+	interesting := make(map[*axNode]bool)
+	root.collectInteresting(interesting, false)
+	assert.Equal(t, []map[string]interface{}{{
+		"role": "RootWebArea",
+		"name": "page",
+		"children": []map[string]interface{}{
+			{"role": "button", "name": "OK", "disabled": true},
+			{"role": "checkbox", "name": "agree", "checked": "mixed"},
+			{"role": "heading", "name": "Title", "level": 2.0},
+		},
+	}}, root.serializeTree(interesting), "should prune the generic and text nodes")
+
+	all := root.serializeTree(nil)
+	require.Len(t, all, 1)
+	children, ok := all[0]["children"].([]map[string]interface{})
+	require.True(t, ok)
+	require.Len(t, children, 2)
+	assert.Equal(t, "generic", children[0]["role"])
+
+	assert.Same(t, root.children[0].children[1], root.find(42))
+	assert.Nil(t, root.find(43))
+}
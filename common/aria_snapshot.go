@@ -0,0 +1,182 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/chromedp/cdproto/accessibility"
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/dom"
+)
+
+// ariaNode is a node of an ARIA snapshot. A node without a role is a text
+// node.
+type ariaNode struct {
+	role     string
+	name     string
+	attrs    []string
+	text     string
+	children []*ariaNode
+}
+
+// ariaSnapshot returns the ARIA snapshot of the element's subtree, which
+// lists the roles, the accessible names, and the states of the nodes in a
+// YAML format. Each node is a list item such as `heading "Title" [level=1]`,
+// and its children are nested under it.
+func (h *ElementHandle) ariaSnapshot(apiCtx context.Context) (string, error) {
+	node, err := dom.DescribeNode().WithObjectID(h.remoteObject.ObjectID).Do(cdp.WithExecutor(apiCtx, h.session))
+	if err != nil {
+		return "", fmt.Errorf("describing element: %w", err)
+	}
+	action := accessibility.GetFullAXTree().WithFrameID(cdp.FrameID(h.frame.ID()))
+	nodes, err := action.Do(cdp.WithExecutor(apiCtx, h.session))
+	if err != nil {
+		return "", fmt.Errorf("getting accessibility tree: %w", err)
+	}
+	root := newAXTree(nodes)
+	if root == nil {
+		return "", nil
+	}
+	needle := root.find(node.BackendNodeID)
+	if needle == nil {
+		// the elements that are not rendered are not in the tree.
+		return "", nil
+	}
+
+	var sb strings.Builder
+	writeARIANodes(&sb, needle.ariaNodes(), 0)
+	return strings.TrimSuffix(sb.String(), "\n"), nil
+}
+
+// ariaNodes returns the ARIA snapshot nodes of the node. The nodes without
+// semantics are replaced by their children.
+func (n *axNode) ariaNodes() []*ariaNode {
+	role := n.role()
+	switch role {
+	case "StaticText":
+		text := normalizeWhitespace(n.name())
+		if text == "" {
+			return nil
+		}
+		return []*ariaNode{{text: text}}
+	case "InlineTextBox":
+		return nil
+	}
+
+	var children []*ariaNode
+	for _, c := range n.children {
+		children = append(children, c.ariaNodes()...)
+	}
+	children = mergeARIATexts(children)
+	if n.node.Ignored || role == "" || role == "generic" || role == "none" ||
+		role == "presentation" || role == "LineBreak" || role == "Ignored" {
+		return children
+	}
+
+	an := &ariaNode{
+		role:  role,
+		name:  normalizeWhitespace(n.name()),
+		attrs: n.ariaAttrs(),
+	}
+	// the text that only repeats the name of the node is left out.
+	if len(children) == 1 && children[0].role == "" && children[0].text == an.name {
+		children = nil
+	}
+	an.children = children
+
+	return []*ariaNode{an}
+}
+
+// ariaAttrs returns the states of the node in the ARIA snapshot format.
+func (n *axNode) ariaAttrs() []string {
+	var attrs []string
+	for _, p := range []accessibility.PropertyName{
+		accessibility.PropertyNameChecked,
+		accessibility.PropertyNameDisabled,
+		accessibility.PropertyNameExpanded,
+		accessibility.PropertyNameLevel,
+		accessibility.PropertyNamePressed,
+		accessibility.PropertyNameSelected,
+	} {
+		switch v := n.properties[p]; v {
+		case nil, false, "false":
+		case true, "true":
+			attrs = append(attrs, string(p))
+		case "mixed":
+			attrs = append(attrs, string(p)+"=mixed")
+		default:
+			attrs = append(attrs, fmt.Sprintf("%s=%v", p, v))
+		}
+	}
+	return attrs
+}
+
+// mergeARIATexts merges the adjacent text nodes.
+func mergeARIATexts(nodes []*ariaNode) []*ariaNode {
+	merged := make([]*ariaNode, 0, len(nodes))
+	for _, n := range nodes {
+		if last := len(merged) - 1; last >= 0 && n.role == "" && merged[last].role == "" {
+			merged[last] = &ariaNode{text: merged[last].text + " " + n.text}
+			continue
+		}
+		merged = append(merged, n)
+	}
+	return merged
+}
+
+func writeARIANodes(sb *strings.Builder, nodes []*ariaNode, depth int) {
+	indent := strings.Repeat("  ", depth)
+	for _, n := range nodes {
+		sb.WriteString(indent + "- ")
+		if n.role == "" {
+			sb.WriteString("text: " + yamlScalar(n.text) + "\n")
+			continue
+		}
+		sb.WriteString(n.role)
+		if n.name != "" {
+			sb.WriteString(" " + jsonString(n.name))
+		}
+		for _, a := range n.attrs {
+			sb.WriteString(" [" + a + "]")
+		}
+		switch {
+		case len(n.children) == 0:
+			sb.WriteString("\n")
+		case len(n.children) == 1 && n.children[0].role == "":
+			sb.WriteString(": " + yamlScalar(n.children[0].text) + "\n")
+		default:
+			sb.WriteString(":\n")
+			writeARIANodes(sb, n.children, depth+1)
+		}
+	}
+}
+
+// normalizeWhitespace collapses the whitespace sequences of s to single
+// spaces, and trims it.
+func normalizeWhitespace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// yamlScalar returns s as a plain YAML scalar, or as a quoted one if it
+// could be read as something else.
+func yamlScalar(s string) string {
+	if s == "" || strings.ContainsAny(s[:1], "-?:,[]{}#&*!|>'\"%@`") ||
+		strings.Contains(s, ": ") || strings.Contains(s, " #") || strings.HasSuffix(s, ":") {
+		return jsonString(s)
+	}
+	switch strings.ToLower(s) {
+	case "true", "false", "yes", "no", "null", "~":
+		return jsonString(s)
+	}
+	return s
+}
+
+func jsonString(s string) string {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Sprintf("%q", s)
+	}
+	return string(b)
+}
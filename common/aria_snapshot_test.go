@@ -0,0 +1,70 @@
+package common
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chromedp/cdproto/accessibility"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAXTreeARIASnapshot(t *testing.T) {
+	t.Parallel()
+
+	value := func(v string) *accessibility.Value {
+		return &accessibility.Value{Type: accessibility.ValueTypeString, Value: []byte(v)}
+	}
+	property := func(name accessibility.PropertyName, v string) *accessibility.Property {
+		return &accessibility.Property{Name: name, Value: value(v)}
+	}
+	nodes := []*accessibility.Node{
+		{NodeID: "1", Role: value(`"generic"`), ChildIds: []accessibility.NodeID{"2", "3", "5", "8"}},
+		{
+			NodeID: "2", Role: value(`"heading"`), Name: value(`"  The\n  Title "`), ChildIds: []accessibility.NodeID{"9"},
+			Properties: []*accessibility.Property{property(accessibility.PropertyNameLevel, "1")},
+		},
+		{
+			NodeID: "3", Role: value(`"checkbox"`), Name: value(`"remember"`),
+			Properties: []*accessibility.Property{property(accessibility.PropertyNameChecked, `"true"`)},
+		},
+		{NodeID: "5", Role: value(`"list"`), ChildIds: []accessibility.NodeID{"6", "7"}},
+		{NodeID: "6", Role: value(`"listitem"`), ChildIds: []accessibility.NodeID{"10"}},
+		{NodeID: "7", Role: value(`"listitem"`), ChildIds: []accessibility.NodeID{"11", "12"}},
+		{NodeID: "8", Role: value(`"StaticText"`), Name: value(`"key: value"`)},
+		{NodeID: "9", Role: value(`"StaticText"`), Name: value(`"The Title"`)},
+		{NodeID: "10", Role: value(`"StaticText"`), Name: value(`"one"`)},
+		{NodeID: "11", Role: value(`"StaticText"`), Name: value(`"two"`)},
+		{NodeID: "12", Role: value(`"StaticText"`), Name: value(`" and  three"`)},
+	}
+	root := newAXTree(nodes)
+	require.NotNil(t, root)
+
+	var sb strings.Builder
+	writeARIANodes(&sb, root.ariaNodes(), 0)
+	assert.Equal(t, `- heading "The Title" [level=1]
+- checkbox "remember" [checked]
+- list:
+  - listitem: one
+  - listitem: two and three
+- text: "key: value"
+`, sb.String())
+}
+
+func TestYAMLScalar(t *testing.T) {
+	t.Parallel()
+
+	for in, want := range map[string]string{
+		"plain":      "plain",
+		"":           `""`,
+		"true":       `"true"`,
+		"- item":     `"- item"`,
+		"a: b":       `"a: b"`,
+		`say "hi"`:   `say "hi"`,
+		`"quoted"`:   `"\"quoted\""`,
+		"trailing:":  `"trailing:"`,
+		"with # tag": `"with # tag"`,
+	} {
+		assert.Equal(t, want, yamlScalar(in), "yamlScalar(%q)", in)
+	}
+}
@@ -0,0 +1,212 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/dop251/goja"
+)
+
+// bindingName is the name of the CDP binding that all the exposed functions
+// use to call back into the k6 script.
+const bindingName = "__k6_browser_binding__"
+
+// bindingInitScript installs an exposed function in the page. Calls to the
+// function are kept in the page until their result is delivered, and only
+// their sequence number is sent through the CDP binding.
+const bindingInitScript = `
+(() => {
+	const binding = globalThis[%[1]q];
+	const name = %[2]q;
+	const needsHandle = %[3]t;
+	if (typeof binding !== 'function' || globalThis[name]) {
+		return;
+	}
+	const bindings = globalThis.__k6_browser_bindings__ = globalThis.__k6_browser_bindings__ || {};
+	const state = bindings[name] = {
+		lastSeq: 0,
+		calls: new Map(),
+		deliver(seq, result, error) {
+			const call = this.calls.get(seq);
+			if (!call) {
+				return;
+			}
+			this.calls.delete(seq);
+			if (error !== null) {
+				call.reject(new Error(error));
+			} else {
+				call.resolve(result);
+			}
+		},
+	};
+	globalThis[name] = (...args) => {
+		if (needsHandle && args.length > 1) {
+			return Promise.reject(new Error(
+				'exposed function "' + name + '" with the handle option accepts a single argument'));
+		}
+		const seq = ++state.lastSeq;
+		const promise = new Promise((resolve, reject) => {
+			state.calls.set(seq, { args, resolve, reject });
+		});
+		binding(JSON.stringify({ name, seq }));
+		return promise;
+	};
+})();
+`
+
+const (
+	bindingArgsFn = `(name, seq) => globalThis.__k6_browser_bindings__[name].calls.get(seq).args`
+
+	bindingHandleFn = `(name, seq) => ({
+		value: globalThis.__k6_browser_bindings__[name].calls.get(seq).args[0],
+	})`
+
+	bindingDeliverFn = `(name, seq, result, error) => {
+		globalThis.__k6_browser_bindings__[name].deliver(seq, result, error);
+	}`
+)
+
+// pageBinding is a function exposed to the page by the k6 script.
+type pageBinding struct {
+	name string
+	fn   goja.Callable
+	// the callback receives the source of the call as its first argument.
+	needsSource bool
+	// the callback receives the first argument as a JS handle.
+	needsHandle bool
+	source      string
+}
+
+func newPageBinding(name string, fn goja.Callable, needsSource, needsHandle bool) *pageBinding {
+	return &pageBinding{
+		name:        name,
+		fn:          fn,
+		needsSource: needsSource,
+		needsHandle: needsHandle,
+		source:      fmt.Sprintf(bindingInitScript, bindingName, name, needsHandle),
+	}
+}
+
+// bindingSource describes the page and frame that called an exposed
+// function.
+type bindingSource struct {
+	Context *BrowserContext `js:"context"`
+	Page    *Page           `js:"page"`
+	Frame   *Frame          `js:"frame"`
+}
+
+// bindingPayload is the payload the exposed functions send through the
+// CDP binding.
+type bindingPayload struct {
+	Name string `json:"name"`
+	Seq  int64  `json:"seq"`
+}
+
+// call calls the exposed function with the arguments of the page call,
+// and delivers its result back to the page.
+func (b *pageBinding) call(
+	ctx context.Context, rt *goja.Runtime, execCtx *ExecutionContext, seq int64, src *bindingSource,
+) error {
+	var (
+		result interface{}
+		errMsg interface{}
+	)
+	if v, err := b.invoke(ctx, rt, execCtx, seq, src); err != nil {
+		errMsg = err.Error()
+	} else {
+		result = v
+	}
+
+	opts := evalOptions{forceCallable: true, returnByValue: true}
+	if _, err := execCtx.eval(ctx, opts, bindingDeliverFn, b.name, seq, result, errMsg); err != nil {
+		return fmt.Errorf("delivering result of exposed function %q: %w", b.name, err)
+	}
+
+	return nil
+}
+
+func (b *pageBinding) invoke(
+	ctx context.Context, rt *goja.Runtime, execCtx *ExecutionContext, seq int64, src *bindingSource,
+) (interface{}, error) {
+	var args []goja.Value
+	if b.needsSource {
+		args = append(args, rt.ToValue(src))
+	}
+	callArgs, err := b.args(ctx, rt, execCtx, seq)
+	if err != nil {
+		return nil, fmt.Errorf("getting arguments: %w", err)
+	}
+	args = append(args, callArgs...)
+
+	v, err := b.fn(goja.Undefined(), args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return bindingResult(v)
+}
+
+// args returns the arguments of the page call. The first argument is
+// returned as a JS handle if the binding needs a handle.
+func (b *pageBinding) args(
+	ctx context.Context, rt *goja.Runtime, execCtx *ExecutionContext, seq int64,
+) ([]goja.Value, error) {
+	if b.needsHandle {
+		opts := evalOptions{forceCallable: true, returnByValue: false}
+		res, err := execCtx.eval(ctx, opts, bindingHandleFn, b.name, seq)
+		if err != nil {
+			return nil, err
+		}
+		h, err := unwrapValueHandle(res)
+		if err != nil {
+			return nil, err
+		}
+		return []goja.Value{rt.ToValue(h)}, nil
+	}
+
+	opts := evalOptions{forceCallable: true, returnByValue: true}
+	res, err := execCtx.eval(ctx, opts, bindingArgsFn, b.name, seq)
+	if err != nil {
+		return nil, err
+	}
+	var args []goja.Value
+	if v, ok := res.(goja.Value); ok {
+		if exported, ok := v.Export().([]interface{}); ok {
+			for _, a := range exported {
+				args = append(args, rt.ToValue(a))
+			}
+		}
+	}
+	return args, nil
+}
+
+// bindingResult returns the exported value of the exposed function result.
+// Promises are supported only if they are already settled.
+func bindingResult(v goja.Value) (interface{}, error) {
+	if !gojaValueExists(v) {
+		return nil, nil
+	}
+	p, ok := v.Export().(*goja.Promise)
+	if !ok {
+		return v.Export(), nil
+	}
+	switch p.State() {
+	case goja.PromiseStateFulfilled:
+		return bindingResult(p.Result())
+	case goja.PromiseStateRejected:
+		return nil, fmt.Errorf("%v", p.Result())
+	default:
+		return nil, errors.New("returned promise is not settled")
+	}
+}
+
+// parseBindingPayload parses the payload of a binding call event.
+func parseBindingPayload(payload string) (*bindingPayload, error) {
+	var p bindingPayload
+	if err := json.Unmarshal([]byte(payload), &p); err != nil {
+		return nil, fmt.Errorf("parsing binding payload: %w", err)
+	}
+	return &p, nil
+}
@@ -0,0 +1,48 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/dop251/goja"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBindingResult(t *testing.T) {
+	t.Parallel()
+
+	rt := goja.New()
+
+	v, err := bindingResult(rt.ToValue(42))
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), v)
+
+	v, err = bindingResult(goja.Undefined())
+	require.NoError(t, err)
+	assert.Nil(t, v)
+
+	p, resolve, reject := rt.NewPromise()
+	_, err = bindingResult(rt.ToValue(p))
+	assert.EqualError(t, err, "returned promise is not settled")
+
+	resolve("done")
+	v, err = bindingResult(rt.ToValue(p))
+	require.NoError(t, err)
+	assert.Equal(t, "done", v)
+
+	p, _, reject = rt.NewPromise()
+	reject("oops")
+	_, err = bindingResult(rt.ToValue(p))
+	assert.EqualError(t, err, "oops")
+}
+
+func TestParseBindingPayload(t *testing.T) {
+	t.Parallel()
+
+	p, err := parseBindingPayload(`{"name":"add","seq":3}`)
+	require.NoError(t, err)
+	assert.Equal(t, &bindingPayload{Name: "add", Seq: 3}, p)
+
+	_, err = parseBindingPayload(`{`)
+	assert.ErrorContains(t, err, "parsing binding payload")
+}
@@ -82,6 +82,17 @@ type Browser struct {
 	sessionIDtoTargetIDMu sync.RWMutex
 	sessionIDtoTargetID   map[target.SessionID]target.ID
 
+	// downloads in progress by their GUID.
+	downloadsMu sync.Mutex
+	downloads   map[string]*Download
+
+	// version is the version of the browser, fetched on the first call to
+	// Version or UserAgent.
+	versionMu sync.Mutex
+	version   *browserVersion
+
+	tracer *tracer
+
 	vu k6modules.VU
 
 	logger *log.Logger
@@ -110,7 +121,7 @@ func newBrowser(
 	launchOpts *LaunchOptions,
 	logger *log.Logger,
 ) *Browser {
-	return &Browser{
+	b := &Browser{
 		BaseEventEmitter:    NewBaseEventEmitter(ctx),
 		ctx:                 ctx,
 		cancelFn:            cancelFn,
@@ -120,9 +131,13 @@ func newBrowser(
 		contexts:            make(map[cdp.BrowserContextID]*BrowserContext),
 		pages:               make(map[target.ID]*Page),
 		sessionIDtoTargetID: make(map[target.SessionID]target.ID),
+		downloads:           make(map[string]*Download),
 		vu:                  k6ext.GetVU(ctx),
 		logger:              logger,
 	}
+	b.tracer = newTracer(ctx, b, logger)
+
+	return b
 }
 
 func (b *Browser) connect() error {
@@ -137,7 +152,16 @@ func (b *Browser) connect() error {
 	// We don't need to lock this because `connect()` is called only in NewBrowser
 	b.defaultContext = NewBrowserContext(b.ctx, b, "", NewBrowserContextOptions(), b.logger)
 
-	return b.initEvents()
+	if err := b.initEvents(); err != nil {
+		return err
+	}
+	// the downloads of the default context are denied, as it has the default
+	// options, but their events are still emitted to its pages.
+	if err := b.defaultContext.initDownloads(); err != nil {
+		return fmt.Errorf("initializing downloads of the default context: %w", err)
+	}
+
+	return nil
 }
 
 func (b *Browser) disposeContext(id cdp.BrowserContextID) error {
@@ -173,6 +197,8 @@ func (b *Browser) initEvents() error {
 	b.conn.on(cancelCtx, []string{
 		cdproto.EventTargetAttachedToTarget,
 		cdproto.EventTargetDetachedFromTarget,
+		cdproto.EventBrowserDownloadWillBegin,
+		cdproto.EventBrowserDownloadProgress,
 		EventConnectionClose,
 	}, chHandler)
 
@@ -195,6 +221,11 @@ func (b *Browser) initEvents() error {
 				} else if ev, ok := event.data.(*target.EventDetachedFromTarget); ok {
 					b.logger.Debugf("Browser:initEvents:onDetachedFromTarget", "sid:%v", ev.SessionID)
 					b.onDetachedFromTarget(ev)
+				} else if ev, ok := event.data.(*cdpbrowser.EventDownloadWillBegin); ok {
+					b.logger.Debugf("Browser:initEvents:onDownloadWillBegin", "guid:%v url:%q", ev.GUID, ev.URL)
+					b.onDownloadWillBegin(ev)
+				} else if ev, ok := event.data.(*cdpbrowser.EventDownloadProgress); ok {
+					b.onDownloadProgress(ev)
 				} else if event.typ == EventConnectionClose {
 					b.logger.Debugf("Browser:initEvents:EventConnectionClose", "")
 					return
@@ -240,6 +271,16 @@ func (b *Browser) onAttachedToTarget(ev *target.EventAttachedToTarget) {
 		return
 	}
 
+	// A target that already has a page is attached again by a CDP session,
+	// and the session is managed by it.
+	b.pagesMu.RLock()
+	_, ok = b.pages[evti.TargetID]
+	b.pagesMu.RUnlock()
+	if ok {
+		b.logger.Debugf("Browser:onAttachedToTarget:return", "sid:%v tid:%v (cdp session)", ev.SessionID, evti.TargetID)
+		return
+	}
+
 	session := b.conn.getSession(ev.SessionID)
 
 	switch evti.Type {
@@ -314,6 +355,9 @@ func (b *Browser) onAttachedToTarget(ev *target.EventAttachedToTarget) {
 		b.sessionIDtoTargetIDMu.Unlock()
 
 		browserCtx.emit(EventBrowserContextPage, p)
+		if opener != nil {
+			opener.onPopup(p)
+		}
 	default:
 		b.logger.Warnf(
 			"Browser:onAttachedToTarget", "sid:%v tid:%v bctxid:%v bctx nil:%t, unknown target type: %q",
@@ -321,6 +365,52 @@ func (b *Browser) onAttachedToTarget(ev *target.EventAttachedToTarget) {
 	}
 }
 
+// onDownloadWillBegin creates a download for the page of the frame that
+// started it, and emits it to the page download handlers.
+func (b *Browser) onDownloadWillBegin(ev *cdpbrowser.EventDownloadWillBegin) {
+	var page *Page
+	for _, p := range b.getPages() {
+		if p.frameManager.getFrameByID(ev.FrameID) != nil {
+			page = p
+			break
+		}
+	}
+	if page == nil {
+		b.logger.Debugf("Browser:onDownloadWillBegin", "guid:%v fid:%v: page not found", ev.GUID, ev.FrameID)
+		return
+	}
+
+	d := NewDownload(b.ctx, page, ev.GUID, ev.URL, ev.SuggestedFilename, page.browserCtx.downloadsPath, b.logger)
+	b.downloadsMu.Lock()
+	b.downloads[ev.GUID] = d
+	b.downloadsMu.Unlock()
+
+	page.callEventHandlers(EventPageDownload, d, nil)
+}
+
+// onDownloadProgress finishes the download once the browser completes or
+// cancels it.
+func (b *Browser) onDownloadProgress(ev *cdpbrowser.EventDownloadProgress) {
+	var failure string
+	switch ev.State {
+	case cdpbrowser.DownloadProgressStateCompleted:
+	case cdpbrowser.DownloadProgressStateCanceled:
+		failure = "canceled"
+	default:
+		return
+	}
+
+	b.downloadsMu.Lock()
+	d, ok := b.downloads[ev.GUID]
+	delete(b.downloads, ev.GUID)
+	b.downloadsMu.Unlock()
+
+	if ok {
+		b.logger.Debugf("Browser:onDownloadProgress", "guid:%v state:%v", ev.GUID, ev.State)
+		d.finish(failure)
+	}
+}
+
 // onDetachedFromTarget event can be issued multiple times per target if multiple
 // sessions have been attached to it. So we'll remove the page only once.
 func (b *Browser) onDetachedFromTarget(ev *target.EventDetachedFromTarget) {
@@ -409,6 +499,14 @@ func (b *Browser) Close() {
 		if err := b.browserProc.userDataDir.Cleanup(); err != nil {
 			b.logger.Errorf("Browser:Close", "cleaning up the user data directory: %v", err)
 		}
+		b.contextsMu.RLock()
+		for _, bctx := range b.contexts {
+			if err := bctx.flushHAR(); err != nil {
+				b.logger.Errorf("Browser:Close", "%v", err)
+			}
+			bctx.cleanupDownloads()
+		}
+		b.contextsMu.RUnlock()
 	}()
 
 	b.logger.Debugf("Browser:Close", "")
@@ -435,14 +533,16 @@ func (b *Browser) Close() {
 	b.conn.Close()
 }
 
-// Contexts returns list of browser contexts.
+// Contexts returns the open browser contexts created with NewContext or
+// NewPage. The contexts are removed from the list when they're closed.
+// The default browser context isn't listed, since it can't be closed.
 func (b *Browser) Contexts() []api.BrowserContext {
 	b.contextsMu.RLock()
 	defer b.contextsMu.RUnlock()
 
 	contexts := make([]api.BrowserContext, 0, len(b.contexts))
-	for _, b := range b.contexts {
-		contexts = append(contexts, b)
+	for _, bctx := range b.contexts {
+		contexts = append(contexts, bctx)
 	}
 
 	return contexts
@@ -471,6 +571,12 @@ func (b *Browser) NewContext(opts goja.Value) api.BrowserContext {
 	b.contextsMu.Lock()
 	defer b.contextsMu.Unlock()
 	browserCtx := NewBrowserContext(b.ctx, b, browserContextID, browserCtxOpts, b.logger)
+	if err := browserCtx.initDownloads(); err != nil {
+		k6ext.Panic(b.ctx, "initializing downloads: %w", err)
+	}
+	if err := browserCtx.restoreStorageState(); err != nil {
+		k6ext.Panic(b.ctx, "restoring storage state: %w", err)
+	}
 	b.contexts[browserContextID] = browserCtx
 
 	return browserCtx
@@ -498,26 +604,112 @@ func (b *Browser) On(event string) *goja.Promise {
 	})
 }
 
+// StartTracing starts recording a Chrome DevTools trace. The tracing is
+// browser-wide, so the trace contains the events of all the browser contexts
+// and pages.
+func (b *Browser) StartTracing(opts goja.Value) {
+	b.logger.Debugf("Browser:StartTracing", "")
+
+	if b.tracer.trace != nil {
+		k6ext.Panic(b.ctx, "tracing has already been started")
+	}
+	topts := NewTracingStartOptions()
+	if err := topts.Parse(b.ctx, opts); err != nil {
+		k6ext.Panic(b.ctx, "parsing tracing options: %w", err)
+	}
+	if err := b.tracer.start(topts); err != nil {
+		k6ext.Panic(b.ctx, "starting tracing: %w", err)
+	}
+}
+
+// StopTracing stops recording the trace and returns it as a zip file
+// containing a trace.json file. The trace is also saved at the path given to
+// StartTracing, if any.
+func (b *Browser) StopTracing() goja.ArrayBuffer {
+	b.logger.Debugf("Browser:StopTracing", "")
+
+	if b.tracer.trace == nil {
+		k6ext.Panic(b.ctx, "tracing has not been started")
+	}
+	buf, err := b.tracer.stop(b.tracer.trace.opts.Path)
+	if err != nil {
+		k6ext.Panic(b.ctx, "stopping tracing: %w", err)
+	}
+
+	return b.vu.Runtime().NewArrayBuffer(buf)
+}
+
 // UserAgent returns the controlled browser's user agent string.
 func (b *Browser) UserAgent() string {
-	action := cdpbrowser.GetVersion()
-	_, _, _, ua, _, err := action.Do(cdp.WithExecutor(b.ctx, b.conn))
+	v, err := b.fetchVersion()
 	if err != nil {
 		k6ext.Panic(b.ctx, "getting browser user agent: %w", err)
 	}
-	return ua
+	return v.userAgent
 }
 
 // Version returns the controlled browser's version.
 func (b *Browser) Version() string {
-	action := cdpbrowser.GetVersion()
-	_, product, _, _, _, err := action.Do(cdp.WithExecutor(b.ctx, b.conn))
+	v, err := b.fetchVersion()
 	if err != nil {
 		k6ext.Panic(b.ctx, "getting browser version: %w", err)
 	}
-	i := strings.Index(product, "/")
+	i := strings.Index(v.product, "/")
 	if i == -1 {
-		return product
+		return v.product
 	}
-	return product[i+1:]
+	return v.product[i+1:]
+}
+
+// VersionInfo returns the version information of the controlled browser:
+// the product, the revision, the CDP protocol version, the V8 version as
+// jsVersion, and the user agent.
+func (b *Browser) VersionInfo() map[string]string {
+	v, err := b.fetchVersion()
+	if err != nil {
+		k6ext.Panic(b.ctx, "getting browser version information: %w", err)
+	}
+	return map[string]string{
+		"product":         v.product,
+		"revision":        v.revision,
+		"protocolVersion": v.protocolVersion,
+		"jsVersion":       v.jsVersion,
+		"userAgent":       v.userAgent,
+	}
+}
+
+// browserVersion is the version information of the browser.
+type browserVersion struct {
+	protocolVersion string
+	product         string
+	revision        string
+	userAgent       string
+	jsVersion       string
+}
+
+// fetchVersion returns the version information of the browser. It's fetched
+// once, since it doesn't change while the browser is running.
+func (b *Browser) fetchVersion() (*browserVersion, error) {
+	b.versionMu.Lock()
+	defer b.versionMu.Unlock()
+
+	if b.version != nil {
+		return b.version, nil
+	}
+
+	var (
+		v      browserVersion
+		err    error
+		action = cdpbrowser.GetVersion()
+	)
+	v.protocolVersion, v.product, v.revision, v.userAgent, v.jsVersion, err = action.Do(
+		cdp.WithExecutor(b.ctx, b.conn))
+	if err != nil {
+		return nil, fmt.Errorf("fetching version information: %w", err)
+	}
+	b.logger.Debugf("Browser:fetchVersion", "product:%q revision:%q protocol:%q js:%q ua:%q",
+		v.product, v.revision, v.protocolVersion, v.jsVersion, v.userAgent)
+	b.version = &v
+
+	return b.version, nil
 }
@@ -22,8 +22,14 @@ package common
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"os"
 	"reflect"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/grafana/xk6-browser/api"
@@ -34,8 +40,10 @@ import (
 
 	cdpbrowser "github.com/chromedp/cdproto/browser"
 	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/network"
 	"github.com/chromedp/cdproto/storage"
 	"github.com/chromedp/cdproto/target"
+	"github.com/chromedp/cdproto/webauthn"
 	"github.com/dop251/goja"
 )
 
@@ -50,6 +58,8 @@ var _ api.BrowserContext = &BrowserContext{}
 type BrowserContext struct {
 	BaseEventEmitter
 
+	Tracing *Tracing `js:"tracing"` // Public JS API
+
 	ctx             context.Context
 	browser         *Browser
 	id              cdp.BrowserContextID
@@ -59,6 +69,30 @@ type BrowserContext struct {
 	vu              k6modules.VU
 
 	evaluateOnNewDocumentSources []string
+
+	// downloadsPath is the temporary directory the browser saves the
+	// downloads of the context in. Empty if downloads are not accepted.
+	downloadsPath string
+
+	// hars record the network activity of the context if recordHar is
+	// set, or if a HAR file is updated with routeFromHAR.
+	harsMu sync.Mutex
+	hars   []*harRecorder
+
+	routesMu sync.RWMutex
+	routes   []*routeHandler
+
+	authenticatorsMu  sync.Mutex
+	authenticators    []*virtualAuthenticator
+	authenticatorsSeq int
+	// authenticatorSessions are the sessions of the pages that the
+	// authenticators are added to, by their target IDs.
+	authenticatorSessions map[target.ID]session
+
+	// storageStateOrigins are the local storage items of the storageState
+	// option by the origins that they aren't restored in yet.
+	storageStateMu      sync.Mutex
+	storageStateOrigins map[string][][2]string
 }
 
 // NewBrowserContext creates a new browser context.
@@ -75,6 +109,10 @@ func NewBrowserContext(
 		vu:               k6ext.GetVU(ctx),
 		timeoutSettings:  NewTimeoutSettings(nil),
 	}
+	b.Tracing = NewTracing(ctx, &b, logger)
+	if opts != nil && opts.RecordHAR != nil {
+		b.hars = append(b.hars, newHARRecorder(opts.RecordHAR, nil, logger))
+	}
 
 	if opts != nil && len(opts.Permissions) > 0 {
 		b.GrantPermissions(opts.Permissions, nil)
@@ -83,44 +121,112 @@ func NewBrowserContext(
 	return &b
 }
 
+// AddCookies adds the cookies to the context. Each cookie needs a name, a
+// value, and either a url or a domain and a path.
 func (b *BrowserContext) AddCookies(cookies goja.Value) {
-	k6ext.Panic(b.ctx, "BrowserContext.addCookies(cookies) has not been implemented yet")
+	b.logger.Debugf("BrowserContext:AddCookies", "bctxid:%v", b.id)
+
+	params, err := parseCookieParams(b.vu.Runtime(), cookies)
+	if err != nil {
+		k6ext.Panic(b.ctx, "adding cookies: %w", err)
+	}
+	action := storage.SetCookies(params).WithBrowserContextID(b.id)
+	if err := action.Do(cdp.WithExecutor(b.ctx, b.browser.conn)); err != nil {
+		k6ext.Panic(b.ctx, "adding cookies: %w", err)
+	}
 }
 
-// AddInitScript adds a script that will be initialized on all new pages.
+// AddCredential adds the WebAuthn credential to the virtual authenticator.
+// The credential is added to the authenticators of the current and the
+// new pages of the context.
+func (b *BrowserContext) AddCredential(authenticatorID string, credential goja.Value) {
+	b.logger.Debugf("BrowserContext:AddCredential", "bctxid:%v authenticatorID:%q", b.id, authenticatorID)
+
+	a := b.getVirtualAuthenticator(authenticatorID)
+	if a == nil {
+		k6ext.Panic(b.ctx, "adding credential: virtual authenticator %q not found", authenticatorID)
+	}
+	c, err := parseCredential(credential)
+	if err != nil {
+		k6ext.Panic(b.ctx, "parsing credential: %w", err)
+	}
+
+	a.mu.Lock()
+	a.credentials = append(a.credentials, c)
+	a.mu.Unlock()
+
+	for _, p := range b.getPages() {
+		id, ok := a.pageID(p)
+		if !ok {
+			continue
+		}
+		if err := webauthn.AddCredential(id, c).Do(cdp.WithExecutor(b.ctx, p.session)); err != nil {
+			k6ext.Panic(b.ctx, "adding credential in target ID %s: %w", p.targetID, err)
+		}
+	}
+}
+
+// AddInitScript adds a script that runs in every frame of the browser
+// context's pages when a new document is created, before the page's
+// own scripts. The scripts run in their registration order.
 func (b *BrowserContext) AddInitScript(script goja.Value, arg goja.Value) {
 	b.logger.Debugf("BrowserContext:AddInitScript", "bctxid:%v", b.id)
 
-	rt := b.vu.Runtime()
+	source, err := initScriptSource(b.vu.Runtime(), script, arg)
+	if err != nil {
+		k6ext.Panic(b.ctx, "adding init script: %w", err)
+	}
 
-	source := ""
-	if script != nil && !goja.IsUndefined(script) && !goja.IsNull(script) {
-		switch script.ExportType() {
-		case reflect.TypeOf(string("")):
-			source = script.String()
-		case reflect.TypeOf(goja.Object{}):
-			opts := script.ToObject(rt)
-			for _, k := range opts.Keys() {
-				switch k {
-				case "content":
-					source = opts.Get(k).String()
-				}
-			}
-		default:
-			_, isCallable := goja.AssertFunction(script)
-			if !isCallable {
-				source = fmt.Sprintf("(%s);", script.ToString().String())
-			} else {
-				source = fmt.Sprintf("(%s)(...args);", script.ToString().String())
-			}
+	b.evaluateOnNewDocumentSources = append(b.evaluateOnNewDocumentSources, source)
+
+	for _, p := range b.getPages() {
+		if err := p.evaluateOnNewDocument(source); err != nil {
+			k6ext.Panic(b.ctx, "adding init script: %w", err)
 		}
 	}
+}
 
-	b.evaluateOnNewDocumentSources = append(b.evaluateOnNewDocumentSources, source)
+// AddVirtualAuthenticator adds a virtual WebAuthn authenticator to the
+// current and the new pages of the context, and returns its ID.
+func (b *BrowserContext) AddVirtualAuthenticator(opts goja.Value) string {
+	b.logger.Debugf("BrowserContext:AddVirtualAuthenticator", "bctxid:%v", b.id)
 
-	for _, p := range b.browser.getPages() {
-		p.evaluateOnNewDocument(source)
+	aopts := NewVirtualAuthenticatorOptions()
+	if err := aopts.Parse(b.ctx, opts); err != nil {
+		k6ext.Panic(b.ctx, "parsing virtual authenticator options: %w", err)
 	}
+
+	// The sessions are taken with the authenticator published, so the
+	// pages that are being created get it either from here or from their
+	// frame session, but not from both.
+	b.authenticatorsMu.Lock()
+	b.authenticatorsSeq++
+	a := newVirtualAuthenticator(fmt.Sprintf("authenticator-%d", b.authenticatorsSeq), aopts.toProtocol())
+	b.authenticators = append(b.authenticators, a)
+	sessions := make([]session, 0, len(b.authenticatorSessions))
+	for tid, s := range b.authenticatorSessions {
+		select {
+		case <-s.Done():
+			delete(b.authenticatorSessions, tid)
+		default:
+			sessions = append(sessions, s)
+		}
+	}
+	b.authenticatorsMu.Unlock()
+
+	for _, s := range sessions {
+		err := a.attach(b.ctx, s)
+		select {
+		case <-s.Done():
+			// ignore the pages that are closed meanwhile
+		default:
+			if err != nil {
+				k6ext.Panic(b.ctx, "%w in target ID %s", err, s.TargetID())
+			}
+		}
+	}
+
+	return a.id
 }
 
 // Browser returns the browser instance that this browser context belongs to.
@@ -128,22 +234,78 @@ func (b *BrowserContext) Browser() api.Browser {
 	return b.browser
 }
 
-// ClearCookies clears cookies.
-func (b *BrowserContext) ClearCookies() {
+// ClearCookies clears the cookies of the context that match all the
+// filters of the options, or all the cookies of the context if there are
+// no filters. The cookies of the other contexts aren't affected.
+func (b *BrowserContext) ClearCookies(opts goja.Value) {
 	b.logger.Debugf("BrowserContext:ClearCookies", "bctxid:%v", b.id)
 
-	action := storage.ClearCookies().WithBrowserContextID(b.id)
-	if err := action.Do(b.ctx); err != nil {
+	copts := NewBrowserContextClearCookiesOptions()
+	if err := copts.Parse(b.ctx, opts); err != nil {
+		k6ext.Panic(b.ctx, "parsing clear cookies options: %w", err)
+	}
+	if err := b.clearCookies(copts); err != nil {
 		k6ext.Panic(b.ctx, "clearing cookies: %w", err)
 	}
 }
 
+// clearCookies clears the cookies that match the options. The matching
+// cookies are deleted one by one in a page of the context, so that the
+// other cookies aren't touched. Without a page there's no session to
+// delete them with, and as no page can set cookies meanwhile, all the
+// cookies are cleared and the ones that don't match are set again.
+func (b *BrowserContext) clearCookies(opts *BrowserContextClearCookiesOptions) error {
+	ctx := cdp.WithExecutor(b.ctx, b.browser.conn)
+
+	if !opts.hasFilters() {
+		if err := storage.ClearCookies().WithBrowserContextID(b.id).Do(ctx); err != nil {
+			return fmt.Errorf("deleting cookies: %w", err)
+		}
+		return nil
+	}
+	cookies, err := storage.GetCookies().WithBrowserContextID(b.id).Do(ctx)
+	if err != nil {
+		return fmt.Errorf("getting cookies: %w", err)
+	}
+	if pages := b.getPages(); len(pages) > 0 {
+		ctx := cdp.WithExecutor(b.ctx, pages[0].session)
+		for _, c := range cookies {
+			if !opts.matches(c) {
+				continue
+			}
+			action := network.DeleteCookies(c.Name).WithDomain(c.Domain).WithPath(c.Path)
+			if err := action.Do(ctx); err != nil {
+				return fmt.Errorf("deleting cookie %q: %w", c.Name, err)
+			}
+		}
+		return nil
+	}
+
+	var keep []*network.CookieParam
+	for _, c := range cookies {
+		if !opts.matches(c) {
+			keep = append(keep, cookieParamFromProtocol(c))
+		}
+	}
+	if err := storage.ClearCookies().WithBrowserContextID(b.id).Do(ctx); err != nil {
+		return fmt.Errorf("deleting cookies: %w", err)
+	}
+	if len(keep) == 0 {
+		return nil
+	}
+	if err := storage.SetCookies(keep).WithBrowserContextID(b.id).Do(ctx); err != nil {
+		return fmt.Errorf("restoring the cookies that don't match: %w", err)
+	}
+
+	return nil
+}
+
 // ClearPermissions clears any permission overrides.
 func (b *BrowserContext) ClearPermissions() {
 	b.logger.Debugf("BrowserContext:ClearPermissions", "bctxid:%v", b.id)
 
 	action := cdpbrowser.ResetPermissions().WithBrowserContextID(b.id)
-	if err := action.Do(b.ctx); err != nil {
+	if err := action.Do(cdp.WithExecutor(b.ctx, b.browser.conn)); err != nil {
 		k6ext.Panic(b.ctx, "clearing permissions: %w", err)
 	}
 }
@@ -155,16 +317,131 @@ func (b *BrowserContext) Close() {
 	if b.id == "" {
 		k6ext.Panic(b.ctx, "default browser context can't be closed")
 	}
+	b.Tracing.discard()
+	if err := b.removeVirtualAuthenticators(); err != nil {
+		k6ext.Panic(b.ctx, "%w", err)
+	}
+	// the HAR is flushed before the context is disposed so that the
+	// pending response bodies can still be fetched.
+	if err := b.flushHAR(); err != nil {
+		k6ext.Panic(b.ctx, "%w", err)
+	}
 	if err := b.browser.disposeContext(b.id); err != nil {
 		k6ext.Panic(b.ctx, "disposing browser context: %w", err)
 	}
+	b.cleanupDownloads()
 }
 
-func (b *BrowserContext) Cookies() []goja.Object {
-	k6ext.Panic(b.ctx, "BrowserContext.cookies() has not been implemented yet")
+// flushHAR writes the recorded network activity to the HAR files, if it's
+// recorded.
+func (b *BrowserContext) flushHAR() error {
+	b.harsMu.Lock()
+	hars := b.hars
+	b.harsMu.Unlock()
+
+	for _, h := range hars {
+		if err := h.flush(); err != nil {
+			return fmt.Errorf("recording HAR: %w", err)
+		}
+	}
 	return nil
 }
 
+// recordHAR records the request in the HAR files, if it's recorded.
+func (b *BrowserContext) recordHAR(req *Request, end time.Time) {
+	b.harsMu.Lock()
+	hars := b.hars
+	b.harsMu.Unlock()
+
+	for _, h := range hars {
+		h.record(req, end)
+	}
+}
+
+// addHARRecorder starts recording the network activity of the requests
+// matching the filter to the HAR file at path.
+func (b *BrowserContext) addHARRecorder(path string, filter func(*Request) bool) {
+	h := newHARRecorder(&RecordHAROptions{Path: path, Content: HARContentEmbed}, filter, b.logger)
+
+	b.harsMu.Lock()
+	defer b.harsMu.Unlock()
+	b.hars = append(b.hars, h)
+}
+
+// Cookies returns the cookies of the context that are sent to at least one
+// of the URLs, or all the cookies of the context if there are no URLs.
+func (b *BrowserContext) Cookies(urls ...string) []*api.Cookie {
+	b.logger.Debugf("BrowserContext:Cookies", "bctxid:%v urls:%q", b.id, urls)
+
+	action := storage.GetCookies().WithBrowserContextID(b.id)
+	pcookies, err := action.Do(cdp.WithExecutor(b.ctx, b.browser.conn))
+	if err != nil {
+		k6ext.Panic(b.ctx, "getting cookies: %w", err)
+	}
+	cookies := make([]*api.Cookie, 0, len(pcookies))
+	for _, c := range pcookies {
+		cookies = append(cookies, cookieFromProtocol(c))
+	}
+	if cookies, err = filterCookies(cookies, urls...); err != nil {
+		k6ext.Panic(b.ctx, "getting cookies: %w", err)
+	}
+
+	return cookies
+}
+
+// Credentials returns the WebAuthn credentials of the virtual
+// authenticator. These are the credentials added with AddCredential, and
+// the ones that are registered by the pages, which are only returned while
+// a page that registered them is open.
+func (b *BrowserContext) Credentials(authenticatorID string) goja.Value {
+	b.logger.Debugf("BrowserContext:Credentials", "bctxid:%v authenticatorID:%q", b.id, authenticatorID)
+
+	a := b.getVirtualAuthenticator(authenticatorID)
+	if a == nil {
+		k6ext.Panic(b.ctx, "getting credentials: virtual authenticator %q not found", authenticatorID)
+	}
+
+	// the credentials of the same ID in the pages are the same credential,
+	// and the one that is used the most is the latest.
+	var (
+		credentials = []*webauthn.Credential{}
+		indexes     = make(map[string]int)
+	)
+	a.mu.Lock()
+	for _, c := range a.credentials {
+		indexes[c.CredentialID] = len(credentials)
+		credentials = append(credentials, c)
+	}
+	a.mu.Unlock()
+	for _, p := range b.getPages() {
+		id, ok := a.pageID(p)
+		if !ok {
+			continue
+		}
+		cs, err := webauthn.GetCredentials(id).Do(cdp.WithExecutor(b.ctx, p.session))
+		if err != nil {
+			k6ext.Panic(b.ctx, "getting credentials in target ID %s: %w", p.targetID, err)
+		}
+		for _, c := range cs {
+			i, ok := indexes[c.CredentialID]
+			if !ok {
+				indexes[c.CredentialID] = len(credentials)
+				credentials = append(credentials, c)
+				continue
+			}
+			if c.SignCount > credentials[i].SignCount {
+				credentials[i] = c
+			}
+		}
+	}
+	v, err := toJSONValue(credentials)
+	if err != nil {
+		k6ext.Panic(b.ctx, "converting credentials: %w", err)
+	}
+
+	return b.vu.Runtime().ToValue(v)
+}
+
 func (b *BrowserContext) ExposeBinding(name string, callback goja.Callable, opts goja.Value) {
 	k6ext.Panic(b.ctx, "BrowserContext.exposeBinding(name, callback, opts) has not been implemented yet")
 }
@@ -173,55 +450,102 @@ func (b *BrowserContext) ExposeFunction(name string, callback goja.Callable) {
 	k6ext.Panic(b.ctx, "BrowserContext.exposeFunction(name, callback) has not been implemented yet")
 }
 
+// permissionsToProtocol maps the permission names to their protocol
+// counterparts.
+var permissionsToProtocol = map[string]cdpbrowser.PermissionType{ //nolint:gochecknoglobals
+	"geolocation":          cdpbrowser.PermissionTypeGeolocation,
+	"midi":                 cdpbrowser.PermissionTypeMidi,
+	"midi-sysex":           cdpbrowser.PermissionTypeMidiSysex,
+	"notifications":        cdpbrowser.PermissionTypeNotifications,
+	"camera":               cdpbrowser.PermissionTypeVideoCapture,
+	"microphone":           cdpbrowser.PermissionTypeAudioCapture,
+	"background-sync":      cdpbrowser.PermissionTypeBackgroundSync,
+	"ambient-light-sensor": cdpbrowser.PermissionTypeSensors,
+	"accelerometer":        cdpbrowser.PermissionTypeSensors,
+	"gyroscope":            cdpbrowser.PermissionTypeSensors,
+	"magnetometer":         cdpbrowser.PermissionTypeSensors,
+	"accessibility-events": cdpbrowser.PermissionTypeAccessibilityEvents,
+	"clipboard-read":       cdpbrowser.PermissionTypeClipboardReadWrite,
+	"clipboard-write":      cdpbrowser.PermissionTypeClipboardSanitizedWrite,
+	"payment-handler":      cdpbrowser.PermissionTypePaymentHandler,
+}
+
 // GrantPermissions enables the specified permissions, all others will be disabled.
+// The origin option limits the permissions to the given origin.
 func (b *BrowserContext) GrantPermissions(permissions []string, opts goja.Value) {
 	b.logger.Debugf("BrowserContext:GrantPermissions", "bctxid:%v", b.id)
 
-	permsToProtocol := map[string]cdpbrowser.PermissionType{
-		"geolocation":          cdpbrowser.PermissionTypeGeolocation,
-		"midi":                 cdpbrowser.PermissionTypeMidi,
-		"midi-sysex":           cdpbrowser.PermissionTypeMidiSysex,
-		"notifications":        cdpbrowser.PermissionTypeNotifications,
-		"camera":               cdpbrowser.PermissionTypeVideoCapture,
-		"microphone":           cdpbrowser.PermissionTypeAudioCapture,
-		"background-sync":      cdpbrowser.PermissionTypeBackgroundSync,
-		"ambient-light-sensor": cdpbrowser.PermissionTypeSensors,
-		"accelerometer":        cdpbrowser.PermissionTypeSensors,
-		"gyroscope":            cdpbrowser.PermissionTypeSensors,
-		"magnetometer":         cdpbrowser.PermissionTypeSensors,
-		"accessibility-events": cdpbrowser.PermissionTypeAccessibilityEvents,
-		"clipboard-read":       cdpbrowser.PermissionTypeClipboardReadWrite,
-		"clipboard-write":      cdpbrowser.PermissionTypeClipboardSanitizedWrite,
-		"payment-handler":      cdpbrowser.PermissionTypePaymentHandler,
-	}
 	origin := ""
-
-	rt := b.vu.Runtime()
-	if opts != nil && !goja.IsUndefined(opts) && !goja.IsNull(opts) {
-		opts := opts.ToObject(rt)
-		for _, k := range opts.Keys() {
-			if k == "origin" {
-				origin = opts.Get(k).String()
-				break
-			}
+	if gojaValueExists(opts) {
+		obj := opts.ToObject(b.vu.Runtime())
+		if v := obj.Get("origin"); gojaValueExists(v) {
+			origin = v.String()
 		}
 	}
 
-	perms := make([]cdpbrowser.PermissionType, 0, len(permissions))
+	if err := b.grantPermissions(permissions, origin); err != nil {
+		k6ext.Panic(b.ctx, "granting permissions: %w", err)
+	}
+}
+
+func (b *BrowserContext) grantPermissions(permissions []string, origin string) error {
+	var (
+		perms = make([]cdpbrowser.PermissionType, 0, len(permissions))
+		seen  = make(map[cdpbrowser.PermissionType]bool, len(permissions))
+	)
 	for _, p := range permissions {
-		perms = append(perms, permsToProtocol[p])
+		perm, ok := permissionsToProtocol[p]
+		if !ok {
+			return fmt.Errorf("unknown permission %q, valid permissions are: %s",
+				p, strings.Join(validPermissions(), ", "))
+		}
+		if seen[perm] {
+			continue
+		}
+		seen[perm] = true
+		perms = append(perms, perm)
 	}
 
-	action := cdpbrowser.GrantPermissions(perms).WithOrigin(origin).WithBrowserContextID(b.id)
+	action := cdpbrowser.GrantPermissions(perms).WithBrowserContextID(b.id)
+	if origin != "" {
+		action = action.WithOrigin(origin)
+	}
 	if err := action.Do(cdp.WithExecutor(b.ctx, b.browser.conn)); err != nil {
-		k6ext.Panic(b.ctx, "internal error while granting browser permissions: %w", err)
+		return fmt.Errorf("internal error while granting browser permissions: %w", err)
+	}
+	return nil
+}
+
+// validPermissions returns the sorted list of the permission names.
+func validPermissions() []string {
+	names := make([]string, 0, len(permissionsToProtocol))
+	for n := range permissionsToProtocol {
+		names = append(names, n)
 	}
+	sort.Strings(names)
+	return names
 }
 
-// NewCDPSession returns a new CDP session attached to this target.
-func (b *BrowserContext) NewCDPSession() api.CDPSession {
-	k6ext.Panic(b.ctx, "BrowserContext.newCDPSession() has not been implemented yet")
-	return nil
+// NewCDPSession attaches a new raw CDP session to the page. The session is
+// intended for the protocol domains that the API doesn't cover, and the
+// commands sent with it can desync the state of the page.
+func (b *BrowserContext) NewCDPSession(page api.Page) api.CDPSession {
+	b.logger.Debugf("BrowserContext:NewCDPSession", "bctxid:%v", b.id)
+
+	p, ok := page.(*Page)
+	if !ok {
+		k6ext.Panic(b.ctx, "creating CDP session: unexpected page type %T", page)
+	}
+	if p.browserCtx != b {
+		k6ext.Panic(b.ctx, "creating CDP session: page belongs to another browser context")
+	}
+
+	s, err := NewCDPSession(p.ctx, p)
+	if err != nil {
+		k6ext.Panic(b.ctx, "creating CDP session: %w", err)
+	}
+
+	return s
 }
 
 // NewPage creates a new page inside this browser context.
@@ -250,15 +574,94 @@ func (b *BrowserContext) NewPage() api.Page {
 
 // Pages returns a list of pages inside this browser context.
 func (b *BrowserContext) Pages() []api.Page {
-	pages := make([]api.Page, 1)
+	pages := make([]api.Page, 0)
 	for _, p := range b.browser.getPages() {
+		if p.browserCtx != b {
+			continue
+		}
 		pages = append(pages, p)
 	}
 	return pages
 }
 
-func (b *BrowserContext) Route(url goja.Value, handler goja.Callable) {
-	k6ext.Panic(b.ctx, "BrowserContext.route(url, handler) has not been implemented yet")
+// RemoveVirtualAuthenticator removes the virtual WebAuthn authenticator,
+// and its credentials, from the pages of the context.
+func (b *BrowserContext) RemoveVirtualAuthenticator(authenticatorID string) {
+	b.logger.Debugf("BrowserContext:RemoveVirtualAuthenticator", "bctxid:%v authenticatorID:%q", b.id, authenticatorID)
+
+	var a *virtualAuthenticator
+	b.authenticatorsMu.Lock()
+	for i, va := range b.authenticators {
+		if va.id == authenticatorID {
+			a = va
+			b.authenticators = append(b.authenticators[:i], b.authenticators[i+1:]...)
+			break
+		}
+	}
+	b.authenticatorsMu.Unlock()
+
+	if a == nil {
+		k6ext.Panic(b.ctx, "removing virtual authenticator: virtual authenticator %q not found", authenticatorID)
+	}
+	if err := b.detachVirtualAuthenticator(a); err != nil {
+		k6ext.Panic(b.ctx, "%w", err)
+	}
+}
+
+// Route registers a handler for the requests matching the url in all the
+// pages of the browser context. Page routes take precedence over them.
+func (b *BrowserContext) Route(url goja.Value, handler goja.Value) {
+	b.logger.Debugf("BrowserContext:Route", "bctxid:%v url:%v", b.id, url)
+
+	rh, err := newRouteHandler(b.vu.Runtime(), url, handler)
+	if err != nil {
+		k6ext.Panic(b.ctx, "adding route: %w", err)
+	}
+
+	b.routesMu.Lock()
+	b.routes = append(b.routes, rh)
+	b.routesMu.Unlock()
+
+	if err := b.updateRequestInterception(); err != nil {
+		k6ext.Panic(b.ctx, "adding route: %w", err)
+	}
+}
+
+// RouteFromHAR serves the requests of the browser context's pages from the
+// HAR file at path. With the update option, the network activity is recorded
+// to the HAR file instead, when the browser context is closed.
+func (b *BrowserContext) RouteFromHAR(path string, opts goja.Value) {
+	b.logger.Debugf("BrowserContext:RouteFromHAR", "bctxid:%v path:%q", b.id, path)
+
+	popts := NewRouteFromHAROptions()
+	if err := popts.Parse(b.ctx, opts); err != nil {
+		k6ext.Panic(b.ctx, "parsing routeFromHAR options: %w", err)
+	}
+	if popts.Update {
+		matcher, err := newNativeURLMatcher(popts.URL)
+		if err != nil {
+			k6ext.Panic(b.ctx, "routing from HAR: %w", err)
+		}
+		b.addHARRecorder(path, harUpdateFilter(matcher, nil))
+		return
+	}
+
+	router, err := newHARRouter(path, popts)
+	if err != nil {
+		k6ext.Panic(b.ctx, "routing from HAR: %w", err)
+	}
+	rh, err := newHARRouteHandler(popts.URL, router)
+	if err != nil {
+		k6ext.Panic(b.ctx, "routing from HAR: %w", err)
+	}
+
+	b.routesMu.Lock()
+	b.routes = append(b.routes, rh)
+	b.routesMu.Unlock()
+
+	if err := b.updateRequestInterception(); err != nil {
+		k6ext.Panic(b.ctx, "routing from HAR: %w", err)
+	}
 }
 
 // SetDefaultNavigationTimeout sets the default navigation timeout in milliseconds.
@@ -275,21 +678,36 @@ func (b *BrowserContext) SetDefaultTimeout(timeout int64) {
 	b.timeoutSettings.setDefaultTimeout(timeout)
 }
 
+// SetExtraHTTPHeaders sets the HTTP headers sent with the requests of all
+// the pages in the browser context, including the pages opened later.
+// The page headers with the same name take precedence.
 func (b *BrowserContext) SetExtraHTTPHeaders(headers map[string]string) {
-	k6ext.Panic(b.ctx, "BrowserContext.setExtraHTTPHeaders(headers) has not been implemented yet")
+	b.logger.Debugf("BrowserContext:SetExtraHTTPHeaders", "bctxid:%v", b.id)
+
+	b.opts.ExtraHTTPHeaders = make(map[string]string, len(headers))
+	for k, v := range headers {
+		b.opts.ExtraHTTPHeaders[k] = v
+	}
+	for _, p := range b.getPages() {
+		p.updateExtraHTTPHeaders()
+	}
 }
 
-// SetGeolocation overrides the geo location of the user.
+// SetGeolocation overrides the geo location of the user in all the pages
+// of the browser context. A null geolocation clears the override.
 func (b *BrowserContext) SetGeolocation(geolocation goja.Value) {
 	b.logger.Debugf("BrowserContext:SetGeolocation", "bctxid:%v", b.id)
 
-	g := NewGeolocation()
-	if err := g.Parse(b.ctx, geolocation); err != nil {
-		k6ext.Panic(b.ctx, "parsing geo location: %v", err)
+	var g *Geolocation
+	if gojaValueExists(geolocation) {
+		g = NewGeolocation()
+		if err := g.Parse(b.ctx, geolocation); err != nil {
+			k6ext.Panic(b.ctx, "parsing geo location: %w", err)
+		}
 	}
 
 	b.opts.Geolocation = g
-	for _, p := range b.browser.getPages() {
+	for _, p := range b.getPages() {
 		if err := p.updateGeolocation(); err != nil {
 			k6ext.Panic(b.ctx, "updating geo location in target ID %s: %w", p.targetID, err)
 		}
@@ -318,22 +736,201 @@ func (b *BrowserContext) SetHTTPCredentials(httpCredentials goja.Value) {
 	}
 }
 
-// SetOffline toggles the browser's connectivity on/off.
+// SetNetworkConditions emulates the network conditions in all the pages of the
+// browser context. The conditions are either a preset name, such as "Slow 3G"
+// or "Fast 3G", or an object. Passing null disables the emulation. The offline
+// mode is only changed when the conditions set it.
+func (b *BrowserContext) SetNetworkConditions(conditions goja.Value) {
+	b.logger.Debugf("BrowserContext:SetNetworkConditions", "bctxid:%v", b.id)
+
+	c := NewNetworkConditions()
+	if err := c.Parse(b.ctx, conditions); err != nil {
+		k6ext.Panic(b.ctx, "parsing network conditions: %w", err)
+	}
+	b.opts.NetworkConditions = c
+	if c.hasOffline {
+		b.opts.Offline = c.Offline
+	}
+	for _, p := range b.getPages() {
+		p.updateOffline()
+		p.updateNetworkConditions()
+	}
+}
+
+// SetOffline toggles the connectivity of all the pages in the browser context
+// on/off. It can be called before any page exists, in which case the pages
+// created afterwards start in the given mode.
 func (b *BrowserContext) SetOffline(offline bool) {
 	b.logger.Debugf("BrowserContext:SetOffline", "bctxid:%v offline:%t", b.id, offline)
 
 	b.opts.Offline = offline
-	for _, p := range b.browser.getPages() {
+	for _, p := range b.getPages() {
 		p.updateOffline()
 	}
 }
 
-func (b *BrowserContext) StorageState(opts goja.Value) {
-	k6ext.Panic(b.ctx, "BrowserContext.storageState(opts) has not been implemented yet")
+// StorageState returns the cookies of the context, and the local storage of
+// the origins that are currently open in a page of the context. The local
+// storage of the other origins isn't captured, as it can only be read from
+// their documents. The state is also written to the path option as JSON,
+// if it's set, and can be restored with the storageState option of a new
+// context.
+func (b *BrowserContext) StorageState(opts goja.Value) goja.Value {
+	b.logger.Debugf("BrowserContext:StorageState", "bctxid:%v", b.id)
+
+	sopts := NewBrowserContextStorageStateOptions()
+	if err := sopts.Parse(b.ctx, opts); err != nil {
+		k6ext.Panic(b.ctx, "parsing storage state options: %w", err)
+	}
+	state, err := b.storageState()
+	if err != nil {
+		k6ext.Panic(b.ctx, "getting storage state: %w", err)
+	}
+	if sopts.Path != "" {
+		buf, err := json.MarshalIndent(state, "", "  ")
+		if err != nil {
+			k6ext.Panic(b.ctx, "marshaling storage state: %w", err)
+		}
+		if err := ioutil.WriteFile(sopts.Path, buf, 0o600); err != nil {
+			k6ext.Panic(b.ctx, "writing storage state: %w", err)
+		}
+	}
+	v, err := toJSONValue(state)
+	if err != nil {
+		k6ext.Panic(b.ctx, "converting storage state: %w", err)
+	}
+
+	return b.vu.Runtime().ToValue(v)
+}
+
+func (b *BrowserContext) storageState() (*StorageState, error) {
+	cookies, err := storage.GetCookies().WithBrowserContextID(b.id).Do(cdp.WithExecutor(b.ctx, b.browser.conn))
+	if err != nil {
+		return nil, fmt.Errorf("getting cookies: %w", err)
+	}
+	state := StorageState{
+		Cookies: make([]*api.Cookie, 0, len(cookies)),
+		Origins: []*StorageStateOrigin{},
+	}
+	for _, c := range cookies {
+		state.Cookies = append(state.Cookies, cookieFromProtocol(c))
+	}
+
+	// the local storage can only be read in the frames of the origins.
+	seen := make(map[string]bool)
+	for _, p := range b.getPages() {
+		for _, f := range p.frameManager.Frames() {
+			frame, ok := f.(*Frame)
+			if !ok {
+				continue
+			}
+			o, err := localStorageOf(frame)
+			if err != nil {
+				return nil, err
+			}
+			if o == nil || seen[o.Origin] {
+				continue
+			}
+			seen[o.Origin] = true
+			if len(o.LocalStorage) > 0 {
+				state.Origins = append(state.Origins, o)
+			}
+		}
+	}
+
+	return &state, nil
 }
 
-func (b *BrowserContext) Unroute(url goja.Value, handler goja.Callable) {
-	k6ext.Panic(b.ctx, "BrowserContext.unroute(url, handler) has not been implemented yet")
+// restoreStorageState restores the storageState option in the context. The
+// local storage of an origin is restored in its first document, see
+// onStorageStateRestored.
+func (b *BrowserContext) restoreStorageState() error {
+	if b.opts == nil || b.opts.StorageState == nil {
+		return nil
+	}
+	state := b.opts.StorageState
+	if len(state.Cookies) > 0 {
+		action := storage.SetCookies(state.cookieParams()).WithBrowserContextID(b.id)
+		if err := action.Do(cdp.WithExecutor(b.ctx, b.browser.conn)); err != nil {
+			return fmt.Errorf("setting cookies: %w", err)
+		}
+	}
+	b.storageStateMu.Lock()
+	b.storageStateOrigins = state.localStorageItems()
+	b.storageStateMu.Unlock()
+
+	return nil
+}
+
+// addStorageStateScript adds the init script that restores the local
+// storage of the storageState option to the new frame session, if there
+// are origins left to restore it in.
+func (b *BrowserContext) addStorageStateScript(fs *FrameSession) error {
+	b.storageStateMu.Lock()
+	defer b.storageStateMu.Unlock()
+
+	source, err := localStorageInitScript(b.storageStateOrigins)
+	if err != nil {
+		return err
+	}
+	return fs.setStorageStateScript(source)
+}
+
+// onStorageStateRestored is called when a document of the frame session
+// restores the local storage of the storageState option in the origin. The
+// origin is removed from the init scripts of the pages, so that the next
+// documents of the origin don't restore it again, such as after a page
+// clears its local storage.
+func (b *BrowserContext) onStorageStateRestored(fs *FrameSession, origin string) {
+	b.logger.Debugf("BrowserContext:onStorageStateRestored", "bctxid:%v origin:%q", b.id, origin)
+
+	b.storageStateMu.Lock()
+	defer b.storageStateMu.Unlock()
+
+	_, ok := b.storageStateOrigins[origin]
+	delete(b.storageStateOrigins, origin)
+	source, err := localStorageInitScript(b.storageStateOrigins)
+	if err != nil {
+		b.logger.Errorf("BrowserContext:onStorageStateRestored", "bctxid:%v origin:%q err:%v", b.id, origin, err)
+		return
+	}
+
+	// the frame session can belong to a page that isn't listed yet.
+	sessions := []*FrameSession{fs}
+	if ok {
+		for _, p := range b.getPages() {
+			for _, pfs := range p.frameSessions {
+				if pfs != fs {
+					sessions = append(sessions, pfs)
+				}
+			}
+		}
+	}
+	for _, s := range sessions {
+		if err := s.setStorageStateScript(source); err != nil {
+			b.logger.Debugf("BrowserContext:onStorageStateRestored", "bctxid:%v origin:%q err:%v", b.id, origin, err)
+		}
+	}
+}
+
+// Unroute removes the route handlers registered with the url. If handler
+// is given, only that handler is removed.
+func (b *BrowserContext) Unroute(url goja.Value, handler goja.Value) {
+	b.logger.Debugf("BrowserContext:Unroute", "bctxid:%v url:%v", b.id, url)
+
+	b.routesMu.Lock()
+	routes := make([]*routeHandler, 0, len(b.routes))
+	for _, rh := range b.routes {
+		if !rh.equals(url, handler) {
+			routes = append(routes, rh)
+		}
+	}
+	b.routes = routes
+	b.routesMu.Unlock()
+
+	if err := b.updateRequestInterception(); err != nil {
+		k6ext.Panic(b.ctx, "removing route: %w", err)
+	}
 }
 
 func (b *BrowserContext) WaitForEvent(event string, optsOrPredicate goja.Value) interface{} {
@@ -426,6 +1023,137 @@ func (b *BrowserContext) WaitForEvent(event string, optsOrPredicate goja.Value)
 	return nil
 }
 
+// initDownloads sets the download behavior of the context. Accepted
+// downloads are saved to a temporary directory that is removed when the
+// context is closed.
+func (b *BrowserContext) initDownloads() error {
+	behavior := cdpbrowser.SetDownloadBehaviorBehaviorDeny
+	if b.opts.AcceptDownloads {
+		dir, err := ioutil.TempDir("", "xk6-browser-downloads-*")
+		if err != nil {
+			return fmt.Errorf("creating downloads directory: %w", err)
+		}
+		b.downloadsPath = dir
+		behavior = cdpbrowser.SetDownloadBehaviorBehaviorAllowAndName
+	}
+
+	action := cdpbrowser.SetDownloadBehavior(behavior).
+		WithDownloadPath(b.downloadsPath).
+		WithEventsEnabled(true)
+	if b.id != "" {
+		action = action.WithBrowserContextID(b.id)
+	}
+	if err := action.Do(cdp.WithExecutor(b.ctx, b.browser.conn)); err != nil {
+		return fmt.Errorf("setting download behavior: %w", err)
+	}
+
+	return nil
+}
+
+// cleanupDownloads removes the downloads directory of the context.
+func (b *BrowserContext) cleanupDownloads() {
+	if b.downloadsPath == "" {
+		return
+	}
+	if err := os.RemoveAll(b.downloadsPath); err != nil {
+		b.logger.Errorf("BrowserContext:cleanupDownloads", "bctxid:%v removing %q: %v", b.id, b.downloadsPath, err)
+	}
+}
+
+func (b *BrowserContext) getVirtualAuthenticator(id string) *virtualAuthenticator {
+	b.authenticatorsMu.Lock()
+	defer b.authenticatorsMu.Unlock()
+
+	for _, a := range b.authenticators {
+		if a.id == id {
+			return a
+		}
+	}
+	return nil
+}
+
+// virtualAuthenticatorsFor registers the session of a new page for the
+// authenticators that are added later, and returns the current ones.
+func (b *BrowserContext) virtualAuthenticatorsFor(s session) []*virtualAuthenticator {
+	b.authenticatorsMu.Lock()
+	defer b.authenticatorsMu.Unlock()
+
+	if b.authenticatorSessions == nil {
+		b.authenticatorSessions = make(map[target.ID]session)
+	}
+	b.authenticatorSessions[s.TargetID()] = s
+
+	return append([]*virtualAuthenticator{}, b.authenticators...)
+}
+
+// detachVirtualAuthenticator removes the authenticator from the pages.
+func (b *BrowserContext) detachVirtualAuthenticator(a *virtualAuthenticator) error {
+	for _, p := range b.getPages() {
+		id, ok := a.pageID(p)
+		if !ok {
+			continue
+		}
+		if err := webauthn.RemoveVirtualAuthenticator(id).Do(cdp.WithExecutor(b.ctx, p.session)); err != nil {
+			return fmt.Errorf("removing virtual authenticator in target ID %s: %w", p.targetID, err)
+		}
+	}
+	return nil
+}
+
+// removeVirtualAuthenticators removes all the virtual authenticators of
+// the context.
+func (b *BrowserContext) removeVirtualAuthenticators() error {
+	b.authenticatorsMu.Lock()
+	authenticators := b.authenticators
+	b.authenticators = nil
+	b.authenticatorsMu.Unlock()
+
+	for _, a := range authenticators {
+		if err := b.detachVirtualAuthenticator(a); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getPages returns the pages that belong to this browser context.
+func (b *BrowserContext) getPages() []*Page {
+	var pages []*Page
+	for _, p := range b.browser.getPages() {
+		if p.browserCtx == b {
+			pages = append(pages, p)
+		}
+	}
+	return pages
+}
+
+func (b *BrowserContext) hasRoutes() bool {
+	b.routesMu.RLock()
+	defer b.routesMu.RUnlock()
+
+	return len(b.routes) > 0
+}
+
+// routeHandlers returns the browser context's route handlers in the order
+// they handle the routes of its pages in.
+func (b *BrowserContext) routeHandlers() []*routeHandler {
+	b.routesMu.RLock()
+	defer b.routesMu.RUnlock()
+
+	return reverseRouteHandlers(b.routes)
+}
+
+// updateRequestInterception toggles request interception in all pages of the
+// browser context depending on whether there are any routes registered.
+func (b *BrowserContext) updateRequestInterception() error {
+	for _, p := range b.getPages() {
+		if err := p.updateRequestInterception(); err != nil {
+			return fmt.Errorf("updating request interception in target ID %s: %w", p.targetID, err)
+		}
+	}
+	return nil
+}
+
 func (b *BrowserContext) getSession(id target.SessionID) *Session {
 	return b.browser.conn.getSession(id)
 }
@@ -22,35 +22,49 @@ package common
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"regexp"
 
 	"github.com/grafana/xk6-browser/k6ext"
 
+	"github.com/chromedp/cdproto/network"
 	"github.com/dop251/goja"
+	"github.com/sirupsen/logrus"
 )
 
 // BrowserContextOptions stores browser context options.
 type BrowserContextOptions struct {
-	AcceptDownloads   bool              `js:"acceptDownloads"`
-	BypassCSP         bool              `js:"bypassCSP"`
-	ColorScheme       ColorScheme       `js:"colorScheme"`
-	DeviceScaleFactor float64           `js:"deviceScaleFactor"`
-	ExtraHTTPHeaders  map[string]string `js:"extraHTTPHeaders"`
-	Geolocation       *Geolocation      `js:"geolocation"`
-	HasTouch          bool              `js:"hasTouch"`
-	HttpCredentials   *Credentials      `js:"httpCredentials"`
-	IgnoreHTTPSErrors bool              `js:"ignoreHTTPSErrors"`
-	IsMobile          bool              `js:"isMobile"`
-	JavaScriptEnabled bool              `js:"javaScriptEnabled"`
-	Locale            string            `js:"locale"`
-	Offline           bool              `js:"offline"`
-	Permissions       []string          `js:"permissions"`
-	ReducedMotion     ReducedMotion     `js:"reducedMotion"`
-	Screen            *Screen           `js:"screen"`
-	TimezoneID        string            `js:"timezoneID"`
-	UserAgent         string            `js:"userAgent"`
-	VideosPath        string            `js:"videosPath"`
-	Viewport          *Viewport         `js:"viewport"`
+	AcceptDownloads         bool               `js:"acceptDownloads"`
+	BypassCSP               bool               `js:"bypassCSP"`
+	ColorScheme             ColorScheme        `js:"colorScheme"`
+	DeviceScaleFactor       float64            `js:"deviceScaleFactor"`
+	ExtraHTTPHeaders        map[string]string  `js:"extraHTTPHeaders"`
+	Geolocation             *Geolocation       `js:"geolocation"`
+	HasTouch                bool               `js:"hasTouch"`
+	HttpCredentials         *Credentials       `js:"httpCredentials"`
+	IgnoreHTTPSErrors       bool               `js:"ignoreHTTPSErrors"`
+	IsMobile                bool               `js:"isMobile"`
+	JavaScriptEnabled       bool               `js:"javaScriptEnabled"`
+	Locale                  string             `js:"locale"`
+	LogBrowserConsole       bool               `js:"logBrowserConsole"`
+	LogBrowserConsoleIgnore []string           `js:"logBrowserConsoleIgnore"`
+	NavigationTiming        bool               `js:"navigationTiming"`
+	NetworkConditions       *NetworkConditions `js:"networkConditions"`
+	NetworkIdleIgnore       []string           `js:"networkIdleIgnore"`
+	Offline                 bool               `js:"offline"`
+	Permissions             []string           `js:"permissions"`
+	RecordHAR               *RecordHAROptions  `js:"recordHar"`
+	ReducedMotion           ReducedMotion      `js:"reducedMotion"`
+	Screen                  *Screen            `js:"screen"`
+	StorageState            *StorageState      `js:"storageState"`
+	TestIDAttribute         string             `js:"testIdAttribute"`
+	TimezoneID              string             `js:"timezoneID"`
+	UserAgent               string             `js:"userAgent"`
+	VideosPath              string             `js:"videosPath"`
+	Viewport                *Viewport          `js:"viewport"`
+
+	networkIdleIgnore []*regexp.Regexp
 }
 
 // NewBrowserContextOptions creates a default set of browser context options.
@@ -61,9 +75,11 @@ func NewBrowserContextOptions() *BrowserContextOptions {
 		ExtraHTTPHeaders:  make(map[string]string),
 		JavaScriptEnabled: true,
 		Locale:            DefaultLocale,
+		LogBrowserConsole: true,
 		Permissions:       []string{},
 		ReducedMotion:     ReducedMotionNoPreference,
 		Screen:            &Screen{Width: DefaultScreenWidth, Height: DefaultScreenHeight},
+		TestIDAttribute:   DefaultTestIDAttribute,
 		Viewport:          &Viewport{Width: DefaultScreenWidth, Height: DefaultScreenHeight},
 	}
 }
@@ -72,6 +88,15 @@ func (b *BrowserContextOptions) Parse(ctx context.Context, opts goja.Value) erro
 	rt := k6ext.Runtime(ctx)
 	if opts != nil && !goja.IsUndefined(opts) && !goja.IsNull(opts) {
 		opts := opts.ToObject(rt)
+		// the device is applied first so that the other options
+		// override its emulation settings.
+		if v := opts.Get("device"); gojaValueExists(v) {
+			device, err := parseDevice(rt, v)
+			if err != nil {
+				return err
+			}
+			b.applyDevice(device)
+		}
 		for _, k := range opts.Keys() {
 			switch k {
 			case "acceptDownloads":
@@ -116,6 +141,32 @@ func (b *BrowserContextOptions) Parse(ctx context.Context, opts goja.Value) erro
 				b.JavaScriptEnabled = opts.Get(k).ToBoolean()
 			case "locale":
 				b.Locale = opts.Get(k).String()
+			case "logBrowserConsole":
+				b.LogBrowserConsole = opts.Get(k).ToBoolean()
+			case "logBrowserConsoleIgnore":
+				var levels []string
+				if err := rt.ExportTo(opts.Get(k), &levels); err != nil {
+					return fmt.Errorf("logBrowserConsoleIgnore must be an array of log levels: %w", err)
+				}
+				for _, l := range levels {
+					if _, err := logrus.ParseLevel(l); err != nil {
+						return fmt.Errorf("parsing logBrowserConsoleIgnore: %w", err)
+					}
+				}
+				b.LogBrowserConsoleIgnore = levels
+			case "navigationTiming":
+				b.NavigationTiming = opts.Get(k).ToBoolean()
+			case "networkConditions":
+				conditions := NewNetworkConditions()
+				if err := conditions.Parse(ctx, opts.Get(k)); err != nil {
+					return err
+				}
+				b.NetworkConditions = conditions
+				b.Offline = b.Offline || conditions.Offline
+			case "networkIdleIgnore":
+				if err := b.parseNetworkIdleIgnore(rt, opts.Get(k)); err != nil {
+					return err
+				}
 			case "offline":
 				b.Offline = opts.Get(k).ToBoolean()
 			case "permissions":
@@ -124,6 +175,12 @@ func (b *BrowserContextOptions) Parse(ctx context.Context, opts goja.Value) erro
 						b.Permissions = append(b.Permissions, fmt.Sprintf("%v", p))
 					}
 				}
+			case "recordHar":
+				har, err := parseRecordHAROptions(rt, opts.Get(k))
+				if err != nil {
+					return err
+				}
+				b.RecordHAR = har
 			case "reducedMotion":
 				switch ReducedMotion(opts.Get(k).String()) {
 				case "reduce":
@@ -137,11 +194,26 @@ func (b *BrowserContextOptions) Parse(ctx context.Context, opts goja.Value) erro
 					return err
 				}
 				b.Screen = screen
+			case "storageState":
+				state, err := parseStorageState(opts.Get(k))
+				if err != nil {
+					return err
+				}
+				b.StorageState = state
+			case "testIdAttribute":
+				if attr := opts.Get(k).String(); attr != "" {
+					b.TestIDAttribute = attr
+				}
 			case "timezoneID":
 				b.TimezoneID = opts.Get(k).String()
 			case "userAgent":
 				b.UserAgent = opts.Get(k).String()
 			case "viewport":
+				// a null viewport disables the viewport emulation.
+				if goja.IsNull(opts.Get(k)) {
+					b.Viewport = nil
+					continue
+				}
 				viewport := &Viewport{}
 				if err := viewport.Parse(ctx, opts.Get(k).ToObject(rt)); err != nil {
 					return err
@@ -152,3 +224,180 @@ func (b *BrowserContextOptions) Parse(ctx context.Context, opts goja.Value) erro
 	}
 	return nil
 }
+
+// parseNetworkIdleIgnore parses the URL glob patterns of the requests that
+// don't keep the frames from becoming network idle.
+func (b *BrowserContextOptions) parseNetworkIdleIgnore(rt *goja.Runtime, v goja.Value) error {
+	var patterns []string
+	if err := rt.ExportTo(v, &patterns); err != nil {
+		return fmt.Errorf("networkIdleIgnore must be an array of URL patterns: %w", err)
+	}
+	for _, p := range patterns {
+		re, err := globToRegexp(p)
+		if err != nil {
+			return fmt.Errorf("parsing networkIdleIgnore pattern %q: %w", p, err)
+		}
+		b.networkIdleIgnore = append(b.networkIdleIgnore, re)
+	}
+	b.NetworkIdleIgnore = patterns
+	return nil
+}
+
+// logsBrowserConsole reports whether the browser console messages of the
+// log level are logged.
+func (b *BrowserContextOptions) logsBrowserConsole(level logrus.Level) bool {
+	if !b.LogBrowserConsole {
+		return false
+	}
+	for _, l := range b.LogBrowserConsoleIgnore {
+		if pl, err := logrus.ParseLevel(l); err == nil && pl == level {
+			return false
+		}
+	}
+	return true
+}
+
+// ignoresForNetworkIdle reports whether the URL matches any of the
+// networkIdleIgnore patterns.
+func (b *BrowserContextOptions) ignoresForNetworkIdle(url string) bool {
+	for _, re := range b.networkIdleIgnore {
+		if re.MatchString(url) {
+			return true
+		}
+	}
+	return false
+}
+
+// applyDevice sets the emulation options from the device.
+func (b *BrowserContextOptions) applyDevice(d *Device) {
+	b.UserAgent = d.UserAgent
+	// keeps the default viewport for the devices without a viewport.
+	if d.Viewport.Width > 0 && d.Viewport.Height > 0 {
+		b.Viewport = &Viewport{Width: d.Viewport.Width, Height: d.Viewport.Height}
+	}
+	b.DeviceScaleFactor = d.DeviceScaleFactor
+	b.IsMobile = d.IsMobile
+	b.HasTouch = d.HasTouch
+}
+
+// parseDevice returns the device from a device name or a device object.
+func parseDevice(rt *goja.Runtime, v goja.Value) (*Device, error) {
+	switch dv := v.Export().(type) {
+	case string:
+		d, ok := GetDevices()[dv]
+		if !ok {
+			return nil, fmt.Errorf("unknown device %q", dv)
+		}
+		return &d, nil
+	case Device:
+		return &dv, nil
+	case *Device:
+		return dv, nil
+	}
+
+	var d Device
+	if err := rt.ExportTo(v, &d); err != nil {
+		return nil, fmt.Errorf("parsing device: %w", err)
+	}
+	return &d, nil
+}
+
+// parseRecordHAROptions parses the HAR recording options.
+func parseRecordHAROptions(rt *goja.Runtime, v goja.Value) (*RecordHAROptions, error) {
+	if !gojaValueExists(v) {
+		return nil, nil
+	}
+	opts := &RecordHAROptions{Content: HARContentOmit}
+	o := v.ToObject(rt)
+	for _, k := range o.Keys() {
+		switch k {
+		case "path":
+			opts.Path = o.Get(k).String()
+		case "content":
+			switch c := HARContent(o.Get(k).String()); c {
+			case HARContentOmit, HARContentEmbed:
+				opts.Content = c
+			default:
+				return nil, fmt.Errorf("invalid HAR content %q, must be %q or %q", c, HARContentOmit, HARContentEmbed)
+			}
+		}
+	}
+	if opts.Path == "" {
+		return nil, errors.New("recording HAR requires a path")
+	}
+	return opts, nil
+}
+
+// BrowserContextClearCookiesOptions are the filters of the cookies to clear.
+// A cookie is cleared if it matches all the filters that are set.
+type BrowserContextClearCookiesOptions struct {
+	Name   string `js:"name"`
+	Domain string `js:"domain"`
+	Path   string `js:"path"`
+}
+
+// NewBrowserContextClearCookiesOptions returns the options that clear all
+// the cookies.
+func NewBrowserContextClearCookiesOptions() *BrowserContextClearCookiesOptions {
+	return &BrowserContextClearCookiesOptions{}
+}
+
+// Parse parses the clear cookies options from a JS object.
+func (o *BrowserContextClearCookiesOptions) Parse(ctx context.Context, opts goja.Value) error {
+	if !gojaValueExists(opts) {
+		return nil
+	}
+	rt := k6ext.Runtime(ctx)
+	obj := opts.ToObject(rt)
+	for _, k := range obj.Keys() {
+		switch k {
+		case "name":
+			o.Name = obj.Get(k).String()
+		case "domain":
+			o.Domain = obj.Get(k).String()
+		case "path":
+			o.Path = obj.Get(k).String()
+		}
+	}
+	return nil
+}
+
+// hasFilters returns whether only some of the cookies are cleared.
+func (o *BrowserContextClearCookiesOptions) hasFilters() bool {
+	return o.Name != "" || o.Domain != "" || o.Path != ""
+}
+
+// matches returns whether the cookie matches all the filters.
+func (o *BrowserContextClearCookiesOptions) matches(c *network.Cookie) bool {
+	return (o.Name == "" || o.Name == c.Name) &&
+		(o.Domain == "" || o.Domain == c.Domain) &&
+		(o.Path == "" || o.Path == c.Path)
+}
+
+// BrowserContextStorageStateOptions are the options of getting the storage
+// state of a browser context.
+type BrowserContextStorageStateOptions struct {
+	// Path is the file path to write the storage state to as JSON.
+	Path string `js:"path"`
+}
+
+// NewBrowserContextStorageStateOptions returns the default storage state
+// options.
+func NewBrowserContextStorageStateOptions() *BrowserContextStorageStateOptions {
+	return &BrowserContextStorageStateOptions{}
+}
+
+// Parse parses the storage state options from a JS object.
+func (o *BrowserContextStorageStateOptions) Parse(ctx context.Context, opts goja.Value) error {
+	if !gojaValueExists(opts) {
+		return nil
+	}
+	rt := k6ext.Runtime(ctx)
+	obj := opts.ToObject(rt)
+	for _, k := range obj.Keys() {
+		if k == "path" {
+			o.Path = obj.Get(k).String()
+		}
+	}
+	return nil
+}
@@ -5,6 +5,8 @@ import (
 
 	"github.com/grafana/xk6-browser/k6ext/k6test"
 
+	"github.com/chromedp/cdproto/network"
+	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -21,3 +23,149 @@ func TestBrowserContextOptionsPermissions(t *testing.T) {
 	assert.Len(t, opts.Permissions, 2)
 	assert.Equal(t, opts.Permissions, []string{"camera", "microphone"})
 }
+
+func TestBrowserContextOptionsTestIDAttribute(t *testing.T) {
+	vu := k6test.NewVU(t)
+
+	opts := NewBrowserContextOptions()
+	assert.Equal(t, DefaultTestIDAttribute, opts.TestIDAttribute)
+
+	err := opts.Parse(vu.Context(), vu.ToGojaValue((struct {
+		TestIDAttribute string `js:"testIdAttribute"`
+	}{
+		TestIDAttribute: "data-qa",
+	})))
+	assert.NoError(t, err)
+	assert.Equal(t, "data-qa", opts.TestIDAttribute)
+}
+
+func TestBrowserContextOptionsNullViewport(t *testing.T) {
+	vu := k6test.NewVU(t)
+
+	opts := NewBrowserContextOptions()
+	err := opts.Parse(vu.Context(), vu.Runtime().ToValue(map[string]interface{}{
+		"viewport": nil,
+	}))
+	assert.NoError(t, err)
+	assert.Nil(t, opts.Viewport)
+}
+
+func TestBrowserContextOptionsDevice(t *testing.T) {
+	vu := k6test.NewVU(t)
+
+	t.Run("name", func(t *testing.T) {
+		opts := NewBrowserContextOptions()
+		err := opts.Parse(vu.Context(), vu.Runtime().ToValue(map[string]interface{}{
+			"device":    "iPhone 13",
+			"userAgent": "custom",
+		}))
+		assert.NoError(t, err)
+		assert.Equal(t, &Viewport{Width: 390, Height: 664}, opts.Viewport)
+		assert.Equal(t, 3.0, opts.DeviceScaleFactor)
+		assert.True(t, opts.IsMobile)
+		assert.True(t, opts.HasTouch)
+		assert.Equal(t, "custom", opts.UserAgent, "explicit options should override the device")
+	})
+
+	t.Run("object", func(t *testing.T) {
+		opts := NewBrowserContextOptions()
+		v, err := vu.Runtime().RunString(`({
+			device: {
+				userAgent: 'test',
+				viewport: { width: 300, height: 500 },
+				deviceScaleFactor: 2,
+				hasTouch: true,
+			},
+			viewport: { width: 320, height: 480 },
+		})`)
+		assert.NoError(t, err)
+		assert.NoError(t, opts.Parse(vu.Context(), v))
+		assert.Equal(t, "test", opts.UserAgent)
+		assert.Equal(t, &Viewport{Width: 320, Height: 480}, opts.Viewport)
+		assert.Equal(t, 2.0, opts.DeviceScaleFactor)
+		assert.False(t, opts.IsMobile)
+		assert.True(t, opts.HasTouch)
+	})
+
+	t.Run("no_viewport", func(t *testing.T) {
+		opts := NewBrowserContextOptions()
+		v, err := vu.Runtime().RunString(`({
+			device: { userAgent: 'test', isMobile: true },
+		})`)
+		assert.NoError(t, err)
+		assert.NoError(t, opts.Parse(vu.Context(), v))
+		assert.Equal(t, &Viewport{Width: DefaultScreenWidth, Height: DefaultScreenHeight}, opts.Viewport)
+		assert.True(t, opts.IsMobile)
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		opts := NewBrowserContextOptions()
+		err := opts.Parse(vu.Context(), vu.Runtime().ToValue(map[string]interface{}{
+			"device": "Nokia 3310",
+		}))
+		assert.EqualError(t, err, `unknown device "Nokia 3310"`)
+	})
+}
+
+func TestBrowserContextOptionsNetworkIdleIgnore(t *testing.T) {
+	vu := k6test.NewVU(t)
+
+	opts := NewBrowserContextOptions()
+	err := opts.Parse(vu.Context(), vu.Runtime().ToValue(map[string]interface{}{
+		"networkIdleIgnore": []string{"**/poll?*", "https://analytics.test/**"},
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"**/poll?*", "https://analytics.test/**"}, opts.NetworkIdleIgnore)
+	assert.True(t, opts.ignoresForNetworkIdle("http://localhost/api/poll?since=1"))
+	assert.True(t, opts.ignoresForNetworkIdle("https://analytics.test/collect/1"))
+	assert.False(t, opts.ignoresForNetworkIdle("http://localhost/api/data"))
+
+	err = opts.Parse(vu.Context(), vu.Runtime().ToValue(map[string]interface{}{
+		"networkIdleIgnore": "**/poll",
+	}))
+	assert.ErrorContains(t, err, "networkIdleIgnore must be an array")
+}
+
+func TestBrowserContextOptionsLogBrowserConsole(t *testing.T) {
+	vu := k6test.NewVU(t)
+
+	opts := NewBrowserContextOptions()
+	assert.True(t, opts.logsBrowserConsole(logrus.DebugLevel))
+
+	err := opts.Parse(vu.Context(), vu.Runtime().ToValue(map[string]interface{}{
+		"logBrowserConsoleIgnore": []string{"debug", "warn"},
+	}))
+	assert.NoError(t, err)
+	assert.False(t, opts.logsBrowserConsole(logrus.DebugLevel))
+	assert.False(t, opts.logsBrowserConsole(logrus.WarnLevel))
+	assert.True(t, opts.logsBrowserConsole(logrus.ErrorLevel))
+
+	err = opts.Parse(vu.Context(), vu.Runtime().ToValue(map[string]interface{}{
+		"logBrowserConsole": false,
+	}))
+	assert.NoError(t, err)
+	assert.False(t, opts.logsBrowserConsole(logrus.ErrorLevel))
+
+	err = opts.Parse(vu.Context(), vu.Runtime().ToValue(map[string]interface{}{
+		"logBrowserConsoleIgnore": []string{"noisy"},
+	}))
+	assert.ErrorContains(t, err, `not a valid logrus Level: "noisy"`)
+}
+
+func TestBrowserContextClearCookiesOptions(t *testing.T) {
+	vu := k6test.NewVU(t)
+
+	opts := NewBrowserContextClearCookiesOptions()
+	assert.False(t, opts.hasFilters())
+	assert.True(t, opts.matches(&network.Cookie{Name: "a", Domain: "example.com", Path: "/"}))
+
+	err := opts.Parse(vu.Context(), vu.ToGojaValue(map[string]interface{}{
+		"name":   "session",
+		"domain": "example.com",
+	}))
+	assert.NoError(t, err)
+	assert.True(t, opts.hasFilters())
+	assert.True(t, opts.matches(&network.Cookie{Name: "session", Domain: "example.com", Path: "/app"}))
+	assert.False(t, opts.matches(&network.Cookie{Name: "session", Domain: "other.com", Path: "/"}))
+	assert.False(t, opts.matches(&network.Cookie{Name: "other", Domain: "example.com", Path: "/"}))
+}
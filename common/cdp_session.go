@@ -0,0 +1,191 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/target"
+	"github.com/dop251/goja"
+	"github.com/mailru/easyjson"
+
+	"github.com/grafana/xk6-browser/api"
+	"github.com/grafana/xk6-browser/k6ext"
+)
+
+// Ensure CDPSession implements the api.CDPSession interface.
+var _ api.CDPSession = &CDPSession{}
+
+// CDPSession is a raw CDP session of a page for the protocol domains that
+// the API doesn't cover. It's a dedicated session that is attached to the
+// target of the page, so detaching it leaves the session of the page
+// intact. The commands that change the state of the browser or the page,
+// such as the emulation or the network ones, can still desync the state
+// the extension keeps about the page, and aren't supported.
+type CDPSession struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	page    *Page
+	conn    connection
+	session *Session
+
+	mu       sync.Mutex
+	detached bool
+}
+
+// NewCDPSession attaches a new raw CDP session to the target of the page.
+func NewCDPSession(ctx context.Context, p *Page) (*CDPSession, error) {
+	conn := p.browserCtx.browser.conn
+	action := target.AttachToTarget(p.targetID).WithFlatten(true)
+	sid, err := action.Do(cdp.WithExecutor(ctx, conn))
+	if err != nil {
+		return nil, fmt.Errorf("attaching to target %v: %w", p.targetID, err)
+	}
+	// The connection creates the session on the attachedToTarget event,
+	// which Chromium sends before the reply to attachToTarget.
+	session := conn.getSession(sid)
+	if session == nil {
+		return nil, fmt.Errorf("attaching to target %v: session %v not found", p.targetID, sid)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	return &CDPSession{
+		ctx:     ctx,
+		cancel:  cancel,
+		page:    p,
+		conn:    conn,
+		session: session,
+	}, nil
+}
+
+// Detach detaches the session from the target of the page. The event
+// handlers are removed, and the session can't be used to send commands
+// afterwards.
+func (s *CDPSession) Detach() {
+	s.page.logger.Debugf("CDPSession:Detach", "sid:%v", s.session.ID())
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.detached {
+		k6ext.Panic(s.page.ctx, "detaching CDP session: session is already detached")
+	}
+	s.detached = true
+	s.cancel()
+
+	action := target.DetachFromTarget().WithSessionID(s.session.ID())
+	if err := action.Do(cdp.WithExecutor(s.page.ctx, s.conn)); err != nil {
+		k6ext.Panic(s.page.ctx, "detaching CDP session: %w", err)
+	}
+}
+
+// On registers a handler for the CDP event such as "Animation.animationCreated".
+// The handler is called with the parameters of the event.
+func (s *CDPSession) On(event string, handler goja.Callable) {
+	s.page.logger.Debugf("CDPSession:On", "sid:%v event:%q", s.session.ID(), event)
+
+	if handler == nil {
+		k6ext.Panic(s.page.ctx, "registering %q CDP event handler: handler must be a function", event)
+	}
+	if s.isDetached() {
+		k6ext.Panic(s.page.ctx, "registering %q CDP event handler: session is detached", event)
+	}
+
+	// The handlers are called on the event loop, so the events are only
+	// buffered here until they are queued, not to block the session.
+	ch := make(chan Event, 64)
+	s.session.on(s.ctx, []string{event}, ch)
+	go func() {
+		for {
+			select {
+			case <-s.ctx.Done():
+				return
+			case <-s.session.Done():
+				return
+			case ev := <-ch:
+				s.callHandler(handler, event, ev.data)
+			}
+		}
+	}()
+}
+
+func (s *CDPSession) callHandler(handler goja.Callable, event string, data interface{}) {
+	params, err := toJSONValue(data)
+	if err != nil {
+		s.page.logger.Errorf("CDPSession:On", "sid:%v event:%q converting params: %v",
+			s.session.ID(), event, err)
+		return
+	}
+
+	s.page.queueCallback(func() error {
+		if s.isDetached() {
+			return nil
+		}
+		rt := s.page.vu.Runtime()
+		if _, err := handler(goja.Undefined(), rt.ToValue(params)); err != nil {
+			s.page.logger.Errorf("CDPSession:On", "sid:%v event:%q err:%v",
+				s.session.ID(), event, err)
+		}
+		return nil
+	})
+}
+
+// Send sends the CDP command, such as "Animation.enable", with the
+// parameters, and returns the result of the command.
+func (s *CDPSession) Send(method string, params goja.Value) goja.Value {
+	s.page.logger.Debugf("CDPSession:Send", "sid:%v method:%q", s.session.ID(), method)
+
+	if s.isDetached() {
+		k6ext.Panic(s.page.ctx, "sending %q: session is detached", method)
+	}
+
+	var p easyjson.Marshaler
+	if params != nil && !goja.IsUndefined(params) && !goja.IsNull(params) {
+		buf, err := json.Marshal(params.Export())
+		if err != nil {
+			k6ext.Panic(s.page.ctx, "marshaling %q params: %w", method, err)
+		}
+		raw := easyjson.RawMessage(buf)
+		p = &raw
+	}
+
+	var res easyjson.RawMessage
+	if err := s.session.Execute(s.ctx, method, p, &res); err != nil {
+		k6ext.Panic(s.page.ctx, "sending %q: %w", method, err)
+	}
+	if len(res) == 0 {
+		return goja.Undefined()
+	}
+	result, err := toJSONValue(res)
+	if err != nil {
+		k6ext.Panic(s.page.ctx, "unmarshaling %q result: %w", method, err)
+	}
+
+	return s.page.vu.Runtime().ToValue(result)
+}
+
+func (s *CDPSession) isDetached() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.detached
+}
+
+// toJSONValue returns v as the generic value of its JSON encoding, so that
+// the protocol types have the field names of the protocol in the scripts.
+func toJSONValue(v interface{}) (interface{}, error) {
+	buf, ok := v.(easyjson.RawMessage)
+	if !ok {
+		var err error
+		if buf, err = json.Marshal(v); err != nil {
+			return nil, fmt.Errorf("marshaling: %w", err)
+		}
+	}
+	var res interface{}
+	if err := json.Unmarshal(buf, &res); err != nil {
+		return nil, fmt.Errorf("unmarshaling: %w", err)
+	}
+	return res, nil
+}
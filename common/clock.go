@@ -0,0 +1,283 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dop251/goja"
+
+	"github.com/grafana/xk6-browser/api"
+	"github.com/grafana/xk6-browser/common/js"
+	"github.com/grafana/xk6-browser/k6ext"
+)
+
+// Ensure Clock implements the api.Clock interface.
+var _ api.Clock = &Clock{}
+
+// Clock is the fake clock of a page. It replaces Date, setTimeout,
+// setInterval, and performance.now in the frames of the page, so that the
+// time can be controlled by the script.
+//
+// The state of the clock is kept here as well, and the clock is added to
+// the new documents with a single init script that continues from it. The
+// init script is replaced after each change of the state.
+type Clock struct {
+	ctx  context.Context
+	page *Page
+
+	mu        sync.Mutex
+	installed bool
+	// time is the time of the clock in milliseconds since the epoch at
+	// the real time of anchor.
+	time      int64
+	anchor    time.Time
+	paused    bool
+	fixedTime *int64
+}
+
+// NewClock returns a new fake clock of the page.
+func NewClock(ctx context.Context, p *Page) *Clock {
+	return &Clock{
+		ctx:  ctx,
+		page: p,
+	}
+}
+
+// FastForward moves the time of the clock forward, and fires the timers
+// that are due at most once.
+func (c *Clock) FastForward(ms int64) {
+	c.page.logger.Debugf("Clock:FastForward", "sid:%v ms:%d", c.page.sessionID(), ms)
+
+	if ms < 0 {
+		k6ext.Panic(c.ctx, "fast forwarding clock: ms must be a positive number, got %d", ms)
+	}
+	err := c.update(func() {
+		c.setNow(c.now() + ms)
+	}, "fastForward", ms)
+	if err != nil {
+		k6ext.Panic(c.ctx, "fast forwarding clock: %w", err)
+	}
+}
+
+// Install installs the fake clock in the page. The time of the clock
+// starts at the time option, and runs with the real time.
+func (c *Clock) Install(opts goja.Value) {
+	c.page.logger.Debugf("Clock:Install", "sid:%v", c.page.sessionID())
+
+	copts := NewClockInstallOptions()
+	if err := copts.Parse(c.ctx, opts); err != nil {
+		k6ext.Panic(c.ctx, "parsing clock install options: %w", err)
+	}
+
+	c.mu.Lock()
+	if c.installed {
+		c.mu.Unlock()
+		k6ext.Panic(c.ctx, "installing clock: clock is already installed")
+	}
+	ms := c.install(copts.Time)
+	err := c.updateInitScripts()
+	c.mu.Unlock()
+	if err != nil {
+		k6ext.Panic(c.ctx, "installing clock: %w", err)
+	}
+	if err := c.call("install", ms); err != nil {
+		k6ext.Panic(c.ctx, "installing clock: %w", err)
+	}
+}
+
+// PauseAt moves the time of the clock to the time, fires the timers that
+// are due at most once, and pauses the clock. The time can only be moved
+// with FastForward while the clock is paused.
+func (c *Clock) PauseAt(t goja.Value) {
+	c.page.logger.Debugf("Clock:PauseAt", "sid:%v", c.page.sessionID())
+
+	ms, err := parseClockTime(c.page.vu.Runtime(), t)
+	if err != nil {
+		k6ext.Panic(c.ctx, "pausing clock: %w", err)
+	}
+	err = c.update(func() {
+		c.setNow(ms)
+		c.paused = true
+	}, "pauseAt", ms)
+	if err != nil {
+		k6ext.Panic(c.ctx, "pausing clock: %w", err)
+	}
+}
+
+// Resume resumes the paused clock, so that its time runs with the real
+// time again.
+func (c *Clock) Resume() {
+	c.page.logger.Debugf("Clock:Resume", "sid:%v", c.page.sessionID())
+
+	err := c.update(func() {
+		if c.paused {
+			c.setNow(c.time)
+			c.paused = false
+		}
+	}, "resume")
+	if err != nil {
+		k6ext.Panic(c.ctx, "resuming clock: %w", err)
+	}
+}
+
+// SetFixedTime makes Date return the time at all times. The timers are
+// not affected.
+func (c *Clock) SetFixedTime(t goja.Value) {
+	c.page.logger.Debugf("Clock:SetFixedTime", "sid:%v", c.page.sessionID())
+
+	ms, err := parseClockTime(c.page.vu.Runtime(), t)
+	if err != nil {
+		k6ext.Panic(c.ctx, "setting fixed time: %w", err)
+	}
+	err = c.update(func() {
+		c.fixedTime = &ms
+	}, "setFixedTime", ms)
+	if err != nil {
+		k6ext.Panic(c.ctx, "setting fixed time: %w", err)
+	}
+}
+
+// update installs the clock with the current time if it is not installed,
+// changes its state with fn, and calls the method of the clock in the
+// current documents.
+func (c *Clock) update(fn func(), method string, args ...interface{}) error {
+	c.mu.Lock()
+	installed := c.installed
+	var ms int64
+	if !installed {
+		ms = c.install(0)
+	}
+	fn()
+	err := c.updateInitScripts()
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	// the current documents are called without the lock, so that it
+	// doesn't block the new frame sessions that add the init script.
+	if !installed {
+		if err := c.call("install", ms); err != nil {
+			return err
+		}
+	}
+	return c.call(method, args...)
+}
+
+// install marks the clock as installed starting at the time in
+// milliseconds since the epoch, or at the current time if it is zero, and
+// returns the time. It should be called with the lock held.
+func (c *Clock) install(ms int64) int64 {
+	if ms == 0 {
+		ms = time.Now().UnixMilli()
+	}
+	c.installed = true
+	c.setNow(ms)
+
+	return ms
+}
+
+// now returns the time of the clock in milliseconds since the epoch. It
+// should be called with the lock held.
+func (c *Clock) now() int64 {
+	if c.paused {
+		return c.time
+	}
+	return c.time + time.Since(c.anchor).Milliseconds()
+}
+
+// setNow sets the time of the clock in milliseconds since the epoch. It
+// should be called with the lock held.
+func (c *Clock) setNow(ms int64) {
+	c.time = ms
+	c.anchor = time.Now()
+}
+
+// initScript returns the init script that installs the clock with its
+// current state in a new document, or an empty string if the clock is not
+// installed. It should be called with the lock held.
+func (c *Clock) initScript() (string, error) {
+	if !c.installed {
+		return "", nil
+	}
+	state, err := json.Marshal(struct {
+		Time      int64  `json:"time"`
+		Anchor    int64  `json:"anchor"`
+		Paused    bool   `json:"paused"`
+		FixedTime *int64 `json:"fixedTime"`
+	}{c.time, c.anchor.UnixMilli(), c.paused, c.fixedTime})
+	if err != nil {
+		return "", fmt.Errorf("marshaling clock state: %w", err)
+	}
+
+	return fmt.Sprintf("%s\nglobalThis.__k6BrowserClock.restore(%s);", js.Clock, state), nil
+}
+
+// updateInitScripts replaces the init script of the clock in the frame
+// sessions of the page. It should be called with the lock held.
+func (c *Clock) updateInitScripts() error {
+	source, err := c.initScript()
+	if err != nil {
+		return err
+	}
+	for _, fs := range c.page.frameSessions {
+		if err := fs.setClockScript(source); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addInitScript adds the init script of the clock to the new frame
+// session of the page, if the clock is installed.
+func (c *Clock) addInitScript(fs *FrameSession) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	source, err := c.initScript()
+	if err != nil || source == "" {
+		return err
+	}
+	return fs.setClockScript(source)
+}
+
+// call calls the method of the clock in the current documents of the
+// frames of the page.
+func (c *Clock) call(method string, args ...interface{}) error {
+	var (
+		rt = c.page.vu.Runtime()
+		fn = rt.ToValue(`(method, args) => {
+			` + js.Clock + `
+			return globalThis.__k6BrowserClock[method](...args);
+		}`)
+		eopts = evalOptions{
+			forceCallable: true,
+			returnByValue: true,
+		}
+	)
+	if args == nil {
+		args = []interface{}{}
+	}
+	for _, f := range c.page.frameManager.Frames() {
+		frame, ok := f.(*Frame)
+		if !ok {
+			continue
+		}
+		_, err := frame.evaluate(c.ctx, mainWorld, eopts, fn, rt.ToValue(method), rt.ToValue(args))
+		if err == nil {
+			continue
+		}
+		if frame == c.page.frameManager.MainFrame() {
+			return fmt.Errorf("calling clock %s: %w", method, err)
+		}
+		// A child frame could be navigating or detached, the clock is added
+		// to its new document anyway.
+		c.page.logger.Debugf("Clock:call", "sid:%v fid:%v method:%q err:%v",
+			c.page.sessionID(), frame.ID(), method, err)
+	}
+
+	return nil
+}
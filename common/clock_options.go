@@ -0,0 +1,69 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/dop251/goja"
+
+	"github.com/grafana/xk6-browser/k6ext"
+)
+
+// ClockInstallOptions are the options of Clock.install.
+type ClockInstallOptions struct {
+	// Time is the initial time of the clock in milliseconds since the
+	// epoch. The zero value means the current time.
+	Time int64 `js:"time"`
+}
+
+// NewClockInstallOptions returns the default clock install options.
+func NewClockInstallOptions() *ClockInstallOptions {
+	return &ClockInstallOptions{}
+}
+
+// Parse parses the clock install options from a JS object.
+func (o *ClockInstallOptions) Parse(ctx context.Context, opts goja.Value) error {
+	if !gojaValueExists(opts) {
+		return nil
+	}
+	rt := k6ext.Runtime(ctx)
+	obj := opts.ToObject(rt)
+	for _, k := range obj.Keys() {
+		switch k {
+		case "time":
+			t, err := parseClockTime(rt, obj.Get(k))
+			if err != nil {
+				return err
+			}
+			o.Time = t
+		}
+	}
+	return nil
+}
+
+// parseClockTime returns the time in milliseconds since the epoch from a
+// number of milliseconds, a date string, or a Date object.
+func parseClockTime(rt *goja.Runtime, v goja.Value) (int64, error) {
+	if !gojaValueExists(v) {
+		return 0, errors.New("time is required")
+	}
+	date, err := rt.New(rt.Get("Date"), v)
+	if err != nil {
+		return 0, fmt.Errorf("parsing time: %w", err)
+	}
+	getTime, ok := goja.AssertFunction(date.Get("getTime"))
+	if !ok {
+		return 0, errors.New("date has no getTime method")
+	}
+	t, err := getTime(date)
+	if err != nil {
+		return 0, fmt.Errorf("parsing time: %w", err)
+	}
+	ms := t.ToFloat()
+	if math.IsNaN(ms) {
+		return 0, errors.New("time must be a number, a date string, or a Date")
+	}
+	return int64(ms), nil
+}
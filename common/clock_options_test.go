@@ -0,0 +1,39 @@
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/xk6-browser/k6ext/k6test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClockInstallOptions(t *testing.T) {
+	t.Parallel()
+
+	vu := k6test.NewVU(t)
+	rt := vu.Runtime()
+	want := time.Date(2024, 2, 2, 10, 0, 0, 0, time.UTC).UnixMilli()
+	date, err := rt.RunString(`new Date("2024-02-02T10:00:00Z")`)
+	require.NoError(t, err)
+
+	for name, v := range map[string]interface{}{
+		"number": want,
+		"string": "2024-02-02T10:00:00Z",
+		"date":   date,
+	} {
+		opts := NewClockInstallOptions()
+		err := opts.Parse(vu.Context(), rt.ToValue(map[string]interface{}{"time": v}))
+		require.NoError(t, err, name)
+		assert.Equal(t, want, opts.Time, name)
+	}
+
+	opts := NewClockInstallOptions()
+	require.NoError(t, opts.Parse(vu.Context(), nil))
+	assert.Zero(t, opts.Time)
+
+	err = opts.Parse(vu.Context(), rt.ToValue(map[string]interface{}{"time": "not a date"}))
+	assert.EqualError(t, err, "time must be a number, a date string, or a Date")
+}
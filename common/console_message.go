@@ -0,0 +1,114 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/grafana/xk6-browser/api"
+
+	cdpruntime "github.com/chromedp/cdproto/runtime"
+	"github.com/sirupsen/logrus"
+)
+
+// Ensure ConsoleMessage implements the api.ConsoleMessage interface.
+var _ api.ConsoleMessage = &ConsoleMessage{}
+
+// ConsoleMessage is a console message logged by a page.
+type ConsoleMessage struct {
+	typ      string
+	text     string
+	args     []api.JSHandle
+	location api.ConsoleMessageLocation
+	page     *Page
+}
+
+// NewConsoleMessage returns a new console message for the console API call
+// made in the execution context. The arguments are available as handles
+// only if the execution context is known.
+func NewConsoleMessage(
+	p *Page, execCtx *ExecutionContext, event *cdpruntime.EventConsoleAPICalled,
+) *ConsoleMessage {
+	m := &ConsoleMessage{
+		typ:  event.Type.String(),
+		text: consoleMessageText(event.Args),
+		page: p,
+	}
+	if execCtx != nil {
+		for _, robj := range event.Args {
+			m.args = append(m.args, NewJSHandle(execCtx.ctx, execCtx.session, execCtx, execCtx.Frame(), robj, p.logger))
+		}
+	}
+	if st := event.StackTrace; st != nil && len(st.CallFrames) > 0 {
+		cf := st.CallFrames[0]
+		m.location = api.ConsoleMessageLocation{
+			URL:          cf.URL,
+			LineNumber:   cf.LineNumber,
+			ColumnNumber: cf.ColumnNumber,
+		}
+	}
+	return m
+}
+
+// consoleMessageLogLevel returns the log level of the console API call type.
+func consoleMessageLogLevel(typ cdpruntime.APIType) logrus.Level {
+	switch typ {
+	case cdpruntime.APITypeLog, cdpruntime.APITypeInfo:
+		return logrus.InfoLevel
+	case cdpruntime.APITypeWarning:
+		return logrus.WarnLevel
+	case cdpruntime.APITypeError, cdpruntime.APITypeAssert:
+		return logrus.ErrorLevel
+	default:
+		return logrus.DebugLevel
+	}
+}
+
+// consoleMessageText joins the values of the console call arguments.
+// Objects are represented by their type, as they are available as handles.
+func consoleMessageText(args []*cdpruntime.RemoteObject) string {
+	texts := make([]string, 0, len(args))
+	for _, robj := range args {
+		if robj.ObjectID != "" {
+			typ := robj.Type.String()
+			if robj.Subtype != "" {
+				typ = robj.Subtype.String()
+			}
+			texts = append(texts, "JSHandle@"+typ)
+			continue
+		}
+		v, err := parseRemoteObject(robj)
+		switch {
+		case err != nil:
+			v = robj.Description
+		case v == nil:
+			v = "null"
+		}
+		texts = append(texts, fmt.Sprint(v))
+	}
+	return strings.Join(texts, " ")
+}
+
+// Args returns the handles of the arguments passed to the console call.
+func (m *ConsoleMessage) Args() []api.JSHandle {
+	return m.args
+}
+
+// Location returns where in the page source the message was logged.
+func (m *ConsoleMessage) Location() api.ConsoleMessageLocation {
+	return m.location
+}
+
+// Page returns the page that logged the message.
+func (m *ConsoleMessage) Page() api.Page {
+	return m.page
+}
+
+// Text returns the text of the message.
+func (m *ConsoleMessage) Text() string {
+	return m.text
+}
+
+// Type returns the type of the console call, such as log, warning or error.
+func (m *ConsoleMessage) Type() string {
+	return m.typ
+}
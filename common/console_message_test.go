@@ -0,0 +1,22 @@
+package common
+
+import (
+	"testing"
+
+	cdpruntime "github.com/chromedp/cdproto/runtime"
+	"github.com/mailru/easyjson"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConsoleMessageText(t *testing.T) {
+	t.Parallel()
+
+	args := []*cdpruntime.RemoteObject{
+		{Type: cdpruntime.TypeString, Value: easyjson.RawMessage(`"hello"`)},
+		{Type: cdpruntime.TypeNumber, Value: easyjson.RawMessage(`42`)},
+		{Type: cdpruntime.TypeObject, Subtype: cdpruntime.SubtypeNull, Value: easyjson.RawMessage(`null`)},
+		{Type: cdpruntime.TypeObject, ObjectID: "1"},
+		{Type: cdpruntime.TypeObject, Subtype: cdpruntime.SubtypeArray, ObjectID: "2"},
+	}
+	assert.Equal(t, "hello 42 null JSHandle@object JSHandle@array", consoleMessageText(args))
+}
@@ -25,12 +25,17 @@ import "time"
 const (
 	// Defaults
 
-	DefaultLocale       string        = "en-US"
-	DefaultScreenWidth  int64         = 1280
-	DefaultScreenHeight int64         = 720
-	DefaultTimeout      time.Duration = 30 * time.Second
+	DefaultLocale          string        = "en-US"
+	DefaultScreenWidth     int64         = 1280
+	DefaultScreenHeight    int64         = 720
+	DefaultTestIDAttribute string        = "data-testid"
+	DefaultTimeout         time.Duration = 30 * time.Second
 
 	// Life-cycle consts
 
 	LifeCycleNetworkIdleTimeout time.Duration = 500 * time.Millisecond
+
+	// Debugging consts
+
+	LocatorHighlightTimeout time.Duration = 2 * time.Second
 )
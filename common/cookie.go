@@ -0,0 +1,224 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/network"
+	"github.com/dop251/goja"
+
+	"github.com/grafana/xk6-browser/api"
+)
+
+// cookieFromProtocol converts the protocol cookie to an API cookie.
+func cookieFromProtocol(c *network.Cookie) *api.Cookie {
+	cookie := api.Cookie{
+		Name:         c.Name,
+		Value:        c.Value,
+		Domain:       c.Domain,
+		Path:         c.Path,
+		Expires:      c.Expires,
+		HTTPOnly:     c.HTTPOnly,
+		Secure:       c.Secure,
+		SameSite:     c.SameSite.String(),
+		PartitionKey: c.PartitionKey,
+	}
+	if c.Session {
+		cookie.Expires = -1
+	}
+	// the cookies without the SameSite attribute are treated as Lax by the
+	// browser.
+	if cookie.SameSite == "" {
+		cookie.SameSite = network.CookieSameSiteLax.String()
+	}
+	return &cookie
+}
+
+// cookieParamFromProtocol returns the parameters to set the protocol cookie
+// again.
+func cookieParamFromProtocol(c *network.Cookie) *network.CookieParam {
+	p := network.CookieParam{
+		Name:         c.Name,
+		Value:        c.Value,
+		Domain:       c.Domain,
+		Path:         c.Path,
+		Secure:       c.Secure,
+		HTTPOnly:     c.HTTPOnly,
+		SameSite:     c.SameSite,
+		Priority:     c.Priority,
+		SameParty:    c.SameParty,
+		SourceScheme: c.SourceScheme,
+		SourcePort:   c.SourcePort,
+		PartitionKey: c.PartitionKey,
+	}
+	if !c.Session {
+		sec, frac := math.Modf(c.Expires)
+		t := cdp.TimeSinceEpoch(time.Unix(int64(sec), int64(frac*float64(time.Second))))
+		p.Expires = &t
+	}
+	return &p
+}
+
+// filterCookies returns the cookies that are sent to at least one of the
+// URLs, or all the cookies if there are no URLs.
+func filterCookies(cookies []*api.Cookie, urls ...string) ([]*api.Cookie, error) {
+	if len(urls) == 0 {
+		return cookies, nil
+	}
+	parsed := make([]*url.URL, 0, len(urls))
+	for _, u := range urls {
+		pu, err := url.Parse(u)
+		if err != nil {
+			return nil, fmt.Errorf("parsing URL %q: %w", u, err)
+		}
+		parsed = append(parsed, pu)
+	}
+
+	filtered := make([]*api.Cookie, 0, len(cookies))
+	for _, c := range cookies {
+		for _, u := range parsed {
+			if cookieMatchesURL(c, u) {
+				filtered = append(filtered, c)
+				break
+			}
+		}
+	}
+	return filtered, nil
+}
+
+// cookieMatchesURL returns whether the cookie is sent to the URL.
+func cookieMatchesURL(c *api.Cookie, u *url.URL) bool {
+	// the domain of the host-only cookies doesn't start with a dot, and
+	// they're only sent to the host itself.
+	host := u.Hostname()
+	if strings.HasPrefix(c.Domain, ".") {
+		if !strings.HasSuffix("."+host, c.Domain) {
+			return false
+		}
+	} else if host != c.Domain {
+		return false
+	}
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if !cookiePathMatches(c.Path, path) {
+		return false
+	}
+	// the secure cookies are sent to localhost over HTTP as well.
+	if c.Secure && u.Scheme != "https" && host != "localhost" && host != "127.0.0.1" && host != "::1" {
+		return false
+	}
+	return true
+}
+
+// cookiePathMatches returns whether the request path matches the cookie
+// path as in RFC 6265 section 5.1.4. The cookie path must be equal to the
+// request path, or be a prefix of it that ends with a slash or is followed
+// by one, so that /app doesn't match /application.
+func cookiePathMatches(cookiePath, path string) bool {
+	if !strings.HasPrefix(path, cookiePath) {
+		return false
+	}
+	return len(path) == len(cookiePath) ||
+		strings.HasSuffix(cookiePath, "/") ||
+		path[len(cookiePath)] == '/'
+}
+
+// parseCookieParams parses the cookies to add from a JS array of objects
+// with the name, value, url, domain, path, expires, httpOnly, secure, and
+// sameSite fields.
+func parseCookieParams(rt *goja.Runtime, cookies goja.Value) ([]*network.CookieParam, error) {
+	if !gojaValueExists(cookies) {
+		return nil, errors.New("cookies are required")
+	}
+	var items []goja.Value
+	if err := rt.ExportTo(cookies, &items); err != nil {
+		return nil, errors.New("cookies must be an array")
+	}
+
+	params := make([]*network.CookieParam, 0, len(items))
+	for i, item := range items {
+		p, err := parseCookieParam(rt, item)
+		if err != nil {
+			return nil, fmt.Errorf("cookie #%d: %w", i, err)
+		}
+		params = append(params, p)
+	}
+	return params, nil
+}
+
+func parseCookieParam(rt *goja.Runtime, cookie goja.Value) (*network.CookieParam, error) {
+	if _, ok := cookie.(*goja.Object); !ok {
+		return nil, errors.New("cookie must be an object")
+	}
+
+	var (
+		p                 network.CookieParam
+		hasName, hasValue bool
+		obj               = cookie.ToObject(rt)
+	)
+	for _, k := range obj.Keys() {
+		v := obj.Get(k)
+		switch k {
+		case "name":
+			p.Name, hasName = v.String(), true
+		case "value":
+			p.Value, hasValue = v.String(), true
+		case "url":
+			p.URL = v.String()
+		case "domain":
+			p.Domain = v.String()
+		case "path":
+			p.Path = v.String()
+		case "expires":
+			expires := v.ToFloat()
+			if math.IsNaN(expires) || (expires != -1 && expires <= 0) {
+				return nil, fmt.Errorf("expires must be -1 or a positive number of seconds since the epoch, got %v", v)
+			}
+			if expires != -1 {
+				sec, frac := math.Modf(expires)
+				t := cdp.TimeSinceEpoch(time.Unix(int64(sec), int64(frac*float64(time.Second))))
+				p.Expires = &t
+			}
+		case "httpOnly":
+			p.HTTPOnly = v.ToBoolean()
+		case "secure":
+			p.Secure = v.ToBoolean()
+		case "sameSite":
+			switch ss := network.CookieSameSite(v.String()); ss {
+			case network.CookieSameSiteStrict, network.CookieSameSiteLax, network.CookieSameSiteNone:
+				p.SameSite = ss
+			default:
+				return nil, fmt.Errorf("%q is not a valid sameSite, must be one of: Strict, Lax, None", v)
+			}
+		}
+	}
+
+	switch {
+	case !hasName || p.Name == "":
+		return nil, errors.New("name is required")
+	case !hasValue:
+		return nil, fmt.Errorf("%q: value is required", p.Name)
+	case p.URL != "" && (p.Domain != "" || p.Path != ""):
+		return nil, fmt.Errorf("%q: either url or domain and path must be set, not both", p.Name)
+	case p.URL == "" && (p.Domain == "" || p.Path == ""):
+		return nil, fmt.Errorf("%q: either url or domain and path must be set", p.Name)
+	}
+	if p.URL != "" {
+		u, err := url.Parse(p.URL)
+		if err != nil {
+			return nil, fmt.Errorf("%q: parsing url %q: %w", p.Name, p.URL, err)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return nil, fmt.Errorf("%q: url must be an http or https URL, got %q", p.Name, p.URL)
+		}
+	}
+
+	return &p, nil
+}
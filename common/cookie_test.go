@@ -0,0 +1,242 @@
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/network"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/xk6-browser/api"
+	"github.com/grafana/xk6-browser/k6ext/k6test"
+)
+
+func TestCookieFromProtocol(t *testing.T) {
+	t.Parallel()
+
+	c := cookieFromProtocol(&network.Cookie{
+		Name:         "name",
+		Value:        "value",
+		Domain:       ".example.com",
+		Path:         "/",
+		Expires:      0,
+		Session:      true,
+		HTTPOnly:     true,
+		PartitionKey: "https://top.com",
+	})
+	assert.Equal(t, &api.Cookie{
+		Name:         "name",
+		Value:        "value",
+		Domain:       ".example.com",
+		Path:         "/",
+		Expires:      -1,
+		HTTPOnly:     true,
+		SameSite:     "Lax",
+		PartitionKey: "https://top.com",
+	}, c)
+
+	c = cookieFromProtocol(&network.Cookie{
+		Expires:  1700000000,
+		SameSite: network.CookieSameSiteStrict,
+	})
+	assert.Equal(t, float64(1700000000), c.Expires)
+	assert.Equal(t, "Strict", c.SameSite)
+}
+
+func TestFilterCookies(t *testing.T) {
+	t.Parallel()
+
+	var (
+		host      = &api.Cookie{Name: "host", Domain: "example.com", Path: "/"}
+		subdomain = &api.Cookie{Name: "subdomain", Domain: ".example.com", Path: "/"}
+		path      = &api.Cookie{Name: "path", Domain: "example.com", Path: "/app"}
+		secure    = &api.Cookie{Name: "secure", Domain: "example.com", Path: "/", Secure: true}
+		local     = &api.Cookie{Name: "local", Domain: "localhost", Path: "/", Secure: true}
+		cookies   = []*api.Cookie{host, subdomain, path, secure, local}
+	)
+
+	tests := []struct {
+		name string
+		urls []string
+		want []*api.Cookie
+	}{
+		{
+			name: "no_urls",
+			want: cookies,
+		},
+		{
+			name: "http",
+			urls: []string{"http://example.com"},
+			want: []*api.Cookie{host, subdomain},
+		},
+		{
+			name: "https_path",
+			urls: []string{"https://example.com/app/index.html"},
+			want: []*api.Cookie{host, subdomain, path, secure},
+		},
+		{
+			name: "path_exact",
+			urls: []string{"http://example.com/app"},
+			want: []*api.Cookie{host, subdomain, path},
+		},
+		{
+			name: "path_prefix",
+			urls: []string{"http://example.com/application"},
+			want: []*api.Cookie{host, subdomain},
+		},
+		{
+			name: "subdomain",
+			urls: []string{"https://www.example.com"},
+			want: []*api.Cookie{subdomain},
+		},
+		{
+			name: "localhost",
+			urls: []string{"http://localhost:8080"},
+			want: []*api.Cookie{local},
+		},
+		{
+			name: "multiple_urls",
+			urls: []string{"http://localhost", "http://www.example.com"},
+			want: []*api.Cookie{subdomain, local},
+		},
+		{
+			name: "other_domain",
+			urls: []string{"https://notexample.com"},
+			want: []*api.Cookie{},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := filterCookies(cookies, tt.urls...)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("err_url", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := filterCookies(cookies, "http://exa mple.com")
+		assert.ErrorContains(t, err, `parsing URL "http://exa mple.com"`)
+	})
+}
+
+func TestParseCookieParams(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ok", func(t *testing.T) {
+		t.Parallel()
+
+		vu := k6test.NewVU(t)
+		params, err := parseCookieParams(vu.Runtime(), vu.ToGojaValue([]interface{}{
+			map[string]interface{}{
+				"name":     "session",
+				"value":    "token",
+				"url":      "https://example.com",
+				"httpOnly": true,
+				"secure":   true,
+				"sameSite": "Strict",
+				"expires":  1700000000.5,
+			},
+			map[string]interface{}{
+				"name":    "empty",
+				"value":   "",
+				"domain":  ".example.com",
+				"path":    "/",
+				"expires": -1,
+			},
+		}))
+		require.NoError(t, err)
+		require.Len(t, params, 2)
+
+		expires := cdp.TimeSinceEpoch(time.Unix(1700000000, int64(500*time.Millisecond)))
+		assert.Equal(t, &network.CookieParam{
+			Name:     "session",
+			Value:    "token",
+			URL:      "https://example.com",
+			HTTPOnly: true,
+			Secure:   true,
+			SameSite: network.CookieSameSiteStrict,
+			Expires:  &expires,
+		}, params[0])
+		assert.Equal(t, &network.CookieParam{
+			Name:   "empty",
+			Domain: ".example.com",
+			Path:   "/",
+		}, params[1])
+	})
+
+	t.Run("err", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name    string
+			cookies interface{}
+			wantErr string
+		}{
+			{
+				name:    "not_array",
+				cookies: "cookie",
+				wantErr: "cookies must be an array",
+			},
+			{
+				name:    "not_object",
+				cookies: []interface{}{"cookie"},
+				wantErr: "cookie #0: cookie must be an object",
+			},
+			{
+				name:    "no_name",
+				cookies: []interface{}{map[string]interface{}{"value": "1", "url": "https://example.com"}},
+				wantErr: "cookie #0: name is required",
+			},
+			{
+				name:    "no_value",
+				cookies: []interface{}{map[string]interface{}{"name": "a", "url": "https://example.com"}},
+				wantErr: `cookie #0: "a": value is required`,
+			},
+			{
+				name: "no_path",
+				cookies: []interface{}{
+					map[string]interface{}{"name": "a", "value": "1", "url": "https://example.com"},
+					map[string]interface{}{"name": "b", "value": "2", "domain": "example.com"},
+				},
+				wantErr: `cookie #1: "b": either url or domain and path must be set`,
+			},
+			{
+				name:    "url_and_domain",
+				cookies: []interface{}{map[string]interface{}{"name": "a", "value": "1", "url": "https://example.com", "domain": "example.com"}},
+				wantErr: `cookie #0: "a": either url or domain and path must be set, not both`,
+			},
+			{
+				name:    "url_scheme",
+				cookies: []interface{}{map[string]interface{}{"name": "a", "value": "1", "url": "about:blank"}},
+				wantErr: `cookie #0: "a": url must be an http or https URL, got "about:blank"`,
+			},
+			{
+				name:    "same_site",
+				cookies: []interface{}{map[string]interface{}{"name": "a", "value": "1", "url": "https://example.com", "sameSite": "strict"}},
+				wantErr: `cookie #0: "strict" is not a valid sameSite, must be one of: Strict, Lax, None`,
+			},
+			{
+				name:    "expires",
+				cookies: []interface{}{map[string]interface{}{"name": "a", "value": "1", "url": "https://example.com", "expires": 0}},
+				wantErr: "cookie #0: expires must be -1 or a positive number of seconds since the epoch, got 0",
+			},
+		}
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				vu := k6test.NewVU(t)
+				_, err := parseCookieParams(vu.Runtime(), vu.ToGojaValue(tt.cookies))
+				assert.EqualError(t, err, tt.wantErr)
+			})
+		}
+	})
+}
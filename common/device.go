@@ -473,6 +473,50 @@ func GetDevices() map[string]Device {
 			IsMobile:          true,
 			HasTouch:          true,
 		},
+		"iPhone 12": {
+			Name:      "iPhone 12",
+			UserAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 14_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/14.0.3 Mobile/15E148 Safari/604.1",
+			Viewport: Viewport{
+				Width:  390,
+				Height: 664,
+			},
+			DeviceScaleFactor: 3,
+			IsMobile:          true,
+			HasTouch:          true,
+		},
+		"iPhone 12 landscape": {
+			Name:      "iPhone 12 landscape",
+			UserAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 14_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/14.0.3 Mobile/15E148 Safari/604.1",
+			Viewport: Viewport{
+				Width:  750,
+				Height: 340,
+			},
+			DeviceScaleFactor: 3,
+			IsMobile:          true,
+			HasTouch:          true,
+		},
+		"iPhone 13": {
+			Name:      "iPhone 13",
+			UserAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 15_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/15.0 Mobile/15E148 Safari/604.1",
+			Viewport: Viewport{
+				Width:  390,
+				Height: 664,
+			},
+			DeviceScaleFactor: 3,
+			IsMobile:          true,
+			HasTouch:          true,
+		},
+		"iPhone 13 landscape": {
+			Name:      "iPhone 13 landscape",
+			UserAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 15_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/15.0 Mobile/15E148 Safari/604.1",
+			Viewport: Viewport{
+				Width:  750,
+				Height: 342,
+			},
+			DeviceScaleFactor: 3,
+			IsMobile:          true,
+			HasTouch:          true,
+		},
 		"JioPhone 2": {
 			Name:      "JioPhone 2",
 			UserAgent: "Mozilla/5.0 (Mobile; LYF/F300B/LYF-F300B-001-01-15-130718-i;Android; rv:48.0) Gecko/48.0 Firefox/48.0 KAIOS/2.5",
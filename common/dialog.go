@@ -0,0 +1,117 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/grafana/xk6-browser/api"
+	"github.com/grafana/xk6-browser/k6ext"
+	"github.com/grafana/xk6-browser/log"
+
+	"github.com/chromedp/cdproto/cdp"
+	cdppage "github.com/chromedp/cdproto/page"
+)
+
+// Ensure Dialog implements the api.Dialog interface.
+var _ api.Dialog = &Dialog{}
+
+// Dialog is a JavaScript dialog opened by a page. The page stays blocked
+// until the dialog is accepted or dismissed.
+type Dialog struct {
+	ctx          context.Context
+	session      session
+	typ          cdppage.DialogType
+	message      string
+	defaultValue string
+
+	handledMu sync.Mutex
+	handled   bool
+
+	logger *log.Logger
+}
+
+// NewDialog returns a new dialog for the opened JavaScript dialog event.
+func NewDialog(
+	ctx context.Context, s session, event *cdppage.EventJavascriptDialogOpening, l *log.Logger,
+) *Dialog {
+	return &Dialog{
+		ctx:          ctx,
+		session:      s,
+		typ:          event.Type,
+		message:      event.Message,
+		defaultValue: event.DefaultPrompt,
+		logger:       l,
+	}
+}
+
+// Accept accepts the dialog. The prompt text is only used by prompt dialogs.
+func (d *Dialog) Accept(promptText string) {
+	d.logger.Debugf("Dialog:Accept", "type:%s", d.typ)
+
+	if err := d.handle(true, promptText); err != nil {
+		k6ext.Panic(d.ctx, "accepting dialog: %w", err)
+	}
+}
+
+// DefaultValue returns the default value of prompt dialogs.
+func (d *Dialog) DefaultValue() string {
+	return d.defaultValue
+}
+
+// Dismiss dismisses the dialog.
+func (d *Dialog) Dismiss() {
+	d.logger.Debugf("Dialog:Dismiss", "type:%s", d.typ)
+
+	if err := d.handle(false, ""); err != nil {
+		k6ext.Panic(d.ctx, "dismissing dialog: %w", err)
+	}
+}
+
+// Message returns the message displayed by the dialog.
+func (d *Dialog) Message() string {
+	return d.message
+}
+
+// Type returns the type of the dialog: alert, beforeunload, confirm or prompt.
+func (d *Dialog) Type() string {
+	return d.typ.String()
+}
+
+func (d *Dialog) isHandled() bool {
+	d.handledMu.Lock()
+	defer d.handledMu.Unlock()
+
+	return d.handled
+}
+
+func (d *Dialog) handle(accept bool, promptText string) error {
+	d.handledMu.Lock()
+	defer d.handledMu.Unlock()
+
+	if d.handled {
+		return errors.New("dialog has already been handled")
+	}
+	d.handled = true
+
+	action := cdppage.HandleJavaScriptDialog(accept)
+	if accept && d.typ == cdppage.DialogTypePrompt {
+		action = action.WithPromptText(promptText)
+	}
+	if err := action.Do(cdp.WithExecutor(d.ctx, d.session)); err != nil {
+		return fmt.Errorf("handling %s dialog: %w", d.typ, err)
+	}
+
+	return nil
+}
+
+// handleDefault handles the dialog if the event handlers didn't, so that
+// the page is not blocked. The beforeunload dialogs are accepted to not
+// block the navigations, and the other dialogs are dismissed.
+func (d *Dialog) handleDefault() error {
+	if d.isHandled() {
+		return nil
+	}
+	return d.handle(d.typ == cdppage.DialogTypeBeforeunload, "")
+}
@@ -0,0 +1,157 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/grafana/xk6-browser/api"
+	"github.com/grafana/xk6-browser/k6ext"
+	"github.com/grafana/xk6-browser/log"
+)
+
+// Ensure Download implements the api.Download interface.
+var _ api.Download = &Download{}
+
+// Download is a file download started by a page.
+type Download struct {
+	ctx               context.Context
+	page              *Page
+	guid              string
+	url               string
+	suggestedFilename string
+	// dir is the directory the browser saves the downloads in.
+	dir string
+
+	done       chan struct{}
+	finishOnce sync.Once
+	failure    string
+
+	logger *log.Logger
+}
+
+// NewDownload returns a new download that the browser saves to dir.
+func NewDownload(
+	ctx context.Context, p *Page, guid, url, suggestedFilename, dir string, l *log.Logger,
+) *Download {
+	return &Download{
+		ctx:               ctx,
+		page:              p,
+		guid:              guid,
+		url:               url,
+		suggestedFilename: suggestedFilename,
+		dir:               dir,
+		done:              make(chan struct{}),
+		logger:            l,
+	}
+}
+
+// finish marks the download finished. An empty failure means the download
+// succeeded.
+func (d *Download) finish(failure string) {
+	d.finishOnce.Do(func() {
+		d.failure = failure
+		close(d.done)
+	})
+}
+
+// wait waits for the download to finish and returns the download error.
+func (d *Download) wait() error {
+	select {
+	case <-d.done:
+	case <-d.ctx.Done():
+		return fmt.Errorf("waiting for download: %w", d.ctx.Err())
+	}
+	if d.failure != "" {
+		return fmt.Errorf("download failed: %s", d.failure)
+	}
+	return nil
+}
+
+// path waits for the download to finish and returns the path of the file.
+func (d *Download) path() (string, error) {
+	if err := d.wait(); err != nil {
+		return "", err
+	}
+	if d.dir == "" {
+		return "", errors.New("downloads are not accepted, set the acceptDownloads browser context option")
+	}
+	return filepath.Join(d.dir, d.guid), nil
+}
+
+// Delete deletes the downloaded file, waiting for the download to finish
+// if necessary.
+func (d *Download) Delete() {
+	d.logger.Debugf("Download:Delete", "guid:%s url:%q", d.guid, d.url)
+
+	path, err := d.path()
+	if err != nil {
+		k6ext.Panic(d.ctx, "deleting download: %w", err)
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		k6ext.Panic(d.ctx, "deleting download: %w", err)
+	}
+}
+
+// Failure waits for the download to finish and returns the download error,
+// or an empty string if the download succeeded.
+func (d *Download) Failure() string {
+	select {
+	case <-d.done:
+	case <-d.ctx.Done():
+		k6ext.Panic(d.ctx, "waiting for download: %w", d.ctx.Err())
+	}
+	return d.failure
+}
+
+// Page returns the page that started the download.
+func (d *Download) Page() api.Page {
+	return d.page
+}
+
+// Path waits for the download to finish and returns the path of the
+// downloaded file.
+func (d *Download) Path() string {
+	d.logger.Debugf("Download:Path", "guid:%s url:%q", d.guid, d.url)
+
+	path, err := d.path()
+	if err != nil {
+		k6ext.Panic(d.ctx, "getting download path: %w", err)
+	}
+	return path
+}
+
+// SaveAs waits for the download to finish and copies the downloaded file
+// to the given path.
+func (d *Download) SaveAs(path string) {
+	d.logger.Debugf("Download:SaveAs", "guid:%s url:%q path:%q", d.guid, d.url, path)
+
+	src, err := d.path()
+	if err != nil {
+		k6ext.Panic(d.ctx, "saving download: %w", err)
+	}
+	b, err := ioutil.ReadFile(src) //nolint:gosec
+	if err != nil {
+		k6ext.Panic(d.ctx, "saving download: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		k6ext.Panic(d.ctx, "saving download: creating directory: %w", err)
+	}
+	if err := ioutil.WriteFile(path, b, 0o644); err != nil { //nolint:gosec
+		k6ext.Panic(d.ctx, "saving download: %w", err)
+	}
+}
+
+// SuggestedFilename returns the file name suggested by the browser.
+func (d *Download) SuggestedFilename() string {
+	return d.suggestedFilename
+}
+
+// URL returns the URL of the download.
+func (d *Download) URL() string {
+	return d.url
+}
@@ -55,7 +55,10 @@ func (h *ElementHandle) boundingBox() (*Rect, error) {
 	y := math.Min(quad[1], math.Min(quad[3], math.Min(quad[5], quad[7])))
 	width := math.Max(quad[0], math.Max(quad[2], math.Max(quad[4], quad[6]))) - x
 	height := math.Max(quad[1], math.Max(quad[3], math.Max(quad[5], quad[7]))) - y
-	position := h.frame.position()
+	position, err := h.frame.position()
+	if err != nil {
+		return nil, err
+	}
 
 	return &Rect{X: x + position.X, Y: y + position.Y, Width: width, Height: height}, nil
 }
@@ -239,12 +242,16 @@ func compensateHalfIntegerRoundingError(p Position) Position {
 	return p
 }
 
-func (h *ElementHandle) dblClick(p *Position, opts *MouseClickOptions) error {
-	return h.frame.page.Mouse.click(p.X, p.Y, opts)
+// dblClick double clicks at the position with the modifiers of the options
+// held down.
+func (h *ElementHandle) dblClick(p *Position, opts *ElementHandleDblclickOptions) error {
+	return h.frame.page.Keyboard.withModifiers(opts.Modifiers, func() error {
+		return h.frame.page.Mouse.click(p.X, p.Y, opts.ToMouseClickOptions())
+	})
 }
 
 func (h *ElementHandle) defaultTimeout() time.Duration {
-	return time.Duration(h.frame.manager.timeoutSettings.timeout()) * time.Second
+	return h.frame.manager.timeoutSettings.timeout()
 }
 
 func (h *ElementHandle) dispatchEvent(_ context.Context, typ string, eventInit goja.Value) (interface{}, error) {
@@ -262,6 +269,58 @@ func (h *ElementHandle) dispatchEvent(_ context.Context, typ string, eventInit g
 	return nil, err
 }
 
+// clear empties the input or textarea element by selecting its value and
+// deleting it with the keyboard, so that the page receives the same events
+// as when a user clears it.
+func (h *ElementHandle) clear(apiCtx context.Context) error {
+	fn := `
+		(node) => {
+			if (node.nodeName !== 'INPUT' && node.nodeName !== 'TEXTAREA') {
+				return 'error:notclearable';
+			}
+			return 'done';
+		}
+	`
+	opts := evalOptions{
+		forceCallable: true,
+		returnByValue: true,
+	}
+	result, err := h.eval(apiCtx, opts, fn)
+	if err != nil {
+		return err
+	}
+	if v, ok := result.(goja.Value); ok && v.String() != resultDone {
+		return errorFromDOMError(v.String())
+	}
+
+	editable, err := h.checkElementState(apiCtx, "editable")
+	if err != nil {
+		return err
+	}
+	if !*editable {
+		return errors.New("element is not editable")
+	}
+	if err := h.focus(apiCtx, true); err != nil {
+		return err
+	}
+	if err := h.selectText(apiCtx); err != nil {
+		return err
+	}
+	if err := h.frame.page.Keyboard.press("Delete", NewKeyboardOptions()); err != nil {
+		return err
+	}
+
+	value, err := h.inputValue(apiCtx)
+	if err != nil {
+		return err
+	}
+	if value != "" {
+		return errors.New("clearing the element did not empty its value")
+	}
+
+	return nil
+}
+
 func (h *ElementHandle) fill(_ context.Context, value string) error {
 	fn := `
 		(node, injected, value) => {
@@ -324,8 +383,11 @@ func (h *ElementHandle) getAttribute(apiCtx context.Context, name string) (inter
 	return h.eval(apiCtx, opts, js)
 }
 
-func (h *ElementHandle) hover(apiCtx context.Context, p *Position) error {
-	return h.frame.page.Mouse.move(p.X, p.Y, NewMouseMoveOptions())
+// hover moves the mouse to the position with the modifiers held down.
+func (h *ElementHandle) hover(p *Position, modifiers []string) error {
+	return h.frame.page.Keyboard.withModifiers(modifiers, func() error {
+		return h.frame.page.Mouse.move(p.X, p.Y, NewMouseMoveOptions())
+	})
 }
 
 func (h *ElementHandle) innerHTML(apiCtx context.Context) (interface{}, error) {
@@ -354,20 +416,41 @@ func (h *ElementHandle) innerText(apiCtx context.Context) (interface{}, error) {
 	return h.eval(apiCtx, opts, js)
 }
 
-func (h *ElementHandle) inputValue(apiCtx context.Context) (interface{}, error) {
-	js := `
-		(element) => {
-			if (element.nodeType !== Node.ELEMENT_NODE || (element.nodeName !== 'INPUT' && element.nodeName !== 'TEXTAREA' && element.nodeName !== 'SELECT')) {
-        			throw Error('Node is not an <input>, <textarea> or <select> element');
+// inputValue returns the live value of the input, textarea, or select
+// element, or of the control of the label element.
+func (h *ElementHandle) inputValue(apiCtx context.Context) (string, error) {
+	fn := `
+		(node, injected) => {
+			const element = injected._retarget(node, "follow-label");
+			if (!element) {
+				return { error: "error:notconnected" };
+			}
+			if (!["INPUT", "TEXTAREA", "SELECT"].includes(element.nodeName)) {
+				return { error: "error:hasnovalue" };
 			}
-			return element.value;
+			return { value: element.value };
 		}
 	`
 	opts := evalOptions{
 		forceCallable: true,
 		returnByValue: true,
 	}
-	return h.eval(apiCtx, opts, js)
+	result, err := h.evalWithScript(apiCtx, opts, fn)
+	if err != nil {
+		return "", err
+	}
+	v, ok := result.(goja.Value)
+	if !ok {
+		return "", fmt.Errorf("unexpected type %T", result)
+	}
+	// the value is returned in an object, so that it can't be mistaken
+	// for an error.
+	obj := v.ToObject(h.execCtx.vu.Runtime())
+	if e := obj.Get("error"); gojaValueExists(e) {
+		return "", errorFromDOMError(e.String())
+	}
+
+	return obj.Get("value").String(), nil
 }
 
 func (h *ElementHandle) isChecked(apiCtx context.Context, timeout time.Duration) (bool, error) {
@@ -456,6 +539,13 @@ func (h *ElementHandle) ownerFrame(apiCtx context.Context) *Frame {
 	return nil
 }
 
+// scrollIntoViewIfNeeded scrolls the element into the viewport, unless it's
+// already fully visible. It fails if the element is detached, or has no
+// layout to scroll to.
+func (h *ElementHandle) scrollIntoViewIfNeeded(apiCtx context.Context) error {
+	return h.scrollRectIntoViewIfNeeded(apiCtx, nil)
+}
+
 func (h *ElementHandle) scrollRectIntoViewIfNeeded(apiCtx context.Context, rect *dom.Rect) error {
 	action := dom.ScrollIntoViewIfNeeded().WithObjectID(h.remoteObject.ObjectID).WithRect(rect)
 	err := action.Do(cdp.WithExecutor(apiCtx, h.session))
@@ -484,53 +574,76 @@ func (h *ElementHandle) press(apiCtx context.Context, key string, opts *Keyboard
 }
 
 func (h *ElementHandle) selectOption(apiCtx context.Context, values goja.Value) (interface{}, error) {
-	convertSelectOptionValues := func(values goja.Value) ([]interface{}, error) {
-		if goja.IsNull(values) || goja.IsUndefined(values) {
-			return nil, nil
+	options, handles, err := h.selectOptionValues(values)
+	if err != nil {
+		return nil, err
+	}
+
+	// the handles are passed as separate arguments as they can't be
+	// serialized in the options.
+	fn := `
+		(node, injected, options, ...handles) => {
+			options = options.map((o) => o.handle === undefined ? o : handles[o.handle]);
+			return injected.selectOptions(node, options);
 		}
+	`
+	opts := evalOptions{
+		forceCallable: true,
+		returnByValue: true,
+	}
+	result, err := h.evalWithScript(apiCtx, opts, fn, append([]interface{}{options}, handles...)...)
+	if err != nil {
+		return nil, err
+	}
+	switch result := result.(type) {
+	case string: // An error happened (returned as "error:..." from JS)
+		return nil, errorFromDOMError(result)
+	case []interface{}:
+		values := make([]string, 0, len(result))
+		for _, v := range result {
+			values = append(values, fmt.Sprint(v))
+		}
+		return values, nil
+	}
+	return nil, fmt.Errorf("unexpected selected options type %T", result)
+}
 
-		var (
-			opts []interface{}
-			t    = values.Export()
-			rt   = h.execCtx.vu.Runtime()
-		)
-		switch values.ExportType().Kind() {
-		case reflect.Map:
-			s := reflect.ValueOf(t)
-			for i := 0; i < s.Len(); i++ {
-				item := s.Index(i)
-				switch item.Kind() {
-				case reflect.TypeOf(nil).Kind():
-					return nil, fmt.Errorf("options[%d]: expected object, got null", i)
-				case reflect.TypeOf(&ElementHandle{}).Kind():
-					opts = append(opts, t.(*ElementHandle))
-				case reflect.TypeOf(goja.Object{}).Kind():
-					obj := values.ToObject(rt)
-					opt := SelectOption{}
-					for _, k := range obj.Keys() {
-						switch k {
-						case "value":
-							opt.Value = new(string)
-							*opt.Value = obj.Get(k).String()
-						case "label":
-							opt.Label = new(string)
-							*opt.Label = obj.Get(k).String()
-						case "index":
-							opt.Index = new(int64)
-							*opt.Index = obj.Get(k).ToInteger()
-						}
-					}
-					opts = append(opts, &opt)
-				case reflect.String:
-					opt := SelectOption{Value: new(string)}
-					*opt.Value = item.String()
-					opts = append(opts, &opt)
-				}
-			}
-		case reflect.TypeOf(&ElementHandle{}).Kind():
-			opts = append(opts, t.(*ElementHandle))
-		case reflect.TypeOf(goja.Object{}).Kind():
-			obj := values.ToObject(rt)
+// selectOptionValues converts the values of selectOption to the options
+// of the injected script, and the element handles of the options. The
+// values can be a string matching the value or the label of an option, an
+// object with the value, label, or index of an option, an element handle of
+// an option, or an array of them.
+func (h *ElementHandle) selectOptionValues(values goja.Value) ([]interface{}, []interface{}, error) {
+	var (
+		rt      = h.execCtx.vu.Runtime()
+		items   []goja.Value
+		options = []interface{}{}
+		handles []interface{}
+	)
+	switch {
+	case !gojaValueExists(values):
+		// no values deselect all the options.
+		return options, nil, nil
+	case values.ExportType().Kind() == reflect.Slice:
+		obj := values.ToObject(rt)
+		for _, k := range obj.Keys() {
+			items = append(items, obj.Get(k))
+		}
+	default:
+		items = []goja.Value{values}
+	}
+
+	for i, item := range items {
+		switch v := item.Export().(type) {
+		case nil:
+			return nil, nil, fmt.Errorf("options[%d]: expected object, got null", i)
+		case *ElementHandle:
+			options = append(options, map[string]int{"handle": len(handles)})
+			handles = append(handles, v)
+		case string:
+			options = append(options, &SelectOption{ValueOrLabel: &v})
+		case map[string]interface{}:
+			obj := item.ToObject(rt)
 			opt := SelectOption{}
 			for _, k := range obj.Keys() {
 				switch k {
@@ -545,67 +658,99 @@ func (h *ElementHandle) selectOption(apiCtx context.Context, values goja.Value)
 					*opt.Index = obj.Get(k).ToInteger()
 				}
 			}
-			opts = append(opts, &opt)
-		case reflect.String:
-			opt := SelectOption{Value: new(string)}
-			*opt.Value = t.(string)
-			opts = append(opts, &opt)
+			options = append(options, &opt)
+		default:
+			return nil, nil, fmt.Errorf("options[%d]: expected string, object or element handle, got %T", i, v)
 		}
-
-		return opts, nil
-	}
-	convValues, err := convertSelectOptionValues(values)
-	if err != nil {
-		return nil, err
 	}
 
+	return options, handles, nil
+}
+
+func (h *ElementHandle) selectText(apiCtx context.Context) error {
 	fn := `
-		(node, injected, values) => {
-			return injected.selectOptions(node, values);
+		(node, injected) => {
+			return injected.selectText(node);
 		}
 	`
 	opts := evalOptions{
 		forceCallable: true,
-		returnByValue: false,
+		returnByValue: true,
 	}
-	result, err := h.evalWithScript(apiCtx, opts, fn, convValues)
+	result, err := h.evalWithScript(apiCtx, opts, fn)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	switch result := result.(type) {
-	case string: // An error happened (returned as "error:..." from JS)
+	case string: // Either we're done or an error happened (returned as "error:..." from JS)
 		if result != "done" {
-			return nil, errorFromDOMError(result)
+			return errorFromDOMError(result)
 		}
 	}
-	return result, nil
+	return nil
 }
 
-func (h *ElementHandle) selectText(apiCtx context.Context) error {
-	fn := `
-		(node, injected) => {
-			return injected.selectText(node);
+// setInputFiles sets the files of the file input element. Files on disk are
+// set through CDP, and in-memory files are set as a DataTransfer file list.
+func (h *ElementHandle) setInputFiles(apiCtx context.Context, files *inputFiles) error {
+	check := `
+		(node, count) => {
+			if (node.nodeName.toLowerCase() !== 'input' || node.type.toLowerCase() !== 'file') {
+				return 'error:notfileinput';
+			}
+			if (count > 1 && !node.multiple) {
+				return 'error:notmultiplefileinput';
+			}
+			return 'done';
 		}
 	`
 	opts := evalOptions{
 		forceCallable: true,
 		returnByValue: true,
 	}
-	result, err := h.evalWithScript(apiCtx, opts, fn)
+	result, err := h.eval(apiCtx, opts, check, files.len())
 	if err != nil {
 		return err
 	}
-	switch result := result.(type) {
-	case string: // Either we're done or an error happened (returned as "error:..." from JS)
-		if result != "done" {
-			return errorFromDOMError(result)
+	if v, ok := result.(goja.Value); ok && v.String() != resultDone {
+		return errorFromDOMError(v.String())
+	}
+
+	if len(files.payloads) == 0 {
+		// an empty list clears the selected files.
+		paths := files.paths
+		if paths == nil {
+			paths = []string{}
 		}
+		action := dom.SetFileInputFiles(paths).WithObjectID(h.remoteObject.ObjectID)
+		if err := action.Do(cdp.WithExecutor(apiCtx, h.session)); err != nil {
+			return fmt.Errorf("setting file input files: %w", err)
+		}
+		return nil
 	}
-	return nil
+
+	set := `
+		(node, payloads) => {
+			const dt = new DataTransfer();
+			for (const p of payloads) {
+				const bytes = Uint8Array.from(atob(p.buffer), c => c.charCodeAt(0));
+				dt.items.add(new File([bytes], p.name, { type: p.mimeType }));
+			}
+			node.files = dt.files;
+			node.dispatchEvent(new Event('input', { bubbles: true }));
+			node.dispatchEvent(new Event('change', { bubbles: true }));
+		}
+	`
+	_, err = h.eval(apiCtx, opts, set, files.payloads)
+
+	return err
 }
 
-func (h *ElementHandle) tap(apiCtx context.Context, p *Position) error {
-	return h.frame.page.Touchscreen.tap(p.X, p.Y)
+// tap taps at the position with the modifiers held down.
+func (h *ElementHandle) tap(p *Position, modifiers []string) error {
+	return h.frame.page.Keyboard.withModifiers(modifiers, func() error {
+		return h.frame.page.Touchscreen.tap(p.X, p.Y)
+	})
 }
 
 func (h *ElementHandle) textContent(apiCtx context.Context) (interface{}, error) {
@@ -635,17 +780,7 @@ func (h *ElementHandle) typ(apiCtx context.Context, text string, opts *KeyboardO
 
 func (h *ElementHandle) waitAndScrollIntoViewIfNeeded(apiCtx context.Context, force, noWaitAfter bool, timeout time.Duration) error {
 	fn := func(apiCtx context.Context, handle *ElementHandle) (interface{}, error) {
-		fn := `
-			(element) => {
-				element.scrollIntoViewIfNeeded(true);
-				return [window.scrollX, window.scrollY];
-			}
-		`
-		opts := evalOptions{
-			forceCallable: true,
-			returnByValue: true,
-		}
-		return h.eval(apiCtx, opts, fn)
+		return nil, handle.scrollIntoViewIfNeeded(apiCtx)
 	}
 	actFn := h.newAction([]string{"visible", "stable"}, fn, force, noWaitAfter, timeout)
 	_, err := call(h.ctx, actFn, timeout)
@@ -757,19 +892,30 @@ func (h *ElementHandle) Click(opts goja.Value) *goja.Promise {
 }
 
 func (h *ElementHandle) ContentFrame() api.Frame {
-	var (
-		node *cdp.Node
-		err  error
-	)
+	f, ok, err := h.contentFrame()
+	if err != nil {
+		k6ext.Panic(h.ctx, "%w", err)
+	}
+	if !ok || f == nil {
+		return nil
+	}
+	return f
+}
+
+// contentFrame returns the frame of the iframe element. It reports false if
+// the element isn't an iframe, and returns a nil frame if the frame isn't
+// attached yet.
+func (h *ElementHandle) contentFrame() (*Frame, bool, error) {
 	action := dom.DescribeNode().WithObjectID(h.remoteObject.ObjectID)
-	if node, err = action.Do(cdp.WithExecutor(h.ctx, h.session)); err != nil {
-		k6ext.Panic(h.ctx, "getting remote node %q: %w", h.remoteObject.ObjectID, err)
+	node, err := action.Do(cdp.WithExecutor(h.ctx, h.session))
+	if err != nil {
+		return nil, false, fmt.Errorf("getting remote node %q: %w", h.remoteObject.ObjectID, err)
 	}
 	if node == nil || node.FrameID == "" {
-		return nil
+		return nil, false, nil
 	}
 
-	return h.frame.manager.getFrameByID(node.FrameID)
+	return h.frame.manager.getFrameByID(node.FrameID), true, nil
 }
 
 func (h *ElementHandle) Dblclick(opts goja.Value) {
@@ -778,7 +924,7 @@ func (h *ElementHandle) Dblclick(opts goja.Value) {
 		k6ext.Panic(h.ctx, "parsing element double click options: %w", err)
 	}
 	fn := func(apiCtx context.Context, handle *ElementHandle, p *Position) (interface{}, error) {
-		return nil, handle.dblClick(p, actionOpts.ToMouseClickOptions())
+		return nil, handle.dblClick(p, actionOpts)
 	}
 	pointerFn := h.newPointerAction(fn, &actionOpts.ElementHandleBasePointerOptions)
 	_, err := call(h.ctx, pointerFn, actionOpts.Timeout)
@@ -855,7 +1001,7 @@ func (h *ElementHandle) Hover(opts goja.Value) {
 		k6ext.Panic(h.ctx, "parsing element hover options: %w", err)
 	}
 	fn := func(apiCtx context.Context, handle *ElementHandle, p *Position) (interface{}, error) {
-		return nil, handle.hover(apiCtx, p)
+		return nil, handle.hover(p, actionOpts.Modifiers)
 	}
 	pointerFn := h.newPointerAction(fn, &actionOpts.ElementHandleBasePointerOptions)
 	_, err := call(h.ctx, pointerFn, actionOpts.Timeout)
@@ -912,7 +1058,12 @@ func (h *ElementHandle) InputValue(opts goja.Value) string {
 	}
 	applySlowMo(h.ctx)
 
-	return gojaValueToString(h.ctx, v)
+	s, ok := v.(string)
+	if !ok {
+		k6ext.Panic(h.ctx, "getting element's input value: unexpected type %T", v)
+	}
+
+	return s
 }
 
 // IsChecked checks if a checkbox or radio is checked.
@@ -1155,15 +1306,22 @@ func (h *ElementHandle) setChecked(apiCtx context.Context, checked bool, p *Posi
 		return err
 	}
 
-	state, err = h.checkElementState(apiCtx, "checked")
-	if err != nil {
-		return err
-	}
-	if checked != *state {
-		return errors.New("clicking the checkbox did not change its state")
+	// The state can change asynchronously after the click, for example,
+	// by an event handler of the page, so wait for it within the timeout.
+	for {
+		state, err = h.checkElementState(apiCtx, "checked")
+		if err != nil {
+			return err
+		}
+		if checked == *state {
+			return nil
+		}
+		select {
+		case <-apiCtx.Done():
+			return errors.New("clicking the checkbox did not change its state")
+		case <-time.After(100 * time.Millisecond):
+		}
 	}
-
-	return nil
 }
 
 func (h *ElementHandle) Screenshot(opts goja.Value) goja.ArrayBuffer {
@@ -1181,6 +1339,8 @@ func (h *ElementHandle) Screenshot(opts goja.Value) goja.ArrayBuffer {
 	return rt.NewArrayBuffer(*buf)
 }
 
+// ScrollIntoViewIfNeeded waits for the element to be visible and stable, and
+// scrolls it into the viewport if it's not already fully visible.
 func (h *ElementHandle) ScrollIntoViewIfNeeded(opts goja.Value) {
 	actionOpts := NewElementHandleBaseOptions(h.defaultTimeout())
 	if err := actionOpts.Parse(h.ctx, opts); err != nil {
@@ -1194,7 +1354,6 @@ func (h *ElementHandle) ScrollIntoViewIfNeeded(opts goja.Value) {
 }
 
 func (h *ElementHandle) SelectOption(values goja.Value, opts goja.Value) []string {
-	rt := h.execCtx.vu.Runtime()
 	actionOpts := NewElementHandleBaseOptions(h.defaultTimeout())
 	if err := actionOpts.Parse(h.ctx, opts); err != nil {
 		k6ext.Panic(h.ctx, "parsing selectOption options: %w", err)
@@ -1207,14 +1366,15 @@ func (h *ElementHandle) SelectOption(values goja.Value, opts goja.Value) []strin
 	if err != nil {
 		k6ext.Panic(h.ctx, "selecting options: %w", err)
 	}
-	var returnVal []string
-	if err := rt.ExportTo(asGojaValue(h.ctx, selectedOptions), &returnVal); err != nil {
-		k6ext.Panic(h.ctx, "unpacking selected options: %w", err)
+
+	selected, ok := selectedOptions.([]string)
+	if !ok {
+		k6ext.Panic(h.ctx, "unexpected selected options type %T", selectedOptions)
 	}
 
 	applySlowMo(h.ctx)
 
-	return returnVal
+	return selected
 }
 
 func (h *ElementHandle) SelectText(opts goja.Value) {
@@ -1233,9 +1393,26 @@ func (h *ElementHandle) SelectText(opts goja.Value) {
 	applySlowMo(h.ctx)
 }
 
+// SetInputFiles sets the files of the file input element. The files can be
+// file paths, or objects with name, mimeType and buffer properties. An empty
+// array clears the selected files.
 func (h *ElementHandle) SetInputFiles(files goja.Value, opts goja.Value) {
-	// TODO: implement
-	k6ext.Panic(h.ctx, "ElementHandle.setInputFiles() has not been implemented yet")
+	actionOpts := NewElementHandleBaseOptions(h.defaultTimeout())
+	if err := actionOpts.Parse(h.ctx, opts); err != nil {
+		k6ext.Panic(h.ctx, "parsing setInputFiles options: %w", err)
+	}
+	parsed, err := parseInputFiles(h.ctx, files)
+	if err != nil {
+		k6ext.Panic(h.ctx, "parsing setInputFiles files: %w", err)
+	}
+	fn := func(apiCtx context.Context, handle *ElementHandle) (interface{}, error) {
+		return nil, handle.setInputFiles(apiCtx, parsed)
+	}
+	actFn := h.newAction([]string{}, fn, actionOpts.Force, actionOpts.NoWaitAfter, actionOpts.Timeout)
+	if _, err := call(h.ctx, actFn, actionOpts.Timeout); err != nil {
+		k6ext.Panic(h.ctx, "setting input files: %w", errorFromDOMError(err))
+	}
+	applySlowMo(h.ctx)
 }
 
 func (h *ElementHandle) Tap(opts goja.Value) {
@@ -1245,8 +1422,13 @@ func (h *ElementHandle) Tap(opts goja.Value) {
 		k6ext.Panic(h.ctx, "parsing tap options: %w", err)
 	}
 
+	// tapping can't succeed without touch emulation, so there's no need to
+	// wait for the element.
+	if err := h.frame.page.Touchscreen.ensureTouch(); err != nil {
+		k6ext.Panic(h.ctx, "tapping element: %w", err)
+	}
 	fn := func(apiCtx context.Context, handle *ElementHandle, p *Position) (interface{}, error) {
-		return nil, handle.tap(apiCtx, p)
+		return nil, handle.tap(p, parsedOpts.Modifiers)
 	}
 	pointerFn := h.newPointerAction(fn, &parsedOpts.ElementHandleBasePointerOptions)
 	_, err = call(h.ctx, pointerFn, parsedOpts.Timeout)
@@ -1528,8 +1710,11 @@ func errorFromDOMError(v interface{}) error {
 		"error:notvaliddate":           "malformed value",
 		"error:notinput":               "node is not an HTMLInputElement",
 		"error:hasnovalue":             "node is not an HTMLInputElement or HTMLTextAreaElement or HTMLSelectElement",
+		"error:notclearable":           "element is not an <input> or <textarea> element",
 		"error:notselect":              "element is not a <select> element",
+		"error:optionsnotfound":        "options to select not found",
 		"error:notcheckbox":            "not a checkbox or radio button",
+		"error:notfileinput":           "element is not an <input type=file> element",
 		"error:notmultiplefileinput":   "non-multiple file input can only accept single file",
 		"error:strictmodeviolation":    "strict mode violation, multiple elements returned for selector query",
 		"error:notqueryablenode":       "node is not queryable",
@@ -101,11 +101,13 @@ type ElementHandlePressOptions struct {
 }
 
 type ElementHandleScreenshotOptions struct {
-	Path           string        `json:"path"`
-	Format         ImageFormat   `json:"format"`
-	OmitBackground bool          `json:"omitBackground"`
-	Quality        int64         `json:"quality"`
-	Timeout        time.Duration `json:"timeout"`
+	Path           string               `json:"path"`
+	Format         ImageFormat          `json:"format"`
+	OmitBackground bool                 `json:"omitBackground"`
+	Quality        int64                `json:"quality"`
+	Scale          ScreenshotScale      `json:"scale"`
+	Animations     ScreenshotAnimations `json:"animations"`
+	Timeout        time.Duration        `json:"timeout"`
 }
 
 type ElementHandleSetCheckedOptions struct {
@@ -175,7 +177,7 @@ func (o *ElementHandleBasePointerOptions) Parse(ctx context.Context, opts goja.V
 			case "position":
 				var p map[string]float64
 				o.Position = &Position{}
-				if rt.ExportTo(opts.Get(k), &p) != nil {
+				if rt.ExportTo(opts.Get(k), &p) == nil {
 					o.Position.X = p["x"]
 					o.Position.Y = p["y"]
 				}
@@ -352,6 +354,8 @@ func NewElementHandleScreenshotOptions(defaultTimeout time.Duration) *ElementHan
 		Format:         ImageFormatPNG,
 		OmitBackground: false,
 		Quality:        100,
+		Scale:          ScreenshotScaleDevice,
+		Animations:     ScreenshotAnimationsAllow,
 		Timeout:        defaultTimeout,
 	}
 }
@@ -363,12 +367,24 @@ func (o *ElementHandleScreenshotOptions) Parse(ctx context.Context, opts goja.Va
 		opts := opts.ToObject(rt)
 		for _, k := range opts.Keys() {
 			switch k {
+			case "animations":
+				a, err := parseScreenshotAnimations(opts.Get(k).String())
+				if err != nil {
+					return err
+				}
+				o.Animations = a
 			case "omitBackground":
 				o.OmitBackground = opts.Get(k).ToBoolean()
 			case "path":
 				o.Path = opts.Get(k).String()
 			case "quality":
 				o.Quality = opts.Get(k).ToInteger()
+			case "scale":
+				sc, err := parseScreenshotScale(opts.Get(k).String())
+				if err != nil {
+					return err
+				}
+				o.Scale = sc
 			case "type":
 				if f, ok := imageFormatToID[opts.Get(k).String()]; ok {
 					o.Format = f
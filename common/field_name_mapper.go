@@ -32,8 +32,10 @@ type FieldNameMapper struct {
 }
 
 var methodNameExceptions = map[string]string{
-	"Query":    "$",
-	"QueryAll": "$$",
+	"EvalOnSelector":    "$eval",
+	"EvalOnSelectorAll": "$$eval",
+	"Query":             "$",
+	"QueryAll":          "$$",
 }
 
 // NewFieldNameMapper creates a new field name mapper to add some method name
@@ -0,0 +1,169 @@
+package common
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"mime"
+	"path/filepath"
+	"reflect"
+
+	"github.com/grafana/xk6-browser/api"
+	"github.com/grafana/xk6-browser/k6ext"
+	"github.com/grafana/xk6-browser/log"
+
+	"github.com/dop251/goja"
+)
+
+// Ensure FileChooser implements the api.FileChooser interface.
+var _ api.FileChooser = &FileChooser{}
+
+// FileChooser is a file chooser opened by a page. The file chooser dialog
+// is intercepted and the files are set on its file input element.
+type FileChooser struct {
+	ctx      context.Context
+	page     *Page
+	element  *ElementHandle
+	multiple bool
+
+	logger *log.Logger
+}
+
+// NewFileChooser returns a new file chooser for the file input element.
+func NewFileChooser(ctx context.Context, p *Page, element *ElementHandle, multiple bool, l *log.Logger) *FileChooser {
+	return &FileChooser{
+		ctx:      ctx,
+		page:     p,
+		element:  element,
+		multiple: multiple,
+		logger:   l,
+	}
+}
+
+// Element returns the file input element that opened the file chooser.
+func (c *FileChooser) Element() api.ElementHandle {
+	return c.element
+}
+
+// IsMultiple returns whether the file chooser accepts multiple files.
+func (c *FileChooser) IsMultiple() bool {
+	return c.multiple
+}
+
+// Page returns the page that opened the file chooser.
+func (c *FileChooser) Page() api.Page {
+	return c.page
+}
+
+// SetFiles sets the files of the file input element.
+func (c *FileChooser) SetFiles(files goja.Value, opts goja.Value) {
+	c.logger.Debugf("FileChooser:SetFiles", "multiple:%t", c.multiple)
+
+	c.element.SetInputFiles(files, opts)
+}
+
+// inputFilePayload is an in-memory file set on a file input element.
+type inputFilePayload struct {
+	Name     string `json:"name"`
+	MimeType string `json:"mimeType"`
+	// Buffer is the base64 encoded content of the file.
+	Buffer string `json:"buffer"`
+}
+
+// inputFiles are the files set on a file input element, either as paths
+// on disk or as in-memory payloads.
+type inputFiles struct {
+	paths    []string
+	payloads []*inputFilePayload
+}
+
+func (f *inputFiles) len() int {
+	return len(f.paths) + len(f.payloads)
+}
+
+// parseInputFiles parses a file or an array of files, where each file is
+// either a path or an object with name, mimeType and buffer properties.
+// Paths and objects can't be mixed.
+func parseInputFiles(ctx context.Context, files goja.Value) (*inputFiles, error) {
+	if !gojaValueExists(files) {
+		return nil, errors.New("files are required")
+	}
+
+	rt := k6ext.Runtime(ctx)
+	var values []goja.Value
+	if obj := files.ToObject(rt); obj.ClassName() == "Array" {
+		for _, k := range obj.Keys() {
+			values = append(values, obj.Get(k))
+		}
+	} else {
+		values = []goja.Value{files}
+	}
+
+	var f inputFiles
+	for _, v := range values {
+		if s, ok := v.Export().(string); ok {
+			path, err := filepath.Abs(s)
+			if err != nil {
+				return nil, fmt.Errorf("resolving %q: %w", s, err)
+			}
+			f.paths = append(f.paths, path)
+			continue
+		}
+		p, err := parseInputFilePayload(rt, v)
+		if err != nil {
+			return nil, err
+		}
+		f.payloads = append(f.payloads, p)
+	}
+	if len(f.paths) > 0 && len(f.payloads) > 0 {
+		return nil, errors.New("files must be either all paths or all objects")
+	}
+
+	return &f, nil
+}
+
+func parseInputFilePayload(rt *goja.Runtime, v goja.Value) (*inputFilePayload, error) {
+	if !gojaValueExists(v) || v.ExportType().Kind() != reflect.Map {
+		return nil, errors.New("file must be a path or an object with name, mimeType and buffer")
+	}
+	obj := v.ToObject(rt)
+
+	var p inputFilePayload
+	if name := obj.Get("name"); gojaValueExists(name) {
+		p.Name = name.String()
+	}
+	if p.Name == "" {
+		return nil, errors.New("file name is required")
+	}
+	if mt := obj.Get("mimeType"); gojaValueExists(mt) {
+		p.MimeType = mt.String()
+	}
+	if p.MimeType == "" {
+		p.MimeType = mime.TypeByExtension(filepath.Ext(p.Name))
+	}
+	if p.MimeType == "" {
+		p.MimeType = "application/octet-stream"
+	}
+
+	var (
+		buf    []byte
+		buffer interface{}
+	)
+	if b := obj.Get("buffer"); gojaValueExists(b) {
+		buffer = b.Export()
+	}
+	switch b := buffer.(type) {
+	case goja.ArrayBuffer:
+		buf = b.Bytes()
+	case []byte:
+		buf = b
+	case string:
+		buf = []byte(b)
+	default:
+		return nil, fmt.Errorf("buffer of file %q must be an ArrayBuffer or a string", p.Name)
+	}
+	p.Buffer = base64.StdEncoding.EncodeToString(buf)
+
+	return &p, nil
+}
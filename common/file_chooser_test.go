@@ -0,0 +1,67 @@
+package common
+
+import (
+	"encoding/base64"
+	"path/filepath"
+	"testing"
+
+	"github.com/grafana/xk6-browser/k6ext/k6test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseInputFiles(t *testing.T) {
+	t.Parallel()
+
+	t.Run("paths", func(t *testing.T) {
+		t.Parallel()
+
+		vu := k6test.NewVU(t)
+		f, err := parseInputFiles(vu.Context(), vu.ToGojaValue([]string{"a.txt", "/tmp/b.txt"}))
+		require.NoError(t, err)
+
+		abs, err := filepath.Abs("a.txt")
+		require.NoError(t, err)
+		assert.Equal(t, []string{abs, "/tmp/b.txt"}, f.paths)
+		assert.Empty(t, f.payloads)
+	})
+
+	t.Run("payload", func(t *testing.T) {
+		t.Parallel()
+
+		vu := k6test.NewVU(t)
+		v, err := vu.Runtime().RunString(`({ name: 'data.json', buffer: new ArrayBuffer(2) })`)
+		require.NoError(t, err)
+		f, err := parseInputFiles(vu.Context(), v)
+		require.NoError(t, err)
+
+		require.Len(t, f.payloads, 1)
+		assert.Equal(t, &inputFilePayload{
+			Name:     "data.json",
+			MimeType: "application/json",
+			Buffer:   base64.StdEncoding.EncodeToString([]byte{0, 0}),
+		}, f.payloads[0])
+		assert.Equal(t, 1, f.len())
+	})
+
+	t.Run("err/mixed", func(t *testing.T) {
+		t.Parallel()
+
+		vu := k6test.NewVU(t)
+		v, err := vu.Runtime().RunString(`['a.txt', { name: 'b.txt', mimeType: 'text/plain', buffer: 'b' }]`)
+		require.NoError(t, err)
+		_, err = parseInputFiles(vu.Context(), v)
+		assert.EqualError(t, err, "files must be either all paths or all objects")
+	})
+
+	t.Run("err/no_buffer", func(t *testing.T) {
+		t.Parallel()
+
+		vu := k6test.NewVU(t)
+		v, err := vu.Runtime().RunString(`({ name: 'b.txt' })`)
+		require.NoError(t, err)
+		_, err = parseInputFiles(vu.Context(), v)
+		assert.EqualError(t, err, `buffer of file "b.txt" must be an ArrayBuffer or a string`)
+	})
+}
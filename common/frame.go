@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -16,6 +17,7 @@ import (
 
 	"github.com/chromedp/cdproto/cdp"
 	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/overlay"
 	"github.com/chromedp/cdproto/runtime"
 	"github.com/dop251/goja"
 )
@@ -41,7 +43,7 @@ type Frame struct {
 	parentFrame *Frame
 
 	childFramesMu sync.RWMutex
-	childFrames   map[api.Frame]bool
+	childFrames   []*Frame
 
 	propertiesMu sync.RWMutex
 	id           cdp.FrameID
@@ -65,7 +67,14 @@ type Frame struct {
 
 	loadingStartedTime time.Time
 
-	networkIdleCh chan struct{}
+	networkIdleMu    sync.Mutex
+	networkIdleTimer *time.Timer
+
+	// highlightMu guards the highlight of the elements of the frame, which
+	// is hidden by highlightTimer or by the next highlight.
+	highlightMu     sync.Mutex
+	highlightTimer  *time.Timer
+	hideHighlightFn func()
 
 	inflightRequestsMu sync.RWMutex
 	inflightRequests   map[network.RequestID]bool
@@ -98,7 +107,6 @@ func NewFrame(
 		page:                   m.page,
 		manager:                m,
 		parentFrame:            parentFrame,
-		childFrames:            make(map[api.Frame]bool),
 		id:                     frameID,
 		vu:                     k6ext.GetVU(ctx),
 		lifecycleEvents:        make(map[LifecycleEvent]bool),
@@ -106,7 +114,6 @@ func NewFrame(
 		inflightRequests:       make(map[network.RequestID]bool),
 		executionContexts:      make(map[executionWorld]frameExecutionContext),
 		currentDocument:        &DocumentInfo{},
-		networkIdleCh:          make(chan struct{}),
 		log:                    log,
 	}
 }
@@ -119,7 +126,12 @@ func (f *Frame) addChildFrame(child *Frame) {
 	f.childFramesMu.Lock()
 	defer f.childFramesMu.Unlock()
 
-	f.childFrames[child] = true
+	for _, c := range f.childFrames {
+		if c == child {
+			return
+		}
+	}
+	f.childFrames = append(f.childFrames, child)
 }
 
 func (f *Frame) addRequest(id network.RequestID) {
@@ -131,13 +143,18 @@ func (f *Frame) addRequest(id network.RequestID) {
 	f.inflightRequests[id] = true
 }
 
-func (f *Frame) deleteRequest(id network.RequestID) {
+// deleteRequest removes the request from the inflight requests, and
+// reports whether it was inflight.
+func (f *Frame) deleteRequest(id network.RequestID) bool {
 	f.log.Debugf("Frame:deleteRequest", "fid:%s furl:%q rid:%s", f.ID(), f.URL(), id)
 
 	f.inflightRequestsMu.Lock()
 	defer f.inflightRequestsMu.Unlock()
 
+	ok := f.inflightRequests[id]
 	delete(f.inflightRequests, id)
+
+	return ok
 }
 
 func (f *Frame) inflightRequestsLen() int {
@@ -156,6 +173,8 @@ func (f *Frame) clearLifecycle() {
 		for e := range f.lifecycleEvents {
 			f.lifecycleEvents[e] = false
 		}
+		// the lifecycle is cleared when the frame commits a new document.
+		f.lifecycleEvents[LifecycleEventCommit] = true
 	}
 	f.lifecycleEventsMu.Unlock()
 
@@ -202,8 +221,7 @@ func (f *Frame) recalculateLifecycle() {
 	// Only consider a life cycle event as fired if it has triggered for all of subtree.
 	f.childFramesMu.RLock()
 	{
-		for child := range f.childFrames {
-			cf := child.(*Frame)
+		for _, cf := range f.childFrames {
 			// a precaution for preventing a deadlock in *Frame.childFramesMu
 			if cf == f {
 				continue
@@ -261,12 +279,18 @@ func (f *Frame) recalculateLifecycle() {
 func (f *Frame) stopNetworkIdleTimer() {
 	f.log.Debugf("Frame:stopNetworkIdleTimer", "fid:%s furl:%q", f.ID(), f.URL())
 
-	select {
-	case f.networkIdleCh <- struct{}{}:
-	default:
+	f.networkIdleMu.Lock()
+	defer f.networkIdleMu.Unlock()
+
+	if f.networkIdleTimer != nil {
+		f.networkIdleTimer.Stop()
+		f.networkIdleTimer = nil
 	}
 }
 
+// startNetworkIdleTimer fires the networkidle lifecycle event when the frame
+// has no inflight requests for LifeCycleNetworkIdleTimeout. Starting the
+// timer again, or stopping it, cancels the previous one.
 func (f *Frame) startNetworkIdleTimer() {
 	f.log.Debugf("Frame:startNetworkIdleTimer", "fid:%s furl:%q", f.ID(), f.URL())
 
@@ -274,16 +298,44 @@ func (f *Frame) startNetworkIdleTimer() {
 		return
 	}
 
-	f.stopNetworkIdleTimer()
+	f.networkIdleMu.Lock()
+	defer f.networkIdleMu.Unlock()
 
-	go func() {
-		select {
-		case <-f.ctx.Done():
-		case <-f.networkIdleCh:
-		case <-time.After(LifeCycleNetworkIdleTimeout):
-			f.manager.frameLifecycleEvent(cdp.FrameID(f.ID()), LifecycleEventNetworkIdle)
+	if f.networkIdleTimer != nil {
+		f.networkIdleTimer.Stop()
+	}
+	var timer *time.Timer
+	timer = time.AfterFunc(LifeCycleNetworkIdleTimeout, func() {
+		f.networkIdleMu.Lock()
+		// a timer that has been stopped or replaced may still fire
+		// if it has already expired.
+		current := f.networkIdleTimer == timer
+		if current {
+			f.networkIdleTimer = nil
 		}
-	}()
+		f.networkIdleMu.Unlock()
+
+		if !current || f.ctx.Err() != nil || f.inflightRequestsLen() > 0 {
+			return
+		}
+		f.manager.frameLifecycleEvent(cdp.FrameID(f.ID()), LifecycleEventNetworkIdle)
+	})
+	f.networkIdleTimer = timer
+}
+
+// ignoresForNetworkIdle reports whether the request should not keep the
+// frame from becoming network idle. These are the long-lived connections,
+// such as WebSockets and server-sent events, and the requests matching the
+// networkIdleIgnore option of the browser context.
+func (f *Frame) ignoresForNetworkIdle(req *Request) bool {
+	switch network.ResourceType(req.ResourceType()) {
+	case network.ResourceTypeWebSocket, network.ResourceTypeEventSource:
+		return true
+	}
+	if f.page == nil || f.page.browserCtx == nil || f.page.browserCtx.opts == nil {
+		return false
+	}
+	return f.page.browserCtx.opts.ignoresForNetworkIdle(req.URL())
 }
 
 func (f *Frame) detach() {
@@ -304,7 +356,232 @@ func (f *Frame) detach() {
 }
 
 func (f *Frame) defaultTimeout() time.Duration {
-	return time.Duration(f.manager.timeoutSettings.timeout()) * time.Second
+	return f.manager.timeoutSettings.timeout()
+}
+
+// count returns the number of elements matching the selector at the time
+// of the call, without waiting for any elements to match.
+func (f *Frame) count(selector string) (int, error) {
+	frame, selector, err := f.resolveFrameSelector(selector, f.defaultTimeout())
+	if err != nil {
+		return 0, err
+	}
+	if frame != f {
+		return frame.count(selector)
+	}
+
+	document, err := f.document()
+	if err != nil {
+		return 0, fmt.Errorf("getting document: %w", err)
+	}
+	parsedSelector, err := NewSelector(selector)
+	if err != nil {
+		return 0, fmt.Errorf("parsing selector %q: %w", selector, err)
+	}
+	js := `
+		(node, injected, selector) => {
+			const elements = injected.querySelectorAll(selector, node || document);
+			return typeof elements === "string" ? elements : elements.length;
+		}
+	`
+	opts := evalOptions{
+		forceCallable: true,
+		returnByValue: true,
+	}
+	v, err := document.evalWithScript(f.ctx, opts, js, parsedSelector)
+	if err != nil {
+		return 0, errorFromDOMError(err)
+	}
+	gv, ok := v.(goja.Value)
+	if !ok {
+		return 0, fmt.Errorf("counting elements of %q: unexpected type %T", selector, v)
+	}
+	if s, ok := gv.Export().(string); ok {
+		return 0, errorFromDOMError(s)
+	}
+
+	return int(gv.ToInteger()), nil
+}
+
+// elementState reports whether the first element that matches the selector
+// is in the given state. It doesn't wait for the element, and checks the
+// current state with a single evaluation. If no element matches, it reports
+// false for visible and true for hidden, and returns an error for the
+// other states. The timeout bounds entering the frames of the selector and
+// the evaluation.
+func (f *Frame) elementState(selector, state string, strict bool, timeout time.Duration) (bool, error) {
+	frame, selector, err := f.resolveFrameSelector(selector, timeout)
+	if err != nil {
+		return false, err
+	}
+	if frame != f {
+		return frame.elementState(selector, state, strict, timeout)
+	}
+
+	ctx, cancel := context.WithTimeout(f.ctx, timeout)
+	defer cancel()
+
+	document, err := f.document()
+	if err != nil {
+		return false, fmt.Errorf("getting document: %w", err)
+	}
+	parsedSelector, err := NewSelector(selector)
+	if err != nil {
+		return false, fmt.Errorf("parsing selector %q: %w", selector, err)
+	}
+	js := `
+		(node, injected, selector, strict, state) => {
+			const element = injected.querySelector(selector, node || document, strict);
+			if (element === null || typeof element === "string") {
+				return element;
+			}
+			return injected.checkElementState(element, state);
+		}
+	`
+	opts := evalOptions{
+		forceCallable: true,
+		returnByValue: true,
+	}
+	v, err := document.evalWithScript(ctx, opts, js, parsedSelector, strict, state)
+	if err != nil {
+		return false, errorFromDOMError(err)
+	}
+	gv, ok := v.(goja.Value)
+	if !ok {
+		return false, fmt.Errorf("checking is %q %s: unexpected type %T", selector, state, v)
+	}
+	switch r := gv.Export().(type) {
+	case nil:
+		switch state {
+		case "visible":
+			return false, nil
+		case "hidden":
+			return true, nil
+		}
+		return false, fmt.Errorf("checking is %q %s: no element matches the selector", selector, state)
+	case string:
+		return false, errorFromDOMError(r)
+	case bool:
+		return r, nil
+	}
+
+	return false, fmt.Errorf("checking is %q %s: unexpected result %v", selector, state, gv)
+}
+
+// highlightAttribute marks the elements that Locator.highlight highlights.
+const highlightAttribute = "data-k6-browser-highlight"
+
+// highlight highlights the elements matching the selector with the
+// Overlay.highlightNode CDP command until LocatorHighlightTimeout passes or
+// the frame highlights other elements. The overlay can only highlight the
+// elements matching a CSS selector, so the matching elements are marked
+// with highlightAttribute while they're highlighted.
+func (f *Frame) highlight(selector string) error {
+	frame, selector, err := f.resolveFrameSelector(selector, f.defaultTimeout())
+	if err != nil {
+		return err
+	}
+	if frame != f {
+		return frame.highlight(selector)
+	}
+
+	// the previous highlight is hidden first, so that its elements are
+	// unmarked even if they're in another document.
+	f.hideHighlight()
+
+	document, err := f.document()
+	if err != nil {
+		return fmt.Errorf("getting document: %w", err)
+	}
+	parsedSelector, err := NewSelector(selector)
+	if err != nil {
+		return fmt.Errorf("parsing selector %q: %w", selector, err)
+	}
+	js := `
+		(node, injected, selector, attribute) => {
+			const elements = injected.querySelectorAll(selector, node || document);
+			if (typeof elements === "string") {
+				return elements;
+			}
+			for (const element of elements) {
+				element.setAttribute(attribute, "");
+			}
+			return elements.length;
+		}
+	`
+	opts := evalOptions{
+		forceCallable: true,
+		returnByValue: true,
+	}
+	v, err := document.evalWithScript(f.ctx, opts, js, parsedSelector, highlightAttribute)
+	if err != nil {
+		return errorFromDOMError(err)
+	}
+	if gv, ok := v.(goja.Value); ok {
+		if s, ok := gv.Export().(string); ok {
+			return errorFromDOMError(s)
+		}
+	}
+
+	// the highlight is hidden outside of the JS event loop, so it doesn't
+	// keep the document handle, and only uses CDP commands.
+	ctx := cdp.WithExecutor(f.ctx, document.session)
+	execCtxID := document.execCtx.ID()
+	hide := func() {
+		if err := overlay.HideHighlight().Do(ctx); err != nil {
+			f.log.Debugf("Frame:hideHighlight", "fid:%s furl:%q err:%v", f.ID(), f.URL(), err)
+		}
+		js := fmt.Sprintf(`
+			for (const element of document.querySelectorAll("[%[1]s]")) {
+				element.removeAttribute(%[1]q);
+			}
+		`, highlightAttribute)
+		if _, _, err := runtime.Evaluate(js).WithContextID(execCtxID).Do(ctx); err != nil {
+			f.log.Debugf("Frame:hideHighlight", "fid:%s furl:%q err:%v", f.ID(), f.URL(), err)
+		}
+	}
+
+	if err := overlay.Enable().Do(ctx); err != nil {
+		hide()
+		return fmt.Errorf("enabling overlay: %w", err)
+	}
+	config := &overlay.HighlightConfig{
+		ShowInfo:     true,
+		ContentColor: &cdp.RGBA{R: 111, G: 168, B: 220, A: 0.66},
+		PaddingColor: &cdp.RGBA{R: 147, G: 196, B: 125, A: 0.55},
+		BorderColor:  &cdp.RGBA{R: 255, G: 229, B: 153, A: 0.66},
+		MarginColor:  &cdp.RGBA{R: 246, G: 178, B: 107, A: 0.66},
+	}
+	action := overlay.HighlightNode(config).
+		WithObjectID(document.remoteObject.ObjectID).
+		WithSelector("[" + highlightAttribute + "]")
+	if err := action.Do(ctx); err != nil {
+		hide()
+		return fmt.Errorf("highlighting elements: %w", err)
+	}
+
+	f.highlightMu.Lock()
+	defer f.highlightMu.Unlock()
+	f.hideHighlightFn = hide
+	f.highlightTimer = time.AfterFunc(LocatorHighlightTimeout, f.hideHighlight)
+
+	return nil
+}
+
+// hideHighlight hides the highlight of the frame, if any, and unmarks its
+// elements.
+func (f *Frame) hideHighlight() {
+	f.highlightMu.Lock()
+	if f.highlightTimer != nil {
+		f.highlightTimer.Stop()
+	}
+	hide := f.hideHighlightFn
+	f.highlightTimer, f.hideHighlightFn = nil, nil
+	f.highlightMu.Unlock()
+
+	if hide != nil {
+		hide()
+	}
 }
 
 func (f *Frame) document() (*ElementHandle, error) {
@@ -472,17 +749,53 @@ func (f *Frame) onLoadingStopped() {
 	f.lifecycleEvents[LifecycleEventNetworkIdle] = true
 }
 
-func (f *Frame) position() *Position {
-	frame := f.manager.getFrameByID(cdp.FrameID(f.page.targetID))
-	if frame == nil {
-		return nil
+// position returns the offset of the viewport that the box models of the
+// frame's elements are relative to, in page coordinates. The frames that
+// run in the page's process share the page's viewport, while the
+// out-of-process frames have their own viewport, starting at the content
+// box of their frame element.
+func (f *Frame) position() (*Position, error) {
+	root := f
+	for root.parentFrame != nil && root.page.getFrameSession(cdp.FrameID(root.ID())) == nil {
+		root = root.parentFrame
+	}
+	if root.parentFrame == nil {
+		return &Position{X: 0, Y: 0}, nil
+	}
+
+	element, err := root.page.getFrameElement(root)
+	if err != nil {
+		return nil, fmt.Errorf("getting frame element: %w", err)
 	}
-	if frame == f.page.frameManager.MainFrame() {
-		return &Position{X: 0, Y: 0}
+	defer element.Dispose()
+	box, err := element.boundingBox()
+	if err != nil {
+		return nil, fmt.Errorf("getting frame element bounding box: %w", err)
+	}
+	js := `(el) => {
+		const style = getComputedStyle(el);
+		return [
+			parseFloat(style.borderLeftWidth) + parseFloat(style.paddingLeft),
+			parseFloat(style.borderTopWidth) + parseFloat(style.paddingTop),
+		];
+	}`
+	opts := evalOptions{
+		forceCallable: true,
+		returnByValue: true,
 	}
-	element := frame.FrameElement()
-	box := element.BoundingBox()
-	return &Position{X: box.X, Y: box.Y}
+	v, err := element.eval(f.ctx, opts, js)
+	if err != nil {
+		return nil, fmt.Errorf("getting frame element style: %w", err)
+	}
+	var offset []float64
+	if err := f.vu.Runtime().ExportTo(asGojaValue(f.ctx, v), &offset); err != nil {
+		return nil, fmt.Errorf("converting frame element style: %w", err)
+	}
+	if len(offset) != 2 {
+		return nil, fmt.Errorf("unexpected frame element style %v", offset)
+	}
+
+	return &Position{X: box.X + offset[0], Y: box.Y + offset[1]}, nil
 }
 
 func (f *Frame) removeChildFrame(child *Frame) {
@@ -492,7 +805,12 @@ func (f *Frame) removeChildFrame(child *Frame) {
 	f.childFramesMu.Lock()
 	defer f.childFramesMu.Unlock()
 
-	delete(f.childFrames, child)
+	for i, c := range f.childFrames {
+		if c == child {
+			f.childFrames = append(f.childFrames[:i:i], f.childFrames[i+1:]...)
+			return
+		}
+	}
 }
 
 func (f *Frame) requestByID(reqID network.RequestID) *Request {
@@ -574,9 +892,24 @@ func (f *Frame) waitForFunction(
 		return nil, fmt.Errorf("getting injected script: %w", err)
 	}
 
+	// Element handles can only be passed to the execution context they
+	// belong to, so adopt the ones coming from other contexts.
+	for i, a := range args {
+		eh, ok := a.(*ElementHandle)
+		if !ok || eh.execCtx == execCtx {
+			continue
+		}
+		if args[i], err = execCtx.adoptElementHandle(eh); err != nil {
+			return nil, fmt.Errorf("adopting element handle argument: %w", err)
+		}
+	}
+
+	// The truthy value is wrapped in an object, so that primitive values
+	// can also be returned as a JS handle.
 	pageFn := `
 		(injected, predicate, polling, timeout, ...args) => {
-			return injected.waitForPredicateFunction(predicate, polling, timeout, ...args);
+			return injected.waitForPredicateFunction(predicate, polling, timeout, ...args)
+				.then(value => ({ value }));
 		}
 	`
 
@@ -606,6 +939,9 @@ func (f *Frame) waitForFunction(
 				polling,
 				timeout.Milliseconds(), // The JS value is in ms integers
 			}, args...)...)
+		if err == nil {
+			result, err = unwrapValueHandle(result)
+		}
 		if err != nil {
 			cb(func() error {
 				reject(fmt.Errorf("waiting for function promise rejected: %w", err))
@@ -622,6 +958,91 @@ func (f *Frame) waitForFunction(
 	return promise, nil
 }
 
+// unwrapValueHandle returns a JS handle to the value property of the
+// wrapper object, so that primitive values can also be returned as handles.
+func unwrapValueHandle(wrapper interface{}) (api.JSHandle, error) {
+	h, ok := wrapper.(jsHandle)
+	if !ok {
+		return nil, fmt.Errorf("unexpected wrapper type: %T", wrapper)
+	}
+	defer func() { _ = h.dispose() }()
+
+	props, err := h.getProperties()
+	if err != nil {
+		return nil, err
+	}
+	value, ok := props["value"]
+	if !ok {
+		return nil, errors.New("wrapper has no value")
+	}
+
+	return value, nil
+}
+
+// enterFrameSelector separates the selector of an iframe element from the
+// selector of the elements in the iframe's document.
+const enterFrameSelector = " >> internal:control=enter-frame >> "
+
+// resolveFrameSelector returns the frame that the elements matching the
+// selector are in, and their selector in that frame. The iframes entered by
+// the selector are waited for up to the timeout.
+func (f *Frame) resolveFrameSelector(selector string, timeout time.Duration) (*Frame, string, error) {
+	frame := f
+	for {
+		i := strings.Index(selector, enterFrameSelector)
+		if i < 0 {
+			return frame, selector, nil
+		}
+		var err error
+		if frame, err = frame.enterFrame(selector[:i], timeout); err != nil {
+			return nil, "", err
+		}
+		selector = selector[i+len(enterFrameSelector):]
+	}
+}
+
+// enterFrame waits for the iframe element matching the selector with strict
+// mode on, and for its document to be loaded, and returns its frame.
+func (f *Frame) enterFrame(selector string, timeout time.Duration) (*Frame, error) {
+	opts := NewFrameWaitForSelectorOptions(timeout)
+	opts.State = DOMElementStateAttached
+	opts.Strict = true
+	h, err := f.waitForSelector(selector, opts)
+	if err != nil {
+		return nil, fmt.Errorf("waiting for frame %q: %w", selector, err)
+	}
+	defer h.Dispose()
+
+	ctx, cancel := context.WithTimeout(f.ctx, timeout)
+	defer cancel()
+
+	// the frame is attached shortly after its element.
+	const interval = 50 * time.Millisecond
+	var frame *Frame
+	for {
+		cf, ok, err := h.contentFrame()
+		if err != nil {
+			return nil, fmt.Errorf("getting frame of %q: %w", selector, err)
+		}
+		if !ok {
+			return nil, fmt.Errorf("selector %q does not match an iframe", selector)
+		}
+		if frame = cf; frame != nil {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("waiting for frame %q to attach: %w", selector, ctx.Err())
+		case <-time.After(interval):
+		}
+	}
+	if err := frame.waitForContentLoadState(ctx, LifecycleEventDOMContentLoad); err != nil {
+		return nil, fmt.Errorf("waiting for frame %q to load: %w", selector, err)
+	}
+
+	return frame, nil
+}
+
 func (f *Frame) waitForSelectorRetry(
 	selector string, opts *FrameWaitForSelectorOptions, retry int,
 ) (h *ElementHandle, err error) {
@@ -637,6 +1058,15 @@ func (f *Frame) waitForSelectorRetry(
 func (f *Frame) waitForSelector(selector string, opts *FrameWaitForSelectorOptions) (*ElementHandle, error) {
 	f.log.Debugf("Frame:waitForSelector", "fid:%s furl:%q sel:%q", f.ID(), f.URL(), selector)
 
+	frame, selector, err := f.resolveFrameSelector(selector, opts.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	if frame != f {
+		// the frame may still navigate to its source after it's entered.
+		return frame.waitForSelectorRetry(selector, opts, 1)
+	}
+
 	document, err := f.document()
 	if err != nil {
 		return nil, err
@@ -686,7 +1116,7 @@ func (f *Frame) ChildFrames() []api.Frame {
 	defer f.childFramesMu.RUnlock()
 
 	l := make([]api.Frame, 0, len(f.childFrames))
-	for child := range f.childFrames {
+	for _, child := range f.childFrames {
 		l = append(l, child)
 	}
 	return l
@@ -779,6 +1209,35 @@ func (f *Frame) uncheck(selector string, opts *FrameUncheckOptions) error {
 	return nil
 }
 
+// SetChecked checks or unchecks the first element found that matches the
+// selector, depending on checked.
+func (f *Frame) SetChecked(selector string, checked bool, opts goja.Value) {
+	f.log.Debugf("Frame:SetChecked", "fid:%s furl:%q sel:%q checked:%t", f.ID(), f.URL(), selector, checked)
+
+	popts := NewFrameSetCheckedOptions(f.defaultTimeout())
+	if err := popts.Parse(f.ctx, opts); err != nil {
+		k6ext.Panic(f.ctx, "parsing frame set checked options %q: %w", selector, err)
+	}
+	if err := f.setChecked(selector, checked, popts); err != nil {
+		k6ext.Panic(f.ctx, "setting checked state of %q: %w", selector, err)
+	}
+	applySlowMo(f.ctx)
+}
+
+func (f *Frame) setChecked(selector string, checked bool, opts *FrameSetCheckedOptions) error {
+	setChecked := func(apiCtx context.Context, handle *ElementHandle, p *Position) (interface{}, error) {
+		return nil, handle.setChecked(apiCtx, checked, p)
+	}
+	act := f.newPointerAction(
+		selector, DOMElementStateAttached, opts.Strict, setChecked, &opts.ElementHandleBasePointerOptions,
+	)
+	if _, err := call(f.ctx, act, opts.Timeout); err != nil {
+		return errorFromDOMError(err)
+	}
+
+	return nil
+}
+
 // IsChecked returns true if the first element that matches the selector
 // is checked. Otherwise, returns false.
 func (f *Frame) IsChecked(selector string, opts goja.Value) bool {
@@ -797,34 +1256,29 @@ func (f *Frame) IsChecked(selector string, opts goja.Value) bool {
 }
 
 func (f *Frame) isChecked(selector string, opts *FrameIsCheckedOptions) (bool, error) {
-	isChecked := func(apiCtx context.Context, handle *ElementHandle) (interface{}, error) {
-		v, err := handle.isChecked(apiCtx, 0) // Zero timeout when checking state
-		if errors.Is(err, ErrTimedOut) {      // We don't care about timeout errors here!
-			return v, nil
-		}
-		return v, err
-	}
-	act := f.newAction(
-		selector, DOMElementStateAttached, opts.Strict, isChecked, []string{}, false, true, opts.Timeout,
-	)
-	v, err := call(f.ctx, act, opts.Timeout)
-	if err != nil {
-		return false, errorFromDOMError(err)
-	}
-
-	bv, ok := v.(bool)
-	if !ok {
-		return false, fmt.Errorf("checking is %q checked: unexpected type %T", selector, v)
-	}
-
-	return bv, nil
+	return f.elementState(selector, "checked", opts.Strict, opts.Timeout)
 }
 
 // Content returns the HTML content of the frame.
 func (f *Frame) Content() string {
 	f.log.Debugf("Frame:Content", "fid:%s furl:%q", f.ID(), f.URL())
 
-	rt := f.vu.Runtime()
+	content, err := f.content()
+	if err != nil {
+		k6ext.Panic(f.ctx, "getting content: %w", err)
+	}
+
+	return content
+}
+
+// content returns the serialized HTML of the frame's document. It's
+// evaluated in the utility world so that the page's scripts can't change
+// the serialization.
+func (f *Frame) content() (string, error) {
+	if f.IsDetached() {
+		return "", errors.New("frame has been detached")
+	}
+
 	js := `() => {
 		let content = '';
 		if (document.doctype) {
@@ -836,7 +1290,23 @@ func (f *Frame) Content() string {
 		return content;
 	}`
 
-	return gojaValueToString(f.ctx, f.Evaluate(rt.ToValue(js)))
+	f.waitForExecutionContext(utilityWorld)
+
+	eopts := evalOptions{
+		forceCallable: true,
+		returnByValue: true,
+	}
+	rt := f.vu.Runtime()
+	v, err := f.evaluate(f.ctx, utilityWorld, eopts, rt.ToValue(js))
+	if err != nil {
+		return "", err
+	}
+	content, ok := v.(goja.Value)
+	if !ok || !gojaValueExists(content) {
+		return "", fmt.Errorf("unexpected content type %T", v)
+	}
+
+	return content.String(), nil
 }
 
 // Dblclick double clicks an element matching provided selector.
@@ -857,7 +1327,7 @@ func (f *Frame) Dblclick(selector string, opts goja.Value) {
 // an error, or applies slow motion.
 func (f *Frame) dblclick(selector string, opts *FrameDblclickOptions) error {
 	dblclick := func(apiCtx context.Context, eh *ElementHandle, p *Position) (interface{}, error) {
-		return nil, eh.dblClick(p, opts.ToMouseClickOptions())
+		return nil, eh.dblClick(p, &opts.ElementHandleDblclickOptions)
 	}
 	act := f.newPointerAction(
 		selector, DOMElementStateAttached, opts.Strict, dblclick, &opts.ElementHandleBasePointerOptions,
@@ -869,6 +1339,64 @@ func (f *Frame) dblclick(selector string, opts *FrameDblclickOptions) error {
 	return nil
 }
 
+// DragAndDrop drags the first element that matches the source selector and
+// drops it on the first element that matches the target selector. The mouse
+// moves to the target in steps, so that the page sees a realistic drag.
+func (f *Frame) DragAndDrop(source string, target string, opts goja.Value) {
+	f.log.Debugf("Frame:DragAndDrop", "fid:%s furl:%q source:%q target:%q", f.ID(), f.URL(), source, target)
+
+	popts := NewFrameDragAndDropOptions(f.defaultTimeout())
+	if err := popts.Parse(f.ctx, opts); err != nil {
+		k6ext.Panic(f.ctx, "parsing drag and drop options: %w", err)
+	}
+	if err := f.dragAndDrop(source, target, popts); err != nil {
+		k6ext.Panic(f.ctx, "dragging %q to %q: %w", source, target, err)
+	}
+	applySlowMo(f.ctx)
+}
+
+// dragAndDrop is like DragAndDrop but takes parsed options and neither
+// throws an error, or applies slow motion.
+func (f *Frame) dragAndDrop(source string, target string, opts *FrameDragAndDropOptions) error {
+	pointerOpts := func(p *Position) *ElementHandleBasePointerOptions {
+		return &ElementHandleBasePointerOptions{
+			ElementHandleBaseOptions: opts.ElementHandleBaseOptions,
+			Position:                 p,
+			Trial:                    opts.Trial,
+		}
+	}
+
+	moveAndDown := func(apiCtx context.Context, eh *ElementHandle, p *Position) (interface{}, error) {
+		mouse := eh.frame.page.Mouse
+		if err := mouse.move(p.X, p.Y, NewMouseMoveOptions()); err != nil {
+			return nil, err
+		}
+		return nil, mouse.down(p.X, p.Y, NewMouseDownUpOptions())
+	}
+	act := f.newPointerAction(
+		source, DOMElementStateAttached, opts.Strict, moveAndDown, pointerOpts(opts.SourcePosition),
+	)
+	if _, err := call(f.ctx, act, opts.Timeout); err != nil {
+		return errorFromDOMError(err)
+	}
+
+	moveAndUp := func(apiCtx context.Context, eh *ElementHandle, p *Position) (interface{}, error) {
+		mouse := eh.frame.page.Mouse
+		if err := mouse.move(p.X, p.Y, &MouseMoveOptions{Steps: opts.Steps}); err != nil {
+			return nil, err
+		}
+		return nil, mouse.up(p.X, p.Y, NewMouseDownUpOptions())
+	}
+	act = f.newPointerAction(
+		target, DOMElementStateAttached, opts.Strict, moveAndUp, pointerOpts(opts.TargetPosition),
+	)
+	if _, err := call(f.ctx, act, opts.Timeout); err != nil {
+		return errorFromDOMError(err)
+	}
+
+	return nil
+}
+
 // DispatchEvent dispatches an event for the first element matching the selector.
 func (f *Frame) DispatchEvent(selector, typ string, eventInit, opts goja.Value) {
 	f.log.Debugf("Frame:DispatchEvent", "fid:%s furl:%q sel:%q typ:%q", f.ID(), f.URL(), selector, typ)
@@ -880,28 +1408,128 @@ func (f *Frame) DispatchEvent(selector, typ string, eventInit, opts goja.Value)
 	if err := f.dispatchEvent(selector, typ, eventInit, popts); err != nil {
 		k6ext.Panic(f.ctx, "dispatching event %q to %q: %w", typ, selector, err)
 	}
-	applySlowMo(f.ctx)
-}
-
-// dispatchEvent is like DispatchEvent but takes parsed options and neither throws
-// an error, or applies slow motion.
-func (f *Frame) dispatchEvent(selector, typ string, eventInit goja.Value, opts *FrameDispatchEventOptions) error {
-	dispatchEvent := func(apiCtx context.Context, handle *ElementHandle) (interface{}, error) {
-		return handle.dispatchEvent(apiCtx, typ, eventInit)
+	applySlowMo(f.ctx)
+}
+
+// dispatchEvent is like DispatchEvent but takes parsed options and neither throws
+// an error, or applies slow motion.
+func (f *Frame) dispatchEvent(selector, typ string, eventInit goja.Value, opts *FrameDispatchEventOptions) error {
+	dispatchEvent := func(apiCtx context.Context, handle *ElementHandle) (interface{}, error) {
+		return handle.dispatchEvent(apiCtx, typ, eventInit)
+	}
+	const (
+		force       = false
+		noWaitAfter = false
+	)
+	act := f.newAction(
+		selector, DOMElementStateAttached, opts.Strict, dispatchEvent, []string{},
+		force, noWaitAfter, opts.Timeout,
+	)
+	if _, err := call(f.ctx, act, opts.Timeout); err != nil {
+		return errorFromDOMError(err)
+	}
+
+	return nil
+}
+
+// EvalOnSelector runs the page function with the first element matching the
+// selector as its first argument, and returns its result. It doesn't wait
+// for an element to match.
+func (f *Frame) EvalOnSelector(selector string, pageFunc goja.Value, arg goja.Value) interface{} {
+	f.log.Debugf("Frame:EvalOnSelector", "fid:%s furl:%q sel:%q", f.ID(), f.URL(), selector)
+
+	v, err := f.evalOnSelector(selector, pageFunc, arg)
+	if err != nil {
+		k6ext.Panic(f.ctx, "evaluating on selector %q: %w", selector, err)
+	}
+	return v
+}
+
+func (f *Frame) evalOnSelector(selector string, pageFunc goja.Value, arg goja.Value) (interface{}, error) {
+	frame, selector, err := f.resolveFrameSelector(selector, f.defaultTimeout())
+	if err != nil {
+		return nil, err
+	}
+	document, err := frame.document()
+	if err != nil {
+		return nil, fmt.Errorf("getting document: %w", err)
+	}
+	parsedSelector, err := NewSelector(selector)
+	if err != nil {
+		return nil, fmt.Errorf("parsing selector %q: %w", selector, err)
+	}
+	js := `
+		(node, injected, selector) => {
+			return injected.querySelector(selector, node || document, false);
+		}
+	`
+	opts := evalOptions{
+		forceCallable: true,
+		returnByValue: false,
+	}
+	result, err := document.evalWithScript(f.ctx, opts, js, parsedSelector)
+	if err != nil {
+		return nil, errorFromDOMError(err)
+	}
+	handle, ok := result.(api.JSHandle)
+	if !ok {
+		return nil, fmt.Errorf("no element matches selector %q", selector)
+	}
+	defer handle.Dispose()
+	if handle.AsElement() == nil {
+		return nil, fmt.Errorf("no element matches selector %q", selector)
+	}
+
+	return document.execCtx.Eval(f.ctx, pageFunc, evalArgs(k6ext.Runtime(f.ctx), handle, arg)...)
+}
+
+// EvalOnSelectorAll runs the page function with an array of all the elements
+// matching the selector as its first argument, and returns its result. It
+// doesn't wait for any elements to match, so the array can be empty.
+func (f *Frame) EvalOnSelectorAll(selector string, pageFunc goja.Value, arg goja.Value) interface{} {
+	f.log.Debugf("Frame:EvalOnSelectorAll", "fid:%s furl:%q sel:%q", f.ID(), f.URL(), selector)
+
+	v, err := f.evalOnSelectorAll(selector, pageFunc, arg)
+	if err != nil {
+		k6ext.Panic(f.ctx, "evaluating on all of selector %q: %w", selector, err)
+	}
+	return v
+}
+
+func (f *Frame) evalOnSelectorAll(selector string, pageFunc goja.Value, arg goja.Value) (interface{}, error) {
+	frame, selector, err := f.resolveFrameSelector(selector, f.defaultTimeout())
+	if err != nil {
+		return nil, err
+	}
+	document, err := frame.document()
+	if err != nil {
+		return nil, fmt.Errorf("getting document: %w", err)
+	}
+	parsedSelector, err := NewSelector(selector)
+	if err != nil {
+		return nil, fmt.Errorf("parsing selector %q: %w", selector, err)
+	}
+	js := `
+		(node, injected, selector) => {
+			const elements = injected.querySelectorAll(selector, node || document);
+			return typeof elements === "string" ? elements : [...elements];
+		}
+	`
+	opts := evalOptions{
+		forceCallable: true,
+		returnByValue: false,
+	}
+	result, err := document.evalWithScript(f.ctx, opts, js, parsedSelector)
+	if err != nil {
+		return nil, errorFromDOMError(err)
 	}
-	const (
-		force       = false
-		noWaitAfter = false
-	)
-	act := f.newAction(
-		selector, DOMElementStateAttached, opts.Strict, dispatchEvent, []string{},
-		force, noWaitAfter, opts.Timeout,
-	)
-	if _, err := call(f.ctx, act, opts.Timeout); err != nil {
-		return errorFromDOMError(err)
+	elements, ok := result.(api.JSHandle)
+	if !ok {
+		return nil, fmt.Errorf("getting elements: %w", ErrJSHandleInvalid)
 	}
+	defer elements.Dispose()
 
-	return nil
+	return document.execCtx.Eval(f.ctx, pageFunc, evalArgs(k6ext.Runtime(f.ctx), elements, arg)...)
 }
 
 // Evaluate will evaluate provided page function within an execution context.
@@ -978,6 +1606,24 @@ func (f *Frame) fill(selector, value string, opts *FrameFillOptions) error {
 	return nil
 }
 
+func (f *Frame) clear(selector string, opts *FrameClearOptions) error {
+	clear := func(apiCtx context.Context, handle *ElementHandle) (interface{}, error) {
+		return nil, handle.clear(apiCtx)
+	}
+	// the element isn't waited to be editable, as clearing a read-only
+	// element is an error.
+	act := f.newAction(
+		selector, DOMElementStateAttached, opts.Strict,
+		clear, []string{"visible", "enabled"},
+		opts.Force, opts.NoWaitAfter, opts.Timeout,
+	)
+	if _, err := call(f.ctx, act, opts.Timeout); err != nil {
+		return errorFromDOMError(err)
+	}
+
+	return nil
+}
+
 // Focus focuses on the first element that matches the selector.
 func (f *Frame) Focus(selector string, opts goja.Value) {
 	f.log.Debugf("Frame:Focus", "fid:%s furl:%q sel:%q", f.ID(), f.URL(), selector)
@@ -1007,6 +1653,23 @@ func (f *Frame) focus(selector string, opts *FrameBaseOptions) error {
 	return nil
 }
 
+func (f *Frame) scrollIntoViewIfNeeded(selector string, opts *FrameBaseOptions) error {
+	scroll := func(apiCtx context.Context, handle *ElementHandle) (interface{}, error) {
+		return nil, handle.scrollIntoViewIfNeeded(apiCtx)
+	}
+	act := f.newAction(
+		selector, DOMElementStateAttached, opts.Strict, scroll,
+		[]string{"visible", "stable"}, false, true, opts.Timeout,
+	)
+	if _, err := call(f.ctx, act, opts.Timeout); err != nil {
+		return errorFromDOMError(err)
+	}
+
+	return nil
+}
+
+// FrameElement returns the handle of the iframe element that hosts the frame
+// in its parent document. The main frame doesn't have one.
 func (f *Frame) FrameElement() api.ElementHandle {
 	f.log.Debugf("Frame:FrameElement", "fid:%s furl:%q", f.ID(), f.URL())
 
@@ -1017,6 +1680,14 @@ func (f *Frame) FrameElement() api.ElementHandle {
 	return element
 }
 
+// FrameLocator returns a frame locator for the iframes matching the
+// selector in this frame.
+func (f *Frame) FrameLocator(selector string) api.FrameLocator {
+	f.log.Debugf("Frame:FrameLocator", "fid:%s furl:%q sel:%q", f.ID(), f.URL(), selector)
+
+	return NewFrameLocator(f.ctx, selector, f, f.log)
+}
+
 // GetAttribute of the first element found that matches the selector.
 func (f *Frame) GetAttribute(selector, name string, opts goja.Value) goja.Value {
 	f.log.Debugf("Frame:GetAttribute", "fid:%s furl:%q sel:%q name:%s", f.ID(), f.URL(), selector, name)
@@ -1055,6 +1726,86 @@ func (f *Frame) getAttribute(selector, name string, opts *FrameBaseOptions) (goj
 	return gv, nil
 }
 
+// GetByLabel returns a locator for the elements with a matching label.
+func (f *Frame) GetByLabel(text goja.Value, opts goja.Value) api.Locator {
+	f.log.Debugf("Frame:GetByLabel", "fid:%s furl:%q text:%q", f.ID(), f.URL(), text)
+
+	return f.getByText("label", opts, func(exact bool) (string, error) {
+		return textEngineSelector("internal:label", text, exact)
+	})
+}
+
+// GetByPlaceholder returns a locator for the input elements with a
+// matching placeholder.
+func (f *Frame) GetByPlaceholder(text goja.Value, opts goja.Value) api.Locator {
+	f.log.Debugf("Frame:GetByPlaceholder", "fid:%s furl:%q text:%q", f.ID(), f.URL(), text)
+
+	return f.getByText("placeholder", opts, func(exact bool) (string, error) {
+		return attrSelector("placeholder", text, exact)
+	})
+}
+
+// GetByRole returns a locator for the elements with the given ARIA role.
+func (f *Frame) GetByRole(role string, opts goja.Value) api.Locator {
+	f.log.Debugf("Frame:GetByRole", "fid:%s furl:%q role:%q", f.ID(), f.URL(), role)
+
+	popts := NewLocatorGetByRoleOptions()
+	if err := popts.Parse(f.ctx, opts); err != nil {
+		k6ext.Panic(f.ctx, "parsing get by role options: %w", err)
+	}
+	selector, err := roleSelector(role, popts)
+	if err != nil {
+		k6ext.Panic(f.ctx, "getting by role %q: %w", role, err)
+	}
+
+	return NewLocator(f.ctx, selector, f, f.log)
+}
+
+// GetByTestId returns a locator for the elements with the test ID. The test
+// ID attribute is set by the browser context's testIdAttribute option.
+func (f *Frame) GetByTestId(testID goja.Value) api.Locator { //nolint:revive,stylecheck
+	f.log.Debugf("Frame:GetByTestId", "fid:%s furl:%q testID:%q", f.ID(), f.URL(), testID)
+
+	selector, err := attrSelector(f.testIDAttribute(), testID, true)
+	if err != nil {
+		k6ext.Panic(f.ctx, "getting by test ID: %w", err)
+	}
+
+	return NewLocator(f.ctx, selector, f, f.log)
+}
+
+// GetByText returns a locator for the elements with a matching text.
+func (f *Frame) GetByText(text goja.Value, opts goja.Value) api.Locator {
+	f.log.Debugf("Frame:GetByText", "fid:%s furl:%q text:%q", f.ID(), f.URL(), text)
+
+	return f.getByText("text", opts, func(exact bool) (string, error) {
+		return textEngineSelector("internal:text", text, exact)
+	})
+}
+
+// getByText parses the get by text options and returns a locator for the
+// selector built by the given function.
+func (f *Frame) getByText(kind string, opts goja.Value, selector func(exact bool) (string, error)) api.Locator {
+	popts := NewLocatorGetByTextOptions()
+	if err := popts.Parse(f.ctx, opts); err != nil {
+		k6ext.Panic(f.ctx, "parsing get by %s options: %w", kind, err)
+	}
+	s, err := selector(popts.Exact)
+	if err != nil {
+		k6ext.Panic(f.ctx, "getting by %s: %w", kind, err)
+	}
+
+	return NewLocator(f.ctx, s, f, f.log)
+}
+
+// testIDAttribute returns the attribute name used by GetByTestId.
+func (f *Frame) testIDAttribute() string {
+	if f.page == nil || f.page.browserCtx == nil || f.page.browserCtx.opts == nil {
+		return DefaultTestIDAttribute
+	}
+	return f.page.browserCtx.opts.TestIDAttribute
+}
+
 // Goto will navigate the frame to the specified URL and return a HTTP response object.
 func (f *Frame) Goto(url string, opts goja.Value) api.Response {
 	resp := f.manager.NavigateFrame(f, url, opts)
@@ -1079,7 +1830,7 @@ func (f *Frame) Hover(selector string, opts goja.Value) {
 
 func (f *Frame) hover(selector string, opts *FrameHoverOptions) error {
 	hover := func(apiCtx context.Context, handle *ElementHandle, p *Position) (interface{}, error) {
-		return nil, handle.hover(apiCtx, p)
+		return nil, handle.hover(p, opts.Modifiers)
 	}
 	act := f.newPointerAction(
 		selector, DOMElementStateAttached, opts.Strict, hover, &opts.ElementHandleBasePointerOptions,
@@ -1204,12 +1955,12 @@ func (f *Frame) inputValue(selector string, opts *FrameInputValueOptions) (strin
 	if err != nil {
 		return "", errorFromDOMError(err)
 	}
-	gv, ok := v.(goja.Value)
+	s, ok := v.(string)
 	if !ok {
 		return "", fmt.Errorf("getting input value of %q: unexpected type %T", selector, v)
 	}
 
-	return gv.String(), nil
+	return s, nil
 }
 
 // IsDetached returns whether the frame is detached or not.
@@ -1246,27 +1997,7 @@ func (f *Frame) IsEditable(selector string, opts goja.Value) bool {
 }
 
 func (f *Frame) isEditable(selector string, opts *FrameIsEditableOptions) (bool, error) {
-	isEditable := func(apiCtx context.Context, handle *ElementHandle) (interface{}, error) {
-		v, err := handle.isEditable(apiCtx, 0) // Zero timeout when checking state
-		if errors.Is(err, ErrTimedOut) {       // We don't care about timeout errors here!
-			return v, nil
-		}
-		return v, err
-	}
-	act := f.newAction(
-		selector, DOMElementStateAttached, opts.Strict, isEditable, []string{}, false, true, opts.Timeout,
-	)
-	v, err := call(f.ctx, act, opts.Timeout)
-	if err != nil {
-		return false, errorFromDOMError(err)
-	}
-
-	bv, ok := v.(bool)
-	if !ok {
-		return false, fmt.Errorf("checking is %q editable: unexpected type %T", selector, v)
-	}
-
-	return bv, nil
+	return f.elementState(selector, "editable", opts.Strict, opts.Timeout)
 }
 
 // IsEnabled returns true if the first element that matches the selector
@@ -1287,27 +2018,7 @@ func (f *Frame) IsEnabled(selector string, opts goja.Value) bool {
 }
 
 func (f *Frame) isEnabled(selector string, opts *FrameIsEnabledOptions) (bool, error) {
-	isEnabled := func(apiCtx context.Context, handle *ElementHandle) (interface{}, error) {
-		v, err := handle.isEnabled(apiCtx, 0) // Zero timeout when checking state
-		if errors.Is(err, ErrTimedOut) {      // We don't care about timeout errors here!
-			return v, nil
-		}
-		return v, err
-	}
-	act := f.newAction(
-		selector, DOMElementStateAttached, opts.Strict, isEnabled, []string{}, false, true, opts.Timeout,
-	)
-	v, err := call(f.ctx, act, opts.Timeout)
-	if err != nil {
-		return false, errorFromDOMError(err)
-	}
-
-	bv, ok := v.(bool)
-	if !ok {
-		return false, fmt.Errorf("checking is %q enabled: unexpected type %T", selector, v)
-	}
-
-	return bv, nil
+	return f.elementState(selector, "enabled", opts.Strict, opts.Timeout)
 }
 
 // IsDisabled returns true if the first element that matches the selector
@@ -1328,27 +2039,7 @@ func (f *Frame) IsDisabled(selector string, opts goja.Value) bool {
 }
 
 func (f *Frame) isDisabled(selector string, opts *FrameIsDisabledOptions) (bool, error) {
-	isDisabled := func(apiCtx context.Context, handle *ElementHandle) (interface{}, error) {
-		v, err := handle.isDisabled(apiCtx, 0) // Zero timeout when checking state
-		if errors.Is(err, ErrTimedOut) {       // We don't care about timeout errors here!
-			return v, nil
-		}
-		return v, err
-	}
-	act := f.newAction(
-		selector, DOMElementStateAttached, opts.Strict, isDisabled, []string{}, false, true, opts.Timeout,
-	)
-	v, err := call(f.ctx, act, opts.Timeout)
-	if err != nil {
-		return false, errorFromDOMError(err)
-	}
-
-	bv, ok := v.(bool)
-	if !ok {
-		return false, fmt.Errorf("checking is %q disabled: unexpected type %T", selector, v)
-	}
-
-	return bv, nil
+	return f.elementState(selector, "disabled", opts.Strict, opts.Timeout)
 }
 
 // IsHidden returns true if the first element that matches the selector
@@ -1369,27 +2060,7 @@ func (f *Frame) IsHidden(selector string, opts goja.Value) bool {
 }
 
 func (f *Frame) isHidden(selector string, opts *FrameIsHiddenOptions) (bool, error) {
-	isHidden := func(apiCtx context.Context, handle *ElementHandle) (interface{}, error) {
-		v, err := handle.isHidden(apiCtx, 0) // Zero timeout when checking state
-		if errors.Is(err, ErrTimedOut) {     // We don't care about timeout errors here!
-			return v, nil
-		}
-		return v, err
-	}
-	act := f.newAction(
-		selector, DOMElementStateAttached, opts.Strict, isHidden, []string{}, false, true, opts.Timeout,
-	)
-	v, err := call(f.ctx, act, opts.Timeout)
-	if err != nil {
-		return false, errorFromDOMError(err)
-	}
-
-	bv, ok := v.(bool)
-	if !ok {
-		return false, fmt.Errorf("checking is %q hidden: unexpected type %T", selector, v)
-	}
-
-	return bv, nil
+	return f.elementState(selector, "hidden", opts.Strict, opts.Timeout)
 }
 
 // IsVisible returns true if the first element that matches the selector
@@ -1410,27 +2081,7 @@ func (f *Frame) IsVisible(selector string, opts goja.Value) bool {
 }
 
 func (f *Frame) isVisible(selector string, opts *FrameIsVisibleOptions) (bool, error) {
-	isVisible := func(apiCtx context.Context, handle *ElementHandle) (interface{}, error) {
-		v, err := handle.isVisible(apiCtx, 0) // Zero timeout when checking state
-		if errors.Is(err, ErrTimedOut) {      // We don't care about timeout errors here!
-			return v, nil
-		}
-		return v, err
-	}
-	act := f.newAction(
-		selector, DOMElementStateAttached, opts.Strict, isVisible, []string{}, false, true, opts.Timeout,
-	)
-	v, err := call(f.ctx, act, opts.Timeout)
-	if err != nil {
-		return false, errorFromDOMError(err)
-	}
-
-	bv, ok := v.(bool)
-	if !ok {
-		return false, fmt.Errorf("checking is %q visible: unexpected type %T", selector, v)
-	}
-
-	return bv, nil
+	return f.elementState(selector, "visible", opts.Strict, opts.Timeout)
 }
 
 // ID returns the frame id.
@@ -1501,6 +2152,10 @@ func (f *Frame) Page() api.Page {
 
 // ParentFrame returns the parent frame, if one exists.
 func (f *Frame) ParentFrame() api.Frame {
+	// avoid returning a non-nil interface holding a nil frame.
+	if f.parentFrame == nil {
+		return nil
+	}
 	return f.parentFrame
 }
 
@@ -1565,25 +2220,9 @@ func (f *Frame) selectOption(selector string, values goja.Value, opts *FrameSele
 	if err != nil {
 		return nil, errorFromDOMError(err)
 	}
-	selectHandle, ok := v.(jsHandle)
+	vals, ok := v.([]string)
 	if !ok {
-		return nil, fmt.Errorf("unexpected select element type %T", v)
-	}
-
-	// pack the selected <option> values inside <select> into a slice
-	optHandles, err := selectHandle.getProperties()
-	if err != nil {
-		return nil, fmt.Errorf("getProperties: %w", err)
-	}
-	vals := make([]string, 0, len(optHandles))
-	for _, oh := range optHandles {
-		vals = append(vals, oh.JSONValue().String())
-		if err := oh.dispose(); err != nil {
-			return nil, fmt.Errorf("optionHandle.dispose: %w", err)
-		}
-	}
-	if err := selectHandle.dispose(); err != nil {
-		return nil, fmt.Errorf("selectHandle.dispose: %w", err)
+		return nil, fmt.Errorf("unexpected selected options type %T", v)
 	}
 
 	return vals, nil
@@ -1597,6 +2236,19 @@ func (f *Frame) SetContent(html string, opts goja.Value) {
 	if err := parsedOpts.Parse(f.ctx, opts); err != nil {
 		k6ext.Panic(f.ctx, "parsing set content options: %w", err)
 	}
+	if err := f.setContent(html, parsedOpts); err != nil {
+		k6ext.Panic(f.ctx, "setting content: %w", err)
+	}
+
+	applySlowMo(f.ctx)
+}
+
+// setContent is like SetContent but takes parsed options and neither throws
+// an error, or applies slow motion.
+func (f *Frame) setContent(html string, opts *FrameSetContentOptions) error {
+	if f.IsDetached() {
+		return errors.New("frame has been detached")
+	}
 
 	js := `(html) => {
 		window.stop();
@@ -1604,6 +2256,9 @@ func (f *Frame) SetContent(html string, opts goja.Value) {
 		document.write(html);
 		document.close();
 	}`
+	if opts.BaseURL != "" {
+		html = withBaseURL(html, opts.BaseURL)
+	}
 
 	f.waitForExecutionContext(utilityWorld)
 
@@ -1613,15 +2268,106 @@ func (f *Frame) SetContent(html string, opts goja.Value) {
 	}
 	rt := f.vu.Runtime()
 	if _, err := f.evaluate(f.ctx, utilityWorld, eopts, rt.ToValue(js), rt.ToValue(html)); err != nil {
-		k6ext.Panic(f.ctx, "setting content: %w", err)
+		return err
 	}
 
-	applySlowMo(f.ctx)
+	ctx, cancel := context.WithTimeout(f.ctx, opts.Timeout)
+	defer cancel()
+	if err := f.waitForContentLoadState(ctx, opts.WaitUntil); err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			err = &k6ext.UserFriendlyError{Err: err, Timeout: opts.Timeout}
+		}
+		return fmt.Errorf("waiting for %q: %w", opts.WaitUntil, err)
+	}
+
+	return nil
+}
+
+// waitForContentLoadState waits for the document written to the frame to
+// reach the load state. The frame's lifecycle events can't be used since
+// writing a document doesn't navigate the frame.
+func (f *Frame) waitForContentLoadState(ctx context.Context, state LifecycleEvent) error {
+	js := `(domContentLoaded) => new Promise((resolve) => {
+		const done = () => domContentLoaded ? document.readyState !== 'loading' : document.readyState === 'complete';
+		if (done()) {
+			return resolve();
+		}
+		document.addEventListener('readystatechange', () => done() && resolve());
+	})`
+
+	eopts := evalOptions{
+		forceCallable: true,
+		returnByValue: true,
+	}
+	// the written document is already committed.
+	if state == LifecycleEventCommit {
+		return nil
+	}
+	rt := f.vu.Runtime()
+	domContentLoaded := state == LifecycleEventDOMContentLoad
+	if _, err := f.evaluate(ctx, utilityWorld, eopts, rt.ToValue(js), rt.ToValue(domContentLoaded)); err != nil {
+		return err
+	}
+	if state != LifecycleEventNetworkIdle {
+		return nil
+	}
+
+	// the network is idle when the frame has no inflight requests for
+	// the network idle timeout.
+	const interval = 50 * time.Millisecond
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	var idle time.Duration
+	for idle < LifeCycleNetworkIdleTimeout {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+		if f.inflightRequestsLen() > 0 {
+			idle = 0
+			continue
+		}
+		idle += interval
+	}
+
+	return nil
 }
 
+// SetInputFiles sets the files of the first file input element that
+// matches the selector. The files can be file paths, or objects with name,
+// mimeType and buffer properties. An empty array clears the selected files.
 func (f *Frame) SetInputFiles(selector string, files goja.Value, opts goja.Value) {
-	k6ext.Panic(f.ctx, "Frame.setInputFiles(selector, files, opts) has not been implemented yet")
-	// TODO: needs slowMo
+	f.log.Debugf("Frame:SetInputFiles", "fid:%s furl:%q sel:%q", f.ID(), f.URL(), selector)
+
+	popts := NewFrameSetInputFilesOptions(f.defaultTimeout())
+	if err := popts.Parse(f.ctx, opts); err != nil {
+		k6ext.Panic(f.ctx, "parsing setInputFiles options: %w", err)
+	}
+	if err := f.setInputFiles(selector, files, popts); err != nil {
+		k6ext.Panic(f.ctx, "setting input files on %q: %w", selector, err)
+	}
+
+	applySlowMo(f.ctx)
+}
+
+func (f *Frame) setInputFiles(selector string, files goja.Value, opts *FrameSetInputFilesOptions) error {
+	parsed, err := parseInputFiles(f.ctx, files)
+	if err != nil {
+		return fmt.Errorf("parsing files: %w", err)
+	}
+	setInputFiles := func(apiCtx context.Context, handle *ElementHandle) (interface{}, error) {
+		return nil, handle.setInputFiles(apiCtx, parsed)
+	}
+	act := f.newAction(
+		selector, DOMElementStateAttached, opts.Strict, setInputFiles,
+		[]string{}, opts.Force, opts.NoWaitAfter, opts.Timeout,
+	)
+	if _, err := call(f.ctx, act, opts.Timeout); err != nil {
+		return errorFromDOMError(err)
+	}
+
+	return nil
 }
 
 // Tap the first element that matches the selector.
@@ -1640,8 +2386,13 @@ func (f *Frame) Tap(selector string, opts goja.Value) {
 }
 
 func (f *Frame) tap(selector string, opts *FrameTapOptions) error {
+	// tapping can't succeed without touch emulation, so there's no need to
+	// wait for the element.
+	if err := f.page.Touchscreen.ensureTouch(); err != nil {
+		return err
+	}
 	tap := func(apiCtx context.Context, handle *ElementHandle, p *Position) (interface{}, error) {
-		return nil, handle.tap(apiCtx, p)
+		return nil, handle.tap(p, opts.Modifiers)
 	}
 	act := f.newPointerAction(
 		selector, DOMElementStateAttached, opts.Strict, tap, &opts.ElementHandleBasePointerOptions,
@@ -1753,6 +2504,8 @@ func (f *Frame) setURL(url string) {
 }
 
 // WaitForFunction waits for the given predicate to return a truthy value.
+// The predicate is evaluated in the main execution context of this frame,
+// and the returned promise resolves to a handle of the truthy value.
 func (f *Frame) WaitForFunction(fn goja.Value, opts goja.Value, jsArgs ...goja.Value) *goja.Promise {
 	f.log.Debugf("Frame:WaitForFunction", "fid:%s furl:%q", f.ID(), f.URL())
 
@@ -1762,9 +2515,6 @@ func (f *Frame) WaitForFunction(fn goja.Value, opts goja.Value, jsArgs ...goja.V
 		k6ext.Panic(f.ctx, "parsing waitForFunction options: %w", err)
 	}
 
-	f.executionContextMu.RLock()
-	defer f.executionContextMu.RUnlock()
-
 	js := fn.ToString().String()
 	_, isCallable := goja.AssertFunction(fn)
 	if !isCallable {
@@ -1790,12 +2540,14 @@ func (f *Frame) WaitForFunction(fn goja.Value, opts goja.Value, jsArgs ...goja.V
 	return promise
 }
 
-// WaitForLoadState waits for the given load state to be reached.
+// WaitForLoadState waits for the given load state to be reached by the frame
+// and its child frames. It returns immediately if the state is already
+// reached.
 func (f *Frame) WaitForLoadState(state string, opts goja.Value) {
 	f.log.Debugf("Frame:WaitForLoadState", "fid:%s furl:%q state:%s", f.ID(), f.URL(), state)
 	defer f.log.Debugf("Frame:WaitForLoadState:return", "fid:%s furl:%q state:%s", f.ID(), f.URL(), state)
 
-	parsedOpts := NewFrameWaitForLoadStateOptions(f.defaultTimeout())
+	parsedOpts := NewFrameWaitForLoadStateOptions(f.manager.timeoutSettings.navigationTimeout())
 	err := parsedOpts.Parse(f.ctx, opts)
 	if err != nil {
 		k6ext.Panic(f.ctx, "parsing waitForLoadState %q options: %v", state, err)
@@ -1808,16 +2560,35 @@ func (f *Frame) WaitForLoadState(state string, opts goja.Value) {
 		}
 	}
 
-	if f.hasLifecycleEventFired(waitUntil) {
-		return
+	if err := f.waitForLoadState(waitUntil, parsedOpts.Timeout); err != nil {
+		k6ext.Panic(f.ctx, "waiting for load state %q: %w", waitUntil, err)
 	}
+}
 
-	_, err = waitForEvent(f.ctx, f, []string{EventFrameAddLifecycle}, func(data interface{}) bool {
-		return data.(LifecycleEvent) == waitUntil
-	}, parsedOpts.Timeout)
-	if err != nil {
-		k6ext.Panic(f.ctx, "waiting for load state %q: %v", state, err)
+func (f *Frame) waitForLoadState(state LifecycleEvent, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(f.ctx, timeout)
+	defer cancel()
+
+	// subscribe before checking the state, so that the event isn't missed
+	// if the state is reached in the meantime.
+	ch := make(chan Event)
+	f.on(ctx, []string{EventFrameAddLifecycle}, ch)
+
+	for !f.hasSubtreeLifecycleEventFired(state) {
+		select {
+		case <-ctx.Done():
+			return &k6ext.UserFriendlyError{
+				Err:     ctx.Err(),
+				Timeout: timeout,
+			}
+		case ev := <-ch:
+			if e, ok := ev.data.(LifecycleEvent); ok && e == state {
+				return nil
+			}
+		}
 	}
+
+	return nil
 }
 
 // WaitForNavigation waits for the given navigation lifecycle event to happen.
@@ -1851,6 +2622,66 @@ func (f *Frame) WaitForTimeout(timeout int64) {
 	}
 }
 
+// WaitForURL waits for the frame to navigate to a URL matching the given
+// glob pattern, RegExp or predicate function. It also matches the URL
+// changes within the same document, such as the ones made with the History
+// API. It returns right after the waitUntil lifecycle event if the frame is
+// already at a matching URL.
+func (f *Frame) WaitForURL(urlOrPredicate, opts goja.Value) {
+	f.log.Debugf("Frame:WaitForURL", "fid:%s furl:%q", f.ID(), f.URL())
+	defer f.log.Debugf("Frame:WaitForURL:return", "fid:%s furl:%q", f.ID(), f.URL())
+
+	parsedOpts := NewFrameWaitForURLOptions(f.manager.timeoutSettings.navigationTimeout())
+	if err := parsedOpts.Parse(f.ctx, opts); err != nil {
+		k6ext.Panic(f.ctx, "parsing waitForURL options: %w", err)
+	}
+	if err := f.waitForURL(urlOrPredicate, parsedOpts); err != nil {
+		k6ext.Panic(f.ctx, "waiting for URL: %w", err)
+	}
+}
+
+func (f *Frame) waitForURL(urlOrPredicate goja.Value, opts *FrameWaitForURLOptions) error {
+	matchURL, err := newURLMatcher(f.vu.Runtime(), urlOrPredicate)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(f.ctx, opts.Timeout)
+	defer cancel()
+
+	// The events are registered before checking the current URL so that no
+	// navigation can slip in between.
+	ch := make(chan Event)
+	f.on(ctx, []string{EventFrameNavigation, EventFrameAddLifecycle}, ch)
+
+	matched, err := matchURL(f.URL())
+	if err != nil {
+		return err
+	}
+	for {
+		// The lifecycle events are cleared when a new document commits, and
+		// they stay as they are for the navigations within the same document.
+		if matched && f.hasSubtreeLifecycleEventFired(opts.WaitUntil) {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return fmt.Errorf("%w after %s", ErrTimedOut, opts.Timeout)
+			}
+			return ctx.Err()
+		case ev := <-ch:
+			nev, ok := ev.data.(*NavigationEvent)
+			if !ok || nev.err != nil {
+				continue
+			}
+			if matched, err = matchURL(nev.url); err != nil {
+				return err
+			}
+		}
+	}
+}
+
 func (f *Frame) adoptBackendNodeID(world executionWorld, id cdp.BackendNodeID) (*ElementHandle, error) {
 	f.log.Debugf("Frame:adoptBackendNodeID", "fid:%s furl:%q world:%s id:%d", f.ID(), f.URL(), world, id)
 
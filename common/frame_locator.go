@@ -0,0 +1,121 @@
+package common
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/xk6-browser/api"
+	"github.com/grafana/xk6-browser/log"
+
+	"github.com/dop251/goja"
+)
+
+// Ensure FrameLocator implements the api.FrameLocator interface.
+var _ api.FrameLocator = &FrameLocator{}
+
+// FrameLocator represents a way to find elements in an iframe at any moment.
+// The iframe is found again every time one of its locators is used, and the
+// locators wait for it to be attached and loaded with strict mode on.
+type FrameLocator struct {
+	selector string
+
+	frame *Frame
+
+	ctx context.Context
+	log *log.Logger
+}
+
+// NewFrameLocator creates and returns a new frame locator for the iframes
+// matching the selector in the frame.
+func NewFrameLocator(ctx context.Context, selector string, f *Frame, l *log.Logger) *FrameLocator {
+	return &FrameLocator{
+		selector: selector,
+		frame:    f,
+		ctx:      ctx,
+		log:      l,
+	}
+}
+
+// First returns a new frame locator matching the first iframe that matches
+// the frame locator's selector.
+func (fl *FrameLocator) First() api.FrameLocator {
+	fl.log.Debugf("FrameLocator:First", "fid:%s furl:%q sel:%q", fl.frame.ID(), fl.frame.URL(), fl.selector)
+
+	return fl.nth(0)
+}
+
+// Last returns a new frame locator matching the last iframe that matches
+// the frame locator's selector.
+func (fl *FrameLocator) Last() api.FrameLocator {
+	fl.log.Debugf("FrameLocator:Last", "fid:%s furl:%q sel:%q", fl.frame.ID(), fl.frame.URL(), fl.selector)
+
+	return fl.nth(-1)
+}
+
+// Nth returns a new frame locator matching the iframe at the given index
+// among the iframes matching the frame locator's selector.
+func (fl *FrameLocator) Nth(index int) api.FrameLocator {
+	fl.log.Debugf("FrameLocator:Nth", "fid:%s furl:%q sel:%q nth:%d", fl.frame.ID(), fl.frame.URL(), fl.selector, index)
+
+	return fl.nth(index)
+}
+
+func (fl *FrameLocator) nth(i int) *FrameLocator {
+	return NewFrameLocator(fl.ctx, fmt.Sprintf("%s >> nth=%d", fl.selector, i), fl.frame, fl.log)
+}
+
+// FrameLocator returns a new frame locator for the iframes matching the
+// selector in the frame locator's iframe.
+func (fl *FrameLocator) FrameLocator(selector string) api.FrameLocator {
+	fl.log.Debugf("FrameLocator:FrameLocator", "fid:%s furl:%q sel:%q inner:%q", fl.frame.ID(), fl.frame.URL(), fl.selector, selector)
+
+	return NewFrameLocator(fl.ctx, fl.selector+enterFrameSelector+selector, fl.frame, fl.log)
+}
+
+// GetByLabel returns a locator for the elements with a matching label in the
+// frame locator's iframe.
+func (fl *FrameLocator) GetByLabel(text goja.Value, opts goja.Value) api.Locator {
+	return fl.enter(fl.frame.GetByLabel(text, opts))
+}
+
+// GetByPlaceholder returns a locator for the input elements with a matching
+// placeholder in the frame locator's iframe.
+func (fl *FrameLocator) GetByPlaceholder(text goja.Value, opts goja.Value) api.Locator {
+	return fl.enter(fl.frame.GetByPlaceholder(text, opts))
+}
+
+// GetByRole returns a locator for the elements with the given ARIA role in
+// the frame locator's iframe.
+func (fl *FrameLocator) GetByRole(role string, opts goja.Value) api.Locator {
+	return fl.enter(fl.frame.GetByRole(role, opts))
+}
+
+// GetByTestId returns a locator for the elements with the given test ID in
+// the frame locator's iframe.
+func (fl *FrameLocator) GetByTestId(testID goja.Value) api.Locator { //nolint:revive,stylecheck
+	return fl.enter(fl.frame.GetByTestId(testID))
+}
+
+// GetByText returns a locator for the elements with a matching text in the
+// frame locator's iframe.
+func (fl *FrameLocator) GetByText(text goja.Value, opts goja.Value) api.Locator {
+	return fl.enter(fl.frame.GetByText(text, opts))
+}
+
+// Locator returns a locator for the elements matching the selector in the
+// frame locator's iframe.
+func (fl *FrameLocator) Locator(selector string, opts goja.Value) api.Locator {
+	fl.log.Debugf("FrameLocator:Locator", "fid:%s furl:%q sel:%q inner:%q", fl.frame.ID(), fl.frame.URL(), fl.selector, selector)
+
+	return NewLocator(fl.ctx, fl.selector+enterFrameSelector+selector, fl.frame, fl.log)
+}
+
+// enter returns a locator for the elements matched by the locator of the
+// frame in the frame locator's iframe.
+func (fl *FrameLocator) enter(l api.Locator) api.Locator {
+	inner, ok := l.(*Locator)
+	if !ok {
+		return l
+	}
+	return NewLocator(fl.ctx, fl.selector+enterFrameSelector+inner.selector, fl.frame, fl.log)
+}
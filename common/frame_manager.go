@@ -454,18 +454,18 @@ func (m *FrameManager) requestFailed(req *Request, canceled bool) {
 		m.logger.Debugf("FrameManager:requestFailed", "frame is nil")
 		return
 	}
-	frame.deleteRequest(req.getID())
-
-	switch rc := frame.inflightRequestsLen(); {
-	case rc == 0:
-		frame.startNetworkIdleTimer()
-	case rc <= 10:
-		for reqID := range frame.inflightRequests {
-			req := frame.requestByID(reqID)
+	if frame.deleteRequest(req.getID()) {
+		switch rc := frame.inflightRequestsLen(); {
+		case rc == 0:
+			frame.startNetworkIdleTimer()
+		case rc <= 10:
+			for reqID := range frame.inflightRequests {
+				req := frame.requestByID(reqID)
 
-			m.logger.Debugf("FrameManager:requestFailed:rc<=10",
-				"reqID:%s inflightURL:%s frameID:%s",
-				reqID, req.URL(), frame.ID())
+				m.logger.Debugf("FrameManager:requestFailed:rc<=10",
+					"reqID:%s inflightURL:%s frameID:%s",
+					reqID, req.URL(), frame.ID())
+			}
 		}
 	}
 
@@ -494,8 +494,7 @@ func (m *FrameManager) requestFinished(req *Request) {
 			"fmid:%d rurl:%s frame:nil", m.ID(), req.URL())
 		return
 	}
-	frame.deleteRequest(req.getID())
-	if frame.inflightRequestsLen() == 0 {
+	if frame.deleteRequest(req.getID()) && frame.inflightRequestsLen() == 0 {
 		frame.startNetworkIdleTimer()
 	}
 	/*
@@ -528,9 +527,11 @@ func (m *FrameManager) requestStarted(req *Request) {
 		return
 	}
 
-	frame.addRequest(req.getID())
-	if frame.inflightRequestsLen() == 1 {
-		frame.stopNetworkIdleTimer()
+	if !frame.ignoresForNetworkIdle(req) {
+		frame.addRequest(req.getID())
+		if frame.inflightRequestsLen() == 1 {
+			frame.stopNetworkIdleTimer()
+		}
 	}
 	if req.documentID != "" {
 		frame.pendingDocument = &DocumentInfo{documentID: req.documentID, request: req}
@@ -538,13 +539,22 @@ func (m *FrameManager) requestStarted(req *Request) {
 	m.logger.Debugf("FrameManager:requestStarted", "fmid:%d rurl:%s pdoc:nil", m.ID(), req.URL())
 }
 
-// Frames returns a list of frames on the page.
+// Frames returns a list of frames on the page, including the nested ones,
+// in the depth-first order of the frame tree starting with the main frame.
 func (m *FrameManager) Frames() []api.Frame {
-	m.framesMu.RLock()
-	defer m.framesMu.RUnlock()
 	frames := make([]api.Frame, 0)
-	for _, frame := range m.frames {
-		frames = append(frames, frame)
+	var walk func(*Frame)
+	walk = func(f *Frame) {
+		frames = append(frames, f)
+		f.childFramesMu.RLock()
+		children := append([]*Frame(nil), f.childFrames...)
+		f.childFramesMu.RUnlock()
+		for _, c := range children {
+			walk(c)
+		}
+	}
+	if main := m.MainFrame(); main != nil {
+		walk(main)
 	}
 	return frames
 }
@@ -583,7 +593,7 @@ func (m *FrameManager) NavigateFrame(frame *Frame, url string, opts goja.Value)
 
 	netMgr := m.page.mainFrameSession.getNetworkManager()
 	defaultReferer := netMgr.extraHTTPHeaders["referer"]
-	parsedOpts := NewFrameGotoOptions(defaultReferer, time.Duration(m.timeoutSettings.navigationTimeout())*time.Second)
+	parsedOpts := NewFrameGotoOptions(defaultReferer, m.timeoutSettings.navigationTimeout())
 	if err := parsedOpts.Parse(m.ctx, opts); err != nil {
 		k6ext.Panic(m.ctx, "parsing frame navigation options to %q: %v", url, err)
 	}
@@ -716,7 +726,7 @@ func (m *FrameManager) WaitForFrameNavigation(frame *Frame, opts goja.Value) api
 		"fmid:%d fid:%s furl:%s",
 		m.ID(), frame.ID(), frame.URL())
 
-	parsedOpts := NewFrameWaitForNavigationOptions(time.Duration(m.timeoutSettings.timeout()) * time.Second)
+	parsedOpts := NewFrameWaitForNavigationOptions(m.timeoutSettings.navigationTimeout())
 	if err := parsedOpts.Parse(m.ctx, opts); err != nil {
 		k6ext.Panic(m.ctx, "parsing wait for frame navigation options: %v", err)
 	}
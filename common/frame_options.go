@@ -41,6 +41,11 @@ type FrameCheckOptions struct {
 	Strict bool `json:"strict"`
 }
 
+type FrameClearOptions struct {
+	ElementHandleBaseOptions
+	Strict bool `json:"strict"`
+}
+
 type FrameClickOptions struct {
 	ElementHandleClickOptions
 	Strict bool `json:"strict"`
@@ -51,6 +56,16 @@ type FrameDblclickOptions struct {
 	Strict bool `json:"strict"`
 }
 
+type FrameDragAndDropOptions struct {
+	ElementHandleBaseOptions
+	SourcePosition *Position `json:"sourcePosition"`
+	TargetPosition *Position `json:"targetPosition"`
+	// Steps is the number of mouse moves from the source to the target.
+	Steps  int64 `json:"steps"`
+	Strict bool  `json:"strict"`
+	Trial  bool  `json:"trial"`
+}
+
 type FrameFillOptions struct {
 	ElementHandleBaseOptions
 	Strict bool `json:"strict"`
@@ -113,9 +128,22 @@ type FrameSelectOptionOptions struct {
 	Strict bool `json:"strict"`
 }
 
+type FrameSetCheckedOptions struct {
+	ElementHandleBasePointerOptions
+	Strict bool `json:"strict"`
+}
+
 type FrameSetContentOptions struct {
 	Timeout   time.Duration  `json:"timeout"`
 	WaitUntil LifecycleEvent `json:"waitUntil"`
+	// BaseURL is the URL that the relative URLs of the content resolve
+	// against. They resolve against the current URL of the frame otherwise.
+	BaseURL string `json:"baseURL"`
+}
+
+type FrameSetInputFilesOptions struct {
+	ElementHandleBaseOptions
+	Strict bool `json:"strict"`
 }
 
 type FrameTapOptions struct {
@@ -160,6 +188,30 @@ type FrameWaitForSelectorOptions struct {
 	Timeout time.Duration   `json:"timeout"`
 }
 
+func NewFrameSetInputFilesOptions(defaultTimeout time.Duration) *FrameSetInputFilesOptions {
+	return &FrameSetInputFilesOptions{
+		ElementHandleBaseOptions: *NewElementHandleBaseOptions(defaultTimeout),
+		Strict:                   false,
+	}
+}
+
+func (o *FrameSetInputFilesOptions) Parse(ctx context.Context, opts goja.Value) error {
+	rt := k6ext.Runtime(ctx)
+	if err := o.ElementHandleBaseOptions.Parse(ctx, opts); err != nil {
+		return err
+	}
+	if opts != nil && !goja.IsUndefined(opts) && !goja.IsNull(opts) {
+		opts := opts.ToObject(rt)
+		for _, k := range opts.Keys() {
+			switch k {
+			case "strict":
+				o.Strict = opts.Get(k).ToBoolean()
+			}
+		}
+	}
+	return nil
+}
+
 func NewFrameBaseOptions(defaultTimeout time.Duration) *FrameBaseOptions {
 	return &FrameBaseOptions{
 		Timeout: defaultTimeout,
@@ -207,6 +259,30 @@ func (o *FrameCheckOptions) Parse(ctx context.Context, opts goja.Value) error {
 	return nil
 }
 
+func NewFrameClearOptions(defaultTimeout time.Duration) *FrameClearOptions {
+	return &FrameClearOptions{
+		ElementHandleBaseOptions: *NewElementHandleBaseOptions(defaultTimeout),
+		Strict:                   false,
+	}
+}
+
+func (o *FrameClearOptions) Parse(ctx context.Context, opts goja.Value) error {
+	rt := k6ext.Runtime(ctx)
+	if err := o.ElementHandleBaseOptions.Parse(ctx, opts); err != nil {
+		return err
+	}
+	if opts != nil && !goja.IsUndefined(opts) && !goja.IsNull(opts) {
+		opts := opts.ToObject(rt)
+		for _, k := range opts.Keys() {
+			switch k {
+			case "strict":
+				o.Strict = opts.Get(k).ToBoolean()
+			}
+		}
+	}
+	return nil
+}
+
 func NewFrameClickOptions(defaultTimeout time.Duration) *FrameClickOptions {
 	return &FrameClickOptions{
 		ElementHandleClickOptions: *NewElementHandleClickOptions(defaultTimeout),
@@ -255,6 +331,50 @@ func (o *FrameDblclickOptions) Parse(ctx context.Context, opts goja.Value) error
 	return nil
 }
 
+func NewFrameDragAndDropOptions(defaultTimeout time.Duration) *FrameDragAndDropOptions {
+	return &FrameDragAndDropOptions{
+		ElementHandleBaseOptions: *NewElementHandleBaseOptions(defaultTimeout),
+		Steps:                    5,
+		Strict:                   false,
+		Trial:                    false,
+	}
+}
+
+func (o *FrameDragAndDropOptions) Parse(ctx context.Context, opts goja.Value) error {
+	rt := k6ext.Runtime(ctx)
+	if err := o.ElementHandleBaseOptions.Parse(ctx, opts); err != nil {
+		return err
+	}
+	if opts != nil && !goja.IsUndefined(opts) && !goja.IsNull(opts) {
+		opts := opts.ToObject(rt)
+		for _, k := range opts.Keys() {
+			switch k {
+			case "sourcePosition", "targetPosition":
+				var p map[string]float64
+				if err := rt.ExportTo(opts.Get(k), &p); err != nil {
+					return fmt.Errorf("parsing %s: %w", k, err)
+				}
+				pos := &Position{X: p["x"], Y: p["y"]}
+				if k == "sourcePosition" {
+					o.SourcePosition = pos
+				} else {
+					o.TargetPosition = pos
+				}
+			case "steps":
+				o.Steps = opts.Get(k).ToInteger()
+				if o.Steps < 1 {
+					return fmt.Errorf("steps must be greater than zero, got %d", o.Steps)
+				}
+			case "strict":
+				o.Strict = opts.Get(k).ToBoolean()
+			case "trial":
+				o.Trial = opts.Get(k).ToBoolean()
+			}
+		}
+	}
+	return nil
+}
+
 func NewFrameFillOptions(defaultTimeout time.Duration) *FrameFillOptions {
 	return &FrameFillOptions{
 		ElementHandleBaseOptions: *NewElementHandleBaseOptions(defaultTimeout),
@@ -486,6 +606,30 @@ func (o *FrameSelectOptionOptions) Parse(ctx context.Context, opts goja.Value) e
 	return nil
 }
 
+func NewFrameSetCheckedOptions(defaultTimeout time.Duration) *FrameSetCheckedOptions {
+	return &FrameSetCheckedOptions{
+		ElementHandleBasePointerOptions: *NewElementHandleBasePointerOptions(defaultTimeout),
+		Strict:                          false,
+	}
+}
+
+func (o *FrameSetCheckedOptions) Parse(ctx context.Context, opts goja.Value) error {
+	rt := k6ext.Runtime(ctx)
+	if err := o.ElementHandleBasePointerOptions.Parse(ctx, opts); err != nil {
+		return err
+	}
+	if opts != nil && !goja.IsUndefined(opts) && !goja.IsNull(opts) {
+		opts := opts.ToObject(rt)
+		for _, k := range opts.Keys() {
+			switch k {
+			case "strict":
+				o.Strict = opts.Get(k).ToBoolean()
+			}
+		}
+	}
+	return nil
+}
+
 func NewFrameSetContentOptions(defaultTimeout time.Duration) *FrameSetContentOptions {
 	return &FrameSetContentOptions{
 		Timeout:   defaultTimeout,
@@ -507,6 +651,8 @@ func (o *FrameSetContentOptions) Parse(ctx context.Context, opts goja.Value) err
 				if err := o.WaitUntil.UnmarshalText([]byte(lifeCycle)); err != nil {
 					return fmt.Errorf("parsing setContent options: %w", err)
 				}
+			case "baseURL":
+				o.BaseURL = opts.Get(k).String()
 			}
 		}
 	}
@@ -729,3 +875,37 @@ func NewFrameDispatchEventOptions(defaultTimeout time.Duration) *FrameDispatchEv
 		FrameBaseOptions: NewFrameBaseOptions(defaultTimeout),
 	}
 }
+
+// FrameWaitForURLOptions are options for Frame.waitForURL.
+type FrameWaitForURLOptions struct {
+	WaitUntil LifecycleEvent `json:"waitUntil"`
+	Timeout   time.Duration  `json:"timeout"`
+}
+
+// NewFrameWaitForURLOptions returns a new FrameWaitForURLOptions.
+func NewFrameWaitForURLOptions(defaultTimeout time.Duration) *FrameWaitForURLOptions {
+	return &FrameWaitForURLOptions{
+		WaitUntil: LifecycleEventLoad,
+		Timeout:   defaultTimeout,
+	}
+}
+
+// Parse parses the waitForURL options.
+func (o *FrameWaitForURLOptions) Parse(ctx context.Context, opts goja.Value) error {
+	rt := k6ext.Runtime(ctx)
+	if opts != nil && !goja.IsUndefined(opts) && !goja.IsNull(opts) {
+		opts := opts.ToObject(rt)
+		for _, k := range opts.Keys() {
+			switch k {
+			case "timeout":
+				o.Timeout = time.Duration(opts.Get(k).ToInteger()) * time.Millisecond
+			case "waitUntil":
+				lifeCycle := opts.Get(k).String()
+				if err := o.WaitUntil.UnmarshalText([]byte(lifeCycle)); err != nil {
+					return fmt.Errorf("parsing waitForURL options: %w", err)
+				}
+			}
+		}
+	}
+	return nil
+}
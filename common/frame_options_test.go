@@ -43,7 +43,7 @@ func TestFrameGotoOptionsParse(t *testing.T) {
 		assert.EqualError(t, err,
 			`parsing goto options: `+
 				`invalid lifecycle event: "none"; must be one of: `+
-				`load, domcontentloaded, networkidle`)
+				`load, domcontentloaded, networkidle, commit`)
 	})
 }
 
@@ -78,7 +78,7 @@ func TestFrameSetContentOptionsParse(t *testing.T) {
 		assert.EqualError(t, err,
 			`parsing setContent options: `+
 				`invalid lifecycle event: "none"; must be one of: `+
-				`load, domcontentloaded, networkidle`)
+				`load, domcontentloaded, networkidle, commit`)
 	})
 }
 
@@ -116,6 +116,6 @@ func TestFrameWaitForNavigationOptionsParse(t *testing.T) {
 		assert.EqualError(t, err,
 			`parsing waitForNavigation options: `+
 				`invalid lifecycle event: "none"; must be one of: `+
-				`load, domcontentloaded, networkidle`)
+				`load, domcontentloaded, networkidle, commit`)
 	})
 }
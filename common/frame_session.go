@@ -82,6 +82,12 @@ type FrameSession struct {
 	childSessions map[cdp.FrameID]*FrameSession
 	vu            k6modules.VU
 
+	// clockScriptID is the ID of the init script of the clock of the page.
+	clockScriptID cdppage.ScriptIdentifier
+	// storageStateScriptID is the ID of the init script that restores the
+	// local storage of the storageState option of the browser context.
+	storageStateScriptID cdppage.ScriptIdentifier
+
 	logger *log.Logger
 	// logger that will properly serialize RemoteObject instances
 	serializer *log.Logger
@@ -248,6 +254,10 @@ func (fs *FrameSession) initEvents() {
 					fs.onTargetCrashed(ev)
 				case *cdplog.EventEntryAdded:
 					fs.onLogEntryAdded(ev)
+				case *cdppage.EventFileChooserOpened:
+					// The handlers set the files through this session,
+					// so don't block the event loop while they run.
+					go fs.onFileChooserOpened(ev)
 				case *cdppage.EventFrameAttached:
 					fs.onFrameAttached(ev.FrameID, ev.ParentFrameID)
 				case *cdppage.EventFrameDetached:
@@ -261,10 +271,16 @@ func (fs *FrameSession) initEvents() {
 					fs.onFrameStartedLoading(ev.FrameID)
 				case *cdppage.EventFrameStoppedLoading:
 					fs.onFrameStoppedLoading(ev.FrameID)
+				case *cdppage.EventJavascriptDialogOpening:
+					fs.onJavascriptDialogOpening(ev)
 				case *cdppage.EventLifecycleEvent:
 					fs.onPageLifecycle(ev)
 				case *cdppage.EventNavigatedWithinDocument:
 					fs.onPageNavigatedWithinDocument(ev)
+				case *cdpruntime.EventBindingCalled:
+					// The callback can wait for the events of this session,
+					// so don't block the event loop while it runs.
+					go fs.onBindingCalled(ev)
 				case *cdpruntime.EventConsoleAPICalled:
 					fs.onConsoleAPICalled(ev)
 				case *cdpruntime.EventExceptionThrown:
@@ -370,7 +386,6 @@ func (fs *FrameSession) initOptions() error {
 	var (
 		opts       = fs.manager.page.browserCtx.opts
 		optActions = []Action{}
-		state      = fs.vu.State()
 	)
 
 	if fs.isMainFrame() {
@@ -381,6 +396,9 @@ func (fs *FrameSession) initOptions() error {
 				fs.session.ID(), fs.targetID, err)
 			return err
 		}
+		if err := fs.addVirtualAuthenticators(); err != nil {
+			return err
+		}
 	}
 	if opts.BypassCSP {
 		optActions = append(optActions, cdppage.SetBypassCSP(true))
@@ -412,28 +430,40 @@ func (fs *FrameSession) initOptions() error {
 	}
 	fs.updateExtraHTTPHeaders(true)
 
-	var reqIntercept bool
-	if state.Options.BlockedHostnames.Trie != nil ||
-		len(state.Options.BlacklistIPs) > 0 {
-		reqIntercept = true
+	if err := fs.updateRequestInterception(true); err != nil {
+		return err
 	}
-	if err := fs.updateRequestInterception(reqIntercept); err != nil {
+	if err := fs.updateFileChooserInterception(true); err != nil {
 		return err
 	}
 
 	fs.updateOffline(true)
+	fs.updateNetworkConditions(true)
 	fs.updateHTTPCredentials(true)
 	if err := fs.updateEmulateMedia(true); err != nil {
 		return err
 	}
+	if err := fs.initBindings(); err != nil {
+		return err
+	}
 
 	// if (screencastOptions)
 	//   promises.push(this._startVideoRecording(screencastOptions));
 
-	/*for (const source of this._crPage._browserContext._evaluateOnNewDocumentSources)
-	      promises.push(this._evaluateOnNewDocument(source, 'main'));
-	  for (const source of this._crPage._page._evaluateOnNewDocumentSources)
-	      promises.push(this._evaluateOnNewDocument(source, 'main'));*/
+	if err := fs.page.browserCtx.addStorageStateScript(fs); err != nil {
+		return err
+	}
+	// The browser context scripts run before the page scripts.
+	sources := append([]string{}, fs.page.browserCtx.evaluateOnNewDocumentSources...)
+	sources = append(sources, fs.page.evaluateOnNewDocumentSources...)
+	for _, source := range sources {
+		if err := fs.evaluateOnNewDocument(source); err != nil {
+			return err
+		}
+	}
+	if err := fs.page.Clock.addInitScript(fs); err != nil {
+		return err
+	}
 
 	optActions = append(optActions, cdpruntime.RunIfWaitingForDebugger())
 
@@ -446,6 +476,84 @@ func (fs *FrameSession) initOptions() error {
 	return nil
 }
 
+// initBindings adds the bindings used by the exposed functions and the web
+// vitals collection, and installs the functions the page already exposes.
+func (fs *FrameSession) initBindings() error {
+	for _, name := range []string{bindingName, webVitalBindingName, storageStateBindingName} {
+		action := cdpruntime.AddBinding(name)
+		if err := action.Do(cdp.WithExecutor(fs.ctx, fs.session)); err != nil {
+			return fmt.Errorf("adding binding %q: %w", name, err)
+		}
+	}
+	if err := fs.evaluateOnNewDocument(fmt.Sprintf(webVitalsInitScript, webVitalBindingName)); err != nil {
+		return fmt.Errorf("installing web vitals collection: %w", err)
+	}
+	for _, b := range fs.page.getBindings() {
+		if err := fs.addBindingScript(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (fs *FrameSession) addBindingScript(b *pageBinding) error {
+	if err := fs.evaluateOnNewDocument(b.source); err != nil {
+		return fmt.Errorf("installing exposed function %q: %w", b.name, err)
+	}
+	return nil
+}
+
+func (fs *FrameSession) evaluateOnNewDocument(source string) error {
+	action := cdppage.AddScriptToEvaluateOnNewDocument(source)
+	if _, err := action.Do(cdp.WithExecutor(fs.ctx, fs.session)); err != nil {
+		return fmt.Errorf("adding script to evaluate on new document: %w", err)
+	}
+	return nil
+}
+
+// setClockScript replaces the init script of the clock of the page with
+// the source. It is called with the lock of the clock held.
+func (fs *FrameSession) setClockScript(source string) error {
+	if err := fs.replaceScript(&fs.clockScriptID, source); err != nil {
+		return fmt.Errorf("replacing clock script: %w", err)
+	}
+	return nil
+}
+
+// setStorageStateScript replaces the init script that restores the local
+// storage of the storage state with the source, or removes it if the
+// source is empty. It is called with the storage state lock of the browser
+// context held.
+func (fs *FrameSession) setStorageStateScript(source string) error {
+	if err := fs.replaceScript(&fs.storageStateScriptID, source); err != nil {
+		return fmt.Errorf("replacing storage state script: %w", err)
+	}
+	return nil
+}
+
+// replaceScript removes the init script of the ID if there's one, and adds
+// the source as an init script with its ID saved to id, if it isn't empty.
+func (fs *FrameSession) replaceScript(id *cdppage.ScriptIdentifier, source string) error {
+	if *id != "" {
+		action := cdppage.RemoveScriptToEvaluateOnNewDocument(*id)
+		if err := action.Do(cdp.WithExecutor(fs.ctx, fs.session)); err != nil {
+			return fmt.Errorf("removing script to evaluate on new document: %w", err)
+		}
+		*id = ""
+	}
+	if source == "" {
+		return nil
+	}
+	action := cdppage.AddScriptToEvaluateOnNewDocument(source)
+	sid, err := action.Do(cdp.WithExecutor(fs.ctx, fs.session))
+	if err != nil {
+		return fmt.Errorf("adding script to evaluate on new document: %w", err)
+	}
+	*id = sid
+
+	return nil
+}
+
 func (fs *FrameSession) initRendererEvents() {
 	fs.logger.Debugf("NewFrameSession:initEvents:initRendererEvents",
 		"sid:%v tid:%v", fs.session.ID(), fs.targetID)
@@ -462,6 +570,7 @@ func (fs *FrameSession) initRendererEvents() {
 		cdproto.EventPageJavascriptDialogOpening,
 		cdproto.EventPageLifecycleEvent,
 		cdproto.EventPageNavigatedWithinDocument,
+		cdproto.EventRuntimeBindingCalled,
 		cdproto.EventRuntimeConsoleAPICalled,
 		cdproto.EventRuntimeExceptionThrown,
 		cdproto.EventRuntimeExecutionContextCreated,
@@ -510,10 +619,64 @@ func (fs *FrameSession) navigateFrame(frame *Frame, url, referrer string) (strin
 	return documentID.String(), err
 }
 
+func (fs *FrameSession) onBindingCalled(event *cdpruntime.EventBindingCalled) {
+	fs.logger.Debugf("FrameSession:onBindingCalled",
+		"sid:%v tid:%v name:%s ectxid:%d",
+		fs.session.ID(), fs.targetID, event.Name, event.ExecutionContextID)
+
+	if event.Name == webVitalBindingName {
+		fs.onWebVitals(event.Payload)
+		return
+	}
+	if event.Name == storageStateBindingName {
+		fs.page.browserCtx.onStorageStateRestored(fs, event.Payload)
+		return
+	}
+	if event.Name != bindingName {
+		return
+	}
+	payload, err := parseBindingPayload(event.Payload)
+	if err != nil {
+		fs.logger.Errorf("FrameSession:onBindingCalled", "sid:%v tid:%v err:%v",
+			fs.session.ID(), fs.targetID, err)
+		return
+	}
+
+	fs.contextIDToContextMu.Lock()
+	execCtx := fs.contextIDToContext[event.ExecutionContextID]
+	fs.contextIDToContextMu.Unlock()
+	if execCtx == nil {
+		fs.logger.Debugf("FrameSession:onBindingCalled",
+			"sid:%v tid:%v ectxid:%d execution context not found",
+			fs.session.ID(), fs.targetID, event.ExecutionContextID)
+		return
+	}
+
+	fs.page.onBindingCalled(execCtx, payload)
+}
+
 func (fs *FrameSession) onConsoleAPICalled(event *cdpruntime.EventConsoleAPICalled) {
+	if fs.page.hasEventHandlers(EventPageConsole) {
+		fs.contextIDToContextMu.Lock()
+		execCtx := fs.contextIDToContext[event.ExecutionContextID]
+		fs.contextIDToContextMu.Unlock()
+		fs.page.callEventHandlers(EventPageConsole, NewConsoleMessage(fs.page, execCtx, event), nil)
+	}
+
+	level := consoleMessageLogLevel(event.Type)
+	if !fs.page.browserCtx.opts.logsBrowserConsole(level) {
+		return
+	}
+
 	l := fs.serializer.
 		WithTime(event.Timestamp.Time()).
-		WithField("source", "browser-console-api")
+		WithField("source", "browser-console-api").
+		WithField("url", fs.page.URL())
+	if st := event.StackTrace; st != nil && len(st.CallFrames) > 0 {
+		// the line and column numbers are zero based.
+		cf := st.CallFrames[0]
+		l = l.WithField("location", fmt.Sprintf("%s:%d:%d", cf.URL, cf.LineNumber+1, cf.ColumnNumber+1))
+	}
 
 	if s := fs.vu.State(); s.Group.Path != "" {
 		l = l.WithField("group", s.Group.Path)
@@ -530,20 +693,11 @@ func (fs *FrameSession) onConsoleAPICalled(event *cdpruntime.EventConsoleAPICall
 
 	l = l.WithField("objects", parsedObjects)
 
-	switch event.Type {
-	case "log", "info":
-		l.Info()
-	case "warning":
-		l.Warn()
-	case "error":
-		l.Error()
-	default:
-		l.Debug()
-	}
+	l.Log(level)
 }
 
 func (fs *FrameSession) onExceptionThrown(event *cdpruntime.EventExceptionThrown) {
-	fs.page.emit(EventPageError, event.ExceptionDetails)
+	fs.page.callEventHandlers(EventPageError, NewPageError(event.ExceptionDetails), nil)
 }
 
 func (fs *FrameSession) onExecutionContextCreated(event *cdpruntime.EventExecutionContextCreated) {
@@ -621,6 +775,26 @@ func (fs *FrameSession) onExecutionContextsCleared() {
 	}
 }
 
+func (fs *FrameSession) onFileChooserOpened(event *cdppage.EventFileChooserOpened) {
+	fs.logger.Debugf("FrameSession:onFileChooserOpened",
+		"sid:%v tid:%v fid:%v mode:%s",
+		fs.session.ID(), fs.targetID, event.FrameID, event.Mode)
+
+	frame := fs.manager.getFrameByID(event.FrameID)
+	if frame == nil {
+		return
+	}
+	element, err := frame.adoptBackendNodeID(mainWorld, event.BackendNodeID)
+	if err != nil {
+		fs.logger.Errorf("FrameSession:onFileChooserOpened",
+			"sid:%v tid:%v err:%v", fs.session.ID(), fs.targetID, err)
+		return
+	}
+	multiple := event.Mode == cdppage.FileChooserOpenedModeSelectMultiple
+	chooser := NewFileChooser(fs.ctx, fs.page, element, multiple, fs.logger)
+	fs.page.callEventHandlers(EventPageFilechooser, chooser, nil)
+}
+
 func (fs *FrameSession) onFrameAttached(frameID cdp.FrameID, parentFrameID cdp.FrameID) {
 	fs.logger.Debugf("FrameSession:onFrameAttached",
 		"sid:%v tid:%v fid:%v pfid:%v",
@@ -681,6 +855,26 @@ func (fs *FrameSession) onFrameStoppedLoading(frameID cdp.FrameID) {
 	fs.manager.frameLoadingStopped(frameID)
 }
 
+func (fs *FrameSession) onJavascriptDialogOpening(event *cdppage.EventJavascriptDialogOpening) {
+	fs.logger.Debugf("FrameSession:onJavascriptDialogOpening",
+		"sid:%v tid:%v url:%s type:%s",
+		fs.session.ID(), fs.targetID, event.URL, event.Type)
+
+	dialog := NewDialog(fs.ctx, fs.session, event, fs.logger)
+	// the script handles the dialog it waits for, otherwise the dialog is
+	// handled after the handlers are called.
+	waited := fs.page.hasEventWaiters(EventPageDialog)
+	fs.page.callEventHandlers(EventPageDialog, dialog, func() {
+		if waited {
+			return
+		}
+		if err := dialog.handleDefault(); err != nil {
+			fs.logger.Errorf("FrameSession:onJavascriptDialogOpening",
+				"sid:%v tid:%v err:%v", fs.session.ID(), fs.targetID, err)
+		}
+	})
+}
+
 func (fs *FrameSession) onLogEntryAdded(event *cdplog.EventEntryAdded) {
 	l := fs.logger.
 		WithTime(event.Entry.Timestamp.Time()).
@@ -882,25 +1076,25 @@ func (fs *FrameSession) onTargetCrashed(event *inspector.EventTargetCrashed) {
 	fs.page.didCrash()
 }
 
+// addVirtualAuthenticators adds the virtual WebAuthn authenticators of the
+// browser context to the target.
+func (fs *FrameSession) addVirtualAuthenticators() error {
+	for _, a := range fs.manager.page.browserCtx.virtualAuthenticatorsFor(fs.session) {
+		if err := a.attach(fs.ctx, fs.session); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (fs *FrameSession) updateEmulateMedia(initial bool) error {
 	fs.logger.Debugf("NewFrameSession:updateEmulateMedia", "sid:%v tid:%v", fs.session.ID(), fs.targetID)
 
-	features := make([]*emulation.MediaFeature, 0)
-
-	switch fs.page.colorScheme {
-	case ColorSchemeLight:
-		features = append(features, &emulation.MediaFeature{Name: "prefers-color-scheme", Value: "light"})
-	case ColorSchemeDark:
-		features = append(features, &emulation.MediaFeature{Name: "prefers-color-scheme", Value: "dark"})
-	default:
-		features = append(features, &emulation.MediaFeature{Name: "prefers-color-scheme", Value: ""})
-	}
-
-	switch fs.page.reducedMotion {
-	case ReducedMotionReduce:
-		features = append(features, &emulation.MediaFeature{Name: "prefers-reduced-motion", Value: "reduce"})
-	default:
-		features = append(features, &emulation.MediaFeature{Name: "prefers-reduced-motion", Value: ""})
+	// An empty feature value resets the feature to the system default.
+	features := []*emulation.MediaFeature{
+		{Name: "prefers-color-scheme", Value: string(fs.page.colorScheme)},
+		{Name: "prefers-reduced-motion", Value: string(fs.page.reducedMotion)},
+		{Name: "forced-colors", Value: string(fs.page.forcedColors)},
 	}
 
 	action := emulation.SetEmulatedMedia().
@@ -915,13 +1109,14 @@ func (fs *FrameSession) updateEmulateMedia(initial bool) error {
 func (fs *FrameSession) updateExtraHTTPHeaders(initial bool) {
 	fs.logger.Debugf("NewFrameSession:updateExtraHTTPHeaders", "sid:%v tid:%v", fs.session.ID(), fs.targetID)
 
-	// Merge extra headers from browser context and page, where page specific headers ake precedence.
+	// Merge extra headers from browser context and page, where page specific headers take precedence.
+	// Header names are case-insensitive, so they are merged by their lowercase names.
 	mergedHeaders := make(network.Headers)
 	for k, v := range fs.page.browserCtx.opts.ExtraHTTPHeaders {
-		mergedHeaders[k] = v
+		mergedHeaders[strings.ToLower(k)] = v
 	}
 	for k, v := range fs.page.extraHTTPHeaders {
-		mergedHeaders[k] = v
+		mergedHeaders[strings.ToLower(k)] = v
 	}
 	if !initial || len(mergedHeaders) > 0 {
 		fs.networkManager.SetExtraHTTPHeaders(mergedHeaders)
@@ -932,14 +1127,23 @@ func (fs *FrameSession) updateGeolocation(initial bool) error {
 	fs.logger.Debugf("NewFrameSession:updateGeolocation", "sid:%v tid:%v", fs.session.ID(), fs.targetID)
 
 	geolocation := fs.page.browserCtx.opts.Geolocation
-	if !initial || geolocation != nil {
-		action := emulation.SetGeolocationOverride().
-			WithLatitude(geolocation.Latitude).
-			WithLongitude(geolocation.Longitude).
-			WithAccuracy(geolocation.Accurracy)
+	if geolocation == nil {
+		if initial {
+			return nil
+		}
+		action := emulation.ClearGeolocationOverride()
 		if err := action.Do(cdp.WithExecutor(fs.ctx, fs.session)); err != nil {
-			return fmt.Errorf("%w", err)
+			return fmt.Errorf("clearing geolocation override: %w", err)
 		}
+		return nil
+	}
+
+	action := emulation.SetGeolocationOverride().
+		WithLatitude(geolocation.Latitude).
+		WithLongitude(geolocation.Longitude).
+		WithAccuracy(geolocation.Accurracy)
+	if err := action.Do(cdp.WithExecutor(fs.ctx, fs.session)); err != nil {
+		return fmt.Errorf("setting geolocation override: %w", err)
 	}
 	return nil
 }
@@ -962,13 +1166,54 @@ func (fs *FrameSession) updateOffline(initial bool) {
 	}
 }
 
-func (fs *FrameSession) updateRequestInterception(enable bool) error {
+func (fs *FrameSession) updateNetworkConditions(initial bool) {
+	fs.logger.Debugf("NewFrameSession:updateNetworkConditions", "sid:%v tid:%v", fs.session.ID(), fs.targetID)
+
+	conditions := fs.page.browserCtx.opts.NetworkConditions
+	if conditions == nil {
+		if initial {
+			return
+		}
+		conditions = NewNetworkConditions()
+	}
+	fs.networkManager.SetNetworkConditions(*conditions)
+}
+
+func (fs *FrameSession) updateRequestInterception(initial bool) error {
+	state := fs.vu.State()
+	enable := state.Options.BlockedHostnames.Trie != nil ||
+		len(state.Options.BlacklistIPs) > 0 ||
+		fs.page.hasRoutes()
+
 	fs.logger.Debugf("NewFrameSession:updateRequestInterception",
 		"sid:%v tid:%v on:%v",
 		fs.session.ID(),
 		fs.targetID, enable)
 
-	return fs.networkManager.setRequestInterception(enable || fs.page.hasRoutes())
+	if !initial || enable {
+		return fs.networkManager.setRequestInterception(enable)
+	}
+	return nil
+}
+
+// updateFileChooserInterception intercepts the file chooser dialogs while
+// the page has file chooser handlers.
+func (fs *FrameSession) updateFileChooserInterception(initial bool) error {
+	enable := fs.page.hasEventHandlers(EventPageFilechooser)
+
+	fs.logger.Debugf("NewFrameSession:updateFileChooserInterception",
+		"sid:%v tid:%v on:%v",
+		fs.session.ID(),
+		fs.targetID, enable)
+
+	if initial && !enable {
+		return nil
+	}
+	action := cdppage.SetInterceptFileChooserDialog(enable)
+	if err := action.Do(cdp.WithExecutor(fs.ctx, fs.session)); err != nil {
+		return fmt.Errorf("setting file chooser interception: %w", err)
+	}
+	return nil
 }
 
 func (fs *FrameSession) updateViewport() error {
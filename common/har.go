@@ -0,0 +1,365 @@
+package common
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/grafana/xk6-browser/log"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/network"
+)
+
+// HARContent is the policy for storing the response bodies in a HAR file.
+type HARContent string
+
+const (
+	// HARContentOmit leaves the response bodies out of the HAR file.
+	HARContentOmit HARContent = "omit"
+	// HARContentEmbed embeds the response bodies in the HAR file.
+	HARContentEmbed HARContent = "embed"
+)
+
+// RecordHAROptions are the options for recording the network activity of a
+// browser context to a HAR file.
+type RecordHAROptions struct {
+	Path    string     `js:"path"`
+	Content HARContent `js:"content"`
+}
+
+// The types below follow the HAR 1.2 specification.
+// See http://www.softwareishard.com/blog/har-12-spec for details.
+
+type harLog struct {
+	Log harLogContent `json:"log"`
+}
+
+type harLogContent struct {
+	Version string      `json:"version"`
+	Creator harCreator  `json:"creator"`
+	Entries []*harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime time.Time   `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+	ServerIPAddress string      `json:"serverIPAddress,omitempty"`
+	// FromCache is "memory" or "disk" for the responses served from the
+	// browser cache, like in the HAR files exported by the Chrome DevTools.
+	FromCache string `json:"_fromCache,omitempty"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int64          `json:"headersSize"`
+	BodySize    int64          `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int64          `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int64          `json:"headersSize"`
+	BodySize    int64          `json:"bodySize"`
+	// Error is the network error of the failed requests.
+	Error string `json:"_error,omitempty"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harContent struct {
+	Size     int64  `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+// harTimings are in milliseconds, -1 means that the timing doesn't apply
+// to the request.
+type harTimings struct {
+	Blocked float64 `json:"blocked"`
+	DNS     float64 `json:"dns"`
+	Connect float64 `json:"connect"`
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+	SSL     float64 `json:"ssl"`
+}
+
+// harRecorder records the network activity of a browser context and writes
+// it to a HAR file when it's flushed.
+type harRecorder struct {
+	opts   *RecordHAROptions
+	logger *log.Logger
+	// filter limits the recorded requests to the ones it returns true for.
+	filter func(*Request) bool
+
+	// pending tracks the response bodies that are being fetched.
+	pending sync.WaitGroup
+
+	mu      sync.Mutex
+	entries []*harEntry
+	// closed stops recording the entries before the pending response bodies
+	// are waited for, so that none are added while waiting.
+	closed  bool
+	flushed bool
+}
+
+func newHARRecorder(opts *RecordHAROptions, filter func(*Request) bool, logger *log.Logger) *harRecorder {
+	return &harRecorder{
+		opts:   opts,
+		logger: logger,
+		filter: filter,
+	}
+}
+
+// record adds an entry for the finished, failed or redirected request that
+// ended at the given time.
+func (h *harRecorder) record(req *Request, end time.Time) {
+	if isInternalURL(req.url) || (h.filter != nil && !h.filter(req)) {
+		return
+	}
+	e := newHAREntry(req, end)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return
+	}
+	h.entries = append(h.entries, e)
+
+	resp := req.response
+	if h.opts.Content != HARContentEmbed || resp == nil || req.errorText != "" ||
+		(resp.status >= 300 && resp.status <= 399) {
+		return
+	}
+	// the body is fetched asynchronously since the requests are recorded
+	// while handling the network events of the session.
+	h.pending.Add(1)
+	go func() {
+		defer h.pending.Done()
+		if err := resp.fetchBody(); err != nil {
+			h.logger.Debugf("harRecorder:record", "url:%s err:%v", req.URL(), err)
+			return
+		}
+		resp.bodyMu.RLock()
+		body := resp.body
+		resp.bodyMu.RUnlock()
+
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		e.Response.Content.Size = int64(len(body))
+		if utf8.Valid(body) {
+			e.Response.Content.Text = string(body)
+		} else {
+			e.Response.Content.Text = base64.StdEncoding.EncodeToString(body)
+			e.Response.Content.Encoding = "base64"
+		}
+	}()
+}
+
+// flush writes the recorded entries to the HAR file. The entries recorded
+// afterwards are ignored.
+func (h *harRecorder) flush() error {
+	h.mu.Lock()
+	h.closed = true
+	h.mu.Unlock()
+	h.pending.Wait()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.flushed {
+		return nil
+	}
+	h.flushed = true
+
+	sort.SliceStable(h.entries, func(i, j int) bool {
+		return h.entries[i].StartedDateTime.Before(h.entries[j].StartedDateTime)
+	})
+	entries := h.entries
+	if entries == nil {
+		entries = []*harEntry{}
+	}
+	b, err := json.MarshalIndent(harLog{
+		Log: harLogContent{
+			Version: "1.2",
+			Creator: harCreator{Name: "xk6-browser"},
+			Entries: entries,
+		},
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding HAR: %w", err)
+	}
+	if err := ioutil.WriteFile(h.opts.Path, b, 0o600); err != nil {
+		return fmt.Errorf("writing HAR file: %w", err)
+	}
+
+	return nil
+}
+
+func newHAREntry(req *Request, end time.Time) *harEntry {
+	req.postDataMu.RLock()
+	postData := req.postData
+	req.postDataMu.RUnlock()
+
+	e := &harEntry{
+		StartedDateTime: req.wallTime,
+		Time:            durationMs(end.Sub(req.timestamp)),
+		Request: harRequest{
+			Method:      req.method,
+			URL:         req.URL(),
+			HTTPVersion: "HTTP/1.1",
+			Cookies:     []harNameValue{},
+			Headers:     harHeaders(req.headers),
+			QueryString: []harNameValue{},
+			HeadersSize: -1,
+			BodySize:    int64(len(postData)),
+		},
+		Response: harResponse{
+			Cookies:     []harNameValue{},
+			Headers:     []harNameValue{},
+			HeadersSize: -1,
+			BodySize:    -1,
+			Error:       req.errorText,
+		},
+		Timings: harTimings{Blocked: -1, DNS: -1, Connect: -1, Send: 0, Wait: 0, Receive: 0, SSL: -1},
+	}
+	for k, vs := range req.url.Query() {
+		for _, v := range vs {
+			e.Request.QueryString = append(e.Request.QueryString, harNameValue{Name: k, Value: v})
+		}
+	}
+	if postData != nil {
+		e.Request.PostData = &harPostData{
+			MimeType: headerValue(req.headers, "Content-Type"),
+			Text:     string(postData),
+		}
+	}
+	if req.fromMemoryCache {
+		e.FromCache = "memory"
+	}
+
+	resp := req.response
+	if resp == nil {
+		return e
+	}
+	if resp.protocol != "" {
+		e.Request.HTTPVersion = resp.protocol
+	}
+	e.Response.Status = resp.status
+	e.Response.StatusText = resp.statusText
+	e.Response.HTTPVersion = e.Request.HTTPVersion
+	e.Response.Headers = harHeaders(resp.headers)
+	e.Response.RedirectURL = headerValue(resp.headers, "Location")
+	e.Response.Content.MimeType = headerValue(resp.headers, "Content-Type")
+	e.Response.Content.Size = resp.bodySize()
+	if resp.remoteAddress != nil {
+		e.ServerIPAddress = resp.remoteAddress.IPAddress
+	}
+	switch {
+	case req.fromMemoryCache:
+		// the cached responses didn't go through the network.
+		e.Response.BodySize = 0
+	case resp.fromDiskCache:
+		e.FromCache = "disk"
+		e.Response.BodySize = 0
+	default:
+		e.Response.BodySize = e.Response.Content.Size
+	}
+
+	if t := resp.timing; t != nil && e.FromCache == "" {
+		e.Timings = harTimingsFrom(t, end)
+	}
+
+	return e
+}
+
+// harTimingsFrom converts the timing of a response that ended at the given
+// time to HAR timings.
+func harTimingsFrom(t *network.ResourceTiming, end time.Time) harTimings {
+	span := func(start, end float64) float64 {
+		if start < 0 || end < 0 {
+			return -1
+		}
+		return end - start
+	}
+	timings := harTimings{
+		DNS:     span(t.DNSStart, t.DNSEnd),
+		Connect: span(t.ConnectStart, t.ConnectEnd),
+		SSL:     span(t.SslStart, t.SslEnd),
+		Send:    span(t.SendStart, t.SendEnd),
+		Wait:    span(t.SendEnd, t.ReceiveHeadersEnd),
+		Blocked: -1,
+	}
+	// the request is blocked until its first phase starts.
+	for _, start := range []float64{t.DNSStart, t.ConnectStart, t.SendStart} {
+		if start >= 0 {
+			timings.Blocked = start
+			break
+		}
+	}
+	requestTime := cdp.MonotonicTimeEpoch.Add(time.Duration(t.RequestTime * float64(time.Second)))
+	if receive := durationMs(end.Sub(requestTime)) - t.ReceiveHeadersEnd; receive > 0 {
+		timings.Receive = receive
+	}
+	return timings
+}
+
+func harHeaders(headers map[string][]string) []harNameValue {
+	hs := make([]harNameValue, 0, len(headers))
+	for n, vs := range headers {
+		for _, v := range vs {
+			hs = append(hs, harNameValue{Name: n, Value: v})
+		}
+	}
+	sort.SliceStable(hs, func(i, j int) bool { return hs[i].Name < hs[j].Name })
+	return hs
+}
+
+// headerValue returns the value of the header with a case-insensitive name.
+func headerValue(headers map[string][]string, name string) string {
+	for n, vs := range headers {
+		if strings.EqualFold(n, name) {
+			return strings.Join(vs, ",")
+		}
+	}
+	return ""
+}
+
+func durationMs(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,143 @@
+package common
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/chromedp/cdproto/fetch"
+	"github.com/chromedp/cdproto/network"
+)
+
+// harRouter serves the intercepted requests from the entries of a HAR file.
+type harRouter struct {
+	entries  []*harEntry
+	notFound HARNotFound
+	query    HARQueryMatch
+}
+
+// newHARRouter loads the HAR file at path.
+func newHARRouter(path string, opts *RouteFromHAROptions) (*harRouter, error) {
+	b, err := ioutil.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("reading HAR file: %w", err)
+	}
+	var har harLog
+	if err := json.Unmarshal(b, &har); err != nil {
+		return nil, fmt.Errorf("parsing HAR file %q: %w", path, err)
+	}
+	return &harRouter{
+		entries:  har.Log.Entries,
+		notFound: opts.NotFound,
+		query:    opts.Query,
+	}, nil
+}
+
+// route fulfills the route with the response of the matching HAR entry.
+// The routes without a matching entry are aborted, or left unhandled if
+// the notFound policy is fallback.
+func (h *harRouter) route(r *Route) error {
+	e := h.find(r.request.method, r.request.URL())
+	if e == nil {
+		if h.notFound == HARNotFoundFallback {
+			return nil
+		}
+		return r.abort(network.ErrorReasonFailed)
+	}
+	body, err := e.Response.Content.bytes()
+	if err != nil {
+		return fmt.Errorf("decoding HAR response body of %q: %w", e.Request.URL, err)
+	}
+	headers := make([]*fetch.HeaderEntry, 0, len(e.Response.Headers))
+	for _, hdr := range e.Response.Headers {
+		// the body is stored decoded, and its length may have changed.
+		if strings.EqualFold(hdr.Name, "Content-Encoding") || strings.EqualFold(hdr.Name, "Content-Length") {
+			continue
+		}
+		headers = append(headers, &fetch.HeaderEntry{Name: hdr.Name, Value: hdr.Value})
+	}
+	if err := r.fulfill(e.Response.Status, headers, body); err != nil {
+		return fmt.Errorf("fulfilling request from HAR: %w", err)
+	}
+	return nil
+}
+
+// find returns the first entry with a response matching the method and URL,
+// or nil if there isn't any.
+func (h *harRouter) find(method, rawURL string) *harEntry {
+	for _, e := range h.entries {
+		// the failed requests don't have a response to replay.
+		if e.Response.Status == 0 {
+			continue
+		}
+		if !strings.EqualFold(e.Request.Method, method) {
+			continue
+		}
+		if matchHARURL(e.Request.URL, rawURL, h.query) {
+			return e
+		}
+	}
+	return nil
+}
+
+// matchHARURL reports whether the URL of a HAR entry matches the URL of a
+// request, comparing their query strings with the given strategy. The URL
+// fragments are ignored.
+func matchHARURL(entryURL, reqURL string, query HARQueryMatch) bool {
+	eu, err := url.Parse(entryURL)
+	if err != nil {
+		return false
+	}
+	ru, err := url.Parse(reqURL)
+	if err != nil {
+		return false
+	}
+	eu.Fragment, ru.Fragment = "", ""
+	eq, rq := eu.RawQuery, ru.RawQuery
+	eu.RawQuery, ru.RawQuery = "", ""
+	if eu.String() != ru.String() {
+		return false
+	}
+
+	switch query {
+	case HARQueryMatchIgnore:
+		return true
+	case HARQueryMatchUnordered:
+		return reflect.DeepEqual(parseQuery(eq), parseQuery(rq))
+	default:
+		return eq == rq
+	}
+}
+
+func parseQuery(q string) url.Values {
+	v, err := url.ParseQuery(q)
+	if err != nil || len(v) == 0 {
+		return url.Values{}
+	}
+	return v
+}
+
+// bytes returns the decoded response body of the content.
+func (c *harContent) bytes() ([]byte, error) {
+	if c.Encoding == "base64" {
+		return base64.StdEncoding.DecodeString(c.Text)
+	}
+	return []byte(c.Text), nil
+}
+
+// harUpdateFilter returns a HAR recorder filter for the requests whose URL
+// matches, and which are made by the page if it's not nil.
+func harUpdateFilter(matcher urlMatcher, p *Page) func(*Request) bool {
+	return func(req *Request) bool {
+		if p != nil && (req.frame == nil || req.frame.manager.page != p) {
+			return false
+		}
+		ok, err := matcher(req.URL())
+		return err == nil && ok
+	}
+}
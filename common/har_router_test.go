@@ -0,0 +1,61 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchHARURL(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name             string
+		entry, req       string
+		query            HARQueryMatch
+		exact, unordered bool
+	}{
+		{name: "same", entry: "https://a.test/p?x=1&y=2", req: "https://a.test/p?x=1&y=2", exact: true, unordered: true},
+		{name: "reordered", entry: "https://a.test/p?x=1&y=2", req: "https://a.test/p?y=2&x=1", unordered: true},
+		{name: "different_query", entry: "https://a.test/p?x=1", req: "https://a.test/p?x=2"},
+		{name: "fragment", entry: "https://a.test/p?x=1", req: "https://a.test/p?x=1#top", exact: true, unordered: true},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.exact, matchHARURL(tc.entry, tc.req, HARQueryMatchExact))
+			assert.Equal(t, tc.unordered, matchHARURL(tc.entry, tc.req, HARQueryMatchUnordered))
+			assert.True(t, matchHARURL(tc.entry, tc.req, HARQueryMatchIgnore))
+		})
+	}
+
+	assert.False(t, matchHARURL("https://a.test/p", "https://a.test/q", HARQueryMatchIgnore))
+}
+
+func TestHARRouterFind(t *testing.T) {
+	t.Parallel()
+
+	newEntry := func(method, url string, status int64) *harEntry {
+		return &harEntry{
+			Request:  harRequest{Method: method, URL: url},
+			Response: harResponse{Status: status},
+		}
+	}
+	h := &harRouter{
+		entries: []*harEntry{
+			newEntry("GET", "https://a.test/failed", 0),
+			newEntry("GET", "https://a.test/failed", 200),
+			newEntry("POST", "https://a.test/api", 201),
+			newEntry("GET", "https://a.test/api", 200),
+		},
+		query: HARQueryMatchExact,
+	}
+
+	assert.Same(t, h.entries[1], h.find("GET", "https://a.test/failed"), "should skip the entries without a response")
+	assert.Same(t, h.entries[2], h.find("post", "https://a.test/api"))
+	assert.Same(t, h.entries[3], h.find("GET", "https://a.test/api"))
+	assert.Nil(t, h.find("GET", "https://a.test/api?x=1"))
+	assert.Nil(t, h.find("DELETE", "https://a.test/api"))
+}
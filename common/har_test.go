@@ -0,0 +1,72 @@
+package common
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/grafana/xk6-browser/k6ext/k6test"
+	"github.com/grafana/xk6-browser/log"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/network"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHARRecorder(t *testing.T) {
+	t.Parallel()
+
+	vu := k6test.NewVU(t)
+	now := time.Now()
+	ts := cdp.MonotonicTime(now)
+	wt := cdp.TimeSinceEpoch(now)
+	req, err := NewRequest(vu.Context(), &network.EventRequestWillBeSent{
+		RequestID: network.RequestID("1"),
+		Request: &network.Request{
+			URL:     "https://test/old?q=1",
+			Method:  "GET",
+			Headers: network.Headers(map[string]interface{}{"Accept": "*/*"}),
+		},
+		Timestamp: &ts,
+		WallTime:  &wt,
+	}, nil, nil, "", false)
+	require.NoError(t, err)
+
+	end := cdp.MonotonicTime(now.Add(10 * time.Millisecond))
+	req.response = NewHTTPResponse(vu.Context(), req, &network.Response{
+		URL:        "https://test/old?q=1",
+		Status:     302,
+		StatusText: "Found",
+		Protocol:   "http/1.1",
+		Headers:    network.Headers(map[string]interface{}{"Location": "/new"}),
+	}, &end)
+
+	path := filepath.Join(t.TempDir(), "test.har")
+	h := newHARRecorder(&RecordHAROptions{Path: path, Content: HARContentEmbed}, nil, log.NewNullLogger())
+	h.record(req, end.Time())
+	require.NoError(t, h.flush())
+
+	b, err := ioutil.ReadFile(filepath.Clean(path))
+	require.NoError(t, err)
+	var har harLog
+	require.NoError(t, json.Unmarshal(b, &har))
+
+	require.Len(t, har.Log.Entries, 1)
+	e := har.Log.Entries[0]
+	assert.Equal(t, "1.2", har.Log.Version)
+	assert.Equal(t, "GET", e.Request.Method)
+	assert.Equal(t, []harNameValue{{Name: "q", Value: "1"}}, e.Request.QueryString)
+	assert.Equal(t, []harNameValue{{Name: "Accept", Value: "*/*"}}, e.Request.Headers)
+	assert.Equal(t, int64(302), e.Response.Status)
+	assert.Equal(t, "/new", e.Response.RedirectURL)
+	assert.Equal(t, "http/1.1", e.Response.HTTPVersion)
+	assert.InDelta(t, 10, e.Time, 0.001)
+
+	// the requests recorded after the flush are ignored.
+	h.record(req, end.Time())
+	require.NoError(t, h.flush())
+	assert.Len(t, h.entries, 1)
+}
@@ -23,8 +23,13 @@ package common
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"html"
 	"math"
+	"reflect"
+	"regexp"
+	"strings"
 	"time"
 
 	cdpruntime "github.com/chromedp/cdproto/runtime"
@@ -240,3 +245,203 @@ func asGojaValue(ctx context.Context, v interface{}) goja.Value {
 func gojaValueToString(ctx context.Context, v interface{}) string {
 	return asGojaValue(ctx, v).String()
 }
+
+// initScriptSource returns the source of an init script. The script can be
+// a string, an object with the source in its content property, or a function
+// that is called with the JSON serialized arg.
+func initScriptSource(rt *goja.Runtime, script goja.Value, arg goja.Value) (string, error) {
+	if !gojaValueExists(script) {
+		return "", errors.New("script is required")
+	}
+	if _, ok := goja.AssertFunction(script); ok {
+		jsArg := "undefined"
+		if !goja.IsUndefined(arg) && arg != nil {
+			b, err := json.Marshal(arg.Export())
+			if err != nil {
+				return "", fmt.Errorf("serializing script argument: %w", err)
+			}
+			jsArg = string(b)
+		}
+		return fmt.Sprintf("(%s)(%s);", script.String(), jsArg), nil
+	}
+	if script.ExportType().Kind() == reflect.String {
+		return script.String(), nil
+	}
+	content := script.ToObject(rt).Get("content")
+	if !gojaValueExists(content) {
+		return "", errors.New("script must be a function, a string or an object with content")
+	}
+	return content.String(), nil
+}
+
+// urlMatcher reports whether a URL matches a user given URL pattern.
+type urlMatcher func(url string) (bool, error)
+
+// newURLMatcher returns a urlMatcher for the given pattern, which can be a
+// glob pattern string, a RegExp object or a predicate function receiving
+// the URL. A missing pattern matches any URL.
+func newURLMatcher(rt *goja.Runtime, pattern goja.Value) (urlMatcher, error) {
+	if !gojaValueExists(pattern) {
+		return func(string) (bool, error) { return true, nil }, nil
+	}
+	if fn, ok := goja.AssertFunction(pattern); ok {
+		return func(url string) (bool, error) {
+			v, err := fn(goja.Undefined(), rt.ToValue(url))
+			if err != nil {
+				return false, fmt.Errorf("calling URL predicate: %w", err)
+			}
+			return v.ToBoolean(), nil
+		}, nil
+	}
+	if obj, ok := pattern.(*goja.Object); ok && obj.ClassName() == "RegExp" {
+		test, ok := goja.AssertFunction(obj.Get("test"))
+		if !ok {
+			return nil, fmt.Errorf("URL pattern %s is not a valid RegExp", obj)
+		}
+		return func(url string) (bool, error) {
+			v, err := test(obj, rt.ToValue(url))
+			if err != nil {
+				return false, fmt.Errorf("matching URL %q against %s: %w", url, obj, err)
+			}
+			return v.ToBoolean(), nil
+		}, nil
+	}
+	if pattern.ExportType().Kind() != reflect.String {
+		return nil, fmt.Errorf("URL pattern must be a string, a RegExp or a function, got %s", pattern.ExportType())
+	}
+	re, err := globToRegexp(pattern.String())
+	if err != nil {
+		return nil, fmt.Errorf("parsing URL glob pattern %q: %w", pattern, err)
+	}
+	return func(url string) (bool, error) {
+		return re.MatchString(url), nil
+	}, nil
+}
+
+// newNativeURLMatcher returns a urlMatcher for the given pattern that doesn't
+// use the goja runtime, so that it can match the URLs outside of the event
+// loop of the VU. The pattern can be a glob pattern string or a RegExp object,
+// which is converted to a Go regular expression. A missing pattern matches any
+// URL.
+func newNativeURLMatcher(pattern goja.Value) (urlMatcher, error) {
+	if !gojaValueExists(pattern) {
+		return func(string) (bool, error) { return true, nil }, nil
+	}
+	if _, ok := goja.AssertFunction(pattern); ok {
+		return nil, errors.New("URL pattern must be a string or a RegExp, predicate functions aren't supported")
+	}
+	var (
+		re  *regexp.Regexp
+		err error
+	)
+	switch obj, ok := pattern.(*goja.Object); {
+	case ok && obj.ClassName() == "RegExp":
+		if re, err = regExpToRegexp(obj.Get("source").String(), obj.Get("flags").String()); err != nil {
+			return nil, fmt.Errorf("converting URL pattern %s: %w", obj, err)
+		}
+	case pattern.ExportType().Kind() == reflect.String:
+		if re, err = globToRegexp(pattern.String()); err != nil {
+			return nil, fmt.Errorf("parsing URL glob pattern %q: %w", pattern, err)
+		}
+	default:
+		return nil, fmt.Errorf("URL pattern must be a string or a RegExp, got %s", pattern.ExportType())
+	}
+	return func(url string) (bool, error) {
+		return re.MatchString(url), nil
+	}, nil
+}
+
+// regExpToRegexp converts the source and the flags of a JavaScript RegExp to
+// a Go regular expression. The sticky flag anchors the match to the start of
+// the URL, and the g, u and d flags don't change whether a URL matches, so
+// they're ignored.
+func regExpToRegexp(source, flags string) (*regexp.Regexp, error) {
+	var goFlags strings.Builder
+	for _, f := range flags {
+		switch f {
+		case 'i', 'm', 's':
+			goFlags.WriteRune(f)
+		case 'y':
+			source = `\A(?:` + source + ")"
+		case 'g', 'u', 'd':
+		default:
+			return nil, fmt.Errorf("unsupported RegExp flag %q", f)
+		}
+	}
+	if goFlags.Len() > 0 {
+		source = "(?" + goFlags.String() + ")" + source
+	}
+	return regexp.Compile(source)
+}
+
+// globToRegexp converts a glob pattern to a regular expression where:
+//   - "*" matches any characters except "/".
+//   - "**" matches any characters including "/".
+//   - "?" matches a single character.
+//   - "{a,b}" matches any of the comma separated alternatives.
+func globToRegexp(glob string) (*regexp.Regexp, error) {
+	var (
+		b       strings.Builder
+		inGroup bool
+	)
+	b.WriteByte('^')
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case c == '\\' && i+1 < len(glob):
+			i++
+			b.WriteString(regexp.QuoteMeta(string(glob[i])))
+		case c == '*' && i+1 < len(glob) && glob[i+1] == '*':
+			i++
+			b.WriteString(".*")
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteByte('.')
+		case c == '{':
+			inGroup = true
+			b.WriteString("(?:")
+		case c == '}' && inGroup:
+			inGroup = false
+			b.WriteByte(')')
+		case c == ',' && inGroup:
+			b.WriteByte('|')
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteByte('$')
+
+	return regexp.Compile(b.String())
+}
+
+// parsePosition parses an object with x and y properties.
+func parsePosition(rt *goja.Runtime, v goja.Value) (*Position, error) {
+	if !gojaValueExists(v) {
+		return nil, errors.New("position is required")
+	}
+	obj := v.ToObject(rt)
+	x, y := obj.Get("x"), obj.Get("y")
+	if !gojaValueExists(x) || !gojaValueExists(y) {
+		return nil, errors.New("position must be an object with x and y properties")
+	}
+	return &Position{X: x.ToFloat(), Y: y.ToFloat()}, nil
+}
+
+var (
+	reHeadTag = regexp.MustCompile(`(?i)<head(\s[^>]*)?>`)    //nolint:gochecknoglobals
+	reDoctype = regexp.MustCompile(`(?i)^\s*<!doctype[^>]*>`) //nolint:gochecknoglobals
+)
+
+// withBaseURL adds a base element with the URL to the start of the head of
+// the HTML content, or to its start if it doesn't have a head.
+func withBaseURL(content, baseURL string) string {
+	base := fmt.Sprintf(`<base href="%s">`, html.EscapeString(baseURL))
+	if loc := reHeadTag.FindStringIndex(content); loc != nil {
+		return content[:loc[1]] + base + content[loc[1]:]
+	}
+	if loc := reDoctype.FindStringIndex(content); loc != nil {
+		return content[:loc[1]] + base + content[loc[1]:]
+	}
+	return base + content
+}
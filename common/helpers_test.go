@@ -233,3 +233,162 @@ func TestConvertArgument(t *testing.T) {
 		require.Empty(t, arg.UnserializableValue)
 	})
 }
+
+func TestURLMatcher(t *testing.T) {
+	t.Parallel()
+
+	rt := goja.New()
+	mustValue := func(t *testing.T, js string) goja.Value {
+		t.Helper()
+		v, err := rt.RunString(js)
+		require.NoError(t, err)
+		return v
+	}
+
+	tests := []struct {
+		name    string
+		pattern goja.Value
+		url     string
+		want    bool
+	}{
+		{name: "missing", pattern: nil, url: "https://test.k6.io/", want: true},
+		{name: "exact", pattern: rt.ToValue("https://test.k6.io/"), url: "https://test.k6.io/", want: true},
+		{name: "exact_mismatch", pattern: rt.ToValue("https://test.k6.io/"), url: "https://test.k6.io/a", want: false},
+		{name: "star", pattern: rt.ToValue("https://test.k6.io/*.png"), url: "https://test.k6.io/a.png", want: true},
+		{name: "star_slash", pattern: rt.ToValue("https://test.k6.io/*.png"), url: "https://test.k6.io/a/b.png", want: false},
+		{name: "double_star", pattern: rt.ToValue("**/*.png"), url: "https://test.k6.io/a/b.png", want: true},
+		{name: "question_mark", pattern: rt.ToValue("**/?.js"), url: "https://test.k6.io/a.js", want: true},
+		{name: "alternatives", pattern: rt.ToValue("**/*.{png,jpg}"), url: "https://test.k6.io/a.jpg", want: true},
+		{name: "escaped_meta", pattern: rt.ToValue("**/a.js?v=1"), url: "https://test.k6.io/abjs?v=1", want: false},
+		{name: "regexp", pattern: mustValue(t, "/api\\/v[0-9]+/"), url: "https://test.k6.io/api/v2/x", want: true},
+		{name: "regexp_mismatch", pattern: mustValue(t, "/^https:\\/\\/k6/"), url: "https://test.k6.io/", want: false},
+		{name: "predicate", pattern: mustValue(t, "(url) => url.endsWith('.css')"), url: "https://test.k6.io/a.css", want: true},
+	}
+	for _, tt := range tests {
+		match, err := newURLMatcher(rt, tt.pattern)
+		require.NoError(t, err, tt.name)
+		got, err := match(tt.url)
+		require.NoError(t, err, tt.name)
+		require.Equal(t, tt.want, got, tt.name)
+	}
+
+	_, err := newURLMatcher(rt, rt.ToValue(42))
+	require.ErrorContains(t, err, "URL pattern must be a string, a RegExp or a function")
+}
+
+func TestNativeURLMatcher(t *testing.T) {
+	t.Parallel()
+
+	rt := goja.New()
+	mustValue := func(t *testing.T, js string) goja.Value {
+		t.Helper()
+		v, err := rt.RunString(js)
+		require.NoError(t, err)
+		return v
+	}
+
+	tests := []struct {
+		name    string
+		pattern goja.Value
+		url     string
+		want    bool
+	}{
+		{name: "missing", pattern: nil, url: "https://test.k6.io/", want: true},
+		{name: "glob", pattern: rt.ToValue("**/*.png"), url: "https://test.k6.io/a/b.png", want: true},
+		{name: "glob_mismatch", pattern: rt.ToValue("**/*.png"), url: "https://test.k6.io/a.jpg", want: false},
+		{name: "regexp", pattern: mustValue(t, "/api\\/v[0-9]+/"), url: "https://test.k6.io/api/v2/x", want: true},
+		{name: "regexp_mismatch", pattern: mustValue(t, "/^https:\\/\\/k6/"), url: "https://test.k6.io/", want: false},
+		{name: "regexp_ignore_case", pattern: mustValue(t, "/API/i"), url: "https://test.k6.io/api", want: true},
+		{name: "regexp_global", pattern: mustValue(t, "/api/g"), url: "https://test.k6.io/api", want: true},
+		{name: "regexp_sticky", pattern: mustValue(t, "/api/y"), url: "https://test.k6.io/api", want: false},
+	}
+	for _, tt := range tests {
+		match, err := newNativeURLMatcher(tt.pattern)
+		require.NoError(t, err, tt.name)
+		got, err := match(tt.url)
+		require.NoError(t, err, tt.name)
+		require.Equal(t, tt.want, got, tt.name)
+	}
+
+	_, err := newNativeURLMatcher(mustValue(t, "(url) => true"))
+	require.ErrorContains(t, err, "predicate functions aren't supported")
+	_, err = newNativeURLMatcher(rt.ToValue(42))
+	require.ErrorContains(t, err, "URL pattern must be a string or a RegExp")
+	_, err = newNativeURLMatcher(mustValue(t, "/(?<=a)b/"))
+	require.ErrorContains(t, err, "converting URL pattern")
+}
+
+func TestInitScriptSource(t *testing.T) {
+	t.Parallel()
+
+	rt := goja.New()
+	mustValue := func(t *testing.T, js string) goja.Value {
+		t.Helper()
+		v, err := rt.RunString(js)
+		require.NoError(t, err)
+		return v
+	}
+
+	tests := []struct {
+		name   string
+		script goja.Value
+		arg    goja.Value
+		want   string
+	}{
+		{name: "string", script: rt.ToValue("window.x = 1"), want: "window.x = 1"},
+		{name: "content", script: mustValue(t, "({ content: 'window.x = 1' })"), want: "window.x = 1"},
+		{name: "func", script: mustValue(t, "(() => {})"), want: "(() => {})(undefined);"},
+		{
+			name:   "func_arg",
+			script: mustValue(t, "((o) => {})"),
+			arg:    mustValue(t, "({ now: 42 })"),
+			want:   `((o) => {})({"now":42});`,
+		},
+	}
+	for _, tt := range tests {
+		got, err := initScriptSource(rt, tt.script, tt.arg)
+		require.NoError(t, err, tt.name)
+		require.Equal(t, tt.want, got, tt.name)
+	}
+
+	_, err := initScriptSource(rt, mustValue(t, "({})"), nil)
+	require.ErrorContains(t, err, "script must be a function, a string or an object with content")
+	_, err = initScriptSource(rt, goja.Undefined(), nil)
+	require.ErrorContains(t, err, "script is required")
+}
+
+func TestParsePosition(t *testing.T) {
+	t.Parallel()
+
+	rt := goja.New()
+	p, err := parsePosition(rt, rt.ToValue(map[string]interface{}{"x": 1.5, "y": 2}))
+	require.NoError(t, err)
+	require.Equal(t, &Position{X: 1.5, Y: 2}, p)
+
+	_, err = parsePosition(rt, rt.ToValue(map[string]interface{}{"x": 1}))
+	require.EqualError(t, err, "position must be an object with x and y properties")
+
+	_, err = parsePosition(rt, goja.Undefined())
+	require.EqualError(t, err, "position is required")
+}
+
+func TestWithBaseURL(t *testing.T) {
+	t.Parallel()
+
+	const base = `<base href="https://a.test/x?a=1&amp;b=2">`
+	testCases := []struct {
+		name, content, want string
+	}{
+		{name: "head", content: `<html><head lang="en"><title>t</title></head></html>`, want: `<html><head lang="en">` + base + `<title>t</title></head></html>`},
+		{name: "doctype", content: `<!DOCTYPE html><p>hi</p>`, want: `<!DOCTYPE html>` + base + `<p>hi</p>`},
+		{name: "fragment", content: `<header>hi</header>`, want: base + `<header>hi</header>`},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tc.want, withBaseURL(tc.content, "https://a.test/x?a=1&b=2"))
+		})
+	}
+}
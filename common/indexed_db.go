@@ -0,0 +1,95 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/dop251/goja"
+
+	"github.com/grafana/xk6-browser/api"
+	"github.com/grafana/xk6-browser/common/js"
+	"github.com/grafana/xk6-browser/k6ext"
+)
+
+// Ensure IndexedDB implements the api.IndexedDB interface.
+var _ api.IndexedDB = &IndexedDB{}
+
+// IndexedDB reads the IndexedDB databases of the origin of the main frame
+// of a page. The databases are opened in their current versions, so they
+// aren't upgraded or created.
+type IndexedDB struct {
+	ctx  context.Context
+	page *Page
+}
+
+// NewIndexedDB returns the IndexedDB databases of the page.
+func NewIndexedDB(ctx context.Context, p *Page) *IndexedDB {
+	return &IndexedDB{
+		ctx:  ctx,
+		page: p,
+	}
+}
+
+// Databases returns the databases with their versions, object stores, and
+// indexes.
+func (db *IndexedDB) Databases() goja.Value {
+	db.page.logger.Debugf("IndexedDB:Databases", "sid:%v", db.page.sessionID())
+
+	v, err := db.call("databases")
+	if err != nil {
+		k6ext.Panic(db.ctx, "listing IndexedDB databases: %w", err)
+	}
+	return v
+}
+
+// Records returns the records of the object store of the database, up to
+// the limit option. The total number of the records in the store, and
+// whether the records are truncated to the limit, are returned with them.
+func (db *IndexedDB) Records(database string, objectStore string, opts goja.Value) goja.Value {
+	db.page.logger.Debugf("IndexedDB:Records", "sid:%v database:%q objectStore:%q",
+		db.page.sessionID(), database, objectStore)
+
+	ropts := NewIndexedDBRecordsOptions()
+	if err := ropts.Parse(db.ctx, opts); err != nil {
+		k6ext.Panic(db.ctx, "parsing IndexedDB records options: %w", err)
+	}
+	v, err := db.call("records", database, objectStore, ropts.Limit)
+	if err != nil {
+		k6ext.Panic(db.ctx, "getting IndexedDB records of %q in %q: %w", objectStore, database, err)
+	}
+	return v
+}
+
+// call calls the method of the IndexedDB helper script in the main frame
+// of the page, and returns its result.
+func (db *IndexedDB) call(method string, args ...interface{}) (goja.Value, error) {
+	var (
+		rt   = db.page.vu.Runtime()
+		opts = evalOptions{
+			forceCallable: true,
+			returnByValue: true,
+		}
+	)
+	if args == nil {
+		args = []interface{}{}
+	}
+	frame := db.page.frameManager.MainFrame()
+	v, err := frame.evaluate(db.ctx, mainWorld, opts, rt.ToValue(js.IndexedDB), rt.ToValue(method), rt.ToValue(args))
+	if err != nil {
+		return nil, err
+	}
+	gv, ok := v.(goja.Value)
+	if !ok {
+		return nil, errors.New("unexpected result")
+	}
+	// the result is serialized in the page, so that the binary data of the
+	// records is kept.
+	var res interface{}
+	if err := json.Unmarshal([]byte(gv.String()), &res); err != nil {
+		return nil, fmt.Errorf("parsing result: %w", err)
+	}
+
+	return rt.ToValue(res), nil
+}
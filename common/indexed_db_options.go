@@ -0,0 +1,47 @@
+package common
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dop251/goja"
+
+	"github.com/grafana/xk6-browser/k6ext"
+)
+
+// DefaultIndexedDBRecordsLimit is the default maximum number of the records
+// that IndexedDB.records returns.
+const DefaultIndexedDBRecordsLimit = 100
+
+// IndexedDBRecordsOptions are the options of IndexedDB.records.
+type IndexedDBRecordsOptions struct {
+	// Limit is the maximum number of the records to return, so that the
+	// large object stores aren't copied from the page as a whole.
+	Limit int64 `js:"limit"`
+}
+
+// NewIndexedDBRecordsOptions returns the default IndexedDB records options.
+func NewIndexedDBRecordsOptions() *IndexedDBRecordsOptions {
+	return &IndexedDBRecordsOptions{
+		Limit: DefaultIndexedDBRecordsLimit,
+	}
+}
+
+// Parse parses the IndexedDB records options from a JS object.
+func (o *IndexedDBRecordsOptions) Parse(ctx context.Context, opts goja.Value) error {
+	if !gojaValueExists(opts) {
+		return nil
+	}
+	rt := k6ext.Runtime(ctx)
+	obj := opts.ToObject(rt)
+	for _, k := range obj.Keys() {
+		switch k {
+		case "limit":
+			o.Limit = obj.Get(k).ToInteger()
+			if o.Limit <= 0 {
+				return fmt.Errorf("limit must be a positive number, got %d", o.Limit)
+			}
+		}
+	}
+	return nil
+}
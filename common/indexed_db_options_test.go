@@ -0,0 +1,26 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/grafana/xk6-browser/k6ext/k6test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexedDBRecordsOptions(t *testing.T) {
+	t.Parallel()
+
+	vu := k6test.NewVU(t)
+
+	opts := NewIndexedDBRecordsOptions()
+	require.NoError(t, opts.Parse(vu.Context(), nil))
+	assert.Equal(t, int64(DefaultIndexedDBRecordsLimit), opts.Limit)
+
+	require.NoError(t, opts.Parse(vu.Context(), vu.ToGojaValue(map[string]interface{}{"limit": 5})))
+	assert.Equal(t, int64(5), opts.Limit)
+
+	err := opts.Parse(vu.Context(), vu.ToGojaValue(map[string]interface{}{"limit": 0}))
+	assert.EqualError(t, err, "limit must be a positive number, got 0")
+}
@@ -0,0 +1,11 @@
+package js
+
+import (
+	_ "embed"
+)
+
+// Clock defines the fake clock that replaces the time functions of a page
+// as globalThis.__k6BrowserClock.
+//
+//go:embed clock.js
+var Clock string
@@ -0,0 +1,208 @@
+(() => {
+  if (globalThis.__k6BrowserClock) {
+    return;
+  }
+
+  const original = {
+    Date: globalThis.Date,
+    setTimeout: globalThis.setTimeout.bind(globalThis),
+    clearTimeout: globalThis.clearTimeout.bind(globalThis),
+    setInterval: globalThis.setInterval.bind(globalThis),
+    clearInterval: globalThis.clearInterval.bind(globalThis),
+    performanceNow: globalThis.performance.now.bind(globalThis.performance),
+  };
+
+  // Clock is a fake clock that replaces the time functions of the page.
+  // The time runs with the real time unless the clock is paused, and the
+  // timers fire when the time of the clock reaches them.
+  class Clock {
+    constructor() {
+      this.installed = false;
+      this.paused = false;
+      // base is the time of the clock at the real time of anchor.
+      this.base = original.Date.now();
+      this.anchor = original.performanceNow();
+      this.fixedTime = undefined;
+      this.installTime = this.base;
+      this.performanceTime = this.anchor;
+      this.timers = new Map();
+      this.nextTimerID = 1;
+      this.realTimer = undefined;
+    }
+
+    now() {
+      if (this.paused) {
+        return this.base;
+      }
+      return this.base + (original.performanceNow() - this.anchor);
+    }
+
+    setNow(time) {
+      this.base = time;
+      this.anchor = original.performanceNow();
+    }
+
+    dateNow() {
+      if (this.fixedTime !== undefined) {
+        return this.fixedTime;
+      }
+      return Math.floor(this.now());
+    }
+
+    install(time) {
+      if (this.installed) {
+        return;
+      }
+      this.installed = true;
+      this.setNow(time);
+      this.installTime = time;
+      this.performanceTime = original.performanceNow();
+
+      const clock = this;
+      function ClockDate(...args) {
+        if (!new.target) {
+          return new original.Date(clock.dateNow()).toString();
+        }
+        if (args.length === 0) {
+          return new original.Date(clock.dateNow());
+        }
+        return new original.Date(...args);
+      }
+      ClockDate.prototype = original.Date.prototype;
+      ClockDate.now = () => clock.dateNow();
+      ClockDate.parse = original.Date.parse;
+      ClockDate.UTC = original.Date.UTC;
+      ClockDate.toString = () => original.Date.toString();
+
+      globalThis.Date = ClockDate;
+      globalThis.setTimeout = (callback, delay, ...args) =>
+        clock.addTimer(callback, delay, args, false);
+      globalThis.setInterval = (callback, delay, ...args) =>
+        clock.addTimer(callback, delay, args, true);
+      globalThis.clearTimeout = (id) => clock.removeTimer(id);
+      globalThis.clearInterval = (id) => clock.removeTimer(id);
+      globalThis.performance.now = () =>
+        clock.performanceTime + (clock.now() - clock.installTime);
+    }
+
+    addTimer(callback, delay, args, interval) {
+      delay = Math.max(0, Number(delay) || 0);
+      const id = this.nextTimerID++;
+      this.timers.set(id, {
+        id,
+        callback,
+        args,
+        delay,
+        interval,
+        due: this.now() + delay,
+      });
+      this.schedule();
+      return id;
+    }
+
+    removeTimer(id) {
+      this.timers.delete(id);
+      this.schedule();
+    }
+
+    // schedule sets a real timer for the first timer of the clock.
+    schedule() {
+      if (this.realTimer !== undefined) {
+        original.clearTimeout(this.realTimer);
+        this.realTimer = undefined;
+      }
+      if (this.paused || this.timers.size === 0) {
+        return;
+      }
+      let due = Infinity;
+      for (const timer of this.timers.values()) {
+        due = Math.min(due, timer.due);
+      }
+      this.realTimer = original.setTimeout(() => {
+        this.realTimer = undefined;
+        this.fire(this.now());
+        this.schedule();
+      }, Math.max(0, due - this.now()));
+    }
+
+    // fire calls the timers that are due at the time in the order of their
+    // due times. Each timer fires at most once, and the intervals are
+    // rescheduled after the time.
+    fire(time) {
+      const due = [...this.timers.values()]
+        .filter((timer) => timer.due <= time)
+        .sort((a, b) => a.due - b.due || a.id - b.id);
+      for (const timer of due) {
+        // the timer can be removed by an earlier callback.
+        if (!this.timers.has(timer.id)) {
+          continue;
+        }
+        if (timer.interval) {
+          timer.due = time + Math.max(timer.delay, 1);
+        } else {
+          this.timers.delete(timer.id);
+        }
+        try {
+          if (typeof timer.callback === 'function') {
+            timer.callback.apply(globalThis, timer.args);
+          } else {
+            (0, eval)(String(timer.callback));
+          }
+        } catch (e) {
+          // report the error as an uncaught error of the page.
+          original.setTimeout(() => {
+            throw e;
+          }, 0);
+        }
+      }
+    }
+
+    // jump moves the time of the clock to the time, and fires the timers
+    // that are due at most once.
+    jump(time) {
+      this.setNow(time);
+      this.fire(time);
+      this.schedule();
+    }
+
+    // restore installs the clock with the state of the clock of the page,
+    // so that the clock continues in a new document. The time of the state
+    // is the time of the clock at the real time of its anchor.
+    restore({ time, anchor, paused, fixedTime }) {
+      this.install(paused ? time : time + (original.Date.now() - anchor));
+      this.paused = paused;
+      this.fixedTime = fixedTime === null ? undefined : fixedTime;
+    }
+
+    fastForward(ms) {
+      if (ms < 0) {
+        throw new Error('cannot fast forward the clock back in time');
+      }
+      this.jump(this.now() + ms);
+    }
+
+    pauseAt(time) {
+      this.setNow(this.now());
+      this.paused = true;
+      this.jump(time);
+    }
+
+    resume() {
+      if (!this.paused) {
+        return;
+      }
+      this.setNow(this.base);
+      this.paused = false;
+      this.schedule();
+    }
+
+    setFixedTime(time) {
+      this.fixedTime = time;
+    }
+  }
+
+  Object.defineProperty(globalThis, '__k6BrowserClock', {
+    value: new Clock(),
+    enumerable: false,
+  });
+})();
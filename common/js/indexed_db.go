@@ -0,0 +1,11 @@
+package js
+
+import (
+	_ "embed"
+)
+
+// IndexedDB defines the function that reads the IndexedDB databases of a
+// page. It's called with the method and its arguments.
+//
+//go:embed indexed_db.js
+var IndexedDB string
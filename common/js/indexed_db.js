@@ -0,0 +1,124 @@
+async (method, args) => {
+  const request = (req) =>
+    new Promise((resolve, reject) => {
+      req.onsuccess = () => resolve(req.result);
+      req.onerror = () => reject(req.error);
+    });
+
+  // open opens the existing database in its current version. The upgrade
+  // is aborted if the database doesn't exist, so that it is not created.
+  const open = (name) =>
+    new Promise((resolve, reject) => {
+      let created = false;
+      const req = indexedDB.open(name);
+      req.onupgradeneeded = () => {
+        created = true;
+        req.transaction.abort();
+      };
+      req.onsuccess = () => resolve(req.result);
+      req.onerror = () => {
+        if (created) {
+          reject(new Error(`database "${name}" not found`));
+          return;
+        }
+        reject(req.error);
+      };
+    });
+
+  const describeStore = (store) => ({
+    name: store.name,
+    keyPath: store.keyPath,
+    autoIncrement: store.autoIncrement,
+    indexes: [...store.indexNames].map((name) => {
+      const index = store.index(name);
+      return {
+        name: index.name,
+        keyPath: index.keyPath,
+        unique: index.unique,
+        multiEntry: index.multiEntry,
+      };
+    }),
+  });
+
+  // serialize converts the structured clones to JSON, so that the binary
+  // data is returned as arrays of bytes.
+  const serialize = (value) =>
+    JSON.stringify(value, (key, v) => {
+      if (v instanceof ArrayBuffer) {
+        return Array.from(new Uint8Array(v));
+      }
+      if (ArrayBuffer.isView(v)) {
+        return Array.from(new Uint8Array(v.buffer, v.byteOffset, v.byteLength));
+      }
+      if (v instanceof Map) {
+        return Object.fromEntries(v);
+      }
+      if (v instanceof Set) {
+        return [...v];
+      }
+      return v;
+    });
+
+  const databases = async () => {
+    const result = [];
+    for (const info of await indexedDB.databases()) {
+      const db = await open(info.name);
+      try {
+        const names = [...db.objectStoreNames];
+        const stores = [];
+        if (names.length > 0) {
+          const tx = db.transaction(names, 'readonly');
+          for (const name of names) {
+            stores.push(describeStore(tx.objectStore(name)));
+          }
+        }
+        result.push({ name: db.name, version: db.version, objectStores: stores });
+      } finally {
+        db.close();
+      }
+    }
+    return result;
+  };
+
+  const records = async (database, objectStore, limit) => {
+    const db = await open(database);
+    try {
+      if (!db.objectStoreNames.contains(objectStore)) {
+        throw new Error(`object store "${objectStore}" not found in database "${database}"`);
+      }
+      const store = db.transaction(objectStore, 'readonly').objectStore(objectStore);
+      const total = await request(store.count());
+      const result = await new Promise((resolve, reject) => {
+        const records = [];
+        const req = store.openCursor();
+        req.onsuccess = () => {
+          const cursor = req.result;
+          if (!cursor || records.length >= limit) {
+            resolve(records);
+            return;
+          }
+          records.push({ key: cursor.key, value: cursor.value });
+          cursor.continue();
+        };
+        req.onerror = () => reject(req.error);
+      });
+      return {
+        version: db.version,
+        total,
+        truncated: result.length < total,
+        records: result,
+      };
+    } finally {
+      db.close();
+    }
+  };
+
+  switch (method) {
+    case 'databases':
+      return serialize(await databases());
+    case 'records':
+      return serialize(await records(...args));
+    default:
+      throw new Error(`unknown IndexedDB method "${method}"`);
+  }
+}
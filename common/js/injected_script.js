@@ -102,6 +102,65 @@ function isVisible(element) {
   return rect.width > 0 && rect.height > 0;
 }
 
+// sortInDOMOrder sorts the matches by the document order of their elements.
+function sortInDOMOrder(matches) {
+  return matches.sort((a, b) => {
+    const position = a.element.compareDocumentPosition(b.element);
+    if (position & Node.DOCUMENT_POSITION_FOLLOWING) {
+      return -1;
+    }
+    if (position & Node.DOCUMENT_POSITION_PRECEDING) {
+      return 1;
+    }
+    return 0;
+  });
+}
+
+function normalizeWhiteSpace(text) {
+  return text.replace(/\s+/g, " ").trim();
+}
+
+function elementText(element) {
+  const text =
+    typeof element.innerText === "string"
+      ? element.innerText
+      : element.textContent;
+  return normalizeWhiteSpace(text || "");
+}
+
+// createTextMatcher returns a function matching texts against a text
+// selector body that can be one of:
+//   - "text"i matches a case-insensitive substring.
+//   - "text"s matches the whole text.
+//   - /source/flags matches a regular expression.
+function createTextMatcher(body) {
+  if (body[0] === "/" && body.lastIndexOf("/") > 0) {
+    const last = body.lastIndexOf("/");
+    const source = unescapeQuotes(body.substring(1, last));
+    const re = new RegExp(source, body.substring(last + 1));
+    return (text) => {
+      re.lastIndex = 0;
+      return re.test(text);
+    };
+  }
+  const exact = body.endsWith('"s');
+  const quoted = exact || body.endsWith('"i') ? body.slice(0, -1) : body;
+  const text = normalizeWhiteSpace(JSON.parse(quoted));
+  if (exact) {
+    return (t) => normalizeWhiteSpace(t) === text;
+  }
+  const lower = text.toLowerCase();
+  return (t) => normalizeWhiteSpace(t).toLowerCase().includes(lower);
+}
+
+// unescapeQuotes removes the backslashes escaping the quotes in a regular
+// expression source, and keeps the other escapes.
+function unescapeQuotes(source) {
+  return source.replace(/\\([\s\S])/g, (m, c) =>
+    c === '"' || c === "'" || c === "`" ? c : m
+  );
+}
+
 function oneLine(s) {
   return s.replace(/\n/g, "↵").replace(/\t/g, "⇆");
 }
@@ -112,9 +171,94 @@ class CSSQueryEngine {
   }
 }
 
+// queryAllByText returns the innermost elements of root whose text matches.
+function queryAllByText(root, matcher) {
+  const result = [];
+  const skip = new Set(["HEAD", "SCRIPT", "STYLE", "NOSCRIPT", "TEMPLATE"]);
+  const matches = (element) =>
+    !skip.has(element.nodeName) && matcher(elementText(element));
+  for (const element of root.querySelectorAll("*")) {
+    if (!matches(element)) {
+      continue;
+    }
+    if ([...element.children].some(matches)) {
+      continue;
+    }
+    result.push(element);
+  }
+  return result;
+}
+
 class TextQueryEngine {
   queryAll(root, selector) {
-    return root.queryAll(selector);
+    let body = selector.trim();
+    const quote = body[0];
+    if (
+      (quote === '"' || quote === "'") &&
+      body.length > 1 &&
+      body[body.length - 1] === quote
+    ) {
+      // Quoted texts match the whole text case-sensitively.
+      body = JSON.stringify(body.slice(1, -1)) + "s";
+    } else if (!(body[0] === "/" && body.lastIndexOf("/") > 0)) {
+      body = JSON.stringify(body) + "i";
+    }
+    return queryAllByText(root, createTextMatcher(body));
+  }
+}
+
+class InternalTextQueryEngine {
+  queryAll(root, selector) {
+    return queryAllByText(root, createTextMatcher(selector));
+  }
+}
+
+// getLabelTexts returns the texts of the labels associated with the element.
+function getLabelTexts(element) {
+  const texts = [];
+  const label = element.getAttribute("aria-label");
+  if (label) {
+    texts.push(label);
+  }
+  const labelledBy = element.getAttribute("aria-labelledby");
+  if (labelledBy) {
+    const document = element.ownerDocument;
+    for (const id of labelledBy.split(/\s+/)) {
+      const e = document.getElementById(id);
+      if (e) {
+        texts.push(elementText(e));
+      }
+    }
+  }
+  for (const l of element.labels || []) {
+    texts.push(elementText(l));
+  }
+  return texts;
+}
+
+class LabelQueryEngine {
+  queryAll(root, selector) {
+    const matcher = createTextMatcher(selector);
+    return [...root.querySelectorAll("*")].filter((element) =>
+      getLabelTexts(element).some(matcher)
+    );
+  }
+}
+
+// AttributeQueryEngine matches the elements with an attribute value
+// matching a text selector body, as in [name="value"i].
+class AttributeQueryEngine {
+  queryAll(root, selector) {
+    const m = selector.match(/^\[([^=\]]+)=(.*)\]$/s);
+    if (!m) {
+      throw new Error(`malformed attribute selector: ${selector}`);
+    }
+    const [, name, body] = m;
+    const matcher = createTextMatcher(body);
+    return [...root.querySelectorAll("*")].filter(
+      (element) =>
+        element.hasAttribute(name) && matcher(element.getAttribute(name))
+    );
   }
 }
 
@@ -143,6 +287,215 @@ class XPathQueryEngine {
   }
 }
 
+const inputTypeToRole = {
+  button: "button",
+  checkbox: "checkbox",
+  email: "textbox",
+  image: "button",
+  number: "spinbutton",
+  radio: "radio",
+  range: "slider",
+  reset: "button",
+  search: "searchbox",
+  submit: "button",
+  tel: "textbox",
+  text: "textbox",
+  url: "textbox",
+};
+
+const tagToRole = {
+  ARTICLE: "article",
+  ASIDE: "complementary",
+  BUTTON: "button",
+  DETAILS: "group",
+  DIALOG: "dialog",
+  FIELDSET: "group",
+  FOOTER: "contentinfo",
+  FORM: "form",
+  H1: "heading",
+  H2: "heading",
+  H3: "heading",
+  H4: "heading",
+  H5: "heading",
+  H6: "heading",
+  HEADER: "banner",
+  HR: "separator",
+  LI: "listitem",
+  MAIN: "main",
+  NAV: "navigation",
+  OL: "list",
+  OPTION: "option",
+  OUTPUT: "status",
+  P: "paragraph",
+  PROGRESS: "progressbar",
+  SECTION: "region",
+  TABLE: "table",
+  TD: "cell",
+  TEXTAREA: "textbox",
+  TH: "columnheader",
+  TR: "row",
+  UL: "list",
+};
+
+// Roles that take their accessible name from their content.
+const nameFromContentRoles = new Set([
+  "button",
+  "cell",
+  "checkbox",
+  "columnheader",
+  "heading",
+  "link",
+  "menuitem",
+  "option",
+  "radio",
+  "row",
+  "switch",
+  "tab",
+  "tooltip",
+  "treeitem",
+]);
+
+function getAriaRole(element) {
+  const explicit = (element.getAttribute("role") || "").trim().split(/\s+/)[0];
+  if (explicit) {
+    return explicit;
+  }
+  switch (element.nodeName) {
+    case "A":
+    case "AREA":
+      return element.hasAttribute("href") ? "link" : null;
+    case "IMG":
+      return element.getAttribute("alt") === "" ? "presentation" : "img";
+    case "INPUT": {
+      const type = (element.getAttribute("type") || "text").toLowerCase();
+      if (element.hasAttribute("list") && type !== "checkbox") {
+        return "combobox";
+      }
+      return inputTypeToRole[type] || null;
+    }
+    case "SELECT":
+      return element.multiple || element.size > 1 ? "listbox" : "combobox";
+    default:
+      return tagToRole[element.nodeName] || null;
+  }
+}
+
+function isHiddenForAria(element) {
+  for (let e = element; e; e = e.parentElement) {
+    if (e.getAttribute("aria-hidden") === "true") {
+      return true;
+    }
+    const style = e.ownerDocument.defaultView.getComputedStyle(e);
+    if (style.display === "none") {
+      return true;
+    }
+    if (e === element && style.visibility === "hidden") {
+      return true;
+    }
+  }
+  return false;
+}
+
+function getAccessibleName(element, role) {
+  const labelledBy = element.getAttribute("aria-labelledby");
+  if (labelledBy) {
+    const document = element.ownerDocument;
+    const text = labelledBy
+      .split(/\s+/)
+      .map((id) => document.getElementById(id))
+      .filter(Boolean)
+      .map((e) => elementText(e))
+      .join(" ");
+    if (text) {
+      return normalizeWhiteSpace(text);
+    }
+  }
+  const label = element.getAttribute("aria-label");
+  if (label && label.trim()) {
+    return normalizeWhiteSpace(label);
+  }
+  if (element.labels && element.labels.length) {
+    return normalizeWhiteSpace(
+      [...element.labels].map((l) => elementText(l)).join(" ")
+    );
+  }
+  if (element.nodeName === "IMG" || element.nodeName === "AREA") {
+    const alt = element.getAttribute("alt");
+    if (alt) {
+      return normalizeWhiteSpace(alt);
+    }
+  }
+  if (element.nodeName === "INPUT") {
+    const type = (element.getAttribute("type") || "").toLowerCase();
+    if (["button", "submit", "reset"].includes(type) && element.value) {
+      return normalizeWhiteSpace(element.value);
+    }
+    if (type === "image" && element.getAttribute("alt")) {
+      return normalizeWhiteSpace(element.getAttribute("alt"));
+    }
+  }
+  if (nameFromContentRoles.has(role)) {
+    const text = elementText(element);
+    if (text) {
+      return text;
+    }
+  }
+  return normalizeWhiteSpace(element.getAttribute("title") || "");
+}
+
+function getAriaChecked(element) {
+  if (
+    element.nodeName === "INPUT" &&
+    ["checkbox", "radio"].includes(element.type)
+  ) {
+    return element.checked;
+  }
+  return element.getAttribute("aria-checked") === "true";
+}
+
+function getAriaDisabled(element) {
+  if (
+    ["BUTTON", "INPUT", "SELECT", "TEXTAREA", "OPTION", "FIELDSET"].includes(
+      element.nodeName
+    ) &&
+    element.matches(":disabled")
+  ) {
+    return true;
+  }
+  return !!element.closest("[aria-disabled=true]");
+}
+
+class RoleQueryEngine {
+  queryAll(root, selector) {
+    const opts = JSON.parse(selector);
+    const matchName = opts.name ? createTextMatcher(opts.name) : null;
+    const result = [];
+    for (const element of root.querySelectorAll("*")) {
+      const role = getAriaRole(element);
+      if (role !== opts.role) {
+        continue;
+      }
+      if (!opts.includeHidden && isHiddenForAria(element)) {
+        continue;
+      }
+      if (opts.checked !== undefined && getAriaChecked(element) !== opts.checked) {
+        continue;
+      }
+      if (
+        opts.disabled !== undefined &&
+        getAriaDisabled(element) !== opts.disabled
+      ) {
+        continue;
+      }
+      if (matchName && !matchName(getAccessibleName(element, role))) {
+        continue;
+      }
+      result.push(element);
+    }
+    return result;
+  }
+}
+
 class InjectedScript {
   constructor() {
     this._replaceRafWithTimeout = false;
@@ -151,6 +504,10 @@ class InjectedScript {
       css: new CSSQueryEngine(),
       text: new TextQueryEngine(),
       xpath: new XPathQueryEngine(),
+      "internal:attr": new AttributeQueryEngine(),
+      "internal:label": new LabelQueryEngine(),
+      "internal:role": new RoleQueryEngine(),
+      "internal:text": new InternalTextQueryEngine(),
     };
   }
 
@@ -176,7 +533,7 @@ class InjectedScript {
         if (typeof selector.capture === "number") {
           return "error:nthnocapture";
         }
-        const nth = part.body;
+        const nth = +part.body;
         const set = new Set();
         for (const root of roots) {
           set.add(root.element);
@@ -194,8 +551,69 @@ class InjectedScript {
     }
 
     if (part.name === "visible") {
-      const visible = Boolean(part.body);
-      return roots.filter((match) => visible === isVisible(match.element));
+      const visible = part.body !== "false";
+      return this._querySelectorRecursively(
+        roots.filter((match) => visible === isVisible(match.element)),
+        selector,
+        index + 1,
+        queryCache
+      );
+    }
+
+    if (part.name === "internal:has-text") {
+      const matcher = createTextMatcher(part.body);
+      return this._querySelectorRecursively(
+        roots.filter((match) => matcher(elementText(match.element))),
+        selector,
+        index + 1,
+        queryCache
+      );
+    }
+
+    if (part.name === "internal:has") {
+      const has = (match) =>
+        this._queryScoped(match.element, part.nested).length > 0;
+      return this._querySelectorRecursively(
+        roots.filter(has),
+        selector,
+        index + 1,
+        queryCache
+      );
+    }
+
+    // "and" keeps the elements that the nested selector also matches,
+    // and "or" adds the elements that the nested selector matches. Both
+    // query the nested selector from the root of the whole query.
+    if (part.name === "internal:and") {
+      const and = new Set(
+        this._queryScoped(this._scopeRoot, part.nested).map(
+          (match) => match.capture || match.element
+        )
+      );
+      return this._querySelectorRecursively(
+        roots.filter((match) => and.has(match.element)),
+        selector,
+        index + 1,
+        queryCache
+      );
+    }
+
+    if (part.name === "internal:or") {
+      const seen = new Set(roots.map((match) => match.element));
+      const merged = roots.slice();
+      for (const match of this._queryScoped(this._scopeRoot, part.nested)) {
+        const element = match.capture || match.element;
+        if (!seen.has(element)) {
+          seen.add(element);
+          merged.push({ element, capture: undefined });
+        }
+      }
+      return this._querySelectorRecursively(
+        sortInDOMOrder(merged),
+        selector,
+        index + 1,
+        queryCache
+      );
     }
 
     const result = [];
@@ -233,6 +651,23 @@ class InjectedScript {
     );
   }
 
+  // _queryScoped runs the selector query from the root, which becomes the
+  // root of the nested "and" and "or" selectors.
+  _queryScoped(root, selector) {
+    const scopeRoot = this._scopeRoot;
+    this._scopeRoot = root;
+    try {
+      return this._querySelectorRecursively(
+        [{ element: root, capture: undefined }],
+        selector,
+        0,
+        new Map()
+      );
+    } finally {
+      this._scopeRoot = scopeRoot;
+    }
+  }
+
   // Make sure we target an appropriate node in the DOM before performing an action.
   _retarget(node, behavior) {
     let element =
@@ -579,12 +1014,7 @@ class InjectedScript {
     if (!root["querySelector"]) {
       return "error:notqueryablenode";
     }
-    const result = this._querySelectorRecursively(
-      [{ element: root, capture: undefined }],
-      selector,
-      0,
-      new Map()
-    );
+    const result = this._queryScoped(root, selector);
     if (strict && result.length > 1) {
       throw "error:strictmodeviolation";
     }
@@ -598,12 +1028,7 @@ class InjectedScript {
     if (!root["querySelectorAll"]) {
       return "error:notqueryablenode";
     }
-    const result = this._querySelectorRecursively(
-      [{ element: root, capture: undefined }],
-      selector,
-      0,
-      new Map()
-    );
+    const result = this._queryScoped(root, selector);
     const set = new Set();
     for (const r of result) {
       set.add(r.capture || r.element);
@@ -648,6 +1073,15 @@ class InjectedScript {
         ) {
           matches = matches && optionToSelect.index === index;
         }
+        if (
+          optionToSelect.valueOrLabel !== undefined &&
+          optionToSelect.valueOrLabel !== null
+        ) {
+          matches =
+            matches &&
+            (optionToSelect.valueOrLabel === option.value ||
+              optionToSelect.valueOrLabel === option.label);
+        }
         return matches;
       };
       if (!remainingOptionsToSelect.some(filter)) {
@@ -663,11 +1097,13 @@ class InjectedScript {
         break;
       }
     }
-    /*if (remainingOptionsToSelect.length) {
-            return continuePolling;
-        }*/
+    if (remainingOptionsToSelect.length) {
+      return "error:optionsnotfound";
+    }
     select.value = undefined;
-    selectedOptions.forEach((option) => (option.selected = true));
+    options.forEach(
+      (option) => (option.selected = selectedOptions.includes(option))
+    );
     select.dispatchEvent(new Event("input", { bubbles: true }));
     select.dispatchEvent(new Event("change", { bubbles: true }));
     return selectedOptions.map((option) => option.value);
@@ -708,7 +1144,7 @@ class InjectedScript {
     const predicate = () => {
       return predicateFn(...args) || continuePolling;
     };
-    if (timeout !== undefined || timeout !== null) {
+    if (timeout) {
       setTimeout(() => {
         timedOut = true;
         if (timeoutPoll) timeoutPoll();
@@ -83,6 +83,8 @@ func (k *Keyboard) Up(key string) {
 // Press sends a key press message to a session target.
 // It delays the action if `Delay` option is specified.
 // A press message consists of successive key down and up messages.
+// The key can be a chord of keys, like "Control+Shift+KeyK", whose keys are
+// pressed in order, and released in the reverse order.
 func (k *Keyboard) Press(key string, opts goja.Value) {
 	kbdOpts := NewKeyboardOptions()
 	if err := kbdOpts.Parse(k.ctx, opts); err != nil {
@@ -93,7 +95,10 @@ func (k *Keyboard) Press(key string, opts goja.Value) {
 	}
 }
 
-// InsertText inserts a text without dispatching key events.
+// InsertText inserts a text into the focused element without dispatching
+// key events, the way an IME or a paste does. Unlike Type, which presses
+// every character, only an input event is dispatched, so it is faster for
+// long texts and doesn't trigger the keydown, keypress and keyup handlers.
 func (k *Keyboard) InsertText(text string) {
 	if err := k.insertText(text); err != nil {
 		k6ext.Panic(k.ctx, "inserting text: %w", err)
@@ -101,7 +106,8 @@ func (k *Keyboard) InsertText(text string) {
 }
 
 // Type sends a press message to a session target for each character in text.
-// It delays the action if `Delay` option is specified.
+// It delays the action if `Delay` option is specified. See InsertText for
+// inserting a text without key events.
 //
 // It sends an insertText message if a character is not among
 // valid characters in the keyboard's layout.
@@ -228,6 +234,34 @@ func (k *Keyboard) keyDefinitionFromKey(key keyboardlayout.KeyInput) keyboardlay
 	return keyDef
 }
 
+// withModifiers holds the modifier keys, such as Shift, down while fn runs.
+// The keys that are already down are left as they are.
+func (k *Keyboard) withModifiers(modifiers []string, fn func() error) (err error) {
+	var pressed []string
+	defer func() {
+		for i := len(pressed) - 1; i >= 0; i-- {
+			if uerr := k.up(pressed[i]); uerr != nil && err == nil {
+				err = uerr
+			}
+		}
+	}()
+	for _, m := range modifiers {
+		bit := k.modifierBitFromKeyName(m)
+		if bit == 0 {
+			return fmt.Errorf("%q is not a valid modifier, must be one of: Alt, Control, Meta, Shift", m)
+		}
+		if k.modifiers&bit != 0 {
+			continue
+		}
+		if err := k.down(m); err != nil {
+			return err
+		}
+		pressed = append(pressed, m)
+	}
+
+	return fn()
+}
+
 func (k *Keyboard) modifierBitFromKeyName(key string) int64 {
 	switch key {
 	case "Alt":
@@ -251,16 +285,81 @@ func (k *Keyboard) press(key string, opts *KeyboardOptions) error {
 		case <-t.C:
 		}
 	}
-	if err := k.down(key); err != nil {
-		return fmt.Errorf("key down: %w", err)
+	keys := splitKeyChord(key)
+	for _, key := range keys {
+		if _, ok := k.layout.ValidKeys[keyboardlayout.KeyInput(key)]; !ok {
+			return fmt.Errorf("%q is not a valid key for layout %q", key, k.layoutName)
+		}
+	}
+	for _, key := range keys {
+		if err := k.down(key); err != nil {
+			return fmt.Errorf("key down: %w", err)
+		}
+	}
+	for i := len(keys) - 1; i >= 0; i-- {
+		if err := k.up(keys[i]); err != nil {
+			return fmt.Errorf("key up: %w", err)
+		}
+	}
+	return nil
+}
+
+// splitKeyChord splits a key chord like "Control+Shift+KeyK" into its
+// keys. A plus sign that doesn't follow a key is the "+" key itself, so
+// "+" and "Shift++" are valid chords.
+func splitKeyChord(chord string) []string {
+	var (
+		keys     []string
+		building string
+	)
+	for _, c := range chord {
+		if c == '+' && building != "" {
+			keys = append(keys, building)
+			building = ""
+			continue
+		}
+		building += string(c)
+	}
+	return append(keys, building)
+}
+
+// pressChar presses the key of a character, holding the Shift key down for
+// the characters that are typed with it, like uppercase letters.
+func (k *Keyboard) pressChar(key keyboardlayout.KeyInput, opts *KeyboardOptions) error {
+	if !k.needsShift(key) {
+		return k.press(string(key), opts)
+	}
+	if err := k.down("Shift"); err != nil {
+		return err
+	}
+	if err := k.press(string(key), opts); err != nil {
+		return err
+	}
+	return k.up("Shift")
+}
+
+// needsShift returns whether the key is typed with the Shift key, and the
+// Shift key is not already pressed.
+func (k *Keyboard) needsShift(key keyboardlayout.KeyInput) bool {
+	if k.modifiers&ModifierKeyShift != 0 {
+		return false
+	}
+	if _, ok := k.layout.Keys[key]; ok {
+		return false
+	}
+	if _, ok := k.layout.KeyDefinition(key); ok {
+		return false
 	}
-	return k.up(key)
+	return k.layout.ShiftKeyDefinition(key).ShiftKey != ""
 }
 
 func (k *Keyboard) typ(text string, opts *KeyboardOptions) error {
 	layout := keyboardlayout.GetKeyboardLayout(k.layoutName)
-	for _, c := range text {
-		if opts.Delay != 0 {
+	// the delay is applied between the characters, not between the key
+	// down and up events of each character.
+	pressOpts := NewKeyboardOptions()
+	for i, c := range text {
+		if opts.Delay != 0 && i > 0 {
 			t := time.NewTimer(time.Duration(opts.Delay) * time.Millisecond)
 			select {
 			case <-k.ctx.Done():
@@ -270,7 +369,7 @@ func (k *Keyboard) typ(text string, opts *KeyboardOptions) error {
 		}
 		keyInput := keyboardlayout.KeyInput(c)
 		if _, ok := layout.ValidKeys[keyInput]; ok {
-			if err := k.press(string(c), opts); err != nil {
+			if err := k.pressChar(keyInput, pressOpts); err != nil {
 				return fmt.Errorf("pressing key: %w", err)
 			}
 			continue
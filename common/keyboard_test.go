@@ -0,0 +1,21 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitKeyChord(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string][]string{
+		"a":                  {"a"},
+		"Control+Shift+KeyK": {"Control", "Shift", "KeyK"},
+		"+":                  {"+"},
+		"Shift++":            {"Shift", "+"},
+	}
+	for chord, want := range tests {
+		assert.Equal(t, want, splitKeyChord(chord), chord)
+	}
+}
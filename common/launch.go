@@ -48,6 +48,7 @@ type LaunchOptions struct {
 	Headless          bool
 	IgnoreDefaultArgs []string
 	LogCategoryFilter string
+	LogFormat         string
 	Proxy             ProxyOptions
 	SlowMo            time.Duration
 	Timeout           time.Duration
@@ -64,6 +65,7 @@ func NewLaunchOptions() *LaunchOptions {
 		Env:               make(map[string]string),
 		Headless:          true,
 		LogCategoryFilter: ".*",
+		LogFormat:         "text",
 		Timeout:           DefaultTimeout,
 	}
 	return &launchOpts
@@ -109,6 +111,8 @@ func (l *LaunchOptions) Parse(ctx context.Context, opts goja.Value) error {
 				}
 			case "logCategoryFilter":
 				l.LogCategoryFilter = opts.Get(k).String()
+			case "logFormat":
+				l.LogFormat = opts.Get(k).String()
 			case "proxy":
 				v := opts.Get(k)
 				switch v.ExportType() {
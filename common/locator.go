@@ -2,8 +2,11 @@ package common
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 
+	"github.com/grafana/xk6-browser/api"
 	"github.com/grafana/xk6-browser/k6ext"
 	"github.com/grafana/xk6-browser/log"
 
@@ -30,6 +33,256 @@ func NewLocator(ctx context.Context, selector string, f *Frame, l *log.Logger) *
 	}
 }
 
+// All returns a locator for each of the elements matching the locator's
+// selector. Each of the locators is pinned to the index of its element.
+//
+// The elements are counted at the time of the call, so the elements that
+// are added or removed afterwards are not reflected in the returned locators.
+func (l *Locator) All() []api.Locator {
+	l.log.Debugf("Locator:All", "fid:%s furl:%q sel:%q", l.frame.ID(), l.frame.URL(), l.selector)
+
+	n, err := l.frame.count(l.selector)
+	if err != nil {
+		k6ext.Panic(l.ctx, "getting all locators of %q: %w", l.selector, err)
+	}
+	locators := make([]api.Locator, n)
+	for i := range locators {
+		locators[i] = l.nth(i)
+	}
+	return locators
+}
+
+// AllInnerTexts returns the inner texts of all the elements matching the
+// locator's selector. It doesn't wait for the elements, and returns an
+// empty slice if no elements match.
+func (l *Locator) AllInnerTexts() []string {
+	l.log.Debugf("Locator:AllInnerTexts", "fid:%s furl:%q sel:%q", l.frame.ID(), l.frame.URL(), l.selector)
+
+	texts, err := l.allTexts("innerText")
+	if err != nil {
+		k6ext.Panic(l.ctx, "getting all inner texts of %q: %w", l.selector, err)
+	}
+	return texts
+}
+
+// AllTextContents returns the text contents of all the elements matching
+// the locator's selector. It doesn't wait for the elements, and returns an
+// empty slice if no elements match.
+func (l *Locator) AllTextContents() []string {
+	l.log.Debugf("Locator:AllTextContents", "fid:%s furl:%q sel:%q", l.frame.ID(), l.frame.URL(), l.selector)
+
+	texts, err := l.allTexts("textContent")
+	if err != nil {
+		k6ext.Panic(l.ctx, "getting all text contents of %q: %w", l.selector, err)
+	}
+	return texts
+}
+
+// allTexts returns the text property, such as innerText, of all the
+// elements matching the locator's selector.
+func (l *Locator) allTexts(property string) ([]string, error) {
+	rt := k6ext.Runtime(l.ctx)
+	fn := rt.ToValue(`(elements, property) => elements.map(e => e[property] || "")`)
+	v, err := l.evaluateAll(fn, rt.ToValue(property))
+	if err != nil {
+		return nil, err
+	}
+	gv, ok := v.(goja.Value)
+	if !ok {
+		return nil, fmt.Errorf("unexpected type %T", v)
+	}
+	values, ok := gv.Export().([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected type %T", gv.Export())
+	}
+	texts := make([]string, len(values))
+	for i, t := range values {
+		texts[i] = fmt.Sprint(t)
+	}
+
+	return texts, nil
+}
+
+// Count returns the number of elements matching the locator's selector.
+// It returns zero if no elements match.
+func (l *Locator) Count() int {
+	l.log.Debugf("Locator:Count", "fid:%s furl:%q sel:%q", l.frame.ID(), l.frame.URL(), l.selector)
+
+	n, err := l.frame.count(l.selector)
+	if err != nil {
+		k6ext.Panic(l.ctx, "counting elements of %q: %w", l.selector, err)
+	}
+	return n
+}
+
+// Nth returns a new locator that matches the element at the given
+// zero based index among the elements matching the locator's selector.
+func (l *Locator) Nth(index int) api.Locator {
+	l.log.Debugf("Locator:Nth", "fid:%s furl:%q sel:%q index:%d", l.frame.ID(), l.frame.URL(), l.selector, index)
+
+	if index < 0 {
+		k6ext.Panic(l.ctx, "getting nth locator of %q: index must be non-negative, got %d", l.selector, index)
+	}
+	return l.nth(index)
+}
+
+// First returns a new locator that matches the first element matching
+// the locator's selector.
+func (l *Locator) First() api.Locator {
+	l.log.Debugf("Locator:First", "fid:%s furl:%q sel:%q", l.frame.ID(), l.frame.URL(), l.selector)
+
+	return l.nth(0)
+}
+
+// Last returns a new locator that matches the last element matching
+// the locator's selector.
+func (l *Locator) Last() api.Locator {
+	l.log.Debugf("Locator:Last", "fid:%s furl:%q sel:%q", l.frame.ID(), l.frame.URL(), l.selector)
+
+	return l.nth(-1)
+}
+
+// FrameLocator returns a frame locator for the iframes matching the selector
+// in the locator's elements.
+func (l *Locator) FrameLocator(selector string) api.FrameLocator {
+	l.log.Debugf("Locator:FrameLocator", "fid:%s furl:%q sel:%q inner:%q", l.frame.ID(), l.frame.URL(), l.selector, selector)
+
+	return NewFrameLocator(l.ctx, l.selector+" >> "+selector, l.frame, l.log)
+}
+
+// Filter returns a new locator that narrows down the locator's elements to
+// the ones matching all the given options.
+func (l *Locator) Filter(opts goja.Value) api.Locator {
+	l.log.Debugf("Locator:Filter", "fid:%s furl:%q sel:%q opts:%+v", l.frame.ID(), l.frame.URL(), l.selector, opts)
+
+	fopts := NewLocatorFilterOptions()
+	if err := fopts.Parse(l.ctx, opts); err != nil {
+		k6ext.Panic(l.ctx, "parsing filter options: %w", err)
+	}
+	fl, err := l.filter(fopts)
+	if err != nil {
+		k6ext.Panic(l.ctx, "filtering %q: %w", l.selector, err)
+	}
+	return fl
+}
+
+func (l *Locator) filter(opts *LocatorFilterOptions) (*Locator, error) {
+	selector := l.selector
+	if opts.HasText != nil {
+		body, err := textSelectorBody(opts.HasText, false)
+		if err != nil {
+			return nil, fmt.Errorf("parsing hasText: %w", err)
+		}
+		selector += " >> internal:has-text=" + body
+	}
+	if opts.Has != nil {
+		if opts.Has.frame != l.frame {
+			return nil, errors.New("inner locator must belong to the same frame")
+		}
+		inner, err := json.Marshal(opts.Has.selector)
+		if err != nil {
+			return nil, fmt.Errorf("encoding inner locator selector %q: %w", opts.Has.selector, err)
+		}
+		selector += " >> internal:has=" + string(inner)
+	}
+	return NewLocator(l.ctx, selector, l.frame, l.log), nil
+}
+
+// And returns a new locator that matches the elements matching both the
+// locator and the other locator.
+func (l *Locator) And(other api.Locator) api.Locator {
+	l.log.Debugf("Locator:And", "fid:%s furl:%q sel:%q", l.frame.ID(), l.frame.URL(), l.selector)
+
+	al, err := l.combine("internal:and", other)
+	if err != nil {
+		k6ext.Panic(l.ctx, "combining %q with and: %w", l.selector, err)
+	}
+	return al
+}
+
+// Or returns a new locator that matches the elements matching either the
+// locator or the other locator. The elements are in the document order,
+// and the ones matching both locators are included once, so the ordinal
+// locators, such as First, don't depend on which side matched.
+func (l *Locator) Or(other api.Locator) api.Locator {
+	l.log.Debugf("Locator:Or", "fid:%s furl:%q sel:%q", l.frame.ID(), l.frame.URL(), l.selector)
+
+	ol, err := l.combine("internal:or", other)
+	if err != nil {
+		k6ext.Panic(l.ctx, "combining %q with or: %w", l.selector, err)
+	}
+	return ol
+}
+
+// combine returns a new locator that combines the elements of the locator
+// with the ones of the other locator using the given selector engine. The
+// other locator's selector is resolved within the same root, which lets the
+// selector engine combine them in a single query.
+func (l *Locator) combine(engine string, other api.Locator) (*Locator, error) {
+	ol, ok := other.(*Locator)
+	if !ok || ol == nil {
+		return nil, fmt.Errorf("unexpected locator type %T", other)
+	}
+	if ol.frame != l.frame {
+		return nil, errors.New("locators must belong to the same frame")
+	}
+	inner, err := json.Marshal(ol.selector)
+	if err != nil {
+		return nil, fmt.Errorf("encoding locator selector %q: %w", ol.selector, err)
+	}
+	return NewLocator(l.ctx, l.selector+" >> "+engine+"="+string(inner), l.frame, l.log), nil
+}
+
+// nth returns a new locator that matches the element at the given index
+// among the elements matching the locator's selector. The index can be -1
+// to match the last element.
+func (l *Locator) nth(i int) *Locator {
+	return NewLocator(l.ctx, fmt.Sprintf("%s >> nth=%d", l.selector, i), l.frame, l.log)
+}
+
+// AriaSnapshot waits for the element matching the locator's selector with
+// strict mode on to be attached, and returns the ARIA snapshot of its
+// subtree. The snapshot lists the roles, accessible names, and states of the
+// nodes in a YAML format suitable for comparing against golden files.
+func (l *Locator) AriaSnapshot(opts goja.Value) string {
+	l.log.Debugf("Locator:AriaSnapshot", "fid:%s furl:%q sel:%q opts:%+v", l.frame.ID(), l.frame.URL(), l.selector, opts)
+
+	popts := NewFrameBaseOptions(l.frame.defaultTimeout())
+	if err := popts.Parse(l.ctx, opts); err != nil {
+		k6ext.Panic(l.ctx, "parsing aria snapshot options: %w", err)
+	}
+	h, err := l.elementHandle(popts)
+	if err != nil {
+		k6ext.Panic(l.ctx, "getting aria snapshot of %q: %w", l.selector, err)
+	}
+	defer h.Dispose()
+
+	snapshot, err := h.ariaSnapshot(l.ctx)
+	if err != nil {
+		k6ext.Panic(l.ctx, "getting aria snapshot of %q: %w", l.selector, err)
+	}
+	return snapshot
+}
+
+// BoundingBox waits for the element matching the locator's selector with
+// strict mode on to be attached, and returns its bounding box in page
+// coordinates. It returns nil if the element is not rendered.
+func (l *Locator) BoundingBox(opts goja.Value) *api.Rect {
+	l.log.Debugf("Locator:BoundingBox", "fid:%s furl:%q sel:%q opts:%+v", l.frame.ID(), l.frame.URL(), l.selector, opts)
+
+	popts := NewFrameBaseOptions(l.frame.defaultTimeout())
+	if err := popts.Parse(l.ctx, opts); err != nil {
+		k6ext.Panic(l.ctx, "parsing bounding box options: %w", err)
+	}
+	h, err := l.elementHandle(popts)
+	if err != nil {
+		k6ext.Panic(l.ctx, "getting bounding box of %q: %w", l.selector, err)
+	}
+	defer h.Dispose()
+
+	return h.BoundingBox()
+}
+
 // Click on an element using locator's selector with strict mode on.
 func (l *Locator) Click(opts goja.Value) {
 	l.log.Debugf("Locator:Click", "fid:%s furl:%q sel:%q opts:%+v", l.frame.ID(), l.frame.URL(), l.selector, opts)
@@ -80,6 +333,40 @@ func (l *Locator) dblclick(opts *FrameDblclickOptions) error {
 	return l.frame.dblclick(l.selector, opts)
 }
 
+// DragTo drags the element found that matches the locator's selector and
+// drops it on the element found that matches the target locator's
+// selector, both with strict mode on.
+func (l *Locator) DragTo(target api.Locator, opts goja.Value) {
+	l.log.Debugf("Locator:DragTo", "fid:%s furl:%q sel:%q opts:%+v", l.frame.ID(), l.frame.URL(), l.selector, opts)
+
+	var err error
+	defer func() { panicOrSlowMo(l.ctx, err) }()
+
+	copts := NewFrameDragAndDropOptions(l.frame.defaultTimeout())
+	if err = copts.Parse(l.ctx, opts); err != nil {
+		err = fmt.Errorf("parsing drag options: %w", err)
+		return
+	}
+	if err = l.dragTo(target, copts); err != nil {
+		err = fmt.Errorf("dragging %q: %w", l.selector, err)
+		return
+	}
+}
+
+// dragTo is like DragTo but takes parsed options and neither throws an
+// error, or applies slow motion.
+func (l *Locator) dragTo(target api.Locator, opts *FrameDragAndDropOptions) error {
+	tl, ok := target.(*Locator)
+	if !ok || tl == nil {
+		return errors.New("target must be a locator")
+	}
+	if tl.frame != l.frame {
+		return errors.New("target locator must belong to the same frame")
+	}
+	opts.Strict = true
+	return l.frame.dragAndDrop(l.selector, tl.selector, opts)
+}
+
 // Check on an element using locator's selector with strict mode on.
 func (l *Locator) Check(opts goja.Value) {
 	l.log.Debugf("Locator:Check", "fid:%s furl:%q sel:%q opts:%+v", l.frame.ID(), l.frame.URL(), l.selector, opts)
@@ -130,6 +417,35 @@ func (l *Locator) uncheck(opts *FrameUncheckOptions) error {
 	return l.frame.uncheck(l.selector, opts)
 }
 
+// SetChecked checks or unchecks an element using locator's selector with
+// strict mode on, depending on checked.
+func (l *Locator) SetChecked(checked bool, opts goja.Value) {
+	l.log.Debugf(
+		"Locator:SetChecked", "fid:%s furl:%q sel:%q checked:%t opts:%+v",
+		l.frame.ID(), l.frame.URL(), l.selector, checked, opts,
+	)
+
+	var err error
+	defer func() { panicOrSlowMo(l.ctx, err) }()
+
+	copts := NewFrameSetCheckedOptions(l.frame.defaultTimeout())
+	if err = copts.Parse(l.ctx, opts); err != nil {
+		err = fmt.Errorf("parsing set checked options: %w", err)
+		return
+	}
+	if err = l.setChecked(checked, copts); err != nil {
+		err = fmt.Errorf("setting checked state of %q: %w", l.selector, err)
+		return
+	}
+}
+
+// setChecked is like SetChecked but takes parsed options and neither
+// throws an error, or applies slow motion.
+func (l *Locator) setChecked(checked bool, opts *FrameSetCheckedOptions) error {
+	opts.Strict = true
+	return l.frame.setChecked(l.selector, checked, opts)
+}
+
 // IsChecked returns true if the element matches the locator's
 // selector and is checked. Otherwise, returns false.
 func (l *Locator) IsChecked(opts goja.Value) bool {
@@ -227,7 +543,8 @@ func (l *Locator) isDisabled(opts *FrameIsDisabledOptions) (bool, error) {
 }
 
 // IsVisible returns true if the element matches the locator's
-// selector and is visible. Otherwise, returns false.
+// selector and is visible. Otherwise, returns false. It doesn't wait
+// for the element to match.
 func (l *Locator) IsVisible(opts goja.Value) bool {
 	l.log.Debugf("Locator:IsVisible", "fid:%s furl:%q sel:%q opts:%+v", l.frame.ID(), l.frame.URL(), l.selector, opts)
 
@@ -251,7 +568,8 @@ func (l *Locator) isVisible(opts *FrameIsVisibleOptions) (bool, error) {
 }
 
 // IsHidden returns true if the element matches the locator's
-// selector and is hidden. Otherwise, returns false.
+// selector and is hidden, or if no element matches. Otherwise, returns
+// false. It doesn't wait for the element to match.
 func (l *Locator) IsHidden(opts goja.Value) bool {
 	l.log.Debugf("Locator:IsHidden", "fid:%s furl:%q sel:%q opts:%+v", l.frame.ID(), l.frame.URL(), l.selector, opts)
 
@@ -274,6 +592,33 @@ func (l *Locator) isHidden(opts *FrameIsHiddenOptions) (bool, error) {
 	return l.frame.isHidden(l.selector, opts)
 }
 
+// Clear empties the input or textarea element using locator's selector
+// with strict mode on. Unlike filling the element with an empty string,
+// it deletes the value with the keyboard.
+func (l *Locator) Clear(opts goja.Value) {
+	l.log.Debugf("Locator:Clear", "fid:%s furl:%q sel:%q opts:%+v", l.frame.ID(), l.frame.URL(), l.selector, opts)
+
+	var err error
+	defer func() { panicOrSlowMo(l.ctx, err) }()
+
+	copts := NewFrameClearOptions(l.frame.defaultTimeout())
+	if err = copts.Parse(l.ctx, opts); err != nil {
+		err = fmt.Errorf("parsing clear options: %w", err)
+		return
+	}
+	if err = l.clear(copts); err != nil {
+		err = fmt.Errorf("clearing %q: %w", l.selector, err)
+		return
+	}
+}
+
+// clear is like Clear but takes parsed options and neither throws an
+// error, or applies slow motion.
+func (l *Locator) clear(opts *FrameClearOptions) error {
+	opts.Strict = true
+	return l.frame.clear(l.selector, opts)
+}
+
 // Fill out the element using locator's selector with strict mode on.
 func (l *Locator) Fill(value string, opts goja.Value) {
 	l.log.Debugf(
@@ -478,6 +823,27 @@ func (l *Locator) selectOption(values goja.Value, opts *FrameSelectOptionOptions
 	return l.frame.selectOption(l.selector, values, opts)
 }
 
+// SetInputFiles sets the files of the file input element found that
+// matches the locator's selector with strict mode on.
+func (l *Locator) SetInputFiles(files goja.Value, opts goja.Value) {
+	l.log.Debugf("Locator:SetInputFiles", "fid:%s furl:%q sel:%q opts:%+v", l.frame.ID(), l.frame.URL(), l.selector, opts)
+
+	copts := NewFrameSetInputFilesOptions(l.frame.defaultTimeout())
+	if err := copts.Parse(l.ctx, opts); err != nil {
+		k6ext.Panic(l.ctx, "parsing setInputFiles options: %w", err)
+	}
+	if err := l.setInputFiles(files, copts); err != nil {
+		k6ext.Panic(l.ctx, "setting input files on %q: %w", l.selector, err)
+	}
+
+	applySlowMo(l.ctx)
+}
+
+func (l *Locator) setInputFiles(files goja.Value, opts *FrameSetInputFilesOptions) error {
+	opts.Strict = true
+	return l.frame.setInputFiles(l.selector, files, opts)
+}
+
 // Press the given key on the element found that matches the locator's
 // selector with strict mode on.
 func (l *Locator) Press(key string, opts goja.Value) {
@@ -532,6 +898,31 @@ func (l *Locator) typ(text string, opts *FrameTypeOptions) error {
 	return l.frame.typ(l.selector, text, opts)
 }
 
+// PressSequentially focuses the element found that matches the locator's
+// selector with strict mode on, and presses a key for every character in
+// text, waiting for the delay option between the characters. Unlike Fill,
+// it dispatches the key events of every character, which some widgets,
+// like autocompletes, rely on.
+func (l *Locator) PressSequentially(text string, opts goja.Value) {
+	l.log.Debugf(
+		"Locator:PressSequentially", "fid:%s furl:%q sel:%q text:%q opts:%+v",
+		l.frame.ID(), l.frame.URL(), l.selector, text, opts,
+	)
+
+	var err error
+	defer func() { panicOrSlowMo(l.ctx, err) }()
+
+	copts := NewFrameTypeOptions(l.frame.defaultTimeout())
+	if err = copts.Parse(l.ctx, opts); err != nil {
+		err = fmt.Errorf("parsing pressSequentially options: %w", err)
+		return
+	}
+	if err = l.typ(text, copts); err != nil {
+		err = fmt.Errorf("pressing %q sequentially in %q: %w", text, l.selector, err)
+		return
+	}
+}
+
 // Hover moves the pointer over the element that matches the locator's
 // selector with strict mode on.
 func (l *Locator) Hover(opts goja.Value) {
@@ -556,6 +947,68 @@ func (l *Locator) hover(opts *FrameHoverOptions) error {
 	return l.frame.hover(l.selector, opts)
 }
 
+// Screenshot waits for the element matching the locator's selector with
+// strict mode on to be visible, and takes a screenshot of it.
+func (l *Locator) Screenshot(opts goja.Value) goja.ArrayBuffer {
+	l.log.Debugf("Locator:Screenshot", "fid:%s furl:%q sel:%q opts:%+v", l.frame.ID(), l.frame.URL(), l.selector, opts)
+
+	copts := NewElementHandleScreenshotOptions(l.frame.defaultTimeout())
+	if err := copts.Parse(l.ctx, opts); err != nil {
+		k6ext.Panic(l.ctx, "parsing screenshot options: %w", err)
+	}
+	buf, err := l.screenshot(copts)
+	if err != nil {
+		k6ext.Panic(l.ctx, "taking screenshot of %q: %w", l.selector, err)
+	}
+
+	return k6ext.Runtime(l.ctx).NewArrayBuffer(buf)
+}
+
+func (l *Locator) screenshot(opts *ElementHandleScreenshotOptions) ([]byte, error) {
+	wopts := NewFrameWaitForSelectorOptions(opts.Timeout)
+	wopts.State = DOMElementStateVisible
+	wopts.Strict = true
+	h, err := l.frame.waitForSelector(l.selector, wopts)
+	if err != nil {
+		return nil, err
+	}
+	defer h.Dispose()
+
+	buf, err := newScreenshotter(l.ctx).screenshotElement(h, opts)
+	if err != nil {
+		return nil, err
+	}
+	return *buf, nil
+}
+
+// ScrollIntoViewIfNeeded waits for the element matching the locator's
+// selector with strict mode on to be visible and stable, and scrolls it
+// into the viewport if it's not already fully visible.
+func (l *Locator) ScrollIntoViewIfNeeded(opts goja.Value) {
+	l.log.Debugf(
+		"Locator:ScrollIntoViewIfNeeded", "fid:%s furl:%q sel:%q opts:%+v",
+		l.frame.ID(), l.frame.URL(), l.selector, opts,
+	)
+
+	var err error
+	defer func() { panicOrSlowMo(l.ctx, err) }()
+
+	popts := NewFrameBaseOptions(l.frame.defaultTimeout())
+	if err = popts.Parse(l.ctx, opts); err != nil {
+		err = fmt.Errorf("parsing scrollIntoViewIfNeeded options: %w", err)
+		return
+	}
+	if err = l.scrollIntoViewIfNeeded(popts); err != nil {
+		err = fmt.Errorf("scrolling %q into view: %w", l.selector, err)
+		return
+	}
+}
+
+func (l *Locator) scrollIntoViewIfNeeded(opts *FrameBaseOptions) error {
+	opts.Strict = true
+	return l.frame.scrollIntoViewIfNeeded(l.selector, opts)
+}
+
 // Tap the element found that matches the locator's selector with strict mode on.
 func (l *Locator) Tap(opts goja.Value) {
 	l.log.Debugf("Locator:Tap", "fid:%s furl:%q sel:%q opts:%+v", l.frame.ID(), l.frame.URL(), l.selector, opts)
@@ -624,3 +1077,117 @@ func (l *Locator) waitFor(opts *FrameWaitForSelectorOptions) error {
 	_, err := l.frame.waitForSelector(l.selector, opts)
 	return err
 }
+
+// Evaluate waits for the element matching the locator's selector with strict
+// mode on to be attached, and runs the page function with the element as its
+// first argument.
+func (l *Locator) Evaluate(pageFunc goja.Value, arg goja.Value) interface{} {
+	l.log.Debugf("Locator:Evaluate", "fid:%s furl:%q sel:%q", l.frame.ID(), l.frame.URL(), l.selector)
+
+	v, err := l.evaluate(pageFunc, arg)
+	if err != nil {
+		k6ext.Panic(l.ctx, "evaluating %q: %w", l.selector, err)
+	}
+	return v
+}
+
+func (l *Locator) evaluate(pageFunc goja.Value, arg goja.Value) (interface{}, error) {
+	h, err := l.elementHandle(NewFrameBaseOptions(l.frame.defaultTimeout()))
+	if err != nil {
+		return nil, err
+	}
+	defer h.Dispose()
+
+	return h.execCtx.Eval(l.ctx, pageFunc, evalArgs(k6ext.Runtime(l.ctx), h, arg)...)
+}
+
+// EvaluateAll runs the page function with an array of all the elements
+// matching the locator's selector as its first argument. It doesn't wait
+// for any elements to match, so the array can be empty.
+func (l *Locator) EvaluateAll(pageFunc goja.Value, arg goja.Value) interface{} {
+	l.log.Debugf("Locator:EvaluateAll", "fid:%s furl:%q sel:%q", l.frame.ID(), l.frame.URL(), l.selector)
+
+	v, err := l.evaluateAll(pageFunc, arg)
+	if err != nil {
+		k6ext.Panic(l.ctx, "evaluating all %q: %w", l.selector, err)
+	}
+	return v
+}
+
+func (l *Locator) evaluateAll(pageFunc goja.Value, arg goja.Value) (interface{}, error) {
+	return l.frame.evalOnSelectorAll(l.selector, pageFunc, arg)
+}
+
+// evalArgs returns the arguments of a page function called with the handle,
+// followed by the optional argument.
+func evalArgs(rt *goja.Runtime, h api.JSHandle, arg goja.Value) []goja.Value {
+	args := []goja.Value{rt.ToValue(h)}
+	if arg != nil {
+		args = append(args, arg)
+	}
+	return args
+}
+
+// ElementHandle waits for the element matching the locator's selector with
+// strict mode on to be attached, and returns a handle to it. The handle
+// should be disposed by the caller once it's no longer needed, otherwise
+// the element can't be garbage collected by the browser.
+func (l *Locator) ElementHandle(opts goja.Value) api.ElementHandle {
+	l.log.Debugf("Locator:ElementHandle", "fid:%s furl:%q sel:%q opts:%+v", l.frame.ID(), l.frame.URL(), l.selector, opts)
+
+	popts := NewFrameBaseOptions(l.frame.defaultTimeout())
+	if err := popts.Parse(l.ctx, opts); err != nil {
+		k6ext.Panic(l.ctx, "parsing element handle options: %w", err)
+	}
+	h, err := l.elementHandle(popts)
+	if err != nil {
+		k6ext.Panic(l.ctx, "getting element handle of %q: %w", l.selector, err)
+	}
+	return h
+}
+
+func (l *Locator) elementHandle(opts *FrameBaseOptions) (*ElementHandle, error) {
+	wopts := NewFrameWaitForSelectorOptions(opts.Timeout)
+	wopts.State = DOMElementStateAttached
+	wopts.Strict = true
+	return l.frame.waitForSelector(l.selector, wopts)
+}
+
+// Highlight highlights the elements matching the locator's selector in
+// the browser window for LocatorHighlightTimeout. It's a debugging aid for
+// the headed mode, and it does nothing in the headless mode.
+func (l *Locator) Highlight() {
+	l.log.Debugf("Locator:Highlight", "fid:%s furl:%q sel:%q", l.frame.ID(), l.frame.URL(), l.selector)
+
+	if l.frame.page.browserCtx.browser.launchOpts.Headless {
+		l.log.Warnf("Locator:Highlight", "highlighting %q has no effect in headless mode", l.selector)
+		return
+	}
+	if err := l.frame.highlight(l.selector); err != nil {
+		k6ext.Panic(l.ctx, "highlighting %q: %w", l.selector, err)
+	}
+}
+
+// ElementHandles returns handles to all the elements matching the locator's
+// selector, without waiting for any elements to match. As with
+// ElementHandle, the handles should be disposed by the caller.
+func (l *Locator) ElementHandles() []api.ElementHandle {
+	l.log.Debugf("Locator:ElementHandles", "fid:%s furl:%q sel:%q", l.frame.ID(), l.frame.URL(), l.selector)
+
+	frame, selector, err := l.frame.resolveFrameSelector(l.selector, l.frame.defaultTimeout())
+	if err != nil {
+		k6ext.Panic(l.ctx, "getting element handles of %q: %w", l.selector, err)
+	}
+	document, err := frame.document()
+	if err != nil {
+		k6ext.Panic(l.ctx, "getting document: %w", err)
+	}
+	handles, err := document.queryAll(selector, document.evalWithScript)
+	if err != nil {
+		k6ext.Panic(l.ctx, "getting element handles of %q: %w", l.selector, err)
+	}
+	if handles == nil {
+		return []api.ElementHandle{}
+	}
+	return handles
+}
@@ -0,0 +1,129 @@
+package common
+
+import (
+	"context"
+	"errors"
+
+	"github.com/grafana/xk6-browser/k6ext"
+
+	"github.com/dop251/goja"
+)
+
+// LocatorFilterOptions are the options for narrowing down a locator.
+type LocatorFilterOptions struct {
+	// HasText is a string or a RegExp the element's text must match.
+	HasText goja.Value
+	// Has is a locator that must match a descendant of the element.
+	Has *Locator
+}
+
+// NewLocatorFilterOptions returns a new LocatorFilterOptions.
+func NewLocatorFilterOptions() *LocatorFilterOptions {
+	return &LocatorFilterOptions{}
+}
+
+// Parse parses the locator filter options.
+func (o *LocatorFilterOptions) Parse(ctx context.Context, opts goja.Value) error {
+	if !gojaValueExists(opts) {
+		return nil
+	}
+	rt := k6ext.Runtime(ctx)
+	obj := opts.ToObject(rt)
+	for _, k := range obj.Keys() {
+		v := obj.Get(k)
+		switch k {
+		case "hasText":
+			if gojaValueExists(v) {
+				o.HasText = v
+			}
+		case "has":
+			if !gojaValueExists(v) {
+				continue
+			}
+			l, ok := v.Export().(*Locator)
+			if !ok {
+				return errors.New("has must be a locator")
+			}
+			o.Has = l
+		}
+	}
+	return nil
+}
+
+// LocatorGetByRoleOptions are the options for locating elements by their
+// ARIA role.
+type LocatorGetByRoleOptions struct {
+	// Name is a string or a RegExp the element's accessible name must match.
+	Name goja.Value
+	// Exact makes a string Name match the whole accessible name
+	// case-sensitively.
+	Exact bool
+	// Checked matches the elements by their checked state if set.
+	Checked *bool
+	// Disabled matches the elements by their disabled state if set.
+	Disabled *bool
+	// IncludeHidden also matches the elements hidden from the
+	// accessibility tree.
+	IncludeHidden bool
+}
+
+// NewLocatorGetByRoleOptions returns a new LocatorGetByRoleOptions.
+func NewLocatorGetByRoleOptions() *LocatorGetByRoleOptions {
+	return &LocatorGetByRoleOptions{}
+}
+
+// Parse parses the get by role options.
+func (o *LocatorGetByRoleOptions) Parse(ctx context.Context, opts goja.Value) error {
+	if !gojaValueExists(opts) {
+		return nil
+	}
+	rt := k6ext.Runtime(ctx)
+	obj := opts.ToObject(rt)
+	for _, k := range obj.Keys() {
+		v := obj.Get(k)
+		if !gojaValueExists(v) {
+			continue
+		}
+		switch k {
+		case "name":
+			o.Name = v
+		case "exact":
+			o.Exact = v.ToBoolean()
+		case "checked":
+			b := v.ToBoolean()
+			o.Checked = &b
+		case "disabled":
+			b := v.ToBoolean()
+			o.Disabled = &b
+		case "includeHidden":
+			o.IncludeHidden = v.ToBoolean()
+		}
+	}
+	return nil
+}
+
+// LocatorGetByTextOptions are the options for locating elements by their
+// text, label or placeholder.
+type LocatorGetByTextOptions struct {
+	// Exact makes a string match the whole text case-sensitively.
+	Exact bool
+}
+
+// NewLocatorGetByTextOptions returns a new LocatorGetByTextOptions.
+func NewLocatorGetByTextOptions() *LocatorGetByTextOptions {
+	return &LocatorGetByTextOptions{}
+}
+
+// Parse parses the get by text options.
+func (o *LocatorGetByTextOptions) Parse(ctx context.Context, opts goja.Value) error {
+	if !gojaValueExists(opts) {
+		return nil
+	}
+	obj := opts.ToObject(k6ext.Runtime(ctx))
+	for _, k := range obj.Keys() {
+		if k == "exact" {
+			o.Exact = obj.Get(k).ToBoolean()
+		}
+	}
+	return nil
+}
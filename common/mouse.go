@@ -22,11 +22,13 @@ package common
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/grafana/xk6-browser/api"
 	"github.com/grafana/xk6-browser/k6ext"
 
+	"github.com/chromedp/cdproto"
 	"github.com/chromedp/cdproto/cdp"
 	"github.com/chromedp/cdproto/input"
 	"github.com/dop251/goja"
@@ -45,6 +47,11 @@ type Mouse struct {
 	x               float64
 	y               float64
 	button          input.MouseButton
+	// dragData is the data of the HTML5 drag in progress, if any.
+	dragData *input.DragData
+	// dragPending is whether the next move can start an HTML5 drag, as it's
+	// the first move since the left button was pressed.
+	dragPending bool
 }
 
 // NewMouse creates a new mouse.
@@ -59,34 +66,16 @@ func NewMouse(ctx context.Context, s session, f *Frame, ts *TimeoutSettings, k *
 	}
 }
 
+// click presses and releases the button clickCount times in a row. Each
+// press reports how many times the button is pressed so far, so that the page
+// receives a click event per press, and a dblclick event on the second one.
 func (m *Mouse) click(x float64, y float64, opts *MouseClickOptions) error {
-	mouseDownUpOpts := opts.ToMouseDownUpOptions()
 	if err := m.move(x, y, NewMouseMoveOptions()); err != nil {
 		return err
 	}
-	if err := m.down(x, y, mouseDownUpOpts); err != nil {
-		return err
-	}
-	if opts.Delay != 0 {
-		t := time.NewTimer(time.Duration(opts.Delay) * time.Millisecond)
-		select {
-		case <-m.ctx.Done():
-			t.Stop()
-		case <-t.C:
-		}
-	}
-	if err := m.up(x, y, mouseDownUpOpts); err != nil {
-		return err
-	}
-	return nil
-}
-
-func (m *Mouse) dblClick(x float64, y float64, opts *MouseDblClickOptions) error {
 	mouseDownUpOpts := opts.ToMouseDownUpOptions()
-	if err := m.move(x, y, NewMouseMoveOptions()); err != nil {
-		return err
-	}
-	for i := 0; i < 2; i++ {
+	for i := int64(1); i <= opts.ClickCount; i++ {
+		mouseDownUpOpts.ClickCount = i
 		if err := m.down(x, y, mouseDownUpOpts); err != nil {
 			return err
 		}
@@ -105,6 +94,10 @@ func (m *Mouse) dblClick(x float64, y float64, opts *MouseDblClickOptions) error
 	return nil
 }
 
+func (m *Mouse) dblClick(x float64, y float64, opts *MouseDblClickOptions) error {
+	return m.click(x, y, opts.ToMouseClickOptions())
+}
+
 func (m *Mouse) down(x float64, y float64, opts *MouseDownUpOptions) error {
 	m.button = input.MouseButton(opts.Button)
 	action := input.DispatchMouseEvent(input.MousePressed, m.x, m.y).
@@ -114,6 +107,7 @@ func (m *Mouse) down(x float64, y float64, opts *MouseDownUpOptions) error {
 	if err := action.Do(cdp.WithExecutor(m.ctx, m.session)); err != nil {
 		return err
 	}
+	m.dragPending = m.button == input.Left && m.dragData == nil
 	return nil
 }
 
@@ -123,26 +117,156 @@ func (m *Mouse) move(x float64, y float64, opts *MouseMoveOptions) error {
 	m.x = x
 	m.y = y
 	for i := int64(1); i <= opts.Steps; i++ {
-		x := fromX + (m.x-fromX)*float64(i/opts.Steps)
-		y := fromY + (m.y-fromY)*float64(i/opts.Steps)
-		action := input.DispatchMouseEvent(input.MouseMoved, x, y).
-			WithButton(m.button).
-			WithModifiers(input.Modifier(m.keyboard.modifiers))
-		if err := action.Do(cdp.WithExecutor(m.ctx, m.session)); err != nil {
+		x := fromX + (m.x-fromX)*float64(i)/float64(opts.Steps)
+		y := fromY + (m.y-fromY)*float64(i)/float64(opts.Steps)
+		var err error
+		switch {
+		case m.dragData != nil:
+			err = m.dispatchDragEvent(input.DragOver, x, y)
+		case m.dragPending:
+			m.dragPending = false
+			err = m.moveAndInterceptDrag(x, y)
+		default:
+			err = m.dispatchMove(x, y)
+		}
+		if err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+func (m *Mouse) dispatchMove(x float64, y float64) error {
+	action := input.DispatchMouseEvent(input.MouseMoved, x, y).
+		WithButton(m.button).
+		WithModifiers(input.Modifier(m.keyboard.modifiers))
+	return action.Do(cdp.WithExecutor(m.ctx, m.session))
+}
+
+func (m *Mouse) dispatchDragEvent(typ input.DispatchDragEventType, x float64, y float64) error {
+	action := input.DispatchDragEvent(typ, x, y, m.dragData).
+		WithModifiers(input.Modifier(m.keyboard.modifiers))
+	if err := action.Do(cdp.WithExecutor(m.ctx, m.session)); err != nil {
+		return fmt.Errorf("dispatching %s drag event: %w", typ, err)
+	}
+	return nil
+}
+
+// moveAndInterceptDrag makes the first move since the left button was
+// pressed, and intercepts the HTML5 drag the move starts, if any. The
+// browser doesn't perform HTML5 drags for the emulated mouse events, so the
+// intercepted drag is dispatched as drag events until the button is
+// released.
+func (m *Mouse) moveAndInterceptDrag(x float64, y float64) error {
+	// tracks whether the move starts a drag that the page doesn't cancel.
+	const trackDrag = `() => {
+		let started = Promise.resolve(false);
+		let dragEvent = null;
+		const onDragStart = (event) => dragEvent = event;
+		const onMouseMove = () => {
+			started = new Promise((resolve) => {
+				window.addEventListener('dragstart', onDragStart, { once: true, capture: true });
+				setTimeout(() => resolve(dragEvent ? !dragEvent.defaultPrevented : false), 0);
+			});
+		};
+		window.addEventListener('mousemove', onMouseMove, { once: true, capture: true });
+		window.__k6_browser_drag_started__ = async () => {
+			const v = await started;
+			window.removeEventListener('mousemove', onMouseMove, { capture: true });
+			window.removeEventListener('dragstart', onDragStart, { capture: true });
+			delete window.__k6_browser_drag_started__;
+			return v;
+		};
+	}`
+
+	rt := k6ext.Runtime(m.ctx)
+	frames := m.frame.page.frameManager.Frames()
+	opts := evalOptions{forceCallable: true, returnByValue: true}
+	for _, f := range frames {
+		// frames without an execution context can't start a drag.
+		_, _ = f.(*Frame).evaluate(m.ctx, utilityWorld, opts, rt.ToValue(trackDrag))
+	}
+
+	ctx, cancel := context.WithCancel(m.ctx)
+	defer cancel()
+	ch := make(chan Event, 1)
+	m.session.on(ctx, []string{cdproto.EventInputDragIntercepted}, ch)
+
+	if err := input.SetInterceptDrags(true).Do(cdp.WithExecutor(m.ctx, m.session)); err != nil {
+		return fmt.Errorf("intercepting drags: %w", err)
+	}
+	err := m.dispatchMove(x, y)
+	if err == nil {
+		err = m.waitForInterceptedDrag(frames, ch)
+	}
+	if serr := input.SetInterceptDrags(false).Do(cdp.WithExecutor(m.ctx, m.session)); err == nil && serr != nil {
+		err = fmt.Errorf("stopping intercepting drags: %w", serr)
+	}
+	if err != nil {
+		return err
+	}
+	if m.dragData != nil {
+		return m.dispatchDragEvent(input.DragEnter, x, y)
+	}
+
+	return nil
+}
+
+// waitForInterceptedDrag waits for the intercepted drag, if the move started
+// one, and keeps its data.
+func (m *Mouse) waitForInterceptedDrag(frames []api.Frame, ch chan Event) error {
+	const dragStarted = `() => window.__k6_browser_drag_started__ ? window.__k6_browser_drag_started__() : false`
+
+	var started bool
+	rt := k6ext.Runtime(m.ctx)
+	opts := evalOptions{forceCallable: true, returnByValue: true}
+	for _, f := range frames {
+		v, err := f.(*Frame).evaluate(m.ctx, utilityWorld, opts, rt.ToValue(dragStarted))
+		if gv, ok := v.(goja.Value); ok && err == nil && gv.ToBoolean() {
+			started = true
+		}
+	}
+	if !started {
+		return nil
+	}
+
+	timeout := m.timeoutSettings.timeout()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case ev := <-ch:
+		if di, ok := ev.data.(*input.EventDragIntercepted); ok {
+			m.dragData = di.Data
+		}
+		return nil
+	case <-timer.C:
+		return fmt.Errorf("waiting for the intercepted drag: %w after %s", ErrTimedOut, timeout)
+	case <-m.ctx.Done():
+		return m.ctx.Err()
+	}
+}
+
+func (m *Mouse) wheel(deltaX float64, deltaY float64) error {
+	action := input.DispatchMouseEvent(input.MouseWheel, m.x, m.y).
+		WithModifiers(input.Modifier(m.keyboard.modifiers)).
+		WithDeltaX(deltaX).
+		WithDeltaY(deltaY)
+	return action.Do(cdp.WithExecutor(m.ctx, m.session))
+}
+
 func (m *Mouse) up(x float64, y float64, opts *MouseDownUpOptions) error {
-	var button input.MouseButton = input.Left
-	var clickCount int64 = 1
+	m.dragPending = false
+	if m.dragData != nil {
+		err := m.dispatchDragEvent(input.Drop, m.x, m.y)
+		m.dragData = nil
+		m.button = input.None
+		return err
+	}
 	m.button = input.None
 	action := input.DispatchMouseEvent(input.MouseReleased, m.x, m.y).
-		WithButton(button).
+		WithButton(input.MouseButton(opts.Button)).
 		WithModifiers(input.Modifier(m.keyboard.modifiers)).
-		WithClickCount(clickCount)
+		WithClickCount(opts.ClickCount)
 	if err := action.Do(cdp.WithExecutor(m.ctx, m.session)); err != nil {
 		return err
 	}
@@ -183,11 +307,11 @@ func (m *Mouse) Down(x float64, y float64, opts goja.Value) {
 
 // Move will trigger a MouseMoved event in the browser.
 func (m *Mouse) Move(x float64, y float64, opts goja.Value) {
-	mouseOpts := NewMouseDownUpOptions()
+	mouseOpts := NewMouseMoveOptions()
 	if err := mouseOpts.Parse(m.ctx, opts); err != nil {
 		k6ext.Panic(m.ctx, "parsing mouse move options: %w", err)
 	}
-	if err := m.down(x, y, mouseOpts); err != nil {
+	if err := m.move(x, y, mouseOpts); err != nil {
 		k6ext.Panic(m.ctx, "moving the mouse pointer to x:%f y:%f: %w", x, y, err)
 	}
 }
@@ -203,28 +327,10 @@ func (m *Mouse) Up(x float64, y float64, opts goja.Value) {
 	}
 }
 
-// Wheel will trigger a MouseWheel event in the browser
-/*func (m *Mouse) Wheel(opts goja.Value) {
-	var deltaX float64 = 0.0
-	var deltaY float64 = 0.0
-
-	if opts != nil && !goja.IsUndefined(opts) && !goja.IsNull(opts) {
-		opts := opts.ToObject(rt)
-		for _, k := range opts.Keys() {
-			switch k {
-			case "deltaX":
-				deltaX = opts.Get(k).ToFloat()
-			case "deltaY":
-				deltaY = opts.Get(k).ToFloat()
-			}
-		}
+// Wheel will trigger a MouseWheel event in the browser at the current mouse
+// position. The deltas are in CSS pixels.
+func (m *Mouse) Wheel(deltaX float64, deltaY float64) {
+	if err := m.wheel(deltaX, deltaY); err != nil {
+		k6ext.Panic(m.ctx, "scrolling the mouse wheel by x:%f y:%f: %w", deltaX, deltaY, err)
 	}
-
-	action := input.DispatchMouseEvent(input.MouseWheel, m.x, m.y).
-		WithModifiers(input.Modifier(m.keyboard.modifiers)).
-		WithDeltaX(deltaX).
-		WithDeltaY(deltaY)
-	if err := action.Do(cdp.WithExecutor(m.ctx, m.session)); err != nil {
-		k6Throw(m.ctx, "mouse down: %w", err)
-	}
-}*/
+}
@@ -104,9 +104,11 @@ func (o *MouseDblClickOptions) Parse(ctx context.Context, opts goja.Value) error
 	return nil
 }
 
-func (o *MouseDblClickOptions) ToMouseDownUpOptions() *MouseDownUpOptions {
-	o2 := NewMouseDownUpOptions()
+func (o *MouseDblClickOptions) ToMouseClickOptions() *MouseClickOptions {
+	o2 := NewMouseClickOptions()
 	o2.Button = o.Button
+	o2.ClickCount = 2
+	o2.Delay = o.Delay
 	return o2
 }
 
@@ -0,0 +1,97 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/grafana/xk6-browser/k6ext"
+
+	k6metrics "go.k6.io/k6/metrics"
+)
+
+// navigationTimingFn returns the navigation timing of the current document
+// as JSON, or an empty string if it's not available.
+const navigationTimingFn = `() => {
+	const [entry] = performance.getEntriesByType('navigation');
+	return entry ? JSON.stringify(entry) : '';
+}`
+
+// navigationTiming is the subset of the PerformanceNavigationTiming entry
+// the navigation timing metrics are calculated from. All the values are in
+// milliseconds relative to the start of the navigation.
+type navigationTiming struct {
+	Name                     string  `json:"name"`
+	DomainLookupStart        float64 `json:"domainLookupStart"`
+	DomainLookupEnd          float64 `json:"domainLookupEnd"`
+	ConnectStart             float64 `json:"connectStart"`
+	ConnectEnd               float64 `json:"connectEnd"`
+	ResponseStart            float64 `json:"responseStart"`
+	DOMContentLoadedEventEnd float64 `json:"domContentLoadedEventEnd"`
+	LoadEventEnd             float64 `json:"loadEventEnd"`
+}
+
+// metrics returns the navigation timing metric values by their metric.
+// The events that haven't happened yet are left out.
+func (n *navigationTiming) metrics(k6m *k6ext.CustomMetrics) map[*k6metrics.Metric]float64 {
+	values := map[*k6metrics.Metric]float64{
+		k6m.BrowserNavigationDNS: n.DomainLookupEnd - n.DomainLookupStart,
+		k6m.BrowserNavigationTCP: n.ConnectEnd - n.ConnectStart,
+	}
+	if n.ResponseStart > 0 {
+		values[k6m.BrowserNavigationTTFB] = n.ResponseStart
+	}
+	if n.DOMContentLoadedEventEnd > 0 {
+		values[k6m.BrowserNavigationDOMContentLoaded] = n.DOMContentLoadedEventEnd
+	}
+	if n.LoadEventEnd > 0 {
+		values[k6m.BrowserNavigationLoad] = n.LoadEventEnd
+	}
+	return values
+}
+
+// emitNavigationTiming emits the navigation timing of the main frame
+// document as k6 metrics, tagged with its URL and the response status.
+func (p *Page) emitNavigationTiming(resp *Response) error {
+	rt := p.vu.Runtime()
+	opts := evalOptions{forceCallable: true, returnByValue: true}
+	v, err := p.frameManager.MainFrame().evaluate(p.ctx, utilityWorld, opts, rt.ToValue(navigationTimingFn))
+	if err != nil {
+		return fmt.Errorf("getting navigation timing: %w", err)
+	}
+	s := gojaValueToString(p.ctx, v)
+	if s == "" {
+		return nil
+	}
+	var timing navigationTiming
+	if err := json.Unmarshal([]byte(s), &timing); err != nil {
+		return fmt.Errorf("parsing navigation timing: %w", err)
+	}
+
+	values := timing.metrics(k6ext.GetCustomMetrics(p.ctx))
+
+	state := p.vu.State()
+	tags := state.CloneTags()
+	if state.Options.SystemTags.Has(k6metrics.TagURL) {
+		tags["url"] = timing.Name
+	}
+	if resp != nil && state.Options.SystemTags.Has(k6metrics.TagStatus) {
+		tags["status"] = strconv.FormatInt(resp.Status(), 10)
+	}
+	sampleTags := k6metrics.IntoSampleTags(&tags)
+
+	now := time.Now()
+	samples := make([]k6metrics.Sample, 0, len(values))
+	for m, value := range values {
+		samples = append(samples, k6metrics.Sample{
+			Metric: m,
+			Tags:   sampleTags,
+			Value:  value,
+			Time:   now,
+		})
+	}
+	k6metrics.PushIfNotDone(p.ctx, state.Samples, k6metrics.ConnectedSamples{Samples: samples})
+
+	return nil
+}
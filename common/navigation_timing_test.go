@@ -0,0 +1,32 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/grafana/xk6-browser/k6ext"
+
+	k6metrics "go.k6.io/k6/metrics"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNavigationTimingMetrics(t *testing.T) {
+	t.Parallel()
+
+	k6m := k6ext.RegisterCustomMetrics(k6metrics.NewRegistry())
+
+	timing := navigationTiming{
+		DomainLookupStart:        1,
+		DomainLookupEnd:          3,
+		ConnectStart:             3,
+		ConnectEnd:               7,
+		ResponseStart:            20,
+		DOMContentLoadedEventEnd: 50,
+	}
+	assert.Equal(t, map[*k6metrics.Metric]float64{
+		k6m.BrowserNavigationDNS:              2,
+		k6m.BrowserNavigationTCP:              4,
+		k6m.BrowserNavigationTTFB:             20,
+		k6m.BrowserNavigationDOMContentLoaded: 50,
+	}, timing.metrics(k6m), "should leave out the load event that hasn't happened yet")
+}
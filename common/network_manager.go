@@ -72,6 +72,7 @@ type NetworkManager struct {
 
 	extraHTTPHeaders               map[string]string
 	offline                        bool
+	networkConditions              NetworkConditions
 	userCacheDisabled              bool
 	userReqInterceptionEnabled     bool
 	protocolReqInterceptionEnabled bool
@@ -94,15 +95,16 @@ func NewNetworkManager(
 		ctx:              ctx,
 		// TODO: Pass an internal logger instead of basing it on k6's logger?
 		// See https://github.com/grafana/xk6-browser/issues/54
-		logger:           log.New(state.Logger, false, nil),
-		session:          s,
-		parent:           parent,
-		frameManager:     fm,
-		resolver:         resolver,
-		vu:               vu,
-		reqIDToRequest:   make(map[network.RequestID]*Request),
-		attemptedAuth:    make(map[fetch.RequestID]bool),
-		extraHTTPHeaders: make(map[string]string),
+		logger:            log.New(state.Logger, false, nil),
+		session:           s,
+		parent:            parent,
+		frameManager:      fm,
+		resolver:          resolver,
+		vu:                vu,
+		reqIDToRequest:    make(map[network.RequestID]*Request),
+		attemptedAuth:     make(map[fetch.RequestID]bool),
+		extraHTTPHeaders:  make(map[string]string),
+		networkConditions: *NewNetworkConditions(),
 	}
 	m.initEvents()
 	if err := m.initDomains(); err != nil {
@@ -307,8 +309,10 @@ func (m *NetworkManager) handleRequestRedirect(req *Request, redirectResponse *n
 	resp := NewHTTPResponse(m.ctx, req, redirectResponse, timestamp)
 	req.response = resp
 	req.redirectChain = append(req.redirectChain, req)
+	req.finish()
 
 	m.emitResponseMetrics(resp, req)
+	m.recordHAR(req, resp.timestamp)
 	m.deleteRequestByID(req.requestID)
 
 	/*
@@ -393,6 +397,8 @@ func (m *NetworkManager) onLoadingFailed(event *network.EventLoadingFailed) {
 	}
 	req.setErrorText(event.ErrorText)
 	req.responseEndTiming = float64(event.Timestamp.Time().Unix()-req.timestamp.Unix()) * 1000
+	req.finish()
+	m.recordHAR(req, event.Timestamp.Time())
 	m.deleteRequestByID(event.RequestID)
 	m.frameManager.requestFailed(req, event.Canceled)
 }
@@ -419,14 +425,25 @@ func (m *NetworkManager) onLoadingFinished(event *network.EventLoadingFinished)
 		}
 	}
 	req.responseEndTiming = float64(event.Timestamp.Time().Unix()-req.timestamp.Unix()) * 1000
+	// the body is complete, so the metrics below can read it.
+	req.finish()
 	// Skip data and blob URLs when emitting metrics, since they're internal to the browser.
 	if !isInternalURL(req.url) {
 		m.emitResponseMetrics(req.response, req)
 	}
+	m.recordHAR(req, event.Timestamp.Time())
 	m.deleteRequestByID(event.RequestID)
 	m.frameManager.requestFinished(req)
 }
 
+// recordHAR records the request in the HAR file of the browser context.
+func (m *NetworkManager) recordHAR(req *Request, end time.Time) {
+	if m.frameManager == nil || m.frameManager.page == nil {
+		return
+	}
+	m.frameManager.page.browserCtx.recordHAR(req, end)
+}
+
 func isInternalURL(u *url.URL) bool {
 	return u.Scheme == "data" || u.Scheme == "blob"
 }
@@ -466,6 +483,9 @@ func (m *NetworkManager) onRequest(event *network.EventRequestWillBeSent, interc
 		m.logger.Debugf("NetworkManager", "skipping request handling of %s URL", req.url.Scheme)
 		return
 	}
+	if req.redirectedFrom != nil {
+		req.redirectedFrom.setRedirectedTo(req)
+	}
 	m.reqsMu.Lock()
 	m.reqIDToRequest[event.RequestID] = req
 	m.reqsMu.Unlock()
@@ -492,6 +512,8 @@ func (m *NetworkManager) onRequestPaused(event *fetch.EventRequestPaused) {
 					"request %s %s was interrupted: %s", event.Request.Method, event.Request.URL, failErr)
 				return
 			}
+		} else if m.routeRequest(event) {
+			return
 		}
 		action := fetch.ContinueRequest(event.RequestID)
 		if err := action.Do(cdp.WithExecutor(m.ctx, m.session)); err != nil {
@@ -531,6 +553,51 @@ func (m *NetworkManager) onRequestPaused(event *fetch.EventRequestPaused) {
 	failErr = checkBlockedIPs(ip, state.Options.BlacklistIPs)
 }
 
+// routeRequest lets the page's route handlers handle the paused request.
+// It returns true if the page takes over the request, which then stays paused
+// until it's handled by one of them or continued.
+func (m *NetworkManager) routeRequest(event *fetch.EventRequestPaused) bool {
+	if m.frameManager == nil || m.frameManager.page == nil {
+		return false
+	}
+	page := m.frameManager.page
+	if !page.hasRoutes() {
+		return false
+	}
+
+	req := m.requestFromID(network.RequestID(event.NetworkID))
+	if req == nil {
+		var err error
+		if req, err = m.requestFromPausedEvent(event); err != nil {
+			m.logger.Errorf("NetworkManager:routeRequest", "creating request: %s", err)
+			return false
+		}
+	}
+
+	route := NewRoute(m.ctx, m.session, req, event.RequestID, m.logger)
+	page.routeRequest(route)
+
+	return true
+}
+
+// requestFromPausedEvent creates a request from a paused request event for
+// requests that are paused before the request will be sent event is received.
+func (m *NetworkManager) requestFromPausedEvent(event *fetch.EventRequestPaused) (*Request, error) {
+	var frame *Frame
+	if event.FrameID != "" {
+		frame = m.frameManager.getFrameByID(event.FrameID)
+	}
+	ev := &network.EventRequestWillBeSent{
+		RequestID: network.RequestID(event.NetworkID),
+		Request:   event.Request,
+		FrameID:   event.FrameID,
+		Type:      event.ResourceType,
+		Timestamp: &cdp.MonotonicTime{},
+		WallTime:  &cdp.TimeSinceEpoch{},
+	}
+	return NewRequest(m.ctx, ev, frame, nil, string(event.RequestID), true)
+}
+
 func checkBlockedHosts(host string, blockedHosts *k6types.HostnameTrie) error {
 	if blockedHosts == nil {
 		return nil
@@ -614,7 +681,9 @@ func (m *NetworkManager) requestFromID(reqID network.RequestID) *Request {
 }
 
 func (m *NetworkManager) setRequestInterception(value bool) error {
-	m.userReqInterceptionEnabled = value
+	// Keep intercepting requests to be able to respond to authentication
+	// challenges if credentials were set.
+	m.userReqInterceptionEnabled = value || m.credentials != nil
 	return m.updateProtocolRequestInterception()
 }
 
@@ -695,12 +764,31 @@ func (m *NetworkManager) SetOfflineMode(offline bool) {
 	}
 	m.offline = offline
 
-	action := network.EmulateNetworkConditions(m.offline, 0, -1, -1)
-	if err := action.Do(cdp.WithExecutor(m.ctx, m.session)); err != nil {
+	if err := m.emulateNetworkConditions(); err != nil {
 		k6ext.Panic(m.ctx, "setting offline mode: %w", err)
 	}
 }
 
+// SetNetworkConditions emulates the latency and the throughput of the network.
+// The offline mode is left as is and is toggled with SetOfflineMode.
+func (m *NetworkManager) SetNetworkConditions(c NetworkConditions) {
+	c.Offline = false
+	if m.networkConditions == c {
+		return
+	}
+	m.networkConditions = c
+
+	if err := m.emulateNetworkConditions(); err != nil {
+		k6ext.Panic(m.ctx, "setting network conditions: %w", err)
+	}
+}
+
+func (m *NetworkManager) emulateNetworkConditions() error {
+	c := m.networkConditions
+	action := network.EmulateNetworkConditions(m.offline, c.Latency, c.DownloadThroughput, c.UploadThroughput)
+	return action.Do(cdp.WithExecutor(m.ctx, m.session))
+}
+
 // SetUserAgent overrides the browser user agent string.
 func (m *NetworkManager) SetUserAgent(userAgent string) {
 	action := emulation.SetUserAgentOverride(userAgent)
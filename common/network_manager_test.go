@@ -198,3 +198,69 @@ func TestOnRequestPausedBlockedIPs(t *testing.T) {
 		})
 	}
 }
+
+func TestOnRequestPausedRoute(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		newPage func(*routeHandler) *Page
+	}{
+		{
+			name: "page",
+			newPage: func(rh *routeHandler) *Page {
+				return &Page{routes: []*routeHandler{rh}}
+			},
+		},
+		{
+			name: "browser_context",
+			newPage: func(rh *routeHandler) *Page {
+				return &Page{browserCtx: &BrowserContext{routes: []*routeHandler{rh}}}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			nm, session := newTestNetworkManager(t, k6lib.Options{})
+			vu, ok := nm.vu.(*k6test.VU)
+			require.True(t, ok)
+
+			rt := vu.Runtime()
+			handler, err := rt.RunString(`route => route.fulfill({ status: 200, body: "ok" })`)
+			require.NoError(t, err)
+			rh, err := newRouteHandler(rt, rt.ToValue("**/*"), handler)
+			require.NoError(t, err)
+			page := tc.newPage(rh)
+			page.vu = vu
+			page.logger = nm.logger
+			nm.frameManager = &FrameManager{page: page}
+			ev := &fetch.EventRequestPaused{
+				RequestID: "1234",
+				Request: &network.Request{
+					Method: "GET",
+					URL:    "http://host.com/",
+				},
+			}
+
+			// The request is paused on a CDP event goroutine, and the route
+			// handler must be called on the event loop of the VU once it's free.
+			err = vu.RunLoop(func() error {
+				paused := make(chan struct{})
+				go func() {
+					nm.onRequestPaused(ev)
+					close(paused)
+				}()
+				<-paused
+				assert.Empty(t, session.cdpCalls)
+				return nil
+			})
+			require.NoError(t, err)
+
+			assert.Equal(t, []string{"Fetch.fulfillRequest"}, session.cdpCalls)
+		})
+	}
+}
@@ -24,6 +24,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"time"
@@ -38,6 +42,8 @@ import (
 	"github.com/chromedp/cdproto/dom"
 	"github.com/chromedp/cdproto/emulation"
 	cdppage "github.com/chromedp/cdproto/page"
+	"github.com/chromedp/cdproto/performance"
+	cdpruntime "github.com/chromedp/cdproto/runtime"
 	"github.com/chromedp/cdproto/target"
 	"github.com/dop251/goja"
 )
@@ -50,9 +56,11 @@ var _ api.Page = &Page{}
 type Page struct {
 	BaseEventEmitter
 
-	Keyboard    *Keyboard    `js:"keyboard"`    // Public JS API
-	Mouse       *Mouse       `js:"mouse"`       // Public JS API
-	Touchscreen *Touchscreen `js:"touchscreen"` // Public JS API
+	Accessibility *Accessibility `js:"accessibility"` // Public JS API
+	Clock         *Clock         `js:"clock"`         // Public JS API
+	Keyboard      *Keyboard      `js:"keyboard"`      // Public JS API
+	Mouse         *Mouse         `js:"mouse"`         // Public JS API
+	Touchscreen   *Touchscreen   `js:"touchscreen"`   // Public JS API
 
 	ctx context.Context
 
@@ -79,7 +87,12 @@ type Page struct {
 	mediaType        MediaType
 	colorScheme      ColorScheme
 	reducedMotion    ReducedMotion
+	forcedColors     ForcedColors
 	extraHTTPHeaders map[string]string
+	cpuThrottleRate  float64
+
+	// the performance domain is enabled on the first call to Metrics.
+	performanceEnabled bool
 
 	backgroundPage bool
 
@@ -87,9 +100,27 @@ type Page struct {
 	// TODO: FrameSession changes by attachFrameSession (mutex?)
 	frameSessions map[cdp.FrameID]*FrameSession
 	workers       map[target.SessionID]*Worker
-	routes        []api.Route
 	vu            k6modules.VU
 
+	routesMu sync.RWMutex
+	routes   []*routeHandler
+
+	evaluateOnNewDocumentSources []string
+
+	bindingsMu sync.RWMutex
+	bindings   map[string]*pageBinding
+
+	eventHandlersMu sync.RWMutex
+	eventHandlers   map[string][]goja.Callable
+	// eventWaiters are the numbers of the WaitForEvent calls waiting for
+	// the events.
+	eventWaiters map[string]int
+
+	popupsMu sync.Mutex
+	// popups are the open popups the page opened since the last
+	// WaitForPopup call returned.
+	popups []*Page
+
 	logger *log.Logger
 }
 
@@ -116,11 +147,15 @@ func NewPage(
 		reducedMotion:    bctx.opts.ReducedMotion,
 		extraHTTPHeaders: bctx.opts.ExtraHTTPHeaders,
 		timeoutSettings:  NewTimeoutSettings(bctx.timeoutSettings),
+		Accessibility:    NewAccessibility(ctx, s),
 		Keyboard:         NewKeyboard(ctx, s),
 		jsEnabled:        true,
 		frameSessions:    make(map[cdp.FrameID]*FrameSession),
 		workers:          make(map[target.SessionID]*Worker),
-		routes:           make([]api.Route, 0),
+		routes:           make([]*routeHandler, 0),
+		bindings:         make(map[string]*pageBinding),
+		eventHandlers:    make(map[string][]goja.Callable),
+		eventWaiters:     make(map[string]int),
 		vu:               k6ext.GetVU(ctx),
 		logger:           logger,
 	}
@@ -135,7 +170,8 @@ func NewPage(
 	}
 
 	var err error
-	p.frameManager = NewFrameManager(ctx, s, &p, bctx.timeoutSettings, p.logger)
+	p.Clock = NewClock(ctx, &p)
+	p.frameManager = NewFrameManager(ctx, s, &p, p.timeoutSettings, p.logger)
 	p.mainFrameSession, err = NewFrameSession(ctx, s, &p, nil, tid, p.logger)
 	if err != nil {
 		p.logger.Debugf("Page:NewPage:NewFrameSession:return", "sid:%v tid:%v err:%v",
@@ -144,8 +180,8 @@ func NewPage(
 		return nil, err
 	}
 	p.frameSessions[cdp.FrameID(tid)] = p.mainFrameSession
-	p.Mouse = NewMouse(ctx, s, p.frameManager.MainFrame(), bctx.timeoutSettings, p.Keyboard)
-	p.Touchscreen = NewTouchscreen(ctx, s, p.Keyboard)
+	p.Mouse = NewMouse(ctx, s, p.frameManager.MainFrame(), p.timeoutSettings, p.Keyboard)
+	p.Touchscreen = NewTouchscreen(ctx, s, p.Keyboard, bctx.opts.HasTouch)
 
 	action := target.SetAutoAttach(true, true).WithFlatten(true)
 	if err := action.Do(cdp.WithExecutor(p.ctx, p.session)); err != nil {
@@ -165,7 +201,11 @@ func (p *Page) closeWorker(sessionID target.SessionID) {
 }
 
 func (p *Page) defaultTimeout() time.Duration {
-	return time.Duration(p.timeoutSettings.timeout()) * time.Second
+	return p.timeoutSettings.timeout()
+}
+
+func (p *Page) navigationTimeout() time.Duration {
+	return p.timeoutSettings.navigationTimeout()
 }
 
 func (p *Page) didClose() {
@@ -177,6 +217,14 @@ func (p *Page) didClose() {
 	}
 	p.closedMu.Unlock()
 
+	// the closed popups can't be returned by WaitForPopup anymore.
+	p.popupsMu.Lock()
+	p.popups = nil
+	p.popupsMu.Unlock()
+	if p.opener != nil {
+		p.opener.removePopup(p)
+	}
+
 	p.emit(EventPageClose, p)
 }
 
@@ -187,8 +235,15 @@ func (p *Page) didCrash() {
 	p.emit(EventPageCrash, p)
 }
 
-func (p *Page) evaluateOnNewDocument(source string) {
-	// TODO: implement
+// evaluateOnNewDocument adds the source to the scripts that run in the
+// main world of all the frames of the page when a new document is created.
+func (p *Page) evaluateOnNewDocument(source string) error {
+	for _, fs := range p.frameSessions {
+		if err := fs.evaluateOnNewDocument(source); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (p *Page) getFrameElement(f *Frame) (handle *ElementHandle, _ error) {
@@ -201,10 +256,20 @@ func (p *Page) getFrameElement(f *Frame) (handle *ElementHandle, _ error) {
 
 	parent := f.parentFrame
 	if parent == nil {
-		return nil, errors.New("frame has been detached 1")
+		if f.IsDetached() {
+			return nil, errors.New("frame has been detached")
+		}
+		return nil, errors.New("main frame has no frame element")
 	}
 
-	parentSession := p.getFrameSession(cdp.FrameID(parent.ID()))
+	// the frames that run in the process of their parent share its session.
+	var parentSession *FrameSession
+	for root := parent; root != nil && parentSession == nil; root = root.parentFrame {
+		parentSession = p.getFrameSession(cdp.FrameID(root.ID()))
+	}
+	if parentSession == nil {
+		return nil, errors.New("frame has been detached")
+	}
 	action := dom.GetFrameOwner(cdp.FrameID(f.ID()))
 	backendNodeId, _, err := action.Do(cdp.WithExecutor(p.ctx, parentSession.session))
 	if err != nil {
@@ -216,7 +281,7 @@ func (p *Page) getFrameElement(f *Frame) (handle *ElementHandle, _ error) {
 
 	parent = f.parentFrame
 	if parent == nil {
-		return nil, errors.New("frame has been detached 2")
+		return nil, errors.New("frame has been detached")
 	}
 	return parent.adoptBackendNodeID(mainWorld, backendNodeId)
 }
@@ -282,8 +347,191 @@ func (p *Page) getFrameSession(frameID cdp.FrameID) *FrameSession {
 	return p.frameSessions[frameID]
 }
 
+func (p *Page) getBinding(name string) *pageBinding {
+	p.bindingsMu.RLock()
+	defer p.bindingsMu.RUnlock()
+
+	return p.bindings[name]
+}
+
+func (p *Page) getBindings() []*pageBinding {
+	p.bindingsMu.RLock()
+	defer p.bindingsMu.RUnlock()
+
+	bindings := make([]*pageBinding, 0, len(p.bindings))
+	for _, b := range p.bindings {
+		bindings = append(bindings, b)
+	}
+	return bindings
+}
+
+// exposeBinding registers the binding and installs it in all the frames of
+// the page, including the frames created by future navigations.
+func (p *Page) exposeBinding(b *pageBinding) error {
+	p.bindingsMu.Lock()
+	if _, ok := p.bindings[b.name]; ok {
+		p.bindingsMu.Unlock()
+		return fmt.Errorf("function %q has been already registered", b.name)
+	}
+	p.bindings[b.name] = b
+	p.bindingsMu.Unlock()
+
+	for _, fs := range p.frameSessions {
+		if err := fs.addBindingScript(b); err != nil {
+			return err
+		}
+	}
+	source := p.vu.Runtime().ToValue(b.source)
+	for _, f := range p.frameManager.Frames() {
+		frame, ok := f.(*Frame)
+		if !ok {
+			continue
+		}
+		// A frame could be navigating or detached, the script will be
+		// evaluated on its new document anyway.
+		if _, err := frame.evaluate(p.ctx, mainWorld, evalOptions{}, source); err != nil {
+			p.logger.Debugf("Page:exposeBinding", "sid:%v fid:%v name:%q err:%v",
+				p.sessionID(), frame.ID(), b.name, err)
+		}
+	}
+
+	return nil
+}
+
+// onBindingCalled calls the exposed function that the page called.
+func (p *Page) onBindingCalled(execCtx *ExecutionContext, payload *bindingPayload) {
+	p.logger.Debugf("Page:onBindingCalled", "sid:%v name:%q seq:%d",
+		p.sessionID(), payload.Name, payload.Seq)
+
+	b := p.getBinding(payload.Name)
+	if b == nil {
+		return
+	}
+
+	src := &bindingSource{
+		Context: p.browserCtx,
+		Page:    p,
+		Frame:   execCtx.Frame(),
+	}
+	p.queueCallback(func() error {
+		if err := b.call(p.ctx, p.vu.Runtime(), execCtx, payload.Seq, src); err != nil {
+			p.logger.Errorf("Page:onBindingCalled", "sid:%v name:%q err:%v",
+				p.sessionID(), payload.Name, err)
+		}
+		return nil
+	})
+}
+
+// queueCallback queues fn to be called on the event loop of the VU, as
+// only the VU can use the goja runtime. The events are received on the
+// CDP event goroutines, so the script callbacks are called with it. The
+// callbacks queued after the iteration ended are not called.
+func (p *Page) queueCallback(fn func() error) {
+	p.vu.RegisterCallback()(fn)
+}
+
+// hasEventHandlers reports whether the script has handlers for the event,
+// or waits for it.
+func (p *Page) hasEventHandlers(event string) bool {
+	p.eventHandlersMu.RLock()
+	defer p.eventHandlersMu.RUnlock()
+
+	return len(p.eventHandlers[event]) > 0 || p.eventWaiters[event] > 0
+}
+
+func (p *Page) hasEventWaiters(event string) bool {
+	p.eventHandlersMu.RLock()
+	defer p.eventHandlersMu.RUnlock()
+
+	return p.eventWaiters[event] > 0
+}
+
+// callEventHandlers emits the event for the WaitForEvent calls, and queues
+// the calls of the handlers the script registered for the event with the
+// given argument, in their registration order. done, if not nil, is called
+// after the handlers, or right away if there are no handlers.
+func (p *Page) callEventHandlers(event string, arg interface{}, done func()) {
+	p.emit(event, arg)
+
+	p.eventHandlersMu.RLock()
+	handlers := make([]goja.Callable, len(p.eventHandlers[event]))
+	copy(handlers, p.eventHandlers[event])
+	p.eventHandlersMu.RUnlock()
+
+	if len(handlers) == 0 {
+		if done != nil {
+			done()
+		}
+		return
+	}
+
+	p.queueCallback(func() error {
+		rt := p.vu.Runtime()
+		for _, h := range handlers {
+			if _, err := h(goja.Undefined(), rt.ToValue(arg)); err != nil {
+				p.logger.Errorf("Page:callEventHandlers", "sid:%v event:%q err:%v",
+					p.sessionID(), event, err)
+			}
+		}
+		if done != nil {
+			done()
+		}
+		return nil
+	})
+}
+
 func (p *Page) hasRoutes() bool {
-	return len(p.routes) > 0
+	p.routesMu.RLock()
+	n := len(p.routes)
+	p.routesMu.RUnlock()
+
+	return n > 0 || (p.browserCtx != nil && p.browserCtx.hasRoutes())
+}
+
+// routeRequest runs the route through the page's route handlers, and then
+// through the browser context's route handlers if none of them handled it,
+// all in the same way. The native handlers are called right away, and the rest of them, starting
+// with the first one that uses the goja runtime, are called on the event
+// loop of the VU. The request stays paused until one of the handlers handles
+// it, or it's continued after none of them did.
+func (p *Page) routeRequest(r *Route) {
+	p.routesMu.RLock()
+	handlers := reverseRouteHandlers(p.routes)
+	p.routesMu.RUnlock()
+	if p.browserCtx != nil {
+		handlers = append(handlers, p.browserCtx.routeHandlers()...)
+	}
+
+	n := 0
+	for n < len(handlers) && handlers[n].isNative() {
+		n++
+	}
+	handled, err := handleRoute(nil, handlers[:n], r)
+	if handled || err != nil || n == len(handlers) {
+		p.finishRoute(r, handled, err)
+		return
+	}
+	p.queueCallback(func() error {
+		handled, err := handleRoute(p.vu.Runtime(), handlers[n:], r)
+		p.finishRoute(r, handled, err)
+		return nil
+	})
+}
+
+// finishRoute continues the request of the route if none of the route
+// handlers handled it.
+func (p *Page) finishRoute(r *Route, handled bool, err error) {
+	if err != nil {
+		p.logger.Errorf("Page:routeRequest",
+			"sid:%v url:%s err:%v", p.sessionID(), r.request.URL(), err)
+	}
+	if handled {
+		return
+	}
+	if err := r.continueRequest(NewRouteContinueOptions()); err != nil {
+		p.logger.Errorf("Page:routeRequest",
+			"sid:%v url:%s continuing request: %v", p.sessionID(), r.request.URL(), err)
+	}
 }
 
 func (p *Page) resetViewport() error {
@@ -342,6 +590,92 @@ func (p *Page) updateGeolocation() error {
 	return nil
 }
 
+// waitForNetworkEvent waits for a request or a response event for which the
+// urlOrPredicate matches, and returns the event data.
+func (p *Page) waitForNetworkEvent(
+	event string, urlOrPredicate goja.Value, timeout time.Duration,
+) (interface{}, error) {
+	rt := p.vu.Runtime()
+
+	var match func(data interface{}, url string) (bool, error)
+	if fn, ok := goja.AssertFunction(urlOrPredicate); ok {
+		match = func(data interface{}, _ string) (bool, error) {
+			v, err := fn(goja.Undefined(), rt.ToValue(data))
+			if err != nil {
+				return false, fmt.Errorf("calling predicate: %w", err)
+			}
+			return v.ToBoolean(), nil
+		}
+	} else {
+		matchURL, err := newURLMatcher(rt, urlOrPredicate)
+		if err != nil {
+			return nil, err
+		}
+		match = func(_ interface{}, url string) (bool, error) {
+			return matchURL(url)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(p.ctx, timeout)
+	defer cancel()
+
+	ch := make(chan Event)
+	p.on(ctx, []string{event, EventPageClose}, ch)
+
+	var inspected int
+	for {
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return nil, fmt.Errorf("%w after %s, inspected %d %s event(s)", ErrTimedOut, timeout, inspected, event)
+			}
+			return nil, ctx.Err()
+		case ev := <-ch:
+			if ev.typ == EventPageClose {
+				return nil, errors.New("page closed")
+			}
+			inspected++
+
+			var url string
+			switch data := ev.data.(type) {
+			case *Request:
+				url = data.URL()
+			case *Response:
+				url = data.URL()
+			}
+			ok, err := match(ev.data, url)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				return ev.data, nil
+			}
+		}
+	}
+}
+
+func (p *Page) updateRequestInterception() error {
+	p.logger.Debugf("Page:updateRequestInterception", "sid:%v", p.sessionID())
+
+	for _, fs := range p.frameSessions {
+		if err := fs.updateRequestInterception(false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *Page) updateFileChooserInterception() error {
+	p.logger.Debugf("Page:updateFileChooserInterception", "sid:%v", p.sessionID())
+
+	for _, fs := range p.frameSessions {
+		if err := fs.updateFileChooserInterception(false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (p *Page) updateOffline() {
 	p.logger.Debugf("Page:updateOffline", "sid:%v", p.sessionID())
 
@@ -350,6 +684,14 @@ func (p *Page) updateOffline() {
 	}
 }
 
+func (p *Page) updateNetworkConditions() {
+	p.logger.Debugf("Page:updateNetworkConditions", "sid:%v", p.sessionID())
+
+	for _, fs := range p.frameSessions {
+		fs.updateNetworkConditions(false)
+	}
+}
+
 func (p *Page) updateHttpCredentials() {
 	p.logger.Debugf("Page:updateHttpCredentials", "sid:%v", p.sessionID())
 
@@ -358,16 +700,40 @@ func (p *Page) updateHttpCredentials() {
 	}
 }
 
+// viewportSize returns the emulated viewport size, or a zero size if the
+// viewport is not emulated.
 func (p *Page) viewportSize() Size {
+	if p.emulatedSize == nil || p.emulatedSize.Viewport == nil {
+		return Size{}
+	}
 	return Size{
 		Width:  float64(p.emulatedSize.Viewport.Width),
 		Height: float64(p.emulatedSize.Viewport.Height),
 	}
 }
 
-// AddInitScript adds script to run in all new frames.
+// AddInitScript adds a script that runs in every frame of the page when a
+// new document is created, after the browser context's init scripts and
+// before the page's own scripts.
 func (p *Page) AddInitScript(script goja.Value, arg goja.Value) {
-	k6ext.Panic(p.ctx, "Page.addInitScript(script, arg) has not been implemented yet")
+	p.logger.Debugf("Page:AddInitScript", "sid:%v", p.sessionID())
+
+	source, err := initScriptSource(p.vu.Runtime(), script, arg)
+	if err != nil {
+		k6ext.Panic(p.ctx, "adding init script: %w", err)
+	}
+
+	if err := p.addInitScript(source); err != nil {
+		k6ext.Panic(p.ctx, "adding init script: %w", err)
+	}
+}
+
+// addInitScript adds the source to the init scripts of the page, which
+// run in the new documents of the page and its new frame sessions.
+func (p *Page) addInitScript(source string) error {
+	p.evaluateOnNewDocumentSources = append(p.evaluateOnNewDocumentSources, source)
+
+	return p.evaluateOnNewDocument(source)
 }
 
 func (p *Page) AddScriptTag(opts goja.Value) {
@@ -378,7 +744,8 @@ func (p *Page) AddStyleTag(opts goja.Value) {
 	k6ext.Panic(p.ctx, "Page.addStyleTag(opts) has not been implemented yet")
 }
 
-// BringToFront activates the browser tab for this page.
+// BringToFront activates the browser tab for this page, and waits for the
+// page to become visible. The background tabs are throttled by the browser.
 func (p *Page) BringToFront() {
 	p.logger.Debugf("Page:BringToFront", "sid:%v", p.sessionID())
 
@@ -386,6 +753,40 @@ func (p *Page) BringToFront() {
 	if err := action.Do(cdp.WithExecutor(p.ctx, p.session)); err != nil {
 		k6ext.Panic(p.ctx, "bringing page to front: %w", err)
 	}
+
+	ctx, cancel := context.WithTimeout(p.ctx, p.defaultTimeout())
+	defer cancel()
+	if err := p.waitForVisible(ctx); err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			err = &k6ext.UserFriendlyError{Err: err, Timeout: p.defaultTimeout()}
+		}
+		k6ext.Panic(p.ctx, "waiting for page to become visible: %w", err)
+	}
+}
+
+// waitForVisible waits for the visibility state of the page's document to
+// become visible.
+func (p *Page) waitForVisible(ctx context.Context) error {
+	js := `() => new Promise((resolve) => {
+		if (document.visibilityState === 'visible') {
+			return resolve();
+		}
+		const onChange = () => {
+			if (document.visibilityState === 'visible') {
+				document.removeEventListener('visibilitychange', onChange);
+				resolve();
+			}
+		};
+		document.addEventListener('visibilitychange', onChange);
+	})`
+
+	eopts := evalOptions{
+		forceCallable: true,
+		returnByValue: true,
+	}
+	_, err := p.frameManager.MainFrame().evaluate(ctx, utilityWorld, eopts, p.vu.Runtime().ToValue(js))
+
+	return err
 }
 
 // Check checks an element matching the provided selector.
@@ -421,6 +822,17 @@ func (p *Page) Click(selector string, opts goja.Value) *goja.Promise {
 func (p *Page) Close(opts goja.Value) {
 	p.logger.Debugf("Page:Close", "sid:%v", p.sessionID())
 
+	// the page is not hidden before it's closed, so its web vitals need
+	// to be reported explicitly.
+	action := cdpruntime.Evaluate("(" + webVitalsReportFn + ")()")
+	if _, _, err := action.Do(cdp.WithExecutor(p.ctx, p.session)); err != nil {
+		p.logger.Debugf("Page:Close", "sid:%v reporting web vitals: %v", p.sessionID(), err)
+	}
+	if p.cpuThrottleRate > 1 {
+		if err := p.setCPUThrottlingRate(1); err != nil {
+			p.logger.Errorf("Page:Close", "sid:%v resetting CPU throttling rate: %v", p.sessionID(), err)
+		}
+	}
 	p.browserCtx.Close()
 }
 
@@ -449,14 +861,18 @@ func (p *Page) DispatchEvent(selector string, typ string, eventInit goja.Value,
 	p.MainFrame().DispatchEvent(selector, typ, eventInit, opts)
 }
 
+// DragAndDrop drags the first element that matches the source selector and
+// drops it on the first element that matches the target selector.
 func (p *Page) DragAndDrop(source string, target string, opts goja.Value) {
-	k6ext.Panic(p.ctx, "Page.DragAndDrop(source, target, opts) has not been implemented yet")
+	p.logger.Debugf("Page:DragAndDrop", "sid:%v source:%s target:%s", p.sessionID(), source, target)
+
+	p.MainFrame().DragAndDrop(source, target, opts)
 }
 
 func (p *Page) EmulateMedia(opts goja.Value) {
 	p.logger.Debugf("Page:EmulateMedia", "sid:%v", p.sessionID())
 
-	parsedOpts := NewPageEmulateMediaOptions(p.mediaType, p.colorScheme, p.reducedMotion)
+	parsedOpts := NewPageEmulateMediaOptions(p.mediaType, p.colorScheme, p.reducedMotion, p.forcedColors)
 	if err := parsedOpts.Parse(p.ctx, opts); err != nil {
 		k6ext.Panic(p.ctx, "parsing emulateMedia options: %w", err)
 	}
@@ -464,6 +880,7 @@ func (p *Page) EmulateMedia(opts goja.Value) {
 	p.mediaType = parsedOpts.Media
 	p.colorScheme = parsedOpts.ColorScheme
 	p.reducedMotion = parsedOpts.ReducedMotion
+	p.forcedColors = parsedOpts.ForcedColors
 
 	for _, fs := range p.frameSessions {
 		if err := fs.updateEmulateMedia(false); err != nil {
@@ -499,6 +916,23 @@ func (p *Page) EmulateVisionDeficiency(typ string) {
 	applySlowMo(p.ctx)
 }
 
+// EvalOnSelector runs the page function with the first element matching the
+// selector in the main frame as its first argument, and returns its result.
+func (p *Page) EvalOnSelector(selector string, pageFunc goja.Value, arg goja.Value) interface{} {
+	p.logger.Debugf("Page:EvalOnSelector", "sid:%v selector:%s", p.sessionID(), selector)
+
+	return p.MainFrame().EvalOnSelector(selector, pageFunc, arg)
+}
+
+// EvalOnSelectorAll runs the page function with an array of all the elements
+// matching the selector in the main frame as its first argument, and returns
+// its result.
+func (p *Page) EvalOnSelectorAll(selector string, pageFunc goja.Value, arg goja.Value) interface{} {
+	p.logger.Debugf("Page:EvalOnSelectorAll", "sid:%v selector:%s", p.sessionID(), selector)
+
+	return p.MainFrame().EvalOnSelectorAll(selector, pageFunc, arg)
+}
+
 // Evaluate runs JS code within the execution context of the main frame of the page.
 func (p *Page) Evaluate(pageFunc goja.Value, args ...goja.Value) interface{} {
 	p.logger.Debugf("Page:Evaluate", "sid:%v", p.sessionID())
@@ -512,12 +946,39 @@ func (p *Page) EvaluateHandle(pageFunc goja.Value, args ...goja.Value) api.JSHan
 	return p.MainFrame().EvaluateHandle(pageFunc, args...)
 }
 
+// ExposeBinding is like ExposeFunction, but the callback receives the source
+// of the call, the browser context, page and frame, as its first argument.
+// With the handle option, the first argument of the call is passed to the
+// callback as a JS handle.
 func (p *Page) ExposeBinding(name string, callback goja.Callable, opts goja.Value) {
-	k6ext.Panic(p.ctx, "Page.exposeBinding(name, callback) has not been implemented yet")
+	p.logger.Debugf("Page:ExposeBinding", "sid:%v name:%q", p.sessionID(), name)
+
+	popts := NewPageExposeBindingOptions()
+	if err := popts.Parse(p.ctx, opts); err != nil {
+		k6ext.Panic(p.ctx, "parsing expose binding options: %w", err)
+	}
+	if callback == nil {
+		k6ext.Panic(p.ctx, "exposing binding %q: callback must be a function", name)
+	}
+	if err := p.exposeBinding(newPageBinding(name, callback, true, popts.Handle)); err != nil {
+		k6ext.Panic(p.ctx, "exposing binding %q: %w", name, err)
+	}
 }
 
+// ExposeFunction adds a function with the given name to the window object
+// of every frame in the page. Calling the function runs the callback, and
+// resolves to the callback's return value. Like the event handlers, the
+// callback is called on the event loop of the VU, so a call that waits for
+// the page, such as Evaluate, can't wait for the function's result.
 func (p *Page) ExposeFunction(name string, callback goja.Callable) {
-	k6ext.Panic(p.ctx, "Page.exposeFunction(name, callback) has not been implemented yet")
+	p.logger.Debugf("Page:ExposeFunction", "sid:%v name:%q", p.sessionID(), name)
+
+	if callback == nil {
+		k6ext.Panic(p.ctx, "exposing function %q: callback must be a function", name)
+	}
+	if err := p.exposeBinding(newPageBinding(name, callback, false, false)); err != nil {
+		k6ext.Panic(p.ctx, "exposing function %q: %w", name, err)
+	}
 }
 
 func (p *Page) Fill(selector string, value string, opts goja.Value) {
@@ -542,6 +1003,14 @@ func (p *Page) Frames() []api.Frame {
 	return p.frameManager.Frames()
 }
 
+// FrameLocator returns a frame locator for the iframes matching the selector
+// in the main frame.
+func (p *Page) FrameLocator(selector string) api.FrameLocator {
+	p.logger.Debugf("Page:FrameLocator", "sid:%v sel:%q", p.sessionID(), selector)
+
+	return p.MainFrame().FrameLocator(selector)
+}
+
 func (p *Page) GetAttribute(selector string, name string, opts goja.Value) goja.Value {
 	p.logger.Debugf("Page:GetAttribute", "sid:%v selector:%s name:%s",
 		p.sessionID(), selector, name)
@@ -549,21 +1018,146 @@ func (p *Page) GetAttribute(selector string, name string, opts goja.Value) goja.
 	return p.MainFrame().GetAttribute(selector, name, opts)
 }
 
+// GetByLabel returns a locator for the elements with a matching label.
+func (p *Page) GetByLabel(text goja.Value, opts goja.Value) api.Locator {
+	p.logger.Debugf("Page:GetByLabel", "sid:%v text:%q", p.sessionID(), text)
+
+	return p.MainFrame().GetByLabel(text, opts)
+}
+
+// GetByPlaceholder returns a locator for the input elements with a
+// matching placeholder.
+func (p *Page) GetByPlaceholder(text goja.Value, opts goja.Value) api.Locator {
+	p.logger.Debugf("Page:GetByPlaceholder", "sid:%v text:%q", p.sessionID(), text)
+
+	return p.MainFrame().GetByPlaceholder(text, opts)
+}
+
+// GetByRole returns a locator for the elements with the given ARIA role.
+func (p *Page) GetByRole(role string, opts goja.Value) api.Locator {
+	p.logger.Debugf("Page:GetByRole", "sid:%v role:%q", p.sessionID(), role)
+
+	return p.MainFrame().GetByRole(role, opts)
+}
+
+// GetByTestId returns a locator for the elements with the test ID.
+func (p *Page) GetByTestId(testID goja.Value) api.Locator { //nolint:revive,stylecheck
+	p.logger.Debugf("Page:GetByTestId", "sid:%v testID:%q", p.sessionID(), testID)
+
+	return p.MainFrame().GetByTestId(testID)
+}
+
+// GetByText returns a locator for the elements with a matching text.
+func (p *Page) GetByText(text goja.Value, opts goja.Value) api.Locator {
+	p.logger.Debugf("Page:GetByText", "sid:%v text:%q", p.sessionID(), text)
+
+	return p.MainFrame().GetByText(text, opts)
+}
+
+// GoBack navigates to the previous page in the history, and returns the
+// main resource response. It returns null if there is no previous page, or
+// if the navigation happens within the same document.
 func (p *Page) GoBack(opts goja.Value) api.Response {
-	k6ext.Panic(p.ctx, "Page.goBack(opts) has not been implemented yet")
-	return nil
+	p.logger.Debugf("Page:GoBack", "sid:%v", p.sessionID())
+
+	return p.navigateHistory(-1, opts)
 }
 
+// GoForward navigates to the next page in the history, and returns the
+// main resource response. It returns null if there is no next page, or if
+// the navigation happens within the same document.
 func (p *Page) GoForward(opts goja.Value) api.Response {
-	k6ext.Panic(p.ctx, "Page.goForward(opts) has not been implemented yet")
-	return nil
+	p.logger.Debugf("Page:GoForward", "sid:%v", p.sessionID())
+
+	return p.navigateHistory(+1, opts)
+}
+
+// navigateHistory navigates to the history entry that is delta entries away
+// from the current one.
+func (p *Page) navigateHistory(delta int, opts goja.Value) api.Response {
+	parsedOpts := NewPageNavigationOptions(LifecycleEventLoad, p.navigationTimeout())
+	if err := parsedOpts.Parse(p.ctx, opts); err != nil {
+		k6ext.Panic(p.ctx, "parsing history navigation options: %w", err)
+	}
+
+	current, entries, err := cdppage.GetNavigationHistory().Do(cdp.WithExecutor(p.ctx, p.session))
+	if err != nil {
+		k6ext.Panic(p.ctx, "getting navigation history: %w", err)
+	}
+	i := int(current) + delta
+	if i < 0 || i >= len(entries) {
+		return nil
+	}
+
+	timeoutCtx, timeoutCancelFn := context.WithTimeout(p.ctx, parsedOpts.Timeout)
+	defer timeoutCancelFn()
+
+	mainFrame := p.frameManager.MainFrame()
+	ch, evCancelFn := createWaitForEventHandler(timeoutCtx, mainFrame, []string{EventFrameNavigation}, func(data interface{}) bool {
+		return true // Both successful and failed navigations are considered
+	})
+	defer evCancelFn() // Remove event handler
+
+	chWaitUntil, evCancelFn2 := createWaitForEventHandler(timeoutCtx, mainFrame, []string{EventFrameAddLifecycle}, func(data interface{}) bool {
+		return data.(LifecycleEvent) == parsedOpts.WaitUntil
+	})
+	defer evCancelFn2() // Remove event handler
+
+	action := cdppage.NavigateToHistoryEntry(entries[i].ID)
+	if err := action.Do(cdp.WithExecutor(p.ctx, p.session)); err != nil {
+		k6ext.Panic(p.ctx, "navigating to history entry %q: %w", entries[i].URL, err)
+	}
+
+	var event *NavigationEvent
+	select {
+	case <-timeoutCtx.Done():
+		if errors.Is(timeoutCtx.Err(), context.DeadlineExceeded) {
+			k6ext.Panic(p.ctx, "navigating to history entry %q: %w", entries[i].URL, ErrTimedOut)
+		}
+		return nil
+	case data := <-ch:
+		event = data.(*NavigationEvent)
+	}
+	if event.err != nil {
+		k6ext.Panic(p.ctx, "navigating to history entry %q: %w", entries[i].URL, event.err)
+	}
+	applySlowMo(p.ctx)
+
+	// The same document navigations, such as the ones from the History API,
+	// have no response.
+	if event.newDocument == nil {
+		return nil
+	}
+	if !mainFrame.hasSubtreeLifecycleEventFired(parsedOpts.WaitUntil) {
+		select {
+		case <-timeoutCtx.Done():
+			if errors.Is(timeoutCtx.Err(), context.DeadlineExceeded) {
+				k6ext.Panic(p.ctx, "navigating to history entry %q: %w", entries[i].URL, ErrTimedOut)
+			}
+		case <-chWaitUntil:
+		}
+	}
+
+	req := event.newDocument.request
+	if req == nil || req.response == nil {
+		return nil
+	}
+	return req.response
 }
 
 // Goto will navigate the page to the specified URL and return a HTTP response object.
 func (p *Page) Goto(url string, opts goja.Value) api.Response {
 	p.logger.Debugf("Page:Goto", "sid:%v url:%q", p.sessionID(), url)
 
-	return p.MainFrame().Goto(url, opts)
+	resp := p.MainFrame().Goto(url, opts)
+	if p.browserCtx.opts.NavigationTiming {
+		r, _ := resp.(*Response)
+		if err := p.emitNavigationTiming(r); err != nil {
+			k6ext.Panic(p.ctx, "emitting navigation timing metrics: %w", err)
+		}
+	}
+
+	return resp
 }
 
 func (p *Page) Hover(selector string, opts goja.Value) {
@@ -572,6 +1166,11 @@ func (p *Page) Hover(selector string, opts goja.Value) {
 	p.MainFrame().Hover(selector, opts)
 }
 
+// IndexedDB returns the IndexedDB databases of the page's origin.
+func (p *Page) IndexedDB() api.IndexedDB {
+	return NewIndexedDB(p.ctx, p)
+}
+
 func (p *Page) InnerHTML(selector string, opts goja.Value) string {
 	p.logger.Debugf("Page:InnerHTML", "sid:%v selector:%s", p.sessionID(), selector)
 
@@ -634,6 +1233,44 @@ func (p *Page) Locator(selector string, opts goja.Value) api.Locator {
 	return p.MainFrame().Locator(selector, opts)
 }
 
+// LocalStorage returns the local storage of the page's origin.
+func (p *Page) LocalStorage() api.WebStorage {
+	return NewWebStorage(p.ctx, p, localStorage)
+}
+
+// Metrics returns the run-time metrics of the page by their name, as reported
+// by the Chromium performance counters, e.g.:
+//   - Documents, Frames, Nodes and JSEventListeners: the number of live objects.
+//   - JSHeapUsedSize and JSHeapTotalSize: the JavaScript heap sizes in bytes.
+//   - LayoutCount and RecalcStyleCount: the number of layouts and style
+//     recalculations.
+//   - LayoutDuration, RecalcStyleDuration, ScriptDuration and TaskDuration:
+//     the time spent on them in seconds.
+//
+// The metrics are specific to Chromium, and their set might change between
+// browser versions.
+func (p *Page) Metrics() map[string]float64 {
+	p.logger.Debugf("Page:Metrics", "sid:%v", p.sessionID())
+
+	if !p.performanceEnabled {
+		if err := performance.Enable().Do(cdp.WithExecutor(p.ctx, p.session)); err != nil {
+			k6ext.Panic(p.ctx, "enabling performance metrics: %w", err)
+		}
+		p.performanceEnabled = true
+	}
+
+	metrics, err := performance.GetMetrics().Do(cdp.WithExecutor(p.ctx, p.session))
+	if err != nil {
+		k6ext.Panic(p.ctx, "getting performance metrics: %w", err)
+	}
+	m := make(map[string]float64, len(metrics))
+	for _, metric := range metrics {
+		m[metric.Name] = metric.Value
+	}
+
+	return m
+}
+
 // MainFrame returns the main frame on the page.
 func (p *Page) MainFrame() api.Frame {
 	mf := p.frameManager.MainFrame()
@@ -649,6 +1286,46 @@ func (p *Page) MainFrame() api.Frame {
 	return mf
 }
 
+// On registers a handler to be called for every page event of the given
+// type. The supported events are:
+//   - console: called with a ConsoleMessage for every console API call.
+//   - dialog: called with a Dialog for every JavaScript dialog. The dialogs
+//     the handlers don't accept or dismiss are dismissed, except for the
+//     beforeunload dialogs, which are accepted.
+//   - download: called with a Download for every download started by the
+//     page. Downloads are saved only if the browser context accepts them.
+//   - filechooser: called with a FileChooser for every file chooser opened
+//     by the page. The file chooser dialogs are not shown while there are
+//     handlers, and the handlers should set the files.
+//   - pageerror: called with a PageError for every uncaught exception.
+//
+// The handlers are called on the event loop of the VU, once the script
+// is not busy with another call. A dialog keeps the page blocked until
+// its handlers are called, so the dialogs should not be opened by a call
+// that waits for the page, such as Evaluate.
+func (p *Page) On(event string, handler goja.Callable) {
+	p.logger.Debugf("Page:On", "sid:%v event:%q", p.sessionID(), event)
+
+	events := []string{EventPageConsole, EventPageDialog, EventPageDownload, EventPageFilechooser, EventPageError}
+	if !stringSliceContains(events, event) {
+		k6ext.Panic(p.ctx, "unknown page event: %q, must be one of: %s", event, strings.Join(events, ", "))
+	}
+	if handler == nil {
+		k6ext.Panic(p.ctx, "registering %q page event handler: handler must be a function", event)
+	}
+
+	p.eventHandlersMu.Lock()
+	p.eventHandlers[event] = append(p.eventHandlers[event], handler)
+	first := len(p.eventHandlers[event]) == 1
+	p.eventHandlersMu.Unlock()
+
+	if event == EventPageFilechooser && first {
+		if err := p.updateFileChooserInterception(); err != nil {
+			k6ext.Panic(p.ctx, "intercepting file chooser: %w", err)
+		}
+	}
+}
+
 // Opener returns the opener of the target.
 func (p *Page) Opener() api.Page {
 	return p.opener
@@ -658,10 +1335,54 @@ func (p *Page) Pause() {
 	k6ext.Panic(p.ctx, "Page.pause() has not been implemented yet")
 }
 
+// Pdf generates a PDF of the page and returns its contents. It optionally
+// saves the PDF to the path option. It only works in headless mode.
 func (p *Page) Pdf(opts goja.Value) goja.ArrayBuffer {
-	rt := p.vu.Runtime()
-	k6ext.Panic(p.ctx, "Page.pdf(opts) has not been implemented yet")
-	return rt.NewArrayBuffer([]byte{})
+	p.logger.Debugf("Page:Pdf", "sid:%v", p.sessionID())
+
+	popts := NewPagePDFOptions()
+	if err := popts.Parse(p.ctx, opts); err != nil {
+		k6ext.Panic(p.ctx, "parsing pdf options: %w", err)
+	}
+	if !p.browserCtx.browser.launchOpts.Headless {
+		k6ext.Panic(p.ctx, "generating pdf: only supported in headless mode")
+	}
+	buf, err := p.pdf(popts)
+	if err != nil {
+		k6ext.Panic(p.ctx, "%w", err)
+	}
+
+	return p.vu.Runtime().NewArrayBuffer(buf)
+}
+
+func (p *Page) pdf(opts *PagePDFOptions) ([]byte, error) {
+	action := cdppage.PrintToPDF().
+		WithPaperWidth(opts.Width).
+		WithPaperHeight(opts.Height).
+		WithLandscape(opts.Landscape).
+		WithMarginTop(opts.Margin.Top).
+		WithMarginRight(opts.Margin.Right).
+		WithMarginBottom(opts.Margin.Bottom).
+		WithMarginLeft(opts.Margin.Left).
+		WithPrintBackground(opts.PrintBackground).
+		WithScale(opts.Scale).
+		WithPageRanges(opts.PageRanges)
+	buf, _, err := action.Do(cdp.WithExecutor(p.ctx, p.session))
+	if err != nil {
+		return nil, fmt.Errorf("printing to pdf: %w", err)
+	}
+	if opts.Path == "" {
+		return buf, nil
+	}
+	dir := filepath.Dir(opts.Path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating pdf directory %q: %w", dir, err)
+	}
+	if err := ioutil.WriteFile(opts.Path, buf, 0o644); err != nil {
+		return nil, fmt.Errorf("saving pdf to %q: %w", opts.Path, err)
+	}
+
+	return buf, nil
 }
 
 func (p *Page) Press(selector string, key string, opts goja.Value) {
@@ -686,49 +1407,120 @@ func (p *Page) QueryAll(selector string) []api.ElementHandle {
 func (p *Page) Reload(opts goja.Value) api.Response {
 	p.logger.Debugf("Page:Reload", "sid:%v", p.sessionID())
 
-	parsedOpts := NewPageReloadOptions(LifecycleEventLoad, p.defaultTimeout())
+	parsedOpts := NewPageReloadOptions(LifecycleEventLoad, p.navigationTimeout())
 	if err := parsedOpts.Parse(p.ctx, opts); err != nil {
 		k6ext.Panic(p.ctx, "parsing reload options: %w", err)
 	}
 
-	ch, evCancelFn := createWaitForEventHandler(p.ctx, p.frameManager.MainFrame(), []string{EventFrameNavigation}, func(data interface{}) bool {
+	timeoutCtx, timeoutCancelFn := context.WithTimeout(p.ctx, parsedOpts.Timeout)
+	defer timeoutCancelFn()
+
+	mainFrame := p.frameManager.MainFrame()
+	ch, evCancelFn := createWaitForEventHandler(timeoutCtx, mainFrame, []string{EventFrameNavigation}, func(data interface{}) bool {
 		return true // Both successful and failed navigations are considered
 	})
 	defer evCancelFn() // Remove event handler
 
-	action := cdppage.Reload()
+	chWaitUntil, evCancelFn2 := createWaitForEventHandler(timeoutCtx, mainFrame, []string{EventFrameAddLifecycle}, func(data interface{}) bool {
+		return data.(LifecycleEvent) == parsedOpts.WaitUntil
+	})
+	defer evCancelFn2() // Remove event handler
+
+	action := cdppage.Reload().WithIgnoreCache(parsedOpts.IgnoreCache)
 	if err := action.Do(cdp.WithExecutor(p.ctx, p.session)); err != nil {
 		k6ext.Panic(p.ctx, "reloading page: %w", err)
 	}
 
 	var event *NavigationEvent
 	select {
-	case <-p.ctx.Done():
-	case <-time.After(parsedOpts.Timeout):
-		k6ext.Panic(p.ctx, "%w", ErrTimedOut)
+	case <-timeoutCtx.Done():
+		if errors.Is(timeoutCtx.Err(), context.DeadlineExceeded) {
+			k6ext.Panic(p.ctx, "reloading page: %w", ErrTimedOut)
+		}
+		return nil
 	case data := <-ch:
 		event = data.(*NavigationEvent)
 	}
-
-	if p.frameManager.mainFrame.hasSubtreeLifecycleEventFired(parsedOpts.WaitUntil) {
-		_, _ = waitForEvent(p.ctx, p.frameManager.MainFrame(), []string{EventFrameAddLifecycle}, func(data interface{}) bool {
-			return data.(LifecycleEvent) == parsedOpts.WaitUntil
-		}, parsedOpts.Timeout)
+	if event.err != nil {
+		k6ext.Panic(p.ctx, "reloading page: %w", event.err)
 	}
 
-	var resp *Response
-	req := event.newDocument.request
-	if req != nil {
-		if req.response != nil {
-			resp = req.response
+	if !mainFrame.hasSubtreeLifecycleEventFired(parsedOpts.WaitUntil) {
+		select {
+		case <-timeoutCtx.Done():
+			if errors.Is(timeoutCtx.Err(), context.DeadlineExceeded) {
+				k6ext.Panic(p.ctx, "reloading page: %w", ErrTimedOut)
+			}
+		case <-chWaitUntil:
 		}
 	}
 	applySlowMo(p.ctx)
-	return resp
+
+	if event.newDocument == nil {
+		return nil
+	}
+	req := event.newDocument.request
+	if req == nil || req.response == nil {
+		return nil
+	}
+	return req.response
 }
 
-func (p *Page) Route(url goja.Value, handler goja.Callable) {
-	k6ext.Panic(p.ctx, "Page.route(url, handler) has not been implemented yet")
+// Route registers a handler for the requests matching the url, which
+// can be a glob pattern, a RegExp or a predicate function. Handlers registered
+// later take precedence over the earlier ones.
+func (p *Page) Route(url goja.Value, handler goja.Value) {
+	p.logger.Debugf("Page:Route", "sid:%v url:%v", p.sessionID(), url)
+
+	rh, err := newRouteHandler(p.vu.Runtime(), url, handler)
+	if err != nil {
+		k6ext.Panic(p.ctx, "adding route: %w", err)
+	}
+
+	p.routesMu.Lock()
+	p.routes = append(p.routes, rh)
+	p.routesMu.Unlock()
+
+	if err := p.updateRequestInterception(); err != nil {
+		k6ext.Panic(p.ctx, "adding route: %w", err)
+	}
+}
+
+// RouteFromHAR serves the requests of the page from the HAR file at path.
+// With the update option, the network activity of the page is recorded to
+// the HAR file instead, when the browser context is closed.
+func (p *Page) RouteFromHAR(path string, opts goja.Value) {
+	p.logger.Debugf("Page:RouteFromHAR", "sid:%v path:%q", p.sessionID(), path)
+
+	popts := NewRouteFromHAROptions()
+	if err := popts.Parse(p.ctx, opts); err != nil {
+		k6ext.Panic(p.ctx, "parsing routeFromHAR options: %w", err)
+	}
+	if popts.Update {
+		matcher, err := newNativeURLMatcher(popts.URL)
+		if err != nil {
+			k6ext.Panic(p.ctx, "routing from HAR: %w", err)
+		}
+		p.browserCtx.addHARRecorder(path, harUpdateFilter(matcher, p))
+		return
+	}
+
+	router, err := newHARRouter(path, popts)
+	if err != nil {
+		k6ext.Panic(p.ctx, "routing from HAR: %w", err)
+	}
+	rh, err := newHARRouteHandler(popts.URL, router)
+	if err != nil {
+		k6ext.Panic(p.ctx, "routing from HAR: %w", err)
+	}
+
+	p.routesMu.Lock()
+	p.routes = append(p.routes, rh)
+	p.routesMu.Unlock()
+
+	if err := p.updateRequestInterception(); err != nil {
+		k6ext.Panic(p.ctx, "routing from HAR: %w", err)
+	}
 }
 
 // Screenshot will instruct Chrome to save a screenshot of the current page and save it to specified file.
@@ -752,6 +1544,19 @@ func (p *Page) SelectOption(selector string, values goja.Value, opts goja.Value)
 	return p.MainFrame().SelectOption(selector, values, opts)
 }
 
+// SessionStorage returns the session storage of the page's origin.
+func (p *Page) SessionStorage() api.WebStorage {
+	return NewWebStorage(p.ctx, p, sessionStorage)
+}
+
+// SetChecked checks or unchecks the first element found that matches the
+// selector, depending on checked.
+func (p *Page) SetChecked(selector string, checked bool, opts goja.Value) {
+	p.logger.Debugf("Page:SetChecked", "sid:%v selector:%s checked:%t", p.sessionID(), selector, checked)
+
+	p.MainFrame().SetChecked(selector, checked, opts)
+}
+
 func (p *Page) SetContent(html string, opts goja.Value) {
 	p.logger.Debugf("Page:SetContent", "sid:%v", p.sessionID())
 
@@ -773,16 +1578,25 @@ func (p *Page) SetDefaultTimeout(timeout int64) {
 }
 
 // SetExtraHTTPHeaders sets default HTTP headers for page and whole frame hierarchy.
+// The headers are merged with the browser context's headers, and they override
+// the context headers with the same name. They are sent with the requests that
+// start after the call, and not with the requests that are already in flight.
 func (p *Page) SetExtraHTTPHeaders(headers map[string]string) {
 	p.logger.Debugf("Page:SetExtraHTTPHeaders", "sid:%v", p.sessionID())
 
-	p.extraHTTPHeaders = headers
+	p.extraHTTPHeaders = make(map[string]string, len(headers))
+	for k, v := range headers {
+		p.extraHTTPHeaders[k] = v
+	}
 	p.updateExtraHTTPHeaders()
 }
 
+// SetInputFiles sets the files of the first file input element that
+// matches the selector.
 func (p *Page) SetInputFiles(selector string, files goja.Value, opts goja.Value) {
-	k6ext.Panic(p.ctx, "Page.textContent(selector, opts) has not been implemented yet")
-	// TODO: needs slowMo
+	p.logger.Debugf("Page:SetInputFiles", "sid:%v selector:%s", p.sessionID(), selector)
+
+	p.MainFrame().SetInputFiles(selector, files, opts)
 }
 
 // SetViewportSize will update the viewport width and height.
@@ -811,6 +1625,29 @@ func (p *Page) TextContent(selector string, opts goja.Value) string {
 	return p.MainFrame().TextContent(selector, opts)
 }
 
+// ThrottleCPU slows down the CPU of the page by the given rate, e.g. a rate
+// of 4 means a 4x slowdown. A rate of 1 disables the throttling.
+func (p *Page) ThrottleCPU(rate float64) {
+	p.logger.Debugf("Page:ThrottleCPU", "sid:%v rate:%.2f", p.sessionID(), rate)
+
+	if math.IsNaN(rate) || rate < 1 {
+		k6ext.Panic(p.ctx, `invalid CPU throttling rate "%.2f": precondition 1 <= RATE failed`, rate)
+	}
+	if err := p.setCPUThrottlingRate(rate); err != nil {
+		k6ext.Panic(p.ctx, "%w", err)
+	}
+}
+
+func (p *Page) setCPUThrottlingRate(rate float64) error {
+	action := emulation.SetCPUThrottlingRate(rate)
+	if err := action.Do(cdp.WithExecutor(p.ctx, p.session)); err != nil {
+		return fmt.Errorf("setting CPU throttling rate to %.2f: %w", rate, err)
+	}
+	p.cpuThrottleRate = rate
+
+	return nil
+}
+
 func (p *Page) Title() string {
 	p.logger.Debugf("Page:Title", "sid:%v", p.sessionID())
 
@@ -824,8 +1661,24 @@ func (p *Page) Type(selector string, text string, opts goja.Value) {
 	p.MainFrame().Type(selector, text, opts)
 }
 
-func (p *Page) Unroute(url goja.Value, handler goja.Callable) {
-	k6ext.Panic(p.ctx, "Page.unroute(url, handler) has not been implemented yet")
+// Unroute removes the route handlers registered with the url. If handler
+// is given, only that handler is removed.
+func (p *Page) Unroute(url goja.Value, handler goja.Value) {
+	p.logger.Debugf("Page:Unroute", "sid:%v url:%v", p.sessionID(), url)
+
+	p.routesMu.Lock()
+	routes := make([]*routeHandler, 0, len(p.routes))
+	for _, rh := range p.routes {
+		if !rh.equals(url, handler) {
+			routes = append(routes, rh)
+		}
+	}
+	p.routes = routes
+	p.routesMu.Unlock()
+
+	if err := p.updateRequestInterception(); err != nil {
+		k6ext.Panic(p.ctx, "removing route: %w", err)
+	}
 }
 
 // URL returns the location of the page.
@@ -841,22 +1694,135 @@ func (p *Page) Video() api.Video {
 }
 
 // ViewportSize will return information on the viewport width and height.
+// The size is read from the emulation state, so it doesn't require the page
+// to be loaded. The default viewport size is returned if the viewport is not
+// emulated.
 func (p *Page) ViewportSize() map[string]float64 {
 	p.logger.Debugf("Page:ViewportSize", "sid:%v", p.sessionID())
 
 	vps := p.viewportSize()
+	if vps.Width == 0 && vps.Height == 0 {
+		vps = Size{Width: float64(DefaultScreenWidth), Height: float64(DefaultScreenHeight)}
+	}
 	return map[string]float64{
 		"width":  vps.Width,
 		"height": vps.Height,
 	}
 }
 
-// WaitForEvent waits for the specified event to trigger.
+// WaitForEvent waits for the next event that the predicate returns true
+// for, and returns the payload of the event. The predicate can be given
+// directly, or with the timeout in the options.
+//
+// The dialogs aren't dismissed by default while they're waited for, so the
+// script should accept or dismiss them.
 func (p *Page) WaitForEvent(event string, optsOrPredicate goja.Value) interface{} {
-	k6ext.Panic(p.ctx, "Page.waitForEvent(event, optsOrPredicate) has not been implemented yet")
+	p.logger.Debugf("Page:WaitForEvent", "sid:%v event:%q", p.sessionID(), event)
+
+	if !stringSliceContains(pageWaitForEvents, event) {
+		k6ext.Panic(p.ctx, "unknown page event: %q, must be one of: %s", event, strings.Join(pageWaitForEvents, ", "))
+	}
+	popts := NewPageWaitForEventOptions(p.defaultTimeout())
+	if err := popts.Parse(p.ctx, optsOrPredicate); err != nil {
+		k6ext.Panic(p.ctx, "parsing waitForEvent options: %w", err)
+	}
+	data, err := p.waitForEvent(event, popts)
+	if err != nil {
+		k6ext.Panic(p.ctx, "waiting for %q event: %w", event, err)
+	}
+	return data
+}
+
+// pageWaitForEvents are the events WaitForEvent can wait for.
+var pageWaitForEvents = []string{
+	EventPageClose, EventPageConsole, EventPageCrash, EventPageDialog,
+	EventPageDOMContentLoaded, EventPageDownload, EventPageFilechooser,
+	EventPageFrameAttached, EventPageFrameDetached, EventPageFrameNavigated,
+	EventPageLoad, EventPageError, EventPagePopup, EventPageRequest,
+	EventPageRequestFailed, EventPageRequestFinished, EventPageResponse,
+}
+
+func (p *Page) waitForEvent(event string, opts *PageWaitForEventOptions) (interface{}, error) {
+	ctx, cancel := context.WithTimeout(p.ctx, opts.Timeout)
+	defer cancel()
+
+	ch := make(chan Event)
+	p.on(ctx, []string{event, EventPageClose}, ch)
+	if err := p.addEventWaiter(event); err != nil {
+		return nil, err
+	}
+	defer p.removeEventWaiter(event)
+
+	var inspected int
+	for {
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return nil, fmt.Errorf("%w after %s, inspected %d %s event(s)", ErrTimedOut, opts.Timeout, inspected, event)
+			}
+			return nil, ctx.Err()
+		case ev := <-ch:
+			if ev.typ == EventPageClose && event != EventPageClose {
+				return nil, errors.New("page closed")
+			}
+			inspected++
+
+			// the load events don't have a payload.
+			data := ev.data
+			if data == nil {
+				data = p
+			}
+			if opts.Predicate == nil {
+				return data, nil
+			}
+			ok, err := p.callPredicate(opts.Predicate, data)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				return data, nil
+			}
+		}
+	}
+}
+
+func (p *Page) callPredicate(predicate goja.Callable, data interface{}) (bool, error) {
+	v, err := predicate(goja.Undefined(), p.vu.Runtime().ToValue(data))
+	if err != nil {
+		return false, fmt.Errorf("calling predicate: %w", err)
+	}
+	return v.ToBoolean(), nil
+}
+
+// addEventWaiter counts a WaitForEvent call waiting for the event, so that
+// the events only emitted while the script listens to them are emitted.
+func (p *Page) addEventWaiter(event string) error {
+	p.eventHandlersMu.Lock()
+	p.eventWaiters[event]++
+	first := p.eventWaiters[event] == 1 && len(p.eventHandlers[event]) == 0
+	p.eventHandlersMu.Unlock()
+
+	if event == EventPageFilechooser && first {
+		if err := p.updateFileChooserInterception(); err != nil {
+			return fmt.Errorf("intercepting file chooser: %w", err)
+		}
+	}
 	return nil
 }
 
+func (p *Page) removeEventWaiter(event string) {
+	p.eventHandlersMu.Lock()
+	p.eventWaiters[event]--
+	last := p.eventWaiters[event] == 0 && len(p.eventHandlers[event]) == 0
+	p.eventHandlersMu.Unlock()
+
+	if event == EventPageFilechooser && last {
+		if err := p.updateFileChooserInterception(); err != nil {
+			p.logger.Debugf("Page:removeEventWaiter", "sid:%v event:%q err:%v", p.sessionID(), event, err)
+		}
+	}
+}
+
 // WaitForFunction waits for the given predicate to return a truthy value.
 func (p *Page) WaitForFunction(fn, opts goja.Value, args ...goja.Value) *goja.Promise {
 	p.logger.Debugf("Page:WaitForFunction", "sid:%v", p.sessionID())
@@ -878,14 +1844,139 @@ func (p *Page) WaitForNavigation(opts goja.Value) api.Response {
 	return p.frameManager.MainFrame().WaitForNavigation(opts)
 }
 
+// WaitForPopup waits for the page to open a popup, such as with
+// window.open, and returns the popup page before its first navigation
+// completes. As the popup can be opened before WaitForPopup is called, such
+// as by the click that opens it, it returns the oldest open popup that was
+// opened since the previous WaitForPopup call returned, if there is one.
+// The other popups opened meanwhile are dropped when it returns.
+func (p *Page) WaitForPopup(opts goja.Value) api.Page {
+	p.logger.Debugf("Page:WaitForPopup", "sid:%v", p.sessionID())
+
+	popts := NewPageWaitForEventOptions(p.defaultTimeout())
+	if err := popts.Parse(p.ctx, opts); err != nil {
+		k6ext.Panic(p.ctx, "parsing waitForPopup options: %w", err)
+	}
+	popup, err := p.waitForPopup(popts)
+	if err != nil {
+		k6ext.Panic(p.ctx, "waiting for popup: %w", err)
+	}
+	return popup
+}
+
+func (p *Page) waitForPopup(opts *PageWaitForEventOptions) (*Page, error) {
+	ctx, cancel := context.WithTimeout(p.ctx, opts.Timeout)
+	defer cancel()
+
+	// subscribe before claiming, so that a popup opened in the meantime
+	// isn't missed.
+	ch := make(chan Event)
+	p.on(ctx, []string{EventPagePopup, EventPageClose}, ch)
+
+	// the popups that this call doesn't return are stale for the next one.
+	defer func() {
+		p.popupsMu.Lock()
+		p.popups = nil
+		p.popupsMu.Unlock()
+	}()
+
+	for {
+		popup, err := p.claimPopup(opts.Predicate)
+		if err != nil || popup != nil {
+			return popup, err
+		}
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return nil, fmt.Errorf("%w after %s, no popup was opened", ErrTimedOut, opts.Timeout)
+			}
+			return nil, ctx.Err()
+		case ev := <-ch:
+			if ev.typ == EventPageClose {
+				return nil, errors.New("page closed")
+			}
+		}
+	}
+}
+
+// onPopup is called when the page opens the popup.
+func (p *Page) onPopup(popup *Page) {
+	p.popupsMu.Lock()
+	p.popups = append(p.popups, popup)
+	p.popupsMu.Unlock()
+
+	p.emit(EventPagePopup, popup)
+}
+
+// claimPopup removes and returns the oldest popup that the predicate
+// returns true for, if there is one.
+func (p *Page) claimPopup(predicate goja.Callable) (*Page, error) {
+	p.popupsMu.Lock()
+	popups := append([]*Page(nil), p.popups...)
+	p.popupsMu.Unlock()
+
+	for _, popup := range popups {
+		if predicate != nil {
+			ok, err := p.callPredicate(predicate, popup)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				continue
+			}
+		}
+		p.removePopup(popup)
+
+		return popup, nil
+	}
+
+	return nil, nil
+}
+
+// removePopup removes the popup from the popups WaitForPopup can return.
+func (p *Page) removePopup(popup *Page) {
+	p.popupsMu.Lock()
+	defer p.popupsMu.Unlock()
+
+	for i, pp := range p.popups {
+		if pp == popup {
+			p.popups = append(p.popups[:i], p.popups[i+1:]...)
+			return
+		}
+	}
+}
+
+// WaitForRequest waits for a request with a URL matching the glob pattern
+// or the RegExp, or for a request the predicate function returns true for.
+// Requests served from the cache are matched as well.
 func (p *Page) WaitForRequest(urlOrPredicate, opts goja.Value) api.Request {
-	k6ext.Panic(p.ctx, "Page.waitForRequest(urlOrPredicate, opts) has not been implemented yet")
-	return nil
+	p.logger.Debugf("Page:WaitForRequest", "sid:%v", p.sessionID())
+
+	popts := NewPageWaitForNetworkEventOptions(p.navigationTimeout())
+	if err := popts.Parse(p.ctx, opts); err != nil {
+		k6ext.Panic(p.ctx, "parsing waitForRequest options: %w", err)
+	}
+	data, err := p.waitForNetworkEvent(EventPageRequest, urlOrPredicate, popts.Timeout)
+	if err != nil {
+		k6ext.Panic(p.ctx, "waiting for request: %w", err)
+	}
+	return data.(*Request)
 }
 
+// WaitForResponse waits for a response with a URL matching the glob pattern
+// or the RegExp, or for a response the predicate function returns true for.
 func (p *Page) WaitForResponse(urlOrPredicate, opts goja.Value) api.Response {
-	k6ext.Panic(p.ctx, "Page.waitForResponse(urlOrPredicate, opts) has not been implemented yet")
-	return nil
+	p.logger.Debugf("Page:WaitForResponse", "sid:%v", p.sessionID())
+
+	popts := NewPageWaitForNetworkEventOptions(p.navigationTimeout())
+	if err := popts.Parse(p.ctx, opts); err != nil {
+		k6ext.Panic(p.ctx, "parsing waitForResponse options: %w", err)
+	}
+	data, err := p.waitForNetworkEvent(EventPageResponse, urlOrPredicate, popts.Timeout)
+	if err != nil {
+		k6ext.Panic(p.ctx, "waiting for response: %w", err)
+	}
+	return data.(*Response)
 }
 
 // WaitForSelector waits for the given selector to match the waiting criteria.
@@ -904,6 +1995,14 @@ func (p *Page) WaitForTimeout(timeout int64) {
 	p.frameManager.MainFrame().WaitForTimeout(timeout)
 }
 
+// WaitForURL waits for the main frame to navigate to a URL matching the
+// given glob pattern, RegExp or predicate function.
+func (p *Page) WaitForURL(urlOrPredicate, opts goja.Value) {
+	p.logger.Debugf("Page:WaitForURL", "sid:%v", p.sessionID())
+
+	p.frameManager.MainFrame().WaitForURL(urlOrPredicate, opts)
+}
+
 // Workers returns all WebWorkers of page.
 func (p *Page) Workers() []api.Worker {
 	workers := make([]api.Worker, 0, len(p.workers))
@@ -0,0 +1,47 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+
+	cdpruntime "github.com/chromedp/cdproto/runtime"
+)
+
+// PageError is an uncaught exception thrown in a page.
+type PageError struct {
+	Name    string `js:"name"`
+	Message string `js:"message"`
+	Stack   string `js:"stack"`
+}
+
+// NewPageError returns a new page error from the details of an exception.
+func NewPageError(details *cdpruntime.ExceptionDetails) *PageError {
+	exc := details.Exception
+	if exc == nil {
+		return &PageError{Message: details.Text}
+	}
+	if exc.Subtype != cdpruntime.SubtypeError {
+		// A value other than an error was thrown.
+		v, err := parseRemoteObject(exc)
+		if err != nil || v == nil {
+			return &PageError{Message: exc.Description}
+		}
+		return &PageError{Message: fmt.Sprint(v)}
+	}
+
+	// The description of errors is their stack, which starts with
+	// the name and message of the error.
+	pe := &PageError{
+		Name:    exc.ClassName,
+		Message: exc.Description,
+		Stack:   exc.Description,
+	}
+	if i := strings.Index(exc.Description, "\n    at "); i != -1 {
+		pe.Message = exc.Description[:i]
+	}
+	pe.Message = strings.TrimPrefix(pe.Message, pe.Name+": ")
+	if pe.Message == pe.Name {
+		pe.Message = ""
+	}
+	return pe
+}
@@ -0,0 +1,69 @@
+package common
+
+import (
+	"testing"
+
+	cdpruntime "github.com/chromedp/cdproto/runtime"
+	"github.com/mailru/easyjson"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPageError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		details *cdpruntime.ExceptionDetails
+		want    *PageError
+	}{
+		{
+			name: "error",
+			details: &cdpruntime.ExceptionDetails{
+				Exception: &cdpruntime.RemoteObject{
+					Type:        cdpruntime.TypeObject,
+					Subtype:     cdpruntime.SubtypeError,
+					ClassName:   "TypeError",
+					Description: "TypeError: boom\n    at <anonymous>:1:7",
+				},
+			},
+			want: &PageError{
+				Name:    "TypeError",
+				Message: "boom",
+				Stack:   "TypeError: boom\n    at <anonymous>:1:7",
+			},
+		},
+		{
+			name: "error_without_message",
+			details: &cdpruntime.ExceptionDetails{
+				Exception: &cdpruntime.RemoteObject{
+					Type:        cdpruntime.TypeObject,
+					Subtype:     cdpruntime.SubtypeError,
+					ClassName:   "Error",
+					Description: "Error\n    at <anonymous>:1:7",
+				},
+			},
+			want: &PageError{
+				Name:  "Error",
+				Stack: "Error\n    at <anonymous>:1:7",
+			},
+		},
+		{
+			name: "value",
+			details: &cdpruntime.ExceptionDetails{
+				Exception: &cdpruntime.RemoteObject{
+					Type:  cdpruntime.TypeString,
+					Value: easyjson.RawMessage(`"boom"`),
+				},
+			},
+			want: &PageError{Message: "boom"},
+		},
+		{
+			name:    "no_exception",
+			details: &cdpruntime.ExceptionDetails{Text: "Uncaught"},
+			want:    &PageError{Message: "Uncaught"},
+		},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, NewPageError(tt.details), tt.name)
+	}
+}
@@ -22,7 +22,9 @@ package common
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -32,60 +34,177 @@ import (
 	"github.com/grafana/xk6-browser/k6ext"
 )
 
+// PageExposeBindingOptions are the options for Page.exposeBinding.
+type PageExposeBindingOptions struct {
+	Handle bool `json:"handle"`
+}
+
+// PageEmulateMediaOptions are the options for Page.emulateMedia.
+// The empty values reset the emulation to the system defaults.
 type PageEmulateMediaOptions struct {
 	ColorScheme   ColorScheme   `json:"colorScheme"`
+	ForcedColors  ForcedColors  `json:"forcedColors"`
 	Media         MediaType     `json:"media"`
 	ReducedMotion ReducedMotion `json:"reducedMotion"`
 }
 
-type PageReloadOptions struct {
+// PageNavigationOptions are the options for the navigations within the page
+// history, such as Page.goBack, Page.goForward and Page.reload.
+type PageNavigationOptions struct {
 	WaitUntil LifecycleEvent `json:"waitUntil"`
 	Timeout   time.Duration  `json:"timeout"`
 }
 
+type PageReloadOptions struct {
+	PageNavigationOptions
+	IgnoreCache bool `json:"ignoreCache"`
+}
+
+// PageWaitForEventOptions are the options for waiting for a page event.
+type PageWaitForEventOptions struct {
+	Predicate goja.Callable `json:"predicate"`
+	Timeout   time.Duration `json:"timeout"`
+}
+
+// PageWaitForNetworkEventOptions are the options for waiting for a request or
+// a response.
+type PageWaitForNetworkEventOptions struct {
+	Timeout time.Duration `json:"timeout"`
+}
+
+// PagePDFOptions are the options for Page.pdf. All the sizes are in inches.
+type PagePDFOptions struct {
+	Path            string     `json:"path"`
+	Width           float64    `json:"width"`
+	Height          float64    `json:"height"`
+	Landscape       bool       `json:"landscape"`
+	Margin          PDFMargins `json:"margin"`
+	PrintBackground bool       `json:"printBackground"`
+	Scale           float64    `json:"scale"`
+	PageRanges      string     `json:"pageRanges"`
+}
+
+// PDFMargins are the paper margins of a PDF in inches.
+type PDFMargins struct {
+	Top    float64 `json:"top"`
+	Right  float64 `json:"right"`
+	Bottom float64 `json:"bottom"`
+	Left   float64 `json:"left"`
+}
+
 type PageScreenshotOptions struct {
-	Clip           *page.Viewport `json:"clip"`
-	Path           string         `json:"path"`
-	Format         ImageFormat    `json:"format"`
-	FullPage       bool           `json:"fullPage"`
-	OmitBackground bool           `json:"omitBackground"`
-	Quality        int64          `json:"quality"`
+	Clip           *page.Viewport       `json:"clip"`
+	Path           string               `json:"path"`
+	Format         ImageFormat          `json:"format"`
+	FullPage       bool                 `json:"fullPage"`
+	OmitBackground bool                 `json:"omitBackground"`
+	Quality        int64                `json:"quality"`
+	Scale          ScreenshotScale      `json:"scale"`
+	Animations     ScreenshotAnimations `json:"animations"`
 }
 
-func NewPageEmulateMediaOptions(defaultMedia MediaType, defaultColorScheme ColorScheme, defaultReducedMotion ReducedMotion) *PageEmulateMediaOptions {
+func NewPageEmulateMediaOptions(
+	defaultMedia MediaType, defaultColorScheme ColorScheme, defaultReducedMotion ReducedMotion,
+	defaultForcedColors ForcedColors,
+) *PageEmulateMediaOptions {
 	return &PageEmulateMediaOptions{
 		ColorScheme:   defaultColorScheme,
+		ForcedColors:  defaultForcedColors,
 		Media:         defaultMedia,
 		ReducedMotion: defaultReducedMotion,
 	}
 }
 
+// NewPageExposeBindingOptions returns a new PageExposeBindingOptions.
+func NewPageExposeBindingOptions() *PageExposeBindingOptions {
+	return &PageExposeBindingOptions{}
+}
+
+// Parse parses the expose binding options.
+func (o *PageExposeBindingOptions) Parse(ctx context.Context, opts goja.Value) error {
+	if !gojaValueExists(opts) {
+		return nil
+	}
+	obj := opts.ToObject(k6ext.Runtime(ctx))
+	for _, k := range obj.Keys() {
+		switch k {
+		case "handle":
+			o.Handle = obj.Get(k).ToBoolean()
+		}
+	}
+	return nil
+}
+
+// Parse parses the emulate media options. A null option resets the
+// emulation of that option.
 func (o *PageEmulateMediaOptions) Parse(ctx context.Context, opts goja.Value) error {
 	rt := k6ext.Runtime(ctx)
 	if opts != nil && !goja.IsUndefined(opts) && !goja.IsNull(opts) {
 		opts := opts.ToObject(rt)
 		for _, k := range opts.Keys() {
+			v := opts.Get(k)
+			if goja.IsUndefined(v) {
+				continue
+			}
+			reset := goja.IsNull(v)
 			switch k {
 			case "colorScheme":
-				o.ColorScheme = ColorScheme(opts.Get(k).String())
+				o.ColorScheme = ""
+				if reset {
+					continue
+				}
+				c, ok := colorSchemeToID[v.String()]
+				if !ok {
+					return fmt.Errorf("%q is not a valid color scheme", v.String())
+				}
+				o.ColorScheme = c
+			case "forcedColors":
+				o.ForcedColors = ""
+				if reset {
+					continue
+				}
+				f, ok := forcedColorsToID[v.String()]
+				if !ok {
+					return fmt.Errorf("%q is not a valid forced colors option", v.String())
+				}
+				o.ForcedColors = f
 			case "media":
-				o.Media = MediaType(opts.Get(k).String())
+				o.Media = ""
+				if reset {
+					continue
+				}
+				m, ok := mediaTypeToID[v.String()]
+				if !ok {
+					return fmt.Errorf("%q is not a valid media type", v.String())
+				}
+				o.Media = m
 			case "reducedMotion":
-				o.ReducedMotion = ReducedMotion(opts.Get(k).String())
+				o.ReducedMotion = ""
+				if reset {
+					continue
+				}
+				r, ok := reducedMotionToID[v.String()]
+				if !ok {
+					return fmt.Errorf("%q is not a valid reduced motion option", v.String())
+				}
+				o.ReducedMotion = r
 			}
 		}
 	}
 	return nil
 }
 
-func NewPageReloadOptions(defaultWaitUntil LifecycleEvent, defaultTimeout time.Duration) *PageReloadOptions {
-	return &PageReloadOptions{
+// NewPageNavigationOptions returns the history navigation options with the
+// given defaults.
+func NewPageNavigationOptions(defaultWaitUntil LifecycleEvent, defaultTimeout time.Duration) *PageNavigationOptions {
+	return &PageNavigationOptions{
 		WaitUntil: defaultWaitUntil,
 		Timeout:   defaultTimeout,
 	}
 }
 
-func (o *PageReloadOptions) Parse(ctx context.Context, opts goja.Value) error {
+// Parse parses the history navigation options.
+func (o *PageNavigationOptions) Parse(ctx context.Context, opts goja.Value) error {
 	rt := k6ext.Runtime(ctx)
 	if opts != nil && !goja.IsUndefined(opts) && !goja.IsNull(opts) {
 		opts := opts.ToObject(rt)
@@ -106,6 +225,28 @@ func (o *PageReloadOptions) Parse(ctx context.Context, opts goja.Value) error {
 	return nil
 }
 
+func NewPageReloadOptions(defaultWaitUntil LifecycleEvent, defaultTimeout time.Duration) *PageReloadOptions {
+	return &PageReloadOptions{
+		PageNavigationOptions: *NewPageNavigationOptions(defaultWaitUntil, defaultTimeout),
+	}
+}
+
+func (o *PageReloadOptions) Parse(ctx context.Context, opts goja.Value) error {
+	if err := o.PageNavigationOptions.Parse(ctx, opts); err != nil {
+		return err
+	}
+	rt := k6ext.Runtime(ctx)
+	if opts != nil && !goja.IsUndefined(opts) && !goja.IsNull(opts) {
+		opts := opts.ToObject(rt)
+		for _, k := range opts.Keys() {
+			if k == "ignoreCache" {
+				o.IgnoreCache = opts.Get(k).ToBoolean()
+			}
+		}
+	}
+	return nil
+}
+
 func NewPageScreenshotOptions() *PageScreenshotOptions {
 	return &PageScreenshotOptions{
 		Clip:           nil,
@@ -114,6 +255,8 @@ func NewPageScreenshotOptions() *PageScreenshotOptions {
 		FullPage:       false,
 		OmitBackground: false,
 		Quality:        100,
+		Scale:          ScreenshotScaleDevice,
+		Animations:     ScreenshotAnimationsAllow,
 	}
 }
 
@@ -126,7 +269,7 @@ func (o *PageScreenshotOptions) Parse(ctx context.Context, opts goja.Value) erro
 			switch k {
 			case "clip":
 				var c map[string]float64
-				if rt.ExportTo(opts.Get(k), &c) != nil {
+				if rt.ExportTo(opts.Get(k), &c) == nil {
 					o.Clip = &page.Viewport{
 						X:      c["x"],
 						Y:      c["y"],
@@ -135,6 +278,12 @@ func (o *PageScreenshotOptions) Parse(ctx context.Context, opts goja.Value) erro
 						Scale:  1,
 					}
 				}
+			case "animations":
+				a, err := parseScreenshotAnimations(opts.Get(k).String())
+				if err != nil {
+					return err
+				}
+				o.Animations = a
 			case "fullPage":
 				o.FullPage = opts.Get(k).ToBoolean()
 			case "omitBackground":
@@ -143,6 +292,12 @@ func (o *PageScreenshotOptions) Parse(ctx context.Context, opts goja.Value) erro
 				o.Path = opts.Get(k).String()
 			case "quality":
 				o.Quality = opts.Get(k).ToInteger()
+			case "scale":
+				sc, err := parseScreenshotScale(opts.Get(k).String())
+				if err != nil {
+					return err
+				}
+				o.Scale = sc
 			case "type":
 				if f, ok := imageFormatToID[opts.Get(k).String()]; ok {
 					o.Format = f
@@ -161,3 +316,200 @@ func (o *PageScreenshotOptions) Parse(ctx context.Context, opts goja.Value) erro
 
 	return nil
 }
+
+// NewPageWaitForEventOptions returns a new PageWaitForEventOptions.
+func NewPageWaitForEventOptions(defaultTimeout time.Duration) *PageWaitForEventOptions {
+	return &PageWaitForEventOptions{
+		Timeout: defaultTimeout,
+	}
+}
+
+// Parse parses the predicate function, or the options with the predicate
+// and the timeout.
+func (o *PageWaitForEventOptions) Parse(ctx context.Context, optsOrPredicate goja.Value) error {
+	if !gojaValueExists(optsOrPredicate) {
+		return nil
+	}
+	if fn, ok := goja.AssertFunction(optsOrPredicate); ok {
+		o.Predicate = fn
+		return nil
+	}
+	rt := k6ext.Runtime(ctx)
+	opts := optsOrPredicate.ToObject(rt)
+	for _, k := range opts.Keys() {
+		switch k {
+		case "predicate":
+			fn, ok := goja.AssertFunction(opts.Get(k))
+			if !ok {
+				return errors.New("predicate must be a function")
+			}
+			o.Predicate = fn
+		case "timeout":
+			o.Timeout = time.Duration(opts.Get(k).ToInteger()) * time.Millisecond
+		}
+	}
+
+	return nil
+}
+
+// NewPageWaitForNetworkEventOptions returns a new PageWaitForNetworkEventOptions.
+func NewPageWaitForNetworkEventOptions(defaultTimeout time.Duration) *PageWaitForNetworkEventOptions {
+	return &PageWaitForNetworkEventOptions{
+		Timeout: defaultTimeout,
+	}
+}
+
+// Parse parses the options for waiting for a request or a response.
+func (o *PageWaitForNetworkEventOptions) Parse(ctx context.Context, opts goja.Value) error {
+	rt := k6ext.Runtime(ctx)
+	if opts != nil && !goja.IsUndefined(opts) && !goja.IsNull(opts) {
+		opts := opts.ToObject(rt)
+		for _, k := range opts.Keys() {
+			switch k {
+			case "timeout":
+				o.Timeout = time.Duration(opts.Get(k).ToInteger()) * time.Millisecond
+			}
+		}
+	}
+	return nil
+}
+
+// pdfPaperFormats are the paper sizes in inches.
+var pdfPaperFormats = map[string][2]float64{ //nolint:gochecknoglobals
+	"letter":  {8.5, 11},
+	"legal":   {8.5, 14},
+	"tabloid": {11, 17},
+	"ledger":  {17, 11},
+	"a0":      {33.1, 46.8},
+	"a1":      {23.4, 33.1},
+	"a2":      {16.54, 23.4},
+	"a3":      {11.7, 16.54},
+	"a4":      {8.27, 11.7},
+	"a5":      {5.83, 8.27},
+	"a6":      {4.13, 5.83},
+}
+
+// pdfUnitsToInches are the number of inches in the supported size units.
+var pdfUnitsToInches = map[string]float64{ //nolint:gochecknoglobals
+	"px": 1.0 / 96,
+	"in": 1,
+	"cm": 1 / 2.54,
+	"mm": 1 / 25.4,
+}
+
+// NewPagePDFOptions returns a new PagePDFOptions with the Letter paper size.
+func NewPagePDFOptions() *PagePDFOptions {
+	return &PagePDFOptions{
+		Width:  8.5,
+		Height: 11,
+		Scale:  1,
+	}
+}
+
+// Parse parses the PDF options.
+func (o *PagePDFOptions) Parse(ctx context.Context, opts goja.Value) error {
+	if !gojaValueExists(opts) {
+		return nil
+	}
+	var (
+		rt            = k6ext.Runtime(ctx)
+		obj           = opts.ToObject(rt)
+		width, height goja.Value
+		err           error
+	)
+	for _, k := range obj.Keys() {
+		v := obj.Get(k)
+		switch k {
+		case "path":
+			o.Path = v.String()
+		case "format":
+			size, ok := pdfPaperFormats[strings.ToLower(v.String())]
+			if !ok {
+				return fmt.Errorf("unknown paper format %q", v.String())
+			}
+			o.Width, o.Height = size[0], size[1]
+		case "width":
+			width = v
+		case "height":
+			height = v
+		case "landscape":
+			o.Landscape = v.ToBoolean()
+		case "margin":
+			if err := o.Margin.parse(rt, v); err != nil {
+				return err
+			}
+		case "printBackground":
+			o.PrintBackground = v.ToBoolean()
+		case "scale":
+			o.Scale = v.ToFloat()
+			if o.Scale < 0.1 || o.Scale > 2 {
+				return fmt.Errorf("scale must be between 0.1 and 2, got %v", o.Scale)
+			}
+		case "pageRanges":
+			o.PageRanges = v.String()
+		}
+	}
+	// width and height take precedence over the format.
+	if width != nil {
+		if o.Width, err = parsePDFSize(width); err != nil {
+			return fmt.Errorf("parsing width: %w", err)
+		}
+	}
+	if height != nil {
+		if o.Height, err = parsePDFSize(height); err != nil {
+			return fmt.Errorf("parsing height: %w", err)
+		}
+	}
+	return nil
+}
+
+func (m *PDFMargins) parse(rt *goja.Runtime, v goja.Value) error {
+	if !gojaValueExists(v) {
+		return nil
+	}
+	obj := v.ToObject(rt)
+	for _, k := range obj.Keys() {
+		var side *float64
+		switch k {
+		case "top":
+			side = &m.Top
+		case "right":
+			side = &m.Right
+		case "bottom":
+			side = &m.Bottom
+		case "left":
+			side = &m.Left
+		default:
+			continue
+		}
+		size, err := parsePDFSize(obj.Get(k))
+		if err != nil {
+			return fmt.Errorf("parsing %s margin: %w", k, err)
+		}
+		*side = size
+	}
+	return nil
+}
+
+// parsePDFSize converts a size to inches. Numbers are in pixels and strings
+// can have one of the px, in, cm or mm units, such as "1.5cm".
+func parsePDFSize(v goja.Value) (float64, error) {
+	if !gojaValueExists(v) {
+		return 0, nil
+	}
+	if _, ok := v.Export().(string); !ok {
+		return v.ToFloat() * pdfUnitsToInches["px"], nil
+	}
+	text := strings.TrimSpace(strings.ToLower(v.String()))
+	unit := "px"
+	if len(text) > 2 {
+		if _, ok := pdfUnitsToInches[text[len(text)-2:]]; ok {
+			unit, text = text[len(text)-2:], text[:len(text)-2]
+		}
+	}
+	size, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q", v.String())
+	}
+	return size * pdfUnitsToInches[unit], nil
+}
@@ -0,0 +1,156 @@
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/xk6-browser/k6ext/k6test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPagePDFOptionsParse(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ok", func(t *testing.T) {
+		t.Parallel()
+
+		vu := k6test.NewVU(t)
+		opts := vu.ToGojaValue(map[string]interface{}{
+			"format":          "A4",
+			"landscape":       true,
+			"printBackground": true,
+			"scale":           1.5,
+			"pageRanges":      "1-2",
+			"margin": map[string]interface{}{
+				"top":    "2.54cm",
+				"right":  "1in",
+				"bottom": 96,
+				"left":   "25.4mm",
+			},
+		})
+		pdfOpts := NewPagePDFOptions()
+		require.NoError(t, pdfOpts.Parse(vu.Context(), opts))
+
+		assert.Equal(t, 8.27, pdfOpts.Width)
+		assert.Equal(t, 11.7, pdfOpts.Height)
+		assert.True(t, pdfOpts.Landscape)
+		assert.True(t, pdfOpts.PrintBackground)
+		assert.Equal(t, 1.5, pdfOpts.Scale)
+		assert.Equal(t, "1-2", pdfOpts.PageRanges)
+		assert.InDelta(t, 1, pdfOpts.Margin.Top, 1e-9)
+		assert.InDelta(t, 1, pdfOpts.Margin.Right, 1e-9)
+		assert.InDelta(t, 1, pdfOpts.Margin.Bottom, 1e-9)
+		assert.InDelta(t, 1, pdfOpts.Margin.Left, 1e-9)
+	})
+
+	t.Run("ok/width_over_format", func(t *testing.T) {
+		t.Parallel()
+
+		vu := k6test.NewVU(t)
+		opts := vu.ToGojaValue(map[string]interface{}{
+			"width":  "10in",
+			"format": "A4",
+		})
+		pdfOpts := NewPagePDFOptions()
+		require.NoError(t, pdfOpts.Parse(vu.Context(), opts))
+
+		assert.Equal(t, 10.0, pdfOpts.Width)
+		assert.Equal(t, 11.7, pdfOpts.Height)
+	})
+
+	t.Run("err/invalid_format", func(t *testing.T) {
+		t.Parallel()
+
+		vu := k6test.NewVU(t)
+		opts := vu.ToGojaValue(map[string]interface{}{
+			"format": "B52",
+		})
+		err := NewPagePDFOptions().Parse(vu.Context(), opts)
+		assert.EqualError(t, err, `unknown paper format "B52"`)
+	})
+
+	t.Run("err/invalid_margin", func(t *testing.T) {
+		t.Parallel()
+
+		vu := k6test.NewVU(t)
+		opts := vu.ToGojaValue(map[string]interface{}{
+			"margin": map[string]interface{}{"top": "1 furlong"},
+		})
+		err := NewPagePDFOptions().Parse(vu.Context(), opts)
+		assert.EqualError(t, err, `parsing top margin: invalid size "1 furlong"`)
+	})
+}
+
+func TestPageScreenshotOptionsParse(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ok", func(t *testing.T) {
+		t.Parallel()
+
+		vu := k6test.NewVU(t)
+		opts := vu.ToGojaValue(map[string]interface{}{
+			"animations": "disabled",
+			"clip":       map[string]interface{}{"x": 1, "y": 2, "width": 3, "height": 4},
+			"scale":      "css",
+		})
+		sopts := NewPageScreenshotOptions()
+		require.NoError(t, sopts.Parse(vu.Context(), opts))
+
+		assert.Equal(t, ScreenshotAnimationsDisabled, sopts.Animations)
+		assert.Equal(t, ScreenshotScaleCSS, sopts.Scale)
+		require.NotNil(t, sopts.Clip)
+		assert.Equal(t, 3.0, sopts.Clip.Width)
+	})
+
+	t.Run("err/invalid_scale", func(t *testing.T) {
+		t.Parallel()
+
+		vu := k6test.NewVU(t)
+		opts := vu.ToGojaValue(map[string]interface{}{"scale": "huge"})
+		err := NewPageScreenshotOptions().Parse(vu.Context(), opts)
+		assert.EqualError(t, err, `"huge" is not a valid scale option`)
+	})
+}
+
+func TestPageEmulateMediaOptionsParse(t *testing.T) {
+	t.Parallel()
+
+	vu := k6test.NewVU(t)
+	opts, err := vu.Runtime().RunString(`({ media: null, colorScheme: 'dark', forcedColors: 'active' })`)
+	require.NoError(t, err)
+
+	mopts := NewPageEmulateMediaOptions(MediaTypePrint, ColorSchemeLight, ReducedMotionReduce, ForcedColorsNone)
+	require.NoError(t, mopts.Parse(vu.Context(), opts))
+
+	assert.Equal(t, MediaType(""), mopts.Media)
+	assert.Equal(t, ColorSchemeDark, mopts.ColorScheme)
+	assert.Equal(t, ForcedColorsActive, mopts.ForcedColors)
+	assert.Equal(t, ReducedMotionReduce, mopts.ReducedMotion)
+}
+
+func TestPageWaitForEventOptionsParse(t *testing.T) {
+	t.Parallel()
+
+	vu := k6test.NewVU(t)
+
+	predicate, err := vu.Runtime().RunString(`() => true`)
+	require.NoError(t, err)
+	wopts := NewPageWaitForEventOptions(time.Second)
+	require.NoError(t, wopts.Parse(vu.Context(), predicate))
+	assert.NotNil(t, wopts.Predicate)
+	assert.Equal(t, time.Second, wopts.Timeout)
+
+	opts, err := vu.Runtime().RunString(`({ predicate: () => true, timeout: 100 })`)
+	require.NoError(t, err)
+	wopts = NewPageWaitForEventOptions(time.Second)
+	require.NoError(t, wopts.Parse(vu.Context(), opts))
+	assert.NotNil(t, wopts.Predicate)
+	assert.Equal(t, 100*time.Millisecond, wopts.Timeout)
+
+	opts, err = vu.Runtime().RunString(`({ predicate: "true" })`)
+	require.NoError(t, err)
+	wopts = NewPageWaitForEventOptions(time.Second)
+	require.ErrorContains(t, wopts.Parse(vu.Context(), opts), "predicate must be a function")
+}
@@ -22,10 +22,14 @@ package common
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"mime"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/grafana/xk6-browser/api"
@@ -47,12 +51,19 @@ type Request struct {
 	frame               *Frame
 	response            *Response
 	redirectChain       []*Request
+	redirectedFrom      *Request
+	redirectedTo        *Request
+	redirectMu          sync.RWMutex
+	finished            chan struct{}
+	finishOnce          sync.Once
 	requestID           network.RequestID
 	documentID          string
 	url                 *url.URL
 	method              string
 	headers             map[string][]string
-	postData            string
+	postData            []byte
+	hasPostData         bool
+	postDataMu          sync.RWMutex
 	resourceType        string
 	isNavigationRequest bool
 	allowInterception   bool
@@ -94,7 +105,8 @@ func NewRequest(
 		url:                 u,
 		method:              event.Request.Method,
 		headers:             make(map[string][]string),
-		postData:            event.Request.PostData,
+		postData:            requestPostData(event.Request),
+		hasPostData:         event.Request.HasPostData || event.Request.PostData != "",
 		resourceType:        event.Type.String(),
 		isNavigationRequest: string(event.RequestID) == string(event.LoaderID) && event.Type == network.ResourceTypeDocument,
 		allowInterception:   allowInterception,
@@ -104,6 +116,10 @@ func NewRequest(
 		timestamp:           event.Timestamp.Time(),
 		wallTime:            event.WallTime.Time(),
 		vu:                  k6ext.GetVU(ctx),
+		finished:            make(chan struct{}),
+	}
+	if n := len(redirectChain); n > 0 {
+		r.redirectedFrom = redirectChain[n-1]
 	}
 	for n, v := range event.Request.Headers {
 		switch v := v.(type) {
@@ -118,6 +134,60 @@ func NewRequest(
 	return &r, nil
 }
 
+// requestPostData returns the post data of the request. The post data
+// entries are preferred as, unlike the post data string, they keep the
+// binary bodies intact.
+func requestPostData(req *network.Request) []byte {
+	if len(req.PostDataEntries) == 0 {
+		if req.PostData == "" {
+			return nil
+		}
+		return []byte(req.PostData)
+	}
+	var data []byte
+	for _, e := range req.PostDataEntries {
+		b, err := base64.StdEncoding.DecodeString(e.Bytes)
+		if err != nil {
+			return []byte(req.PostData)
+		}
+		data = append(data, b...)
+	}
+	return data
+}
+
+// fetchPostData fetches the post data of the request if it has any, but
+// it was too long to be sent with the request event.
+func (r *Request) fetchPostData() error {
+	missing := func() bool {
+		r.postDataMu.RLock()
+		defer r.postDataMu.RUnlock()
+
+		return r.hasPostData && r.postData == nil && r.frame != nil
+	}
+	if !missing() {
+		return nil
+	}
+	action := network.GetRequestPostData(r.requestID)
+	data, err := action.Do(cdp.WithExecutor(r.ctx, r.frame.manager.session))
+	if err != nil {
+		return fmt.Errorf("fetching post data: %w", err)
+	}
+	r.postDataMu.Lock()
+	r.postData = []byte(data)
+	r.postDataMu.Unlock()
+	return nil
+}
+
+// body returns the post data of the request, or nil if it doesn't have any.
+func (r *Request) body() []byte {
+	if err := r.fetchPostData(); err != nil {
+		k6ext.Panic(r.ctx, "getting request post data: %w", err)
+	}
+	r.postDataMu.RLock()
+	defer r.postDataMu.RUnlock()
+	return r.postData
+}
+
 func (r *Request) getFrame() *Frame {
 	return r.frame
 }
@@ -141,6 +211,26 @@ func (r *Request) headersSize() int64 {
 	return int64(size)
 }
 
+// finish marks the request as finished loading, either successfully or
+// not. The error text must be set before if it failed.
+func (r *Request) finish() {
+	r.finishOnce.Do(func() { close(r.finished) })
+}
+
+// waitForFinish waits for the request to finish loading, and returns an
+// error if it failed.
+func (r *Request) waitForFinish(ctx context.Context) error {
+	select {
+	case <-r.finished:
+	case <-ctx.Done():
+		return fmt.Errorf("waiting for request to finish: %w", ctx.Err())
+	}
+	if r.errorText != "" {
+		return fmt.Errorf("loading failed: %s", r.errorText)
+	}
+	return nil
+}
+
 func (r *Request) setErrorText(errorText string) {
 	r.errorText = errorText
 }
@@ -207,31 +297,86 @@ func (r *Request) Method() string {
 	return r.method
 }
 
-// PostData returns the request post data, if any.
-func (r *Request) PostData() string {
-	return r.postData
+// PostData returns the request post data as a string, or null if the
+// request doesn't have any. The multipart and URL encoded bodies are
+// returned as they were sent.
+func (r *Request) PostData() goja.Value {
+	data := r.body()
+	if data == nil {
+		return goja.Null()
+	}
+	return r.vu.Runtime().ToValue(string(data))
 }
 
-// PostDataBuffer returns the request post data as an ArrayBuffer.
-func (r *Request) PostDataBuffer() goja.ArrayBuffer {
+// PostDataBuffer returns the request post data as an ArrayBuffer, or null
+// if the request doesn't have any.
+func (r *Request) PostDataBuffer() goja.Value {
+	data := r.body()
+	if data == nil {
+		return goja.Null()
+	}
 	rt := r.vu.Runtime()
-	return rt.NewArrayBuffer([]byte(r.postData))
+	return rt.ToValue(rt.NewArrayBuffer(data))
 }
 
-// PostDataJSON returns the request post data as a JS object.
-func (r *Request) PostDataJSON() string {
-	k6ext.Panic(r.ctx, "Request.postDataJSON() has not been implemented yet")
-	return ""
+// PostDataJSON returns the request post data parsed as JSON, or null if the
+// request doesn't have any. The URL encoded form bodies are returned as an
+// object of the form values.
+func (r *Request) PostDataJSON() goja.Value {
+	data := r.body()
+	if data == nil {
+		return goja.Null()
+	}
+	rt := r.vu.Runtime()
+
+	mediaType, _, _ := mime.ParseMediaType(headerValue(r.headers, "Content-Type"))
+	if mediaType == "application/x-www-form-urlencoded" {
+		values, err := url.ParseQuery(string(data))
+		if err != nil {
+			k6ext.Panic(r.ctx, "parsing post data as form: %w", err)
+		}
+		form := make(map[string]string, len(values))
+		for k, vs := range values {
+			form[k] = vs[len(vs)-1]
+		}
+		return rt.ToValue(form)
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		k6ext.Panic(r.ctx, "parsing post data as JSON: %w", err)
+	}
+	return rt.ToValue(v)
 }
 
+// RedirectedFrom returns the request that was redirected by the server to
+// this one, or null if this request wasn't the result of a redirect. Each
+// request of a redirect chain has the redirect response, which has the
+// status code and the Location header of the hop.
 func (r *Request) RedirectedFrom() api.Request {
-	k6ext.Panic(r.ctx, "Request.redirectedFrom() has not been implemented yet")
-	return nil
+	if r.redirectedFrom == nil {
+		return nil
+	}
+	return r.redirectedFrom
 }
 
+// RedirectedTo returns the new request issued by the browser if the server
+// responded with a redirect, or null otherwise.
 func (r *Request) RedirectedTo() api.Request {
-	k6ext.Panic(r.ctx, "Request.redirectedTo() has not been implemented yet")
-	return nil
+	r.redirectMu.RLock()
+	defer r.redirectMu.RUnlock()
+
+	if r.redirectedTo == nil {
+		return nil
+	}
+	return r.redirectedTo
+}
+
+func (r *Request) setRedirectedTo(to *Request) {
+	r.redirectMu.Lock()
+	defer r.redirectMu.Unlock()
+
+	r.redirectedTo = to
 }
 
 // ResourceType returns the request resource type.
@@ -245,6 +390,8 @@ func (r *Request) Response() api.Response {
 }
 
 func (r *Request) Size() api.HTTPMessageSize {
+	r.postDataMu.RLock()
+	defer r.postDataMu.RUnlock()
 	return api.HTTPMessageSize{
 		Body:    int64(len(r.postData)),
 		Headers: r.headersSize(),
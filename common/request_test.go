@@ -21,6 +21,7 @@
 package common
 
 import (
+	"encoding/base64"
 	"testing"
 	"time"
 
@@ -29,6 +30,7 @@ import (
 
 	"github.com/chromedp/cdproto/cdp"
 	"github.com/chromedp/cdproto/network"
+	"github.com/dop251/goja"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -95,4 +97,68 @@ func TestRequest(t *testing.T) {
 			api.HTTPMessageSize{Headers: int64(33), Body: int64(5)},
 			req.Size())
 	})
+
+	t.Run("PostData()", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, "hello", req.PostData().Export())
+		buf, ok := req.PostDataBuffer().Export().(goja.ArrayBuffer)
+		require.True(t, ok)
+		assert.Equal(t, []byte("hello"), buf.Bytes())
+	})
+
+	t.Run("PostDataJSON()", func(t *testing.T) {
+		t.Parallel()
+		newRequest := func(contentType string, entries ...string) *Request {
+			evt := &network.EventRequestWillBeSent{
+				RequestID: network.RequestID("1234"),
+				Request: &network.Request{
+					URL:         "https://test/post",
+					Method:      "POST",
+					Headers:     network.Headers{"Content-Type": contentType},
+					HasPostData: len(entries) > 0,
+				},
+				Timestamp: &ts,
+				WallTime:  &wt,
+			}
+			for _, e := range entries {
+				evt.Request.PostDataEntries = append(evt.Request.PostDataEntries, &network.PostDataEntry{
+					Bytes: base64.StdEncoding.EncodeToString([]byte(e)),
+				})
+			}
+			req, err := NewRequest(vu.Context(), evt, nil, nil, "intercept", false)
+			require.NoError(t, err)
+			return req
+		}
+
+		req := newRequest("application/json", `{"event":`, `"click","n":1}`)
+		assert.Equal(t, map[string]interface{}{"event": "click", "n": 1.0}, req.PostDataJSON().Export())
+
+		req = newRequest("application/x-www-form-urlencoded; charset=UTF-8", "a=1&b=2&b=3")
+		assert.Equal(t, map[string]string{"a": "1", "b": "3"}, req.PostDataJSON().Export())
+
+		req = newRequest("text/plain")
+		assert.True(t, goja.IsNull(req.PostData()))
+		assert.True(t, goja.IsNull(req.PostDataBuffer()))
+		assert.True(t, goja.IsNull(req.PostDataJSON()))
+
+		req = newRequest("application/json", "{")
+		assert.Panics(t, func() { req.PostDataJSON() })
+	})
+
+	t.Run("RedirectedFrom()", func(t *testing.T) {
+		t.Parallel()
+		assert.Nil(t, req.RedirectedFrom())
+		assert.Nil(t, req.RedirectedTo())
+
+		evt := &network.EventRequestWillBeSent{
+			RequestID: network.RequestID("1234"),
+			Request:   &network.Request{URL: "https://test/get", Method: "GET"},
+			Timestamp: &ts,
+			WallTime:  &wt,
+		}
+		from := &Request{}
+		to, err := NewRequest(vu.Context(), evt, nil, []*Request{{}, from}, "intercept", false)
+		require.NoError(t, err)
+		assert.Same(t, from, to.RedirectedFrom())
+	})
 }
@@ -23,6 +23,7 @@ package common
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"sync"
@@ -34,6 +35,7 @@ import (
 
 	k6modules "go.k6.io/k6/js/modules"
 
+	"github.com/chromedp/cdproto"
 	"github.com/chromedp/cdproto/cdp"
 	"github.com/chromedp/cdproto/network"
 	"github.com/dop251/goja"
@@ -44,18 +46,22 @@ var _ api.Response = &Response{}
 
 // RemoteAddress contains informationa about a remote target.
 type RemoteAddress struct {
-	IPAddress string `json:"ipAddress"`
-	Port      int64  `json:"port"`
+	IPAddress        string `json:"ipAddress" js:"ipAddress"`
+	Port             int64  `json:"port" js:"port"`
+	ConnectionReused bool   `json:"connectionReused" js:"connectionReused"`
 }
 
 // SecurityDetails contains informationa about the security details of a TLS connection.
+// The validity dates are in seconds since the Unix epoch.
 type SecurityDetails struct {
-	SubjectName string   `json:"subjectName"`
-	Issuer      string   `json:"issuer"`
-	ValidFrom   int64    `json:"validFrom"`
-	ValidTo     int64    `json:"validTo"`
-	Protocol    string   `json:"protocol"`
-	SANList     []string `json:"sanList"`
+	SubjectName string   `json:"subjectName" js:"subjectName"`
+	Issuer      string   `json:"issuer" js:"issuer"`
+	ValidFrom   int64    `json:"validFrom" js:"validFrom"`
+	ValidTo     int64    `json:"validTo" js:"validTo"`
+	Protocol    string   `json:"protocol" js:"protocol"`
+	Cipher      string   `json:"cipher" js:"cipher"`
+	KeyExchange string   `json:"keyExchange" js:"keyExchange"`
+	SANList     []string `json:"sanList" js:"sanList"`
 }
 
 // Response represents a browser HTTP response.
@@ -79,8 +85,6 @@ type Response struct {
 	responseTime      time.Time
 	timing            *network.ResourceTiming
 	vu                k6modules.VU
-
-	cachedJSON interface{}
 }
 
 // NewHTTPResponse creates a new HTTP response.
@@ -91,9 +95,13 @@ func NewHTTPResponse(ctx context.Context, req *Request, resp *network.Response,
 		ctx: ctx,
 		// TODO: Pass an internal logger instead of basing it on k6's logger?
 		// See https://github.com/grafana/xk6-browser/issues/54
-		logger:            log.New(state.Logger, false, nil),
-		request:           req,
-		remoteAddress:     &RemoteAddress{IPAddress: resp.RemoteIPAddress, Port: resp.RemotePort},
+		logger:  log.New(state.Logger, false, nil),
+		request: req,
+		remoteAddress: &RemoteAddress{
+			IPAddress:        resp.RemoteIPAddress,
+			Port:             resp.RemotePort,
+			ConnectionReused: resp.ConnectionReused,
+		},
 		securityDetails:   nil,
 		protocol:          resp.Protocol,
 		url:               resp.URL,
@@ -132,6 +140,8 @@ func NewHTTPResponse(ctx context.Context, req *Request, resp *network.Response,
 			ValidFrom:   resp.SecurityDetails.ValidFrom.Time().Unix(),
 			ValidTo:     resp.SecurityDetails.ValidTo.Time().Unix(),
 			Protocol:    resp.SecurityDetails.Protocol,
+			Cipher:      resp.SecurityDetails.Cipher,
+			KeyExchange: resp.SecurityDetails.KeyExchange,
 			SANList:     resp.SecurityDetails.SanList,
 		}
 	}
@@ -149,9 +159,16 @@ func (r *Response) fetchBody() error {
 	if cached() {
 		return nil
 	}
+	// the body can only be fetched after it's fully received.
+	if err := r.request.waitForFinish(r.ctx); err != nil {
+		return fmt.Errorf("fetching response body: %w", err)
+	}
 	action := network.GetResponseBody(r.request.requestID)
 	body, err := action.Do(cdp.WithExecutor(r.ctx, r.request.frame.manager.session))
 	if err != nil {
+		if isEvictedBodyError(err) {
+			err = errors.New("response body is unavailable, it may have been evicted from the browser cache")
+		}
 		return fmt.Errorf("fetching response body: %w", err)
 	}
 	r.bodyMu.Lock()
@@ -160,6 +177,18 @@ func (r *Response) fetchBody() error {
 	return nil
 }
 
+// isEvictedBodyError reports whether the error of fetching a response body is
+// caused by the browser no longer keeping it, as it only keeps the bodies of
+// the recent responses.
+func isEvictedBodyError(err error) bool {
+	var cdpe *cdproto.Error
+	if !errors.As(err, &cdpe) || cdpe.Code != -32000 {
+		return false
+	}
+	return strings.HasPrefix(cdpe.Message, "No resource with given identifier found") ||
+		strings.HasPrefix(cdpe.Message, "No data found for resource with given identifier")
+}
+
 func (r *Response) headersSize() int64 {
 	size := 4 // 4 = 2 spaces + 2 line breaks (HTTP/1.1 200 OK\r\n)
 	size += 8 // httpVersion
@@ -181,18 +210,27 @@ func (r *Response) AllHeaders() map[string]string {
 	return headers
 }
 
-// Body returns the response body as a binary buffer.
-func (r *Response) Body() goja.ArrayBuffer {
+// readBody returns the response body, fetching it on the first call.
+func (r *Response) readBody() ([]byte, error) {
 	if r.status >= 300 && r.status <= 399 {
-		k6ext.Panic(r.ctx, "Response body is unavailable for redirect responses")
+		return nil, errors.New("response body is unavailable for redirect responses")
 	}
 	if err := r.fetchBody(); err != nil {
-		k6ext.Panic(r.ctx, "getting response body: %w", err)
+		return nil, err
 	}
 	r.bodyMu.RLock()
 	defer r.bodyMu.RUnlock()
+	return r.body, nil
+}
+
+// Body returns the response body as a binary buffer.
+func (r *Response) Body() goja.ArrayBuffer {
+	body, err := r.readBody()
+	if err != nil {
+		k6ext.Panic(r.ctx, "getting response body: %w", err)
+	}
 	rt := r.vu.Runtime()
-	return rt.NewArrayBuffer(r.body)
+	return rt.NewArrayBuffer(body)
 }
 
 // bodySize returns the size in bytes of the response body.
@@ -212,11 +250,14 @@ func (r *Response) bodySize() int64 {
 	return int64(len(r.body))
 }
 
-// Finished waits for response to finish, return error if request failed.
-func (r *Response) Finished() bool {
-	// TODO: should return nil|Error
-	k6ext.Panic(r.ctx, "Response.finished() has not been implemented yet")
-	return false
+// Finished waits for the response body to be fully received, and returns
+// null, or an error if the request failed.
+func (r *Response) Finished() goja.Value {
+	rt := r.vu.Runtime()
+	if err := r.request.waitForFinish(r.ctx); err != nil {
+		return rt.NewGoError(err)
+	}
+	return goja.Null()
 }
 
 // Frame returns the frame within which the response was received.
@@ -273,23 +314,19 @@ func (r *Response) HeadersArray() []api.HTTPHeader {
 	return headers
 }
 
-// JSON returns the response body as JSON data.
+// JSON returns the response body parsed as JSON. Each call returns a new
+// value, so changing it doesn't affect the values returned by later calls.
 func (r *Response) JSON() goja.Value {
-	if r.cachedJSON == nil {
-		if err := r.fetchBody(); err != nil {
-			k6ext.Panic(r.ctx, "getting response body: %w", err)
-		}
-
-		var v interface{}
-		r.bodyMu.RLock()
-		defer r.bodyMu.RUnlock()
-		if err := json.Unmarshal(r.body, &v); err != nil {
-			k6ext.Panic(r.ctx, "unmarshalling response body to JSON: %w", err)
-		}
-		r.cachedJSON = v
+	body, err := r.readBody()
+	if err != nil {
+		k6ext.Panic(r.ctx, "getting response body: %w", err)
+	}
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		k6ext.Panic(r.ctx, "unmarshalling response body to JSON: %w", err)
 	}
 	rt := r.vu.Runtime()
-	return rt.ToValue(r.cachedJSON)
+	return rt.ToValue(v)
 }
 
 // Ok returns true if status code of response if considered ok, otherwise returns false.
@@ -305,13 +342,24 @@ func (r *Response) Request() api.Request {
 	return r.request
 }
 
+// SecurityDetails returns the TLS details of the connection, or null if the
+// response wasn't received over a secure connection.
 func (r *Response) SecurityDetails() goja.Value {
+	if r.securityDetails == nil {
+		return goja.Null()
+	}
 	rt := r.vu.Runtime()
 	return rt.ToValue(r.securityDetails)
 }
 
-// ServerAddr returns the remote address of the server.
+// ServerAddr returns the IP address and port of the server that served the
+// response, and whether the connection to it was reused. It returns null if
+// the response was served from a cache or by a service worker.
 func (r *Response) ServerAddr() goja.Value {
+	if r.fromDiskCache || r.request.fromMemoryCache || r.fromPrefetchCache || r.fromServiceWorker ||
+		r.remoteAddress.IPAddress == "" {
+		return goja.Null()
+	}
 	rt := r.vu.Runtime()
 	return rt.ToValue(r.remoteAddress)
 }
@@ -335,12 +383,11 @@ func (r *Response) StatusText() string {
 
 // Text returns the response body as a string.
 func (r *Response) Text() string {
-	if err := r.fetchBody(); err != nil {
+	body, err := r.readBody()
+	if err != nil {
 		k6ext.Panic(r.ctx, "getting response body as text: %w", err)
 	}
-	r.bodyMu.RLock()
-	defer r.bodyMu.RUnlock()
-	return string(r.body)
+	return string(body)
 }
 
 // URL returns the request URL.
@@ -0,0 +1,50 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/chromedp/cdproto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsEvictedBodyError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "evicted",
+			err:  &cdproto.Error{Code: -32000, Message: "No resource with given identifier found"},
+			want: true,
+		},
+		{
+			name: "no_data",
+			err:  fmt.Errorf("wrapped: %w", &cdproto.Error{Code: -32000, Message: "No data found for resource with given identifier"}),
+			want: true,
+		},
+		{
+			name: "other_server_error",
+			err:  &cdproto.Error{Code: -32000, Message: "Target closed"},
+		},
+		{
+			name: "other_code",
+			err:  &cdproto.Error{Code: -32602, Message: "No resource with given identifier found"},
+		},
+		{
+			name: "not_cdp",
+			err:  errors.New("No resource with given identifier found"),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, isEvictedBodyError(tt.err))
+		})
+	}
+}
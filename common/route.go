@@ -0,0 +1,287 @@
+package common
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/grafana/xk6-browser/api"
+	"github.com/grafana/xk6-browser/k6ext"
+	"github.com/grafana/xk6-browser/log"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/fetch"
+	"github.com/chromedp/cdproto/network"
+	"github.com/dop251/goja"
+)
+
+// Ensure Route implements the api.Route interface.
+var _ api.Route = &Route{}
+
+// errorReasons maps the Playwright compatible abort error codes to their
+// protocol counterparts.
+var errorReasons = map[string]network.ErrorReason{ //nolint:gochecknoglobals
+	"aborted":              network.ErrorReasonAborted,
+	"accessdenied":         network.ErrorReasonAccessDenied,
+	"addressunreachable":   network.ErrorReasonAddressUnreachable,
+	"blockedbyclient":      network.ErrorReasonBlockedByClient,
+	"blockedbyresponse":    network.ErrorReasonBlockedByResponse,
+	"connectionaborted":    network.ErrorReasonConnectionAborted,
+	"connectionclosed":     network.ErrorReasonConnectionClosed,
+	"connectionfailed":     network.ErrorReasonConnectionFailed,
+	"connectionrefused":    network.ErrorReasonConnectionRefused,
+	"connectionreset":      network.ErrorReasonConnectionReset,
+	"internetdisconnected": network.ErrorReasonInternetDisconnected,
+	"namenotresolved":      network.ErrorReasonNameNotResolved,
+	"timedout":             network.ErrorReasonTimedOut,
+	"failed":               network.ErrorReasonFailed,
+}
+
+// Route allows to handle an intercepted request by aborting,
+// continuing or fulfilling it.
+type Route struct {
+	ctx       context.Context
+	session   session
+	request   *Request
+	requestID fetch.RequestID
+	handled   bool
+
+	logger *log.Logger
+}
+
+// NewRoute returns a new route for the paused request.
+func NewRoute(
+	ctx context.Context, s session, req *Request, id fetch.RequestID, l *log.Logger,
+) *Route {
+	return &Route{
+		ctx:       ctx,
+		session:   s,
+		request:   req,
+		requestID: id,
+		logger:    l,
+	}
+}
+
+func (r *Route) isHandled() bool {
+	return r.handled
+}
+
+func (r *Route) startHandling() error {
+	if r.handled {
+		return fmt.Errorf("route for %q is already handled", r.request.URL())
+	}
+	r.handled = true
+	return nil
+}
+
+// Abort aborts the route's request with the given error code.
+// It defaults to "failed" when no error code is given.
+func (r *Route) Abort(errorCode string) {
+	r.logger.Debugf("Route:Abort", "url:%q code:%q", r.request.URL(), errorCode)
+
+	if errorCode == "" {
+		errorCode = "failed"
+	}
+	reason, ok := errorReasons[strings.ToLower(errorCode)]
+	if !ok {
+		k6ext.Panic(r.ctx, "aborting request: unknown error code %q", errorCode)
+	}
+	if err := r.abort(reason); err != nil {
+		k6ext.Panic(r.ctx, "aborting request: %w", err)
+	}
+}
+
+func (r *Route) abort(reason network.ErrorReason) error {
+	if err := r.startHandling(); err != nil {
+		return err
+	}
+	action := fetch.FailRequest(r.requestID, reason)
+	return action.Do(cdp.WithExecutor(r.ctx, r.session))
+}
+
+// Continue continues the route's request with optional overrides.
+func (r *Route) Continue(opts goja.Value) {
+	r.logger.Debugf("Route:Continue", "url:%q", r.request.URL())
+
+	copts := NewRouteContinueOptions()
+	if err := copts.Parse(r.ctx, opts); err != nil {
+		k6ext.Panic(r.ctx, "parsing continue options: %w", err)
+	}
+	if err := r.startHandling(); err != nil {
+		k6ext.Panic(r.ctx, "continuing request: %w", err)
+	}
+	if err := r.continueRequest(copts); err != nil {
+		k6ext.Panic(r.ctx, "continuing request: %w", err)
+	}
+}
+
+func (r *Route) continueRequest(opts *RouteContinueOptions) error {
+	action := fetch.ContinueRequest(r.requestID)
+	if opts.URL != "" {
+		action = action.WithURL(opts.URL)
+	}
+	if opts.Method != "" {
+		action = action.WithMethod(opts.Method)
+	}
+	if opts.Headers != nil {
+		action = action.WithHeaders(toHeaderEntries(opts.Headers))
+	}
+	if opts.PostData != nil {
+		action = action.WithPostData(base64.StdEncoding.EncodeToString(opts.PostData))
+	}
+	return action.Do(cdp.WithExecutor(r.ctx, r.session))
+}
+
+// Fulfill fulfills the route's request with the given response.
+func (r *Route) Fulfill(opts goja.Value) {
+	r.logger.Debugf("Route:Fulfill", "url:%q", r.request.URL())
+
+	fopts := NewRouteFulfillOptions()
+	if err := fopts.Parse(r.ctx, opts); err != nil {
+		k6ext.Panic(r.ctx, "parsing fulfill options: %w", err)
+	}
+	// The header names are case insensitive, so they're lowercased for the
+	// content type to override any content-type header.
+	headers := make(map[string]string, len(fopts.Headers)+1)
+	for k, v := range fopts.Headers {
+		headers[strings.ToLower(k)] = v
+	}
+	if fopts.ContentType != "" {
+		headers["content-type"] = fopts.ContentType
+	}
+	if err := r.fulfill(fopts.Status, toHeaderEntries(headers), fopts.Body); err != nil {
+		k6ext.Panic(r.ctx, "fulfilling request: %w", err)
+	}
+}
+
+func (r *Route) fulfill(status int64, headers []*fetch.HeaderEntry, body []byte) error {
+	if err := r.startHandling(); err != nil {
+		return err
+	}
+	sort.SliceStable(headers, func(i, j int) bool {
+		return headers[i].Name < headers[j].Name
+	})
+	action := fetch.FulfillRequest(r.requestID, status).
+		WithResponseHeaders(headers).
+		WithBody(base64.StdEncoding.EncodeToString(body))
+	if text := http.StatusText(int(status)); text != "" {
+		action = action.WithResponsePhrase(text)
+	}
+	return action.Do(cdp.WithExecutor(r.ctx, r.session))
+}
+
+// Request returns the route's request.
+func (r *Route) Request() api.Request {
+	return r.request
+}
+
+// toHeaderEntries converts the headers map to a sorted list of header entries.
+func toHeaderEntries(headers map[string]string) []*fetch.HeaderEntry {
+	entries := make([]*fetch.HeaderEntry, 0, len(headers))
+	for n, v := range headers {
+		entries = append(entries, &fetch.HeaderEntry{Name: n, Value: v})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name < entries[j].Name
+	})
+	return entries
+}
+
+// routeHandler is a user registered handler for the routes matching a URL.
+type routeHandler struct {
+	url     goja.Value
+	matcher urlMatcher
+	fn      goja.Value
+	handler goja.Callable
+	// native handles the routes instead of handler if it's set.
+	native func(*Route) error
+}
+
+func newRouteHandler(rt *goja.Runtime, url goja.Value, handler goja.Value) (*routeHandler, error) {
+	fn, ok := goja.AssertFunction(handler)
+	if !ok {
+		return nil, fmt.Errorf("route handler must be a function")
+	}
+	matcher, err := newURLMatcher(rt, url)
+	if err != nil {
+		return nil, err
+	}
+	return &routeHandler{
+		url:     url,
+		matcher: matcher,
+		fn:      handler,
+		handler: fn,
+	}, nil
+}
+
+// newHARRouteHandler returns a handler that serves the routes matching the
+// url from the HAR router.
+func newHARRouteHandler(url goja.Value, router *harRouter) (*routeHandler, error) {
+	matcher, err := newNativeURLMatcher(url)
+	if err != nil {
+		return nil, err
+	}
+	return &routeHandler{
+		url:     url,
+		matcher: matcher,
+		native:  router.route,
+	}, nil
+}
+
+// equals returns true if the handler was registered with the given url and
+// handler function. A missing handler function matches any handler.
+func (h *routeHandler) equals(url goja.Value, handler goja.Value) bool {
+	if !h.url.StrictEquals(url) {
+		return false
+	}
+	return !gojaValueExists(handler) || (h.fn != nil && h.fn.StrictEquals(handler))
+}
+
+// handle calls the handler with the route if the route's URL matches. Only
+// the native handlers can be called without the goja runtime, and the other
+// ones must be called on the event loop of the VU.
+func (h *routeHandler) handle(rt *goja.Runtime, r *Route) error {
+	ok, err := h.matcher(r.request.URL())
+	if err != nil || !ok {
+		return err
+	}
+	if h.native != nil {
+		return h.native(r)
+	}
+	if _, err := h.handler(goja.Undefined(), rt.ToValue(r)); err != nil {
+		return fmt.Errorf("calling route handler for %q: %w", r.request.URL(), err)
+	}
+	return nil
+}
+
+// isNative returns true if the handler doesn't use the goja runtime.
+func (h *routeHandler) isNative() bool {
+	return h.native != nil
+}
+
+// handleRoute runs the route through the handlers in their order, until one
+// of them handles it. It returns true if the route is handled.
+func handleRoute(rt *goja.Runtime, handlers []*routeHandler, r *Route) (bool, error) {
+	for _, h := range handlers {
+		if err := h.handle(rt, r); err != nil {
+			return r.isHandled(), err
+		}
+		if r.isHandled() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// reverseRouteHandlers returns the handlers in reverse registration order,
+// which is the order they handle the routes in.
+func reverseRouteHandlers(handlers []*routeHandler) []*routeHandler {
+	reversed := make([]*routeHandler, 0, len(handlers))
+	for i := len(handlers) - 1; i >= 0; i-- {
+		reversed = append(reversed, handlers[i])
+	}
+	return reversed
+}
@@ -0,0 +1,206 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/grafana/xk6-browser/k6ext"
+
+	"github.com/dop251/goja"
+)
+
+// RouteContinueOptions are the request overrides used by Route.Continue.
+type RouteContinueOptions struct {
+	URL      string            `json:"url"`
+	Method   string            `json:"method"`
+	Headers  map[string]string `json:"headers"`
+	PostData []byte            `json:"postData"`
+}
+
+// RouteFulfillOptions describe the response used by Route.Fulfill.
+type RouteFulfillOptions struct {
+	Status      int64             `json:"status"`
+	Headers     map[string]string `json:"headers"`
+	ContentType string            `json:"contentType"`
+	Body        []byte            `json:"body"`
+}
+
+// NewRouteContinueOptions returns a new RouteContinueOptions.
+func NewRouteContinueOptions() *RouteContinueOptions {
+	return &RouteContinueOptions{}
+}
+
+// Parse parses the route continue options.
+func (o *RouteContinueOptions) Parse(ctx context.Context, opts goja.Value) error {
+	if !gojaValueExists(opts) {
+		return nil
+	}
+	rt := k6ext.Runtime(ctx)
+	obj := opts.ToObject(rt)
+	for _, k := range obj.Keys() {
+		v := obj.Get(k)
+		switch k {
+		case "url":
+			o.URL = v.String()
+		case "method":
+			o.Method = strings.ToUpper(v.String())
+		case "headers":
+			o.Headers = parseStringMap(rt, v)
+		case "postData":
+			b, err := parseBytes(v)
+			if err != nil {
+				return fmt.Errorf("parsing postData: %w", err)
+			}
+			o.PostData = b
+		}
+	}
+	return nil
+}
+
+// NewRouteFulfillOptions returns a new RouteFulfillOptions.
+func NewRouteFulfillOptions() *RouteFulfillOptions {
+	return &RouteFulfillOptions{
+		Status: 200,
+	}
+}
+
+// Parse parses the route fulfill options.
+func (o *RouteFulfillOptions) Parse(ctx context.Context, opts goja.Value) error {
+	if !gojaValueExists(opts) {
+		return nil
+	}
+	rt := k6ext.Runtime(ctx)
+	obj := opts.ToObject(rt)
+	for _, k := range obj.Keys() {
+		v := obj.Get(k)
+		switch k {
+		case "status":
+			o.Status = v.ToInteger()
+		case "headers":
+			o.Headers = parseStringMap(rt, v)
+		case "contentType":
+			o.ContentType = v.String()
+		case "body":
+			b, err := parseBytes(v)
+			if err != nil {
+				return fmt.Errorf("parsing body: %w", err)
+			}
+			o.Body = b
+		}
+	}
+	return nil
+}
+
+// parseStringMap converts a JS object into a map of strings.
+func parseStringMap(rt *goja.Runtime, v goja.Value) map[string]string {
+	m := make(map[string]string)
+	if !gojaValueExists(v) {
+		return m
+	}
+	obj := v.ToObject(rt)
+	for _, k := range obj.Keys() {
+		m[k] = obj.Get(k).String()
+	}
+	return m
+}
+
+// parseBytes converts a string or an ArrayBuffer value into bytes.
+func parseBytes(v goja.Value) ([]byte, error) {
+	if !gojaValueExists(v) {
+		return nil, nil
+	}
+	switch e := v.Export().(type) {
+	case string:
+		return []byte(e), nil
+	case goja.ArrayBuffer:
+		return e.Bytes(), nil
+	case []byte:
+		return e, nil
+	default:
+		return nil, fmt.Errorf("unsupported type %T, want a string or an ArrayBuffer", e)
+	}
+}
+
+// HARNotFound is the policy for the requests that don't match any entry of
+// the HAR file used by routeFromHAR.
+type HARNotFound string
+
+const (
+	// HARNotFoundAbort aborts the requests that aren't in the HAR file.
+	HARNotFoundAbort HARNotFound = "abort"
+	// HARNotFoundFallback passes the requests that aren't in the HAR file to
+	// the next route handler, or to the network.
+	HARNotFoundFallback HARNotFound = "fallback"
+)
+
+// HARQueryMatch is the strategy for matching the query strings of the
+// requests against the query strings of the HAR entries.
+type HARQueryMatch string
+
+const (
+	// HARQueryMatchExact requires the query strings to be identical.
+	HARQueryMatchExact HARQueryMatch = "exact"
+	// HARQueryMatchUnordered requires the same query parameters, in any order.
+	HARQueryMatchUnordered HARQueryMatch = "unordered"
+	// HARQueryMatchIgnore ignores the query strings.
+	HARQueryMatchIgnore HARQueryMatch = "ignore"
+)
+
+// RouteFromHAROptions are the options used by routeFromHAR.
+type RouteFromHAROptions struct {
+	// NotFound is the policy for the requests that aren't in the HAR file.
+	NotFound HARNotFound `js:"notFound"`
+	// Update records the network activity to the HAR file instead of
+	// serving the responses from it.
+	Update bool `js:"update"`
+	// URL limits the requests served from the HAR file to the matching ones.
+	// It's a glob pattern or a RegExp, since the requests are matched outside
+	// of the event loop.
+	URL goja.Value `js:"url"`
+	// Query is the strategy for matching the query strings.
+	Query HARQueryMatch `js:"query"`
+}
+
+// NewRouteFromHAROptions returns a new RouteFromHAROptions.
+func NewRouteFromHAROptions() *RouteFromHAROptions {
+	return &RouteFromHAROptions{
+		NotFound: HARNotFoundAbort,
+		URL:      goja.Undefined(),
+		Query:    HARQueryMatchExact,
+	}
+}
+
+// Parse parses the routeFromHAR options.
+func (o *RouteFromHAROptions) Parse(ctx context.Context, opts goja.Value) error {
+	if !gojaValueExists(opts) {
+		return nil
+	}
+	rt := k6ext.Runtime(ctx)
+	obj := opts.ToObject(rt)
+	for _, k := range obj.Keys() {
+		v := obj.Get(k)
+		switch k {
+		case "notFound":
+			switch nf := HARNotFound(v.String()); nf {
+			case HARNotFoundAbort, HARNotFoundFallback:
+				o.NotFound = nf
+			default:
+				return fmt.Errorf("invalid notFound %q, must be %q or %q", nf, HARNotFoundAbort, HARNotFoundFallback)
+			}
+		case "update":
+			o.Update = v.ToBoolean()
+		case "url":
+			o.URL = v
+		case "query":
+			switch q := HARQueryMatch(v.String()); q {
+			case HARQueryMatchExact, HARQueryMatchUnordered, HARQueryMatchIgnore:
+				o.Query = q
+			default:
+				return fmt.Errorf("invalid query %q, must be %q, %q or %q",
+					q, HARQueryMatchExact, HARQueryMatchUnordered, HARQueryMatchIgnore)
+			}
+		}
+	}
+	return nil
+}
@@ -29,6 +29,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/chromedp/cdproto/cdp"
 	"github.com/chromedp/cdproto/emulation"
@@ -36,6 +37,10 @@ import (
 	"github.com/dop251/goja"
 )
 
+// disabledAnimationsStyleID is the ID of the style element that pauses the
+// animations while taking a screenshot.
+const disabledAnimationsStyleID = "__xk6_browser_disabled_animations"
+
 type screenshotter struct {
 	ctx context.Context
 }
@@ -44,42 +49,23 @@ func newScreenshotter(ctx context.Context) *screenshotter {
 	return &screenshotter{ctx}
 }
 
+// fullPageSize returns the size of the page's content in CSS pixels.
 func (s *screenshotter) fullPageSize(p *Page) (*Size, error) {
-	rt := p.vu.Runtime()
-	opts := evalOptions{
-		forceCallable: true,
-		returnByValue: true,
-	}
-	result, err := p.frameManager.MainFrame().evaluate(s.ctx, mainWorld, opts, rt.ToValue(`
-        () => {
-            if (!document.body || !document.documentElement) {
-                return null;
-            }
-            return {
-                width: Math.max(
-                    document.body.scrollWidth, document.documentElement.scrollWidth,
-                    document.body.offsetWidth, document.documentElement.offsetWidth,
-                    document.body.clientWidth, document.documentElement.clientWidth
-                ),
-                height: Math.max(
-                    document.body.scrollHeight, document.documentElement.scrollHeight,
-                    document.body.offsetHeight, document.documentElement.offsetHeight,
-                    document.body.clientHeight, document.documentElement.clientHeight
-                ),
-            };
-        }`))
+	//nolint:dogsled
+	_, _, contentSize, _, _, cssContentSize, err := cdppage.GetLayoutMetrics().Do(cdp.WithExecutor(s.ctx, p.session))
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("getting layout metrics: %w", err)
 	}
-	v, ok := result.(goja.Value)
-	if !ok {
-		return nil, fmt.Errorf("unexpected type %T", result)
+	if cssContentSize != nil {
+		contentSize = cssContentSize
+	}
+	if contentSize == nil {
+		return nil, errors.New("getting layout metrics: missing content size")
 	}
-	o := v.ToObject(rt)
 
 	return &Size{
-		Width:  o.Get("width").ToFloat(),
-		Height: o.Get("height").ToFloat(),
+		Width:  math.Ceil(contentSize.Width),
+		Height: math.Ceil(contentSize.Height),
 	}, nil
 }
 
@@ -102,10 +88,11 @@ func (s *screenshotter) originalViewportSize(p *Page) (*Size, *Size, error) {
 	if err != nil {
 		return nil, nil, fmt.Errorf("getting viewport dimensions: %w", err)
 	}
-	r, ok := result.(goja.Object)
+	v, ok := result.(goja.Value)
 	if !ok {
-		return nil, nil, fmt.Errorf("cannot convert to goja object: %w", err)
+		return nil, nil, fmt.Errorf("getting viewport dimensions: unexpected type %T", result)
 	}
+	r := v.ToObject(rt)
 	viewportSize.Width = r.Get("width").ToFloat()
 	viewportSize.Height = r.Get("height").ToFloat()
 	return &viewportSize, &originalViewportSize, nil
@@ -121,6 +108,7 @@ func (s *screenshotter) restoreViewport(p *Page, originalViewport *Size) error {
 //nolint:funlen,cyclop
 func (s *screenshotter) screenshot(
 	sess session, doc, viewport *Rect, format ImageFormat, omitBackground bool, quality int64, path string,
+	scale float64,
 ) (*[]byte, error) {
 	var (
 		buf  []byte
@@ -167,9 +155,8 @@ func (s *screenshotter) screenshot(
 		}
 	}
 
-	scale := 1.0
 	if viewport != nil {
-		scale = visualViewport.Scale
+		scale *= visualViewport.Scale
 	}
 	clip = &cdppage.Viewport{
 		X:      doc.X,
@@ -181,6 +168,11 @@ func (s *screenshotter) screenshot(
 	if clip.Width > 0 && clip.Height > 0 {
 		capture = capture.WithClip(clip)
 	}
+	if doc != nil {
+		// The document rectangle can be out of the viewport, such as
+		// for the full page screenshots.
+		capture = capture.WithCaptureBeyondViewport(true)
+	}
 
 	// Capture screenshot
 	buf, err = capture.Do(cdp.WithExecutor(s.ctx, sess))
@@ -210,78 +202,115 @@ func (s *screenshotter) screenshot(
 	return &buf, nil
 }
 
-func (s *screenshotter) screenshotElement(h *ElementHandle, opts *ElementHandleScreenshotOptions) (*[]byte, error) {
+//nolint:funlen,cyclop
+func (s *screenshotter) screenshotElement(h *ElementHandle, opts *ElementHandleScreenshotOptions) (_ *[]byte, err error) {
+	p := h.frame.page
 	format := opts.Format
-	viewportSize, originalViewportSize, err := s.originalViewportSize(h.frame.page)
+	viewportSize, originalViewportSize, err := s.originalViewportSize(p)
 	if err != nil {
 		return nil, fmt.Errorf("getting original viewport size: %w", err)
 	}
 
-	err = h.waitAndScrollIntoViewIfNeeded(h.ctx, false, true, opts.Timeout)
-	if err != nil {
-		return nil, fmt.Errorf("scrolling element into view: %w", err)
+	if opts.Animations == ScreenshotAnimationsDisabled {
+		if err := s.disableAnimations(p); err != nil {
+			return nil, err
+		}
+		defer func() {
+			if rerr := s.restoreAnimations(p); rerr != nil && err == nil {
+				err = rerr
+			}
+		}()
 	}
 
-	bbox, err := h.boundingBox()
+	bbox, err := s.elementBoundingBox(h, opts.Timeout)
 	if err != nil {
-		return nil, fmt.Errorf("node is either not visible or not an HTMLElement: %w", err)
-	}
-	if bbox.Width <= 0 {
-		return nil, fmt.Errorf("node has 0 width")
-	}
-	if bbox.Height <= 0 {
-		return nil, fmt.Errorf("node has 0 height")
+		return nil, err
 	}
 
-	var overriddenViewportSize *Size
 	fitsViewport := bbox.Width <= viewportSize.Width && bbox.Height <= viewportSize.Height
 	if !fitsViewport {
-		overriddenViewportSize = Size{
+		overriddenViewportSize := Size{
 			Width:  math.Max(viewportSize.Width, bbox.Width),
 			Height: math.Max(viewportSize.Height, bbox.Height),
 		}.enclosingIntSize()
-		if err := h.frame.page.setViewportSize(overriddenViewportSize); err != nil {
+		if err := p.setViewportSize(overriddenViewportSize); err != nil {
 			return nil, fmt.Errorf("setting viewport size to %s: %w",
 				overriddenViewportSize, err)
 		}
-		err = h.waitAndScrollIntoViewIfNeeded(h.ctx, false, true, opts.Timeout)
-		if err != nil {
-			return nil, fmt.Errorf("scrolling element into view: %w", err)
-		}
-		bbox, err = h.boundingBox()
-		if err != nil {
-			return nil, fmt.Errorf("node is either not visible or not an HTMLElement: %w", err)
-		}
-		if bbox.Width <= 0 {
-			return nil, fmt.Errorf("node has 0 width")
-		}
-		if bbox.Height <= 0 {
-			return nil, fmt.Errorf("node has 0 height")
+		defer func() {
+			if rerr := s.restoreViewport(p, originalViewportSize); rerr != nil && err == nil {
+				err = fmt.Errorf("restoring viewport: %w", rerr)
+			}
+		}()
+		if bbox, err = s.elementBoundingBox(h, opts.Timeout); err != nil {
+			return nil, err
 		}
 	}
 
+	// The bounding box is relative to the viewport, while the clip
+	// is relative to the document.
+	scrollOffset, err := s.scrollOffset(p)
+	if err != nil {
+		return nil, err
+	}
 	documentRect := bbox
-	rt := h.execCtx.vu.Runtime()
-	scrollOffset := h.Evaluate(rt.ToValue(`() => { return {x: window.scrollX, y: window.scrollY};}`))
-	switch s := scrollOffset.(type) {
-	case goja.Value:
-		documentRect.X += s.ToObject(rt).Get("x").ToFloat()
-		documentRect.Y += s.ToObject(rt).Get("y").ToFloat()
+	documentRect.X += scrollOffset.X
+	documentRect.Y += scrollOffset.Y
+
+	return s.screenshot(
+		p.session, documentRect.enclosingIntRect(), nil, format,
+		opts.OmitBackground, opts.Quality, opts.Path, s.scale(p, opts.Scale),
+	)
+}
+
+// elementBoundingBox scrolls the element into view and returns its
+// bounding box relative to the main frame's viewport.
+func (s *screenshotter) elementBoundingBox(h *ElementHandle, timeout time.Duration) (*Rect, error) {
+	err := h.waitAndScrollIntoViewIfNeeded(h.ctx, false, true, timeout)
+	if err != nil {
+		return nil, fmt.Errorf("scrolling element into view: %w", err)
+	}
+	bbox, err := h.boundingBox()
+	if err != nil {
+		return nil, fmt.Errorf("node is either not visible or not an HTMLElement: %w", err)
+	}
+	if bbox.Width <= 0 {
+		return nil, errors.New("node has 0 width")
+	}
+	if bbox.Height <= 0 {
+		return nil, errors.New("node has 0 height")
 	}
+	return bbox, nil
+}
 
-	buf, err := s.screenshot(h.frame.page.session, documentRect.enclosingIntRect(), nil, format, opts.OmitBackground, opts.Quality, opts.Path)
+// scrollOffset returns the scroll position of the page's main frame.
+func (s *screenshotter) scrollOffset(p *Page) (*Position, error) {
+	rt := p.vu.Runtime()
+	opts := evalOptions{
+		forceCallable: true,
+		returnByValue: true,
+	}
+	result, err := p.frameManager.MainFrame().evaluate(s.ctx, mainWorld, opts, rt.ToValue(`
+	() => (
+		{ x: window.scrollX, y: window.scrollY }
+	)`))
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("getting scroll offset: %w", err)
 	}
-	if overriddenViewportSize != nil {
-		if err := s.restoreViewport(h.frame.page, originalViewportSize); err != nil {
-			return nil, fmt.Errorf("restoring viewport: %w", err)
-		}
+	v, ok := result.(goja.Value)
+	if !ok {
+		return nil, fmt.Errorf("getting scroll offset: unexpected type %T", result)
 	}
-	return buf, nil
+	o := v.ToObject(rt)
+
+	return &Position{
+		X: o.Get("x").ToFloat(),
+		Y: o.Get("y").ToFloat(),
+	}, nil
 }
 
-func (s *screenshotter) screenshotPage(p *Page, opts *PageScreenshotOptions) (*[]byte, error) {
+//nolint:funlen,cyclop
+func (s *screenshotter) screenshotPage(p *Page, opts *PageScreenshotOptions) (_ *[]byte, err error) {
 	format := opts.Format
 
 	// Infer file format by path
@@ -296,6 +325,19 @@ func (s *screenshotter) screenshotPage(p *Page, opts *PageScreenshotOptions) (*[
 		return nil, fmt.Errorf("getting original viewport size: %w", err)
 	}
 
+	if opts.Animations == ScreenshotAnimationsDisabled {
+		if err := s.disableAnimations(p); err != nil {
+			return nil, err
+		}
+		defer func() {
+			if rerr := s.restoreAnimations(p); rerr != nil && err == nil {
+				err = rerr
+			}
+		}()
+	}
+
+	scale := s.scale(p, opts.Scale)
+
 	if opts.FullPage {
 		fullPageSize, err := s.fullPageSize(p)
 		if err != nil {
@@ -307,14 +349,16 @@ func (s *screenshotter) screenshotPage(p *Page, opts *PageScreenshotOptions) (*[
 			Width:  fullPageSize.Width,
 			Height: fullPageSize.Height,
 		}
-		var overriddenViewportSize *Size
 		fitsViewport := fullPageSize.Width <= viewportSize.Width && fullPageSize.Height <= viewportSize.Height
 		if !fitsViewport {
-			overriddenViewportSize = fullPageSize
-			if err := p.setViewportSize(overriddenViewportSize); err != nil {
-				return nil, fmt.Errorf("setting viewport size to %s: %w",
-					overriddenViewportSize, err)
+			if err := p.setViewportSize(fullPageSize); err != nil {
+				return nil, fmt.Errorf("setting viewport size to %s: %w", fullPageSize, err)
 			}
+			defer func() {
+				if rerr := s.restoreViewport(p, originalViewportSize); rerr != nil && err == nil {
+					err = fmt.Errorf("restoring viewport to %s: %w", originalViewportSize, rerr)
+				}
+			}()
 		}
 		if opts.Clip != nil {
 			documentRect, err = s.trimClipToSize(&Rect{
@@ -328,17 +372,7 @@ func (s *screenshotter) screenshotPage(p *Page, opts *PageScreenshotOptions) (*[
 			}
 		}
 
-		buf, err := s.screenshot(p.session, documentRect, nil, format, opts.OmitBackground, opts.Quality, opts.Path)
-		if err != nil {
-			return nil, err
-		}
-		if overriddenViewportSize != nil {
-			if err := s.restoreViewport(p, originalViewportSize); err != nil {
-				return nil, fmt.Errorf("restoring viewport to %s: %w",
-					originalViewportSize, err)
-			}
-		}
-		return buf, nil
+		return s.screenshot(p.session, documentRect, nil, format, opts.OmitBackground, opts.Quality, opts.Path, scale)
 	}
 
 	viewportRect := &Rect{
@@ -358,7 +392,74 @@ func (s *screenshotter) screenshotPage(p *Page, opts *PageScreenshotOptions) (*[
 			return nil, fmt.Errorf("trimming clip to size: %w", err)
 		}
 	}
-	return s.screenshot(p.session, nil, viewportRect, format, opts.OmitBackground, opts.Quality, opts.Path)
+	return s.screenshot(p.session, nil, viewportRect, format, opts.OmitBackground, opts.Quality, opts.Path, scale)
+}
+
+// scale returns the capture scale for the screenshot scale option.
+// The "device" scale captures a pixel per device pixel, and the "css" scale
+// captures a pixel per CSS pixel.
+func (s *screenshotter) scale(p *Page, scale ScreenshotScale) float64 {
+	if scale != ScreenshotScaleCSS {
+		return 1
+	}
+	if p.browserCtx == nil || p.browserCtx.opts == nil || p.browserCtx.opts.DeviceScaleFactor <= 0 {
+		return 1
+	}
+	return 1 / p.browserCtx.opts.DeviceScaleFactor
+}
+
+// disableAnimations finishes the finite animations, cancels the infinite
+// ones and pauses the new ones until restoreAnimations is called.
+func (s *screenshotter) disableAnimations(p *Page) error {
+	rt := p.vu.Runtime()
+	opts := evalOptions{
+		forceCallable: true,
+		returnByValue: true,
+	}
+	js := `(id) => {
+		const style = document.createElement('style');
+		style.id = id;
+		style.textContent = '*, *::before, *::after {' +
+			'animation-play-state: paused !important;' +
+			'transition: none !important;' +
+			'caret-color: transparent !important; }';
+		(document.head || document.documentElement).appendChild(style);
+		for (const animation of document.getAnimations()) {
+			const { endTime } = animation.effect ? animation.effect.getComputedTiming() : {};
+			if (Number.isFinite(endTime)) {
+				animation.finish();
+			} else {
+				animation.cancel();
+			}
+		}
+	}`
+	_, err := p.frameManager.MainFrame().evaluate(
+		s.ctx, mainWorld, opts, rt.ToValue(js), rt.ToValue(disabledAnimationsStyleID))
+	if err != nil {
+		return fmt.Errorf("disabling animations: %w", err)
+	}
+	return nil
+}
+
+// restoreAnimations lets the animations disabled by disableAnimations run.
+func (s *screenshotter) restoreAnimations(p *Page) error {
+	rt := p.vu.Runtime()
+	opts := evalOptions{
+		forceCallable: true,
+		returnByValue: true,
+	}
+	js := `(id) => {
+		const style = document.getElementById(id);
+		if (style) {
+			style.remove();
+		}
+	}`
+	_, err := p.frameManager.MainFrame().evaluate(
+		s.ctx, mainWorld, opts, rt.ToValue(js), rt.ToValue(disabledAnimationsStyleID))
+	if err != nil {
+		return fmt.Errorf("restoring animations: %w", err)
+	}
+	return nil
 }
 
 func (s *screenshotter) trimClipToSize(clip *Rect, size *Size) (*Rect, error) {
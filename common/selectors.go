@@ -37,9 +37,13 @@
 package common
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"regexp"
 	"strings"
+
+	"github.com/dop251/goja"
 )
 
 // Matches `name:body`, a query engine name and selector for that engine.
@@ -51,6 +55,11 @@ var reXPathSelector *regexp.Regexp = regexp.MustCompile(`^\(*//`)
 type SelectorPart struct {
 	Name string `json:"name"`
 	Body string `json:"body"`
+
+	// Nested is the parsed inner selector of the selectors that combine
+	// elements with an inner selector: "internal:has", "internal:and" and
+	// "internal:or".
+	Nested *Selector `json:"nested,omitempty"`
 }
 
 type Selector struct {
@@ -70,9 +79,33 @@ func NewSelector(selector string) (*Selector, error) {
 		Capture:  nil,
 	}
 	err := s.parse()
+	if err == nil {
+		err = s.parseNested()
+	}
 	return &s, err
 }
 
+// parseNested parses the inner selectors of the parts that have one.
+func (s *Selector) parseNested() error {
+	for _, p := range s.Parts {
+		switch p.Name {
+		case "internal:has", "internal:and", "internal:or":
+		default:
+			continue
+		}
+		var inner string
+		if err := json.Unmarshal([]byte(p.Body), &inner); err != nil {
+			return fmt.Errorf("parsing %s selector %s: %w", p.Name, p.Body, err)
+		}
+		nested, err := NewSelector(inner)
+		if err != nil {
+			return err
+		}
+		p.Nested = nested
+	}
+	return nil
+}
+
 func (s *Selector) appendPart(p *SelectorPart, capture bool) error {
 	s.Parts = append(s.Parts, p)
 	if capture {
@@ -163,3 +196,98 @@ func (s *Selector) parse() error {
 	}
 	return nil
 }
+
+// textSelectorBody returns the body of a text matching selector for the
+// string or RegExp value. Strings match case-insensitive substrings of the
+// whitespace normalized text, or the whole text if exact is true.
+func textSelectorBody(v goja.Value, exact bool) (string, error) {
+	if obj, ok := v.(*goja.Object); ok && obj.ClassName() == "RegExp" {
+		// Escape the quotes so that they don't interfere with splitting
+		// the selector parts. The injected script unescapes them, as the
+		// escapes aren't valid with the u and v flags.
+		source, flags := obj.Get("source").String(), obj.Get("flags").String()
+		return "/" + escapeQuotes(source) + "/" + flags, nil
+	}
+	if !gojaValueExists(v) {
+		return "", errors.New("text must be a string or a RegExp")
+	}
+	b, err := json.Marshal(v.String())
+	if err != nil {
+		return "", fmt.Errorf("encoding text %q: %w", v.String(), err)
+	}
+	if exact {
+		return string(b) + "s", nil
+	}
+	return string(b) + "i", nil
+}
+
+// textEngineSelector returns a selector for the engine matching the elements
+// by the text, such as "internal:text" or "internal:label".
+func textEngineSelector(engine string, text goja.Value, exact bool) (string, error) {
+	body, err := textSelectorBody(text, exact)
+	if err != nil {
+		return "", err
+	}
+	return engine + "=" + body, nil
+}
+
+// attrSelector returns a selector matching the elements whose attribute
+// value matches the text.
+func attrSelector(name string, text goja.Value, exact bool) (string, error) {
+	body, err := textSelectorBody(text, exact)
+	if err != nil {
+		return "", err
+	}
+	return "internal:attr=[" + name + "=" + body + "]", nil
+}
+
+// roleSelector returns a selector matching the elements with the ARIA role
+// and the given options.
+func roleSelector(role string, opts *LocatorGetByRoleOptions) (string, error) {
+	if role == "" {
+		return "", errors.New("role must not be empty")
+	}
+	q := struct {
+		Role          string `json:"role"`
+		Name          string `json:"name,omitempty"`
+		Checked       *bool  `json:"checked,omitempty"`
+		Disabled      *bool  `json:"disabled,omitempty"`
+		IncludeHidden bool   `json:"includeHidden,omitempty"`
+	}{
+		Role:          role,
+		Checked:       opts.Checked,
+		Disabled:      opts.Disabled,
+		IncludeHidden: opts.IncludeHidden,
+	}
+	if opts.Name != nil {
+		name, err := textSelectorBody(opts.Name, opts.Exact)
+		if err != nil {
+			return "", fmt.Errorf("parsing name: %w", err)
+		}
+		q.Name = name
+	}
+	b, err := json.Marshal(q)
+	if err != nil {
+		return "", fmt.Errorf("encoding role query: %w", err)
+	}
+	return "internal:role=" + string(b), nil
+}
+
+// escapeQuotes escapes the unescaped quotes in s with a backslash.
+func escapeQuotes(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\\' && i+1 < len(s):
+			b.WriteByte(c)
+			b.WriteByte(s[i+1])
+			i++
+		case c == '"' || c == '\'' || c == '`':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,194 @@
+package common
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/url"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/network"
+	"github.com/dop251/goja"
+
+	"github.com/grafana/xk6-browser/api"
+)
+
+// storageStateBindingName is the name of the CDP binding that the pages use
+// to report the origins that the local storage of a storage state is
+// restored in.
+const storageStateBindingName = "__k6_browser_storage_state_binding__"
+
+// StorageState is the cookies and the local storage of a browser context,
+// which can be restored in a new browser context.
+type StorageState struct {
+	Cookies []*api.Cookie         `js:"cookies" json:"cookies"`
+	Origins []*StorageStateOrigin `js:"origins" json:"origins"`
+}
+
+// StorageStateOrigin is the local storage of an origin.
+type StorageStateOrigin struct {
+	Origin       string              `js:"origin" json:"origin"`
+	LocalStorage []*StorageStateItem `js:"localStorage" json:"localStorage"`
+}
+
+// StorageStateItem is a local storage item.
+type StorageStateItem struct {
+	Name  string `js:"name" json:"name"`
+	Value string `js:"value" json:"value"`
+}
+
+// parseStorageState parses the storage state from a JS object, or from the
+// JSON file at the path if it's a string.
+func parseStorageState(v goja.Value) (*StorageState, error) {
+	if !gojaValueExists(v) {
+		return nil, nil
+	}
+	var (
+		buf []byte
+		err error
+	)
+	if v.ExportType().Kind() == reflect.String {
+		if buf, err = ioutil.ReadFile(v.String()); err != nil {
+			return nil, fmt.Errorf("reading storage state: %w", err)
+		}
+	} else if buf, err = json.Marshal(v.Export()); err != nil {
+		return nil, fmt.Errorf("marshaling storage state: %w", err)
+	}
+
+	var s StorageState
+	if err := json.Unmarshal(buf, &s); err != nil {
+		return nil, fmt.Errorf("parsing storage state: %w", err)
+	}
+	for i, c := range s.Cookies {
+		if c.Name == "" || c.Domain == "" || c.Path == "" {
+			return nil, fmt.Errorf("parsing storage state: cookie #%d must have a name, a domain, and a path", i)
+		}
+	}
+	for i, o := range s.Origins {
+		if o.Origin == "" {
+			return nil, fmt.Errorf("parsing storage state: origin #%d must have an origin", i)
+		}
+	}
+	return &s, nil
+}
+
+// cookieParams returns the parameters to set the cookies of the state.
+func (s *StorageState) cookieParams() []*network.CookieParam {
+	params := make([]*network.CookieParam, 0, len(s.Cookies))
+	for _, c := range s.Cookies {
+		p := network.CookieParam{
+			Name:         c.Name,
+			Value:        c.Value,
+			Domain:       c.Domain,
+			Path:         c.Path,
+			HTTPOnly:     c.HTTPOnly,
+			Secure:       c.Secure,
+			SameSite:     network.CookieSameSite(c.SameSite),
+			PartitionKey: c.PartitionKey,
+		}
+		if c.Expires > 0 {
+			sec, frac := math.Modf(c.Expires)
+			t := cdp.TimeSinceEpoch(time.Unix(int64(sec), int64(frac*float64(time.Second))))
+			p.Expires = &t
+		}
+		params = append(params, &p)
+	}
+	return params
+}
+
+// localStorageItems returns the local storage items of the state by their
+// origins.
+func (s *StorageState) localStorageItems() map[string][][2]string {
+	origins := make(map[string][][2]string)
+	for _, o := range s.Origins {
+		for _, item := range o.LocalStorage {
+			origins[o.Origin] = append(origins[o.Origin], [2]string{item.Name, item.Value})
+		}
+	}
+	return origins
+}
+
+// localStorageInitScript returns the init script that restores the local
+// storage items of the origins in a document of the origin, or an empty
+// string if there's no local storage to restore. The script reports the
+// origin with the storage state binding after restoring it.
+func localStorageInitScript(origins map[string][][2]string) (string, error) {
+	if len(origins) == 0 {
+		return "", nil
+	}
+	buf, err := json.Marshal(origins)
+	if err != nil {
+		return "", fmt.Errorf("marshaling local storage: %w", err)
+	}
+
+	return fmt.Sprintf(`(() => {
+		const items = %s[location.origin];
+		if (!items) {
+			return;
+		}
+		try {
+			for (const [name, value] of items) {
+				localStorage.setItem(name, value);
+			}
+			globalThis[%q](location.origin);
+		} catch (e) {}
+	})();`, buf, storageStateBindingName), nil
+}
+
+// localStorageOf returns the local storage of the origin of the frame.
+func localStorageOf(f *Frame) (*StorageStateOrigin, error) {
+	u, err := url.Parse(f.URL())
+	if err != nil {
+		return nil, fmt.Errorf("parsing frame URL %q: %w", f.URL(), err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, nil
+	}
+
+	var (
+		rt = f.vu.Runtime()
+		fn = rt.ToValue(`() => {
+			try {
+				return JSON.stringify({ origin: location.origin, items: Object.entries(localStorage) });
+			} catch (e) {
+				return JSON.stringify({ origin: location.origin, items: [] });
+			}
+		}`)
+		opts = evalOptions{
+			forceCallable: true,
+			returnByValue: true,
+		}
+	)
+	v, err := f.evaluate(f.ctx, mainWorld, opts, fn)
+	if err != nil {
+		return nil, fmt.Errorf("getting local storage of %q: %w", f.URL(), err)
+	}
+	gv, ok := v.(goja.Value)
+	if !ok {
+		return nil, errors.New("getting local storage: unexpected result")
+	}
+	var res struct {
+		Origin string      `json:"origin"`
+		Items  [][2]string `json:"items"`
+	}
+	if err := json.Unmarshal([]byte(gv.String()), &res); err != nil {
+		return nil, fmt.Errorf("parsing local storage of %q: %w", f.URL(), err)
+	}
+
+	o := &StorageStateOrigin{
+		Origin:       res.Origin,
+		LocalStorage: make([]*StorageStateItem, 0, len(res.Items)),
+	}
+	for _, item := range res.Items {
+		o.LocalStorage = append(o.LocalStorage, &StorageStateItem{Name: item[0], Value: item[1]})
+	}
+	sort.Slice(o.LocalStorage, func(i, j int) bool {
+		return o.LocalStorage[i].Name < o.LocalStorage[j].Name
+	})
+	return o, nil
+}
@@ -0,0 +1,121 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/network"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/xk6-browser/api"
+	"github.com/grafana/xk6-browser/k6ext/k6test"
+)
+
+func TestParseStorageState(t *testing.T) {
+	t.Parallel()
+
+	want := &StorageState{
+		Cookies: []*api.Cookie{
+			{Name: "session", Value: "token", Domain: "example.com", Path: "/", Expires: -1, SameSite: "Lax"},
+		},
+		Origins: []*StorageStateOrigin{
+			{
+				Origin:       "https://example.com",
+				LocalStorage: []*StorageStateItem{{Name: "theme", Value: "dark"}},
+			},
+		},
+	}
+	state := map[string]interface{}{
+		"cookies": []interface{}{
+			map[string]interface{}{
+				"name": "session", "value": "token", "domain": "example.com", "path": "/",
+				"expires": -1, "httpOnly": false, "secure": false, "sameSite": "Lax",
+			},
+		},
+		"origins": []interface{}{
+			map[string]interface{}{
+				"origin":       "https://example.com",
+				"localStorage": []interface{}{map[string]interface{}{"name": "theme", "value": "dark"}},
+			},
+		},
+	}
+
+	t.Run("object", func(t *testing.T) {
+		t.Parallel()
+
+		vu := k6test.NewVU(t)
+		s, err := parseStorageState(vu.ToGojaValue(state))
+		require.NoError(t, err)
+		assert.Equal(t, want, s)
+	})
+
+	t.Run("path", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "state.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{
+			"cookies": [{"name": "session", "value": "token", "domain": "example.com", "path": "/", "expires": -1, "sameSite": "Lax"}],
+			"origins": [{"origin": "https://example.com", "localStorage": [{"name": "theme", "value": "dark"}]}]
+		}`), 0o600))
+
+		vu := k6test.NewVU(t)
+		s, err := parseStorageState(vu.ToGojaValue(path))
+		require.NoError(t, err)
+		assert.Equal(t, want, s)
+	})
+
+	t.Run("err", func(t *testing.T) {
+		t.Parallel()
+
+		vu := k6test.NewVU(t)
+		_, err := parseStorageState(vu.ToGojaValue(filepath.Join(t.TempDir(), "missing.json")))
+		assert.ErrorContains(t, err, "reading storage state")
+
+		_, err = parseStorageState(vu.ToGojaValue(map[string]interface{}{
+			"cookies": []interface{}{map[string]interface{}{"name": "session", "value": "token"}},
+		}))
+		assert.EqualError(t, err, "parsing storage state: cookie #0 must have a name, a domain, and a path")
+
+		_, err = parseStorageState(vu.ToGojaValue(map[string]interface{}{
+			"origins": []interface{}{map[string]interface{}{"localStorage": []interface{}{}}},
+		}))
+		assert.EqualError(t, err, "parsing storage state: origin #0 must have an origin")
+	})
+}
+
+func TestStorageStateCookieParams(t *testing.T) {
+	t.Parallel()
+
+	s := &StorageState{
+		Cookies: []*api.Cookie{
+			{Name: "session", Value: "token", Domain: "example.com", Path: "/", Expires: -1, SameSite: "Lax"},
+			{Name: "theme", Value: "dark", Domain: ".example.com", Path: "/", Expires: 1700000000, Secure: true},
+		},
+	}
+	expires := cdp.TimeSinceEpoch(time.Unix(1700000000, 0))
+	assert.Equal(t, []*network.CookieParam{
+		{Name: "session", Value: "token", Domain: "example.com", Path: "/", SameSite: network.CookieSameSiteLax},
+		{Name: "theme", Value: "dark", Domain: ".example.com", Path: "/", Secure: true, Expires: &expires},
+	}, s.cookieParams())
+}
+
+func TestStorageStateLocalStorageInitScript(t *testing.T) {
+	t.Parallel()
+
+	s := &StorageState{
+		Origins: []*StorageStateOrigin{{Origin: "https://example.com"}},
+	}
+	source, err := localStorageInitScript(s.localStorageItems())
+	require.NoError(t, err)
+	assert.Empty(t, source)
+
+	s.Origins[0].LocalStorage = []*StorageStateItem{{Name: "theme", Value: "dark"}}
+	source, err = localStorageInitScript(s.localStorageItems())
+	require.NoError(t, err)
+	assert.Contains(t, source, `{"https://example.com":[["theme","dark"]]}[location.origin]`)
+	assert.Contains(t, source, `globalThis["`+storageStateBindingName+`"](location.origin)`)
+}
@@ -20,11 +20,15 @@
 
 package common
 
+import "time"
+
 // TimeoutSettings holds information on timeout settings.
+// The settings fall back to their parent's settings when they are not set,
+// and to DefaultTimeout when none of them is set.
 type TimeoutSettings struct {
 	parent                   *TimeoutSettings
-	defaultTimeout           *int64
-	defaultNavigationTimeout *int64
+	defaultTimeout           *time.Duration
+	defaultNavigationTimeout *time.Duration
 }
 
 // NewTimeoutSettings creates a new timeout settings object.
@@ -37,15 +41,19 @@ func NewTimeoutSettings(parent *TimeoutSettings) *TimeoutSettings {
 	return t
 }
 
+// setDefaultTimeout sets the default timeout in milliseconds.
 func (t *TimeoutSettings) setDefaultTimeout(timeout int64) {
-	t.defaultTimeout = &timeout
+	d := time.Duration(timeout) * time.Millisecond
+	t.defaultTimeout = &d
 }
 
+// setDefaultNavigationTimeout sets the default navigation timeout in milliseconds.
 func (t *TimeoutSettings) setDefaultNavigationTimeout(timeout int64) {
-	t.defaultNavigationTimeout = &timeout
+	d := time.Duration(timeout) * time.Millisecond
+	t.defaultNavigationTimeout = &d
 }
 
-func (t *TimeoutSettings) navigationTimeout() int64 {
+func (t *TimeoutSettings) navigationTimeout() time.Duration {
 	if t.defaultNavigationTimeout != nil {
 		return *t.defaultNavigationTimeout
 	}
@@ -55,15 +63,15 @@ func (t *TimeoutSettings) navigationTimeout() int64 {
 	if t.parent != nil {
 		return t.parent.navigationTimeout()
 	}
-	return int64(DefaultTimeout.Seconds())
+	return DefaultTimeout
 }
 
-func (t *TimeoutSettings) timeout() int64 {
+func (t *TimeoutSettings) timeout() time.Duration {
 	if t.defaultTimeout != nil {
 		return *t.defaultTimeout
 	}
 	if t.parent != nil {
 		return t.parent.timeout()
 	}
-	return int64(DefaultTimeout.Seconds())
+	return DefaultTimeout
 }
@@ -22,6 +22,7 @@ package common
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -65,24 +66,29 @@ func testTimeoutSettingsNewTimeoutSettingsWithParent(t *testing.T) {
 func testTimeoutSettingsSetDefaultTimeout(t *testing.T) {
 	ts := NewTimeoutSettings(nil)
 	ts.setDefaultTimeout(100)
-	assert.Equal(t, int64(100), *ts.defaultTimeout)
+	assert.Equal(t, 100*time.Millisecond, *ts.defaultTimeout)
 }
 
 func testTimeoutSettingsSetDefaultNavigationTimeout(t *testing.T) {
 	ts := NewTimeoutSettings(nil)
 	ts.setDefaultNavigationTimeout(100)
-	assert.Equal(t, int64(100), *ts.defaultNavigationTimeout)
+	assert.Equal(t, 100*time.Millisecond, *ts.defaultNavigationTimeout)
 }
 
 func testTimeoutSettingsNavigationTimeout(t *testing.T) {
 	ts := NewTimeoutSettings(nil)
 
 	// Assert default timeout value is used
-	assert.Equal(t, int64(DefaultTimeout.Seconds()), ts.navigationTimeout())
+	assert.Equal(t, DefaultTimeout, ts.navigationTimeout())
 
 	// Assert custom default timeout is used
+	ts.setDefaultTimeout(1000)
+	assert.Equal(t, time.Second, ts.navigationTimeout())
+
+	// Assert custom default navigation timeout is used (over default timeout)
 	ts.setDefaultNavigationTimeout(100)
-	assert.Equal(t, int64(100), ts.navigationTimeout())
+	assert.Equal(t, 100*time.Millisecond, ts.navigationTimeout())
+	assert.Equal(t, time.Second, ts.timeout())
 }
 
 func testTimeoutSettingsNavigationTimeoutWithParent(t *testing.T) {
@@ -90,26 +96,26 @@ func testTimeoutSettingsNavigationTimeoutWithParent(t *testing.T) {
 	tsWithParent := NewTimeoutSettings(ts)
 
 	// Assert default timeout value is used
-	assert.Equal(t, int64(DefaultTimeout.Seconds()), tsWithParent.navigationTimeout())
+	assert.Equal(t, DefaultTimeout, tsWithParent.navigationTimeout())
 
 	// Assert custom default timeout from parent is used
 	ts.setDefaultNavigationTimeout(1000)
-	assert.Equal(t, int64(1000), tsWithParent.navigationTimeout())
+	assert.Equal(t, time.Second, tsWithParent.navigationTimeout())
 
 	// Assert custom default timeout is used (over parent)
 	tsWithParent.setDefaultNavigationTimeout(100)
-	assert.Equal(t, int64(100), tsWithParent.navigationTimeout())
+	assert.Equal(t, 100*time.Millisecond, tsWithParent.navigationTimeout())
 }
 
 func testTimeoutSettingsTimeout(t *testing.T) {
 	ts := NewTimeoutSettings(nil)
 
 	// Assert default timeout value is used
-	assert.Equal(t, int64(DefaultTimeout.Seconds()), ts.timeout())
+	assert.Equal(t, DefaultTimeout, ts.timeout())
 
 	// Assert custom default timeout is used
 	ts.setDefaultTimeout(100)
-	assert.Equal(t, int64(100), ts.timeout())
+	assert.Equal(t, 100*time.Millisecond, ts.timeout())
 }
 
 func testTimeoutSettingsTimeoutWithParent(t *testing.T) {
@@ -117,13 +123,13 @@ func testTimeoutSettingsTimeoutWithParent(t *testing.T) {
 	tsWithParent := NewTimeoutSettings(ts)
 
 	// Assert default timeout value is used
-	assert.Equal(t, int64(DefaultTimeout.Seconds()), tsWithParent.timeout())
+	assert.Equal(t, DefaultTimeout, tsWithParent.timeout())
 
 	// Assert custom default timeout from parent is used
 	ts.setDefaultTimeout(1000)
-	assert.Equal(t, int64(1000), tsWithParent.timeout())
+	assert.Equal(t, time.Second, tsWithParent.timeout())
 
 	// Assert custom default timeout is used (over parent)
 	tsWithParent.setDefaultTimeout(100)
-	assert.Equal(t, int64(100), tsWithParent.timeout())
+	assert.Equal(t, 100*time.Millisecond, tsWithParent.timeout())
 }
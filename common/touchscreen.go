@@ -22,12 +22,16 @@ package common
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
 	"github.com/grafana/xk6-browser/api"
 	"github.com/grafana/xk6-browser/k6ext"
 
 	"github.com/chromedp/cdproto/cdp"
 	"github.com/chromedp/cdproto/input"
+	"github.com/dop251/goja"
 )
 
 // Ensure Touchscreen implements the EventEmitter and api.Touchscreen interfaces.
@@ -41,18 +45,32 @@ type Touchscreen struct {
 	ctx      context.Context
 	session  session
 	keyboard *Keyboard
+	// hasTouch is whether touch emulation is enabled for the page.
+	hasTouch bool
 }
 
 // NewTouchscreen returns a new TouchScreen.
-func NewTouchscreen(ctx context.Context, s session, k *Keyboard) *Touchscreen {
+func NewTouchscreen(ctx context.Context, s session, k *Keyboard, hasTouch bool) *Touchscreen {
 	return &Touchscreen{
 		ctx:      ctx,
 		session:  s,
 		keyboard: k,
+		hasTouch: hasTouch,
 	}
 }
 
+// ensureTouch returns an error if touch emulation isn't enabled for the page.
+func (t *Touchscreen) ensureTouch() error {
+	if !t.hasTouch {
+		return errors.New("touch emulation is not enabled, set the hasTouch browser context option")
+	}
+	return nil
+}
+
 func (t *Touchscreen) tap(x float64, y float64) error {
+	if err := t.ensureTouch(); err != nil {
+		return err
+	}
 	action := input.DispatchTouchEvent(input.TouchStart, []*input.TouchPoint{{X: x, Y: y}}).
 		WithModifiers(input.Modifier(t.keyboard.modifiers))
 	if err := action.Do(cdp.WithExecutor(t.ctx, t.session)); err != nil {
@@ -66,7 +84,125 @@ func (t *Touchscreen) tap(x float64, y float64) error {
 	return nil
 }
 
-// Tap dispatches a tap start and tap end event.
+func (t *Touchscreen) dispatch(typ input.TouchType, points []*input.TouchPoint) error {
+	action := input.DispatchTouchEvent(typ, points).
+		WithModifiers(input.Modifier(t.keyboard.modifiers))
+	if err := action.Do(cdp.WithExecutor(t.ctx, t.session)); err != nil {
+		return fmt.Errorf("dispatching %s touch event: %w", typ, err)
+	}
+	return nil
+}
+
+// wait waits for d or until the context is done.
+func (t *Touchscreen) wait(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-t.ctx.Done():
+	case <-timer.C:
+	}
+}
+
+// pinchPoints returns the two touch points of a pinch, placed horizontally
+// at the given distance from each other around the center.
+func pinchPoints(center *Position, distance float64) []*input.TouchPoint {
+	return []*input.TouchPoint{
+		{X: center.X - distance/2, Y: center.Y, ID: 0},
+		{X: center.X + distance/2, Y: center.Y, ID: 1},
+	}
+}
+
+func (t *Touchscreen) pinch(center *Position, scale float64, opts *TouchscreenPinchOptions) error {
+	if err := t.ensureTouch(); err != nil {
+		return err
+	}
+	if scale <= 0 {
+		return fmt.Errorf("scale must be greater than zero, got %v", scale)
+	}
+
+	if err := t.dispatch(input.TouchStart, pinchPoints(center, opts.Distance)); err != nil {
+		return err
+	}
+	interval := opts.Duration / time.Duration(opts.Steps)
+	to := opts.Distance * scale
+	for i := int64(1); i <= opts.Steps; i++ {
+		t.wait(interval)
+		d := opts.Distance + (to-opts.Distance)*float64(i)/float64(opts.Steps)
+		if err := t.dispatch(input.TouchMove, pinchPoints(center, d)); err != nil {
+			return err
+		}
+	}
+	return t.dispatch(input.TouchEnd, []*input.TouchPoint{})
+}
+
+func (t *Touchscreen) swipe(from, to *Position, opts *TouchscreenSwipeOptions) error {
+	if err := t.ensureTouch(); err != nil {
+		return err
+	}
+
+	if err := t.dispatch(input.TouchStart, []*input.TouchPoint{{X: from.X, Y: from.Y}}); err != nil {
+		return err
+	}
+	interval := opts.Duration / time.Duration(opts.Steps)
+	for i := int64(1); i <= opts.Steps; i++ {
+		t.wait(interval)
+		x := from.X + (to.X-from.X)*float64(i)/float64(opts.Steps)
+		y := from.Y + (to.Y-from.Y)*float64(i)/float64(opts.Steps)
+		if err := t.dispatch(input.TouchMove, []*input.TouchPoint{{X: x, Y: y}}); err != nil {
+			return err
+		}
+	}
+	return t.dispatch(input.TouchEnd, []*input.TouchPoint{})
+}
+
+// Pinch dispatches the touch events of two fingers moving toward or away
+// from the center point, which is an object with x and y properties. The
+// distance between the fingers changes from the distance option to the
+// distance multiplied by scale, so a scale below 1 zooms out and a scale
+// above 1 zooms in. It requires touch emulation, which is enabled with the
+// hasTouch browser context option.
+func (t *Touchscreen) Pinch(center goja.Value, scale float64, opts goja.Value) {
+	pinchOpts := NewTouchscreenPinchOptions()
+	if err := pinchOpts.Parse(t.ctx, opts); err != nil {
+		k6ext.Panic(t.ctx, "parsing pinch options: %w", err)
+	}
+	c, err := parsePosition(k6ext.Runtime(t.ctx), center)
+	if err != nil {
+		k6ext.Panic(t.ctx, "parsing pinch center: %w", err)
+	}
+	if err := t.pinch(c, scale, pinchOpts); err != nil {
+		k6ext.Panic(t.ctx, "pinching: %w", err)
+	}
+}
+
+// Swipe dispatches a touch start event at the from point, touch move events
+// along the path to the to point, and a touch end event. The points are
+// objects with x and y properties. It requires touch emulation, which is
+// enabled with the hasTouch browser context option.
+func (t *Touchscreen) Swipe(from goja.Value, to goja.Value, opts goja.Value) {
+	swipeOpts := NewTouchscreenSwipeOptions()
+	if err := swipeOpts.Parse(t.ctx, opts); err != nil {
+		k6ext.Panic(t.ctx, "parsing swipe options: %w", err)
+	}
+	rt := k6ext.Runtime(t.ctx)
+	fromPos, err := parsePosition(rt, from)
+	if err != nil {
+		k6ext.Panic(t.ctx, "parsing swipe start point: %w", err)
+	}
+	toPos, err := parsePosition(rt, to)
+	if err != nil {
+		k6ext.Panic(t.ctx, "parsing swipe end point: %w", err)
+	}
+	if err := t.swipe(fromPos, toPos, swipeOpts); err != nil {
+		k6ext.Panic(t.ctx, "swiping: %w", err)
+	}
+}
+
+// Tap dispatches a tap start and tap end event. It requires touch
+// emulation, which is enabled with the hasTouch browser context option.
 func (t *Touchscreen) Tap(x float64, y float64) {
 	if err := t.tap(x, y); err != nil {
 		k6ext.Panic(t.ctx, "tapping: %w", err)
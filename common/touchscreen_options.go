@@ -0,0 +1,92 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dop251/goja"
+
+	"github.com/grafana/xk6-browser/k6ext"
+)
+
+type TouchscreenPinchOptions struct {
+	// Distance is the initial distance between the two touch points.
+	Distance float64 `json:"distance"`
+	// Steps is the number of touch moves from the initial to the final
+	// distance.
+	Steps int64 `json:"steps"`
+	// Duration is the time the pinch takes.
+	Duration time.Duration `json:"duration"`
+}
+
+type TouchscreenSwipeOptions struct {
+	// Steps is the number of touch moves from the start to the end point.
+	Steps int64 `json:"steps"`
+	// Duration is the time the swipe takes.
+	Duration time.Duration `json:"duration"`
+}
+
+func NewTouchscreenPinchOptions() *TouchscreenPinchOptions {
+	return &TouchscreenPinchOptions{
+		Distance: 100,
+		Steps:    10,
+		Duration: 100 * time.Millisecond,
+	}
+}
+
+func (o *TouchscreenPinchOptions) Parse(ctx context.Context, opts goja.Value) error {
+	rt := k6ext.Runtime(ctx)
+	if opts != nil && !goja.IsUndefined(opts) && !goja.IsNull(opts) {
+		opts := opts.ToObject(rt)
+		for _, k := range opts.Keys() {
+			switch k {
+			case "distance":
+				o.Distance = opts.Get(k).ToFloat()
+				if o.Distance <= 0 {
+					return fmt.Errorf("distance must be greater than zero, got %v", o.Distance)
+				}
+			case "steps":
+				o.Steps = opts.Get(k).ToInteger()
+				if o.Steps < 1 {
+					return fmt.Errorf("steps must be greater than zero, got %d", o.Steps)
+				}
+			case "duration":
+				o.Duration = time.Duration(opts.Get(k).ToInteger()) * time.Millisecond
+				if o.Duration < 0 {
+					return fmt.Errorf("duration must be a positive number, got %d", opts.Get(k).ToInteger())
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func NewTouchscreenSwipeOptions() *TouchscreenSwipeOptions {
+	return &TouchscreenSwipeOptions{
+		Steps:    10,
+		Duration: 100 * time.Millisecond,
+	}
+}
+
+func (o *TouchscreenSwipeOptions) Parse(ctx context.Context, opts goja.Value) error {
+	rt := k6ext.Runtime(ctx)
+	if opts != nil && !goja.IsUndefined(opts) && !goja.IsNull(opts) {
+		opts := opts.ToObject(rt)
+		for _, k := range opts.Keys() {
+			switch k {
+			case "steps":
+				o.Steps = opts.Get(k).ToInteger()
+				if o.Steps < 1 {
+					return fmt.Errorf("steps must be greater than zero, got %d", o.Steps)
+				}
+			case "duration":
+				o.Duration = time.Duration(opts.Get(k).ToInteger()) * time.Millisecond
+				if o.Duration < 0 {
+					return fmt.Errorf("duration must be a positive number, got %d", opts.Get(k).ToInteger())
+				}
+			}
+		}
+	}
+	return nil
+}
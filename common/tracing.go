@@ -0,0 +1,285 @@
+package common
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sync"
+
+	"github.com/grafana/xk6-browser/api"
+	"github.com/grafana/xk6-browser/log"
+
+	"github.com/chromedp/cdproto"
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/tracing"
+	"github.com/dop251/goja"
+)
+
+// Ensure Tracing implements the api.Tracing interface.
+var _ api.Tracing = &Tracing{}
+
+// tracer records Chrome DevTools traces of a browser. The traces are saved
+// as zip files containing a trace.json file in the trace event format, which
+// can be opened with the Chrome DevTools performance panel or
+// https://ui.perfetto.dev.
+//
+// The tracing of Chrome is browser-wide, so the traces contain the events of
+// all the browser contexts and pages.
+type tracer struct {
+	ctx     context.Context
+	browser *Browser
+	logger  *log.Logger
+
+	// the state of the running trace, nil if the tracing is not started.
+	trace *trace
+}
+
+type trace struct {
+	opts     *TracingStartOptions
+	cancelFn context.CancelFunc
+	done     chan struct{}
+
+	mu     sync.Mutex
+	events []json.RawMessage
+}
+
+// newTracer creates a new tracer for the browser.
+func newTracer(ctx context.Context, b *Browser, l *log.Logger) *tracer {
+	return &tracer{
+		ctx:     ctx,
+		browser: b,
+		logger:  l,
+	}
+}
+
+func (t *tracer) start(opts *TracingStartOptions) error {
+	ctx, cancel := context.WithCancel(t.ctx)
+	tr := &trace{
+		opts:     opts,
+		cancelFn: cancel,
+		done:     make(chan struct{}),
+	}
+
+	ch := make(chan Event)
+	t.browser.conn.on(ctx, []string{
+		cdproto.EventTracingDataCollected,
+		cdproto.EventTracingTracingComplete,
+	}, ch)
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event := <-ch:
+				switch ev := event.data.(type) {
+				case *tracing.EventDataCollected:
+					tr.mu.Lock()
+					for _, e := range ev.Value {
+						tr.events = append(tr.events, json.RawMessage(e))
+					}
+					tr.mu.Unlock()
+				case *tracing.EventTracingComplete:
+					if ev.DataLossOccurred {
+						t.logger.Warnf("Tracing", "some trace events were lost")
+					}
+					close(tr.done)
+					return
+				}
+			}
+		}
+	}()
+
+	action := tracing.Start().
+		WithTransferMode(tracing.TransferModeReportEvents).
+		WithTraceConfig(&tracing.TraceConfig{
+			IncludedCategories: opts.categories(),
+		})
+	if err := action.Do(cdp.WithExecutor(t.ctx, t.browser.conn)); err != nil {
+		cancel()
+		return err
+	}
+	t.trace = tr
+
+	return nil
+}
+
+// stop stops recording the trace and returns it as a zip file, which is also
+// saved at the path if it's not empty.
+func (t *tracer) stop(path string) ([]byte, error) {
+	tr := t.trace
+	t.trace = nil
+	defer tr.cancelFn()
+
+	// the DOM is captured before the tracing ends so that the snapshots
+	// match the last events of the trace.
+	var snapshots []string
+	if tr.opts.Snapshots {
+		snapshots = t.snapshots()
+	}
+
+	if err := tracing.End().Do(cdp.WithExecutor(t.ctx, t.browser.conn)); err != nil {
+		return nil, err
+	}
+	select {
+	case <-tr.done:
+	case <-t.ctx.Done():
+		return nil, fmt.Errorf("waiting for the trace events: %w", t.ctx.Err())
+	}
+
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	var buf bytes.Buffer
+	if err := writeTrace(&buf, tr.opts.Title, tr.events, snapshots); err != nil {
+		return nil, err
+	}
+	if path != "" {
+		if err := ioutil.WriteFile(path, buf.Bytes(), 0o600); err != nil {
+			return nil, fmt.Errorf("saving trace file: %w", err)
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Tracing records Chrome DevTools traces of a browser context. The tracing
+// of Chrome is browser-wide, so it records the traces with the tracer of the
+// browser, and only one trace can be recorded at a time.
+type Tracing struct {
+	ctx    context.Context
+	bctx   *BrowserContext
+	logger *log.Logger
+
+	// the trace started by the browser context, nil if it's not started.
+	trace *trace
+}
+
+// NewTracing creates a new tracing for the browser context.
+func NewTracing(ctx context.Context, bctx *BrowserContext, l *log.Logger) *Tracing {
+	return &Tracing{
+		ctx:    ctx,
+		bctx:   bctx,
+		logger: l,
+	}
+}
+
+// Start starts recording a trace. It returns an error if a trace is already
+// being recorded in the browser.
+func (t *Tracing) Start(opts goja.Value) error {
+	t.logger.Debugf("Tracing:Start", "bctxid:%v", t.bctx.id)
+
+	tracer := t.bctx.browser.tracer
+	if tracer.trace != nil {
+		return errors.New("tracing has already been started")
+	}
+	topts := NewTracingStartOptions()
+	if err := topts.Parse(t.ctx, opts); err != nil {
+		return fmt.Errorf("parsing tracing options: %w", err)
+	}
+	if err := tracer.start(topts); err != nil {
+		return fmt.Errorf("starting tracing: %w", err)
+	}
+	t.trace = tracer.trace
+
+	return nil
+}
+
+// Stop stops recording the trace and saves it as a zip file at the path of
+// the options. The trace is discarded if there is no path.
+func (t *Tracing) Stop(opts goja.Value) error {
+	t.logger.Debugf("Tracing:Stop", "bctxid:%v", t.bctx.id)
+
+	if !t.started() {
+		return errors.New("tracing has not been started")
+	}
+	sopts := NewTracingStopOptions()
+	if err := sopts.Parse(t.ctx, opts); err != nil {
+		return fmt.Errorf("parsing tracing options: %w", err)
+	}
+	t.trace = nil
+	if _, err := t.bctx.browser.tracer.stop(sopts.Path); err != nil {
+		return fmt.Errorf("stopping tracing: %w", err)
+	}
+
+	return nil
+}
+
+// started returns true if the trace started by the browser context is still
+// being recorded.
+func (t *Tracing) started() bool {
+	return t.trace != nil && t.trace == t.bctx.browser.tracer.trace
+}
+
+// discard stops recording the trace started by the browser context without
+// saving it. It's a no-op if the tracing is not started.
+func (t *Tracing) discard() {
+	if !t.started() {
+		return
+	}
+	t.trace = nil
+	if _, err := t.bctx.browser.tracer.stop(""); err != nil {
+		t.logger.Debugf("Tracing:discard", "bctxid:%v err:%v", t.bctx.id, err)
+	}
+}
+
+// snapshots returns the HTML of the main frame of every page in the browser.
+// The pages that can't be captured are skipped.
+func (t *tracer) snapshots() []string {
+	rt := t.browser.vu.Runtime()
+	fn := rt.ToValue(`() => new XMLSerializer().serializeToString(document)`)
+	opts := evalOptions{forceCallable: true, returnByValue: true}
+
+	var snapshots []string
+	for _, p := range t.browser.getPages() {
+		v, err := p.frameManager.MainFrame().evaluate(t.ctx, utilityWorld, opts, fn)
+		if err != nil {
+			t.logger.Debugf("Tracing:snapshots", "sid:%v err:%v", p.sessionID(), err)
+			continue
+		}
+		snapshots = append(snapshots, gojaValueToString(t.ctx, v))
+	}
+	return snapshots
+}
+
+// writeTrace writes the trace events and the DOM snapshots as a zip file.
+func writeTrace(out io.Writer, title string, events []json.RawMessage, snapshots []string) error {
+	zw := zip.NewWriter(out)
+	w, err := zw.Create("trace.json")
+	if err != nil {
+		return fmt.Errorf("writing trace file: %w", err)
+	}
+	if events == nil {
+		events = []json.RawMessage{}
+	}
+	tr := struct {
+		TraceEvents []json.RawMessage `json:"traceEvents"`
+		Metadata    map[string]string `json:"metadata,omitempty"`
+	}{
+		TraceEvents: events,
+	}
+	if title != "" {
+		tr.Metadata = map[string]string{"title": title}
+	}
+	if err := json.NewEncoder(w).Encode(tr); err != nil {
+		return fmt.Errorf("writing trace events: %w", err)
+	}
+	for i, s := range snapshots {
+		w, err := zw.Create(fmt.Sprintf("snapshots/page-%d.html", i+1))
+		if err != nil {
+			return fmt.Errorf("writing trace file: %w", err)
+		}
+		if _, err := w.Write([]byte(s)); err != nil {
+			return fmt.Errorf("writing snapshot: %w", err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("writing trace file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package common
+
+import (
+	"context"
+
+	"github.com/dop251/goja"
+
+	"github.com/grafana/xk6-browser/k6ext"
+)
+
+type TracingStartOptions struct {
+	// Path is the path of the file the trace is saved to when the tracing
+	// is stopped.
+	Path string `json:"path"`
+	// Screenshots captures the screenshots of the pages in the trace.
+	Screenshots bool `json:"screenshots"`
+	// Snapshots captures the layers and the paint snapshots of the pages in
+	// the trace, and the DOM of the pages when the tracing is stopped.
+	Snapshots bool `json:"snapshots"`
+	// Sources captures the JavaScript sources of the pages in the trace.
+	Sources bool `json:"sources"`
+	// Title is the title of the trace, stored in its metadata.
+	Title string `json:"title"`
+}
+
+func NewTracingStartOptions() *TracingStartOptions {
+	return &TracingStartOptions{}
+}
+
+func (o *TracingStartOptions) Parse(ctx context.Context, opts goja.Value) error {
+	rt := k6ext.Runtime(ctx)
+	if opts != nil && !goja.IsUndefined(opts) && !goja.IsNull(opts) {
+		opts := opts.ToObject(rt)
+		for _, k := range opts.Keys() {
+			switch k {
+			case "path":
+				o.Path = opts.Get(k).String()
+			case "screenshots":
+				o.Screenshots = opts.Get(k).ToBoolean()
+			case "snapshots":
+				o.Snapshots = opts.Get(k).ToBoolean()
+			case "sources":
+				o.Sources = opts.Get(k).ToBoolean()
+			case "title":
+				o.Title = opts.Get(k).String()
+			}
+		}
+	}
+	return nil
+}
+
+type TracingStopOptions struct {
+	// Path is the path of the zip file the trace is saved to. The trace is
+	// discarded if it's empty.
+	Path string `json:"path"`
+}
+
+func NewTracingStopOptions() *TracingStopOptions {
+	return &TracingStopOptions{}
+}
+
+func (o *TracingStopOptions) Parse(ctx context.Context, opts goja.Value) error {
+	rt := k6ext.Runtime(ctx)
+	if opts != nil && !goja.IsUndefined(opts) && !goja.IsNull(opts) {
+		opts := opts.ToObject(rt)
+		for _, k := range opts.Keys() {
+			if k == "path" {
+				o.Path = opts.Get(k).String()
+			}
+		}
+	}
+	return nil
+}
+
+// categories returns the trace categories to record with the options.
+func (o *TracingStartOptions) categories() []string {
+	categories := []string{
+		"devtools.timeline",
+		"v8.execute",
+		"disabled-by-default-devtools.timeline",
+		"disabled-by-default-devtools.timeline.frame",
+		"toplevel",
+		"blink.console",
+		"blink.user_timing",
+		"latencyInfo",
+		"disabled-by-default-devtools.timeline.stack",
+		"loading",
+		"netlog",
+	}
+	if o.Screenshots {
+		categories = append(categories, "disabled-by-default-devtools.screenshot")
+	}
+	if o.Snapshots {
+		categories = append(categories,
+			"disabled-by-default-devtools.timeline.layers",
+			"disabled-by-default-devtools.timeline.picture",
+		)
+	}
+	if o.Sources {
+		categories = append(categories,
+			"disabled-by-default-devtools.v8-source-rundown",
+			"disabled-by-default-devtools.v8-source-rundown-sources",
+		)
+	}
+	return categories
+}
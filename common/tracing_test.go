@@ -0,0 +1,46 @@
+package common
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteTrace(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	events := []json.RawMessage{json.RawMessage(`{"name":"a"}`), json.RawMessage(`{"name":"b"}`)}
+	require.NoError(t, writeTrace(&buf, "checkout", events, []string{"<html></html>"}))
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+
+	files := make(map[string]string)
+	for _, f := range zr.File {
+		r, err := f.Open()
+		require.NoError(t, err)
+		b, err := ioutil.ReadAll(r)
+		require.NoError(t, err)
+		files[f.Name] = string(b)
+	}
+	assert.JSONEq(t,
+		`{"traceEvents":[{"name":"a"},{"name":"b"}],"metadata":{"title":"checkout"}}`,
+		files["trace.json"])
+	assert.Equal(t, "<html></html>", files["snapshots/page-1.html"])
+}
+
+func TestTracingStartOptionsCategories(t *testing.T) {
+	t.Parallel()
+
+	opts := NewTracingStartOptions()
+	assert.NotContains(t, opts.categories(), "disabled-by-default-devtools.screenshot")
+
+	opts.Screenshots = true
+	assert.Contains(t, opts.categories(), "disabled-by-default-devtools.screenshot")
+}
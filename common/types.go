@@ -81,6 +81,29 @@ func (c *ColorScheme) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+// ForcedColors represents a browser forced colors preference.
+type ForcedColors string
+
+// Valid forced colors options.
+const (
+	ForcedColorsActive ForcedColors = "active"
+	ForcedColorsNone   ForcedColors = "none"
+)
+
+func (f ForcedColors) String() string {
+	return forcedColorsToString[f]
+}
+
+var forcedColorsToString = map[ForcedColors]string{
+	ForcedColorsActive: "active",
+	ForcedColorsNone:   "none",
+}
+
+var forcedColorsToID = map[string]ForcedColors{
+	"active": ForcedColorsActive,
+	"none":   ForcedColorsNone,
+}
+
 // Credentials holds HTTP authentication credentials.
 type Credentials struct {
 	Username string `js:"username"`
@@ -178,13 +201,13 @@ func (g *Geolocation) Parse(ctx context.Context, opts goja.Value) error {
 		}
 	}
 
-	if longitude < -180 || longitude > 180 {
+	if math.IsNaN(longitude) || longitude < -180 || longitude > 180 {
 		return fmt.Errorf(`invalid longitude "%.2f": precondition -180 <= LONGITUDE <= 180 failed`, longitude)
 	}
-	if latitude < -90 || latitude > 90 {
+	if math.IsNaN(latitude) || latitude < -90 || latitude > 90 {
 		return fmt.Errorf(`invalid latitude "%.2f": precondition -90 <= LATITUDE <= 90 failed`, latitude)
 	}
-	if accuracy < 0 {
+	if math.IsNaN(accuracy) || accuracy < 0 {
 		return fmt.Errorf(`invalid accuracy "%.2f": precondition 0 <= ACCURACY failed`, accuracy)
 	}
 
@@ -203,6 +226,43 @@ const (
 	ImageFormatPNG  ImageFormat = "png"
 )
 
+// ScreenshotScale is the pixel density of a screenshot.
+type ScreenshotScale string
+
+// Valid screenshot scale options.
+const (
+	ScreenshotScaleCSS    ScreenshotScale = "css"
+	ScreenshotScaleDevice ScreenshotScale = "device"
+)
+
+func parseScreenshotScale(s string) (ScreenshotScale, error) {
+	switch sc := ScreenshotScale(s); sc {
+	case ScreenshotScaleCSS, ScreenshotScaleDevice:
+		return sc, nil
+	default:
+		return "", fmt.Errorf("%q is not a valid scale option", s)
+	}
+}
+
+// ScreenshotAnimations tells whether the animations run while taking a
+// screenshot.
+type ScreenshotAnimations string
+
+// Valid screenshot animations options.
+const (
+	ScreenshotAnimationsAllow    ScreenshotAnimations = "allow"
+	ScreenshotAnimationsDisabled ScreenshotAnimations = "disabled"
+)
+
+func parseScreenshotAnimations(s string) (ScreenshotAnimations, error) {
+	switch a := ScreenshotAnimations(s); a {
+	case ScreenshotAnimationsAllow, ScreenshotAnimationsDisabled:
+		return a, nil
+	default:
+		return "", fmt.Errorf("%q is not a valid animations option", s)
+	}
+}
+
 func (f ImageFormat) String() string {
 	return imageFormatToString[f]
 }
@@ -243,6 +303,12 @@ const (
 	LifecycleEventLoad LifecycleEvent = iota
 	LifecycleEventDOMContentLoad
 	LifecycleEventNetworkIdle
+	// LifecycleEventCommit fires when the frame commits a new document,
+	// which is when the browser sends the CDP Page.frameNavigated event
+	// after receiving the response headers. The navigations within the same
+	// document, reported with Page.navigatedWithinDocument, don't replace
+	// the document, so they are committed right away.
+	LifecycleEventCommit
 )
 
 func (l LifecycleEvent) String() string {
@@ -253,12 +319,14 @@ var lifecycleEventToString = map[LifecycleEvent]string{
 	LifecycleEventLoad:           "load",
 	LifecycleEventDOMContentLoad: "domcontentloaded",
 	LifecycleEventNetworkIdle:    "networkidle",
+	LifecycleEventCommit:         "commit",
 }
 
 var lifecycleEventToID = map[string]LifecycleEvent{
 	"load":             LifecycleEventLoad,
 	"domcontentloaded": LifecycleEventDOMContentLoad,
 	"networkidle":      LifecycleEventNetworkIdle,
+	"commit":           LifecycleEventCommit,
 }
 
 // MarshalJSON marshals the enum as a quoted JSON string.
@@ -329,6 +397,11 @@ const (
 	MediaTypePrint  MediaType = "print"
 )
 
+var mediaTypeToID = map[string]MediaType{
+	"screen": MediaTypeScreen,
+	"print":  MediaTypePrint,
+}
+
 type PollingType int
 
 const (
@@ -452,6 +525,79 @@ type ResourceTiming struct {
 	ResponseEnd           float64 `js:"responseEnd"`
 }
 
+// NetworkConditions represents the network conditions to emulate.
+// A negative throughput disables the throttling of that direction.
+type NetworkConditions struct {
+	Offline            bool    `js:"offline"`
+	Latency            float64 `js:"latency"`            // in milliseconds
+	DownloadThroughput float64 `js:"downloadThroughput"` // in bytes per second
+	UploadThroughput   float64 `js:"uploadThroughput"`   // in bytes per second
+
+	// hasOffline is whether the parsed conditions set the offline mode.
+	hasOffline bool
+}
+
+// networkConditionsPresets are the named network conditions that mirror
+// the presets of the Chrome DevTools.
+//
+//nolint:gochecknoglobals
+var networkConditionsPresets = map[string]NetworkConditions{
+	"Slow 3G": {
+		Latency:            2000,
+		DownloadThroughput: 500 * 1000 / 8 * 0.8,
+		UploadThroughput:   500 * 1000 / 8 * 0.8,
+	},
+	"Fast 3G": {
+		Latency:            562.5,
+		DownloadThroughput: 1.6 * 1000 * 1000 / 8 * 0.9,
+		UploadThroughput:   750 * 1000 / 8 * 0.9,
+	},
+}
+
+// NewNetworkConditions returns network conditions without any throttling.
+func NewNetworkConditions() *NetworkConditions {
+	return &NetworkConditions{
+		DownloadThroughput: -1,
+		UploadThroughput:   -1,
+	}
+}
+
+// Parse parses the network conditions from a preset name or an object.
+func (n *NetworkConditions) Parse(ctx context.Context, opts goja.Value) error {
+	rt := k6ext.Runtime(ctx)
+	if opts == nil || goja.IsUndefined(opts) || goja.IsNull(opts) {
+		return nil
+	}
+	if name, ok := opts.Export().(string); ok {
+		preset, ok := networkConditionsPresets[name]
+		if !ok {
+			return fmt.Errorf("unknown network conditions preset %q", name)
+		}
+		*n = preset
+		return nil
+	}
+
+	o := opts.ToObject(rt)
+	for _, k := range o.Keys() {
+		switch k {
+		case "offline":
+			n.Offline = o.Get(k).ToBoolean()
+			n.hasOffline = true
+		case "latency":
+			n.Latency = o.Get(k).ToFloat()
+		case "downloadThroughput":
+			n.DownloadThroughput = o.Get(k).ToFloat()
+		case "uploadThroughput":
+			n.UploadThroughput = o.Get(k).ToFloat()
+		}
+	}
+	if math.IsNaN(n.Latency) || n.Latency < 0 {
+		return fmt.Errorf(`invalid latency "%.2f": precondition 0 <= LATENCY failed`, n.Latency)
+	}
+
+	return nil
+}
+
 // Screen represents a device screen.
 type Screen struct {
 	Width  int64 `js:"width"`
@@ -475,9 +621,10 @@ func (s *Screen) Parse(ctx context.Context, screen goja.Value) error {
 }
 
 type SelectOption struct {
-	Value *string `json:"value"`
-	Label *string `json:"label"`
-	Index *int64  `json:"index"`
+	Value        *string `json:"value"`
+	Label        *string `json:"label"`
+	Index        *int64  `json:"index"`
+	ValueOrLabel *string `json:"valueOrLabel"`
 }
 
 type Size struct {
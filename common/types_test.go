@@ -3,6 +3,8 @@ package common
 import (
 	"testing"
 
+	"github.com/grafana/xk6-browser/k6ext/k6test"
+
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -116,7 +118,7 @@ func TestLifecycleEventUnmarshalText(t *testing.T) {
 		err := evt.UnmarshalText([]byte("none"))
 		require.EqualError(t, err,
 			`invalid lifecycle event: "none"; `+
-				`must be one of: load, domcontentloaded, networkidle`)
+				`must be one of: load, domcontentloaded, networkidle, commit`)
 	})
 
 	t.Run("err/invalid_empty", func(t *testing.T) {
@@ -126,6 +128,111 @@ func TestLifecycleEventUnmarshalText(t *testing.T) {
 		err := evt.UnmarshalText([]byte(""))
 		require.EqualError(t, err,
 			`invalid lifecycle event: ""; `+
-				`must be one of: load, domcontentloaded, networkidle`)
+				`must be one of: load, domcontentloaded, networkidle, commit`)
 	})
 }
+
+func TestGeolocationParse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		opts   map[string]interface{}
+		expErr string
+	}{
+		{name: "ok", opts: map[string]interface{}{"latitude": 51.5, "longitude": -0.12, "accuracy": 10}},
+		{
+			name:   "err/latitude",
+			opts:   map[string]interface{}{"latitude": 91, "longitude": 0},
+			expErr: `invalid latitude "91.00"`,
+		},
+		{
+			name:   "err/longitude",
+			opts:   map[string]interface{}{"latitude": 0, "longitude": -181},
+			expErr: `invalid longitude "-181.00"`,
+		},
+		{
+			name:   "err/longitude_nan",
+			opts:   map[string]interface{}{"latitude": 0, "longitude": "east"},
+			expErr: `invalid longitude "NaN"`,
+		},
+		{
+			name:   "err/accuracy",
+			opts:   map[string]interface{}{"latitude": 0, "longitude": 0, "accuracy": -1},
+			expErr: `invalid accuracy "-1.00"`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			vu := k6test.NewVU(t)
+			g := NewGeolocation()
+			err := g.Parse(vu.Context(), vu.ToGojaValue(tt.opts))
+			if tt.expErr == "" {
+				require.NoError(t, err)
+				assert.Equal(t, 51.5, g.Latitude)
+				assert.Equal(t, -0.12, g.Longitude)
+				assert.Equal(t, 10.0, g.Accurracy)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expErr)
+		})
+	}
+}
+
+func TestNetworkConditionsParse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		opts   interface{}
+		want   NetworkConditions
+		expErr string
+	}{
+		{
+			name: "preset",
+			opts: "Slow 3G",
+			want: NetworkConditions{Latency: 2000, DownloadThroughput: 50000, UploadThroughput: 50000},
+		},
+		{
+			name: "object",
+			opts: map[string]interface{}{"latency": 100, "downloadThroughput": 1000},
+			want: NetworkConditions{Latency: 100, DownloadThroughput: 1000, UploadThroughput: -1},
+		},
+		{
+			name: "offline",
+			opts: map[string]interface{}{"offline": true},
+			want: NetworkConditions{Offline: true, DownloadThroughput: -1, UploadThroughput: -1, hasOffline: true},
+		},
+		{
+			name:   "err/preset",
+			opts:   "Dial-up",
+			expErr: `unknown network conditions preset "Dial-up"`,
+		},
+		{
+			name:   "err/latency",
+			opts:   map[string]interface{}{"latency": -1},
+			expErr: `invalid latency "-1.00"`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			vu := k6test.NewVU(t)
+			c := NewNetworkConditions()
+			err := c.Parse(vu.Context(), vu.ToGojaValue(tt.opts))
+			if tt.expErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, *c)
+		})
+	}
+}
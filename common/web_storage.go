@@ -0,0 +1,136 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/dop251/goja"
+
+	"github.com/grafana/xk6-browser/api"
+	"github.com/grafana/xk6-browser/k6ext"
+)
+
+// Ensure WebStorage implements the api.WebStorage interface.
+var _ api.WebStorage = &WebStorage{}
+
+const (
+	localStorage   = "localStorage"
+	sessionStorage = "sessionStorage"
+)
+
+// WebStorage is the local or the session storage of the origin of the main
+// frame of a page.
+type WebStorage struct {
+	ctx  context.Context
+	page *Page
+	// kind is either localStorage or sessionStorage.
+	kind string
+}
+
+// NewWebStorage returns the local or the session storage of the page.
+func NewWebStorage(ctx context.Context, p *Page, kind string) *WebStorage {
+	return &WebStorage{
+		ctx:  ctx,
+		page: p,
+		kind: kind,
+	}
+}
+
+// Clear removes all the items of the storage.
+func (s *WebStorage) Clear() {
+	s.page.logger.Debugf("WebStorage:Clear", "sid:%v kind:%s", s.page.sessionID(), s.kind)
+
+	if _, err := s.call("clear"); err != nil {
+		k6ext.Panic(s.ctx, "clearing %s: %w", s.kind, err)
+	}
+}
+
+// GetItem returns the value of the item, or null if there's no item with
+// the name.
+func (s *WebStorage) GetItem(name string) goja.Value {
+	s.page.logger.Debugf("WebStorage:GetItem", "sid:%v kind:%s name:%q", s.page.sessionID(), s.kind, name)
+
+	v, err := s.call("getItem", name)
+	if err != nil {
+		k6ext.Panic(s.ctx, "getting %s item %q: %w", s.kind, name, err)
+	}
+	if v == nil {
+		return goja.Null()
+	}
+	return s.page.vu.Runtime().ToValue(*v)
+}
+
+// RemoveItem removes the item.
+func (s *WebStorage) RemoveItem(name string) {
+	s.page.logger.Debugf("WebStorage:RemoveItem", "sid:%v kind:%s name:%q", s.page.sessionID(), s.kind, name)
+
+	if _, err := s.call("removeItem", name); err != nil {
+		k6ext.Panic(s.ctx, "removing %s item %q: %w", s.kind, name, err)
+	}
+}
+
+// SetItem sets the value of the item.
+func (s *WebStorage) SetItem(name string, value string) {
+	s.page.logger.Debugf("WebStorage:SetItem", "sid:%v kind:%s name:%q", s.page.sessionID(), s.kind, name)
+
+	if _, err := s.call("setItem", name, value); err != nil {
+		k6ext.Panic(s.ctx, "setting %s item %q: %w", s.kind, name, err)
+	}
+}
+
+// call calls the method of the storage in the main frame of the page, and
+// returns its string result, or nil if it returns null. The storage is not
+// available in the documents with an opaque origin, such as the sandboxed
+// iframes and the data URLs, where accessing it throws a SecurityError.
+func (s *WebStorage) call(method string, args ...string) (*string, error) {
+	var (
+		rt = s.page.vu.Runtime()
+		fn = rt.ToValue(`(kind, method, args) => {
+			let storage;
+			try {
+				storage = globalThis[kind];
+			} catch (e) {
+				return JSON.stringify({ error: e.name + ": " + e.message });
+			}
+			if (!storage) {
+				return JSON.stringify({ error: kind + " is not available" });
+			}
+			try {
+				const value = storage[method](...args);
+				return JSON.stringify({ value: typeof value === "string" ? value : null });
+			} catch (e) {
+				return JSON.stringify({ error: e.name + ": " + e.message });
+			}
+		}`)
+		opts = evalOptions{
+			forceCallable: true,
+			returnByValue: true,
+		}
+	)
+	if args == nil {
+		args = []string{}
+	}
+	frame := s.page.frameManager.MainFrame()
+	v, err := frame.evaluate(s.ctx, mainWorld, opts, fn, rt.ToValue(s.kind), rt.ToValue(method), rt.ToValue(args))
+	if err != nil {
+		return nil, err
+	}
+	gv, ok := v.(goja.Value)
+	if !ok {
+		return nil, errors.New("unexpected result")
+	}
+	var res struct {
+		Value *string `json:"value"`
+		Error string  `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(gv.String()), &res); err != nil {
+		return nil, fmt.Errorf("parsing result: %w", err)
+	}
+	if res.Error != "" {
+		return nil, fmt.Errorf("%s of %q is not accessible: %s", s.kind, frame.URL(), res.Error)
+	}
+
+	return res.Value, nil
+}
@@ -0,0 +1,147 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	k6metrics "go.k6.io/k6/metrics"
+)
+
+// webVitalBindingName is the name of the CDP binding that the pages use to
+// report their web vitals.
+const webVitalBindingName = "__k6_browser_web_vital_binding__"
+
+// webVitalsInitScript collects the web vitals of the top frame documents
+// with performance observers, and reports them through the CDP binding when
+// the page is hidden or unloaded, since that's when their values are final.
+const webVitalsInitScript = `
+(() => {
+	const binding = globalThis[%[1]q];
+	if (typeof binding !== 'function' || window !== window.top || globalThis.__k6_browser_web_vitals__) {
+		return;
+	}
+	const vitals = {};
+	let reported = false;
+	const report = () => {
+		if (reported || Object.keys(vitals).length === 0) {
+			return;
+		}
+		reported = true;
+		binding(JSON.stringify({ url: location.href, vitals }));
+	};
+	globalThis.__k6_browser_web_vitals__ = { report };
+
+	const observe = (type, fn, opts) => {
+		try {
+			const po = new PerformanceObserver(list => list.getEntries().forEach(fn));
+			po.observe(Object.assign({ type, buffered: true }, opts));
+			return po;
+		} catch (e) {
+			return null;
+		}
+	};
+
+	const lcp = observe('largest-contentful-paint', e => {
+		vitals.lcp = e.renderTime || e.loadTime || e.startTime;
+	});
+	// the largest contentful paint is not reported after the user interacts.
+	const stopLCP = () => lcp && lcp.disconnect();
+	['keydown', 'pointerdown'].forEach(type => addEventListener(type, stopLCP, { once: true, capture: true }));
+
+	// the cumulative layout shift is the largest burst of layout shifts
+	// within a session window of 5s at most, with gaps of less than 1s.
+	let session = { value: 0, first: 0, last: 0 };
+	if (observe('layout-shift', e => {
+		if (e.hadRecentInput) {
+			return;
+		}
+		if (session.value > 0 && e.startTime - session.last < 1000 && e.startTime - session.first < 5000) {
+			session.value += e.value;
+		} else {
+			session = { value: e.value, first: e.startTime, last: 0 };
+		}
+		session.last = e.startTime;
+		vitals.cls = Math.max(vitals.cls || 0, session.value);
+	})) {
+		vitals.cls = vitals.cls || 0;
+	}
+
+	observe('first-input', e => {
+		vitals.fid = e.processingStart - e.startTime;
+	});
+	observe('event', e => {
+		if (e.interactionId) {
+			vitals.inp = Math.max(vitals.inp || 0, e.duration);
+		}
+	}, { durationThreshold: 16 });
+
+	addEventListener('visibilitychange', () => {
+		if (document.visibilityState === 'hidden') {
+			report();
+		}
+	}, true);
+	addEventListener('pagehide', report, true);
+})();
+`
+
+// webVitalsReportFn reports the web vitals of the page before it's closed.
+const webVitalsReportFn = `() => globalThis.__k6_browser_web_vitals__ && globalThis.__k6_browser_web_vitals__.report()`
+
+// webVitalsPayload is the payload the pages send through the web vitals
+// binding.
+type webVitalsPayload struct {
+	URL    string             `json:"url"`
+	Vitals map[string]float64 `json:"vitals"`
+}
+
+// parseWebVitalsPayload parses the payload of a web vitals binding call.
+func parseWebVitalsPayload(payload string) (*webVitalsPayload, error) {
+	var p webVitalsPayload
+	if err := json.Unmarshal([]byte(payload), &p); err != nil {
+		return nil, fmt.Errorf("parsing web vitals payload: %w", err)
+	}
+	return &p, nil
+}
+
+// onWebVitals emits the web vitals reported by the page as k6 metrics.
+func (fs *FrameSession) onWebVitals(payload string) {
+	p, err := parseWebVitalsPayload(payload)
+	if err != nil {
+		fs.logger.Errorf("FrameSession:onWebVitals", "sid:%v tid:%v err:%v",
+			fs.session.ID(), fs.targetID, err)
+		return
+	}
+
+	vitalToMetric := map[string]*k6metrics.Metric{
+		"cls": fs.k6Metrics.BrowserWebVitalCLS,
+		"fid": fs.k6Metrics.BrowserWebVitalFID,
+		"inp": fs.k6Metrics.BrowserWebVitalINP,
+		"lcp": fs.k6Metrics.BrowserWebVitalLCP,
+	}
+
+	state := fs.vu.State()
+	tags := state.CloneTags()
+	if state.Options.SystemTags.Has(k6metrics.TagURL) {
+		tags["url"] = p.URL
+	}
+	sampleTags := k6metrics.IntoSampleTags(&tags)
+
+	now := time.Now()
+	samples := make([]k6metrics.Sample, 0, len(p.Vitals))
+	for name, value := range p.Vitals {
+		m, ok := vitalToMetric[name]
+		if !ok {
+			continue
+		}
+		fs.logger.Debugf("FrameSession:onWebVitals", "sid:%v tid:%v url:%q %s:%f",
+			fs.session.ID(), fs.targetID, p.URL, name, value)
+		samples = append(samples, k6metrics.Sample{
+			Metric: m,
+			Tags:   sampleTags,
+			Value:  value,
+			Time:   now,
+		})
+	}
+	k6metrics.PushIfNotDone(fs.ctx, state.Samples, k6metrics.ConnectedSamples{Samples: samples})
+}
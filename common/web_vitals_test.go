@@ -0,0 +1,22 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWebVitalsPayload(t *testing.T) {
+	t.Parallel()
+
+	p, err := parseWebVitalsPayload(`{"url":"https://k6.io/","vitals":{"lcp":120.5,"cls":0}}`)
+	require.NoError(t, err)
+	assert.Equal(t, &webVitalsPayload{
+		URL:    "https://k6.io/",
+		Vitals: map[string]float64{"lcp": 120.5, "cls": 0},
+	}, p)
+
+	_, err = parseWebVitalsPayload(`{`)
+	assert.ErrorContains(t, err, "parsing web vitals payload")
+}
@@ -0,0 +1,170 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/target"
+	"github.com/chromedp/cdproto/webauthn"
+	"github.com/dop251/goja"
+
+	"github.com/grafana/xk6-browser/k6ext"
+)
+
+// VirtualAuthenticatorOptions are the options of a virtual WebAuthn
+// authenticator.
+type VirtualAuthenticatorOptions struct {
+	Protocol                    string `js:"protocol"`
+	Transport                   string `js:"transport"`
+	HasResidentKey              bool   `js:"hasResidentKey"`
+	HasUserVerification         bool   `js:"hasUserVerification"`
+	IsUserVerified              bool   `js:"isUserVerified"`
+	AutomaticPresenceSimulation bool   `js:"automaticPresenceSimulation"`
+}
+
+// NewVirtualAuthenticatorOptions returns the default virtual authenticator
+// options, which approve the user presence tests automatically.
+func NewVirtualAuthenticatorOptions() *VirtualAuthenticatorOptions {
+	return &VirtualAuthenticatorOptions{
+		Protocol:                    webauthn.AuthenticatorProtocolCtap2.String(),
+		Transport:                   webauthn.AuthenticatorTransportInternal.String(),
+		AutomaticPresenceSimulation: true,
+	}
+}
+
+// Parse parses the virtual authenticator options from a JS object.
+func (o *VirtualAuthenticatorOptions) Parse(ctx context.Context, opts goja.Value) error {
+	if !gojaValueExists(opts) {
+		return nil
+	}
+	rt := k6ext.Runtime(ctx)
+	obj := opts.ToObject(rt)
+	for _, k := range obj.Keys() {
+		switch k {
+		case "protocol":
+			o.Protocol = obj.Get(k).String()
+		case "transport":
+			o.Transport = obj.Get(k).String()
+		case "hasResidentKey":
+			o.HasResidentKey = obj.Get(k).ToBoolean()
+		case "hasUserVerification":
+			o.HasUserVerification = obj.Get(k).ToBoolean()
+		case "isUserVerified":
+			o.IsUserVerified = obj.Get(k).ToBoolean()
+		case "automaticPresenceSimulation":
+			o.AutomaticPresenceSimulation = obj.Get(k).ToBoolean()
+		}
+	}
+
+	switch webauthn.AuthenticatorProtocol(o.Protocol) {
+	case webauthn.AuthenticatorProtocolCtap2, webauthn.AuthenticatorProtocolU2f:
+	default:
+		return fmt.Errorf("%q is not a valid protocol, must be one of: ctap2, u2f", o.Protocol)
+	}
+	switch webauthn.AuthenticatorTransport(o.Transport) {
+	case webauthn.AuthenticatorTransportUsb, webauthn.AuthenticatorTransportNfc,
+		webauthn.AuthenticatorTransportBle, webauthn.AuthenticatorTransportCable,
+		webauthn.AuthenticatorTransportInternal:
+	default:
+		return fmt.Errorf("%q is not a valid transport, must be one of: usb, nfc, ble, cable, internal", o.Transport)
+	}
+
+	return nil
+}
+
+func (o *VirtualAuthenticatorOptions) toProtocol() *webauthn.VirtualAuthenticatorOptions {
+	return &webauthn.VirtualAuthenticatorOptions{
+		Protocol:                    webauthn.AuthenticatorProtocol(o.Protocol),
+		Transport:                   webauthn.AuthenticatorTransport(o.Transport),
+		HasResidentKey:              o.HasResidentKey,
+		HasUserVerification:         o.HasUserVerification,
+		IsUserVerified:              o.IsUserVerified,
+		AutomaticPresenceSimulation: o.AutomaticPresenceSimulation,
+	}
+}
+
+// virtualAuthenticator is a virtual WebAuthn authenticator of a browser
+// context. The authenticators are per target in the browser, so each page
+// of the context has an authenticator of its own for it.
+type virtualAuthenticator struct {
+	id   string
+	opts *webauthn.VirtualAuthenticatorOptions
+
+	mu sync.Mutex
+	// credentials are the credentials added with AddCredential, which are
+	// also added to the authenticators of the new pages.
+	credentials []*webauthn.Credential
+	// pageIDs are the IDs of the authenticators of the pages.
+	pageIDs map[target.ID]webauthn.AuthenticatorID
+}
+
+func newVirtualAuthenticator(id string, opts *webauthn.VirtualAuthenticatorOptions) *virtualAuthenticator {
+	return &virtualAuthenticator{
+		id:      id,
+		opts:    opts,
+		pageIDs: make(map[target.ID]webauthn.AuthenticatorID),
+	}
+}
+
+// attach adds the authenticator, and its credentials, to the target of
+// the session.
+func (a *virtualAuthenticator) attach(ctx context.Context, s session) error {
+	ctx = cdp.WithExecutor(ctx, s)
+	if err := webauthn.Enable().Do(ctx); err != nil {
+		return fmt.Errorf("enabling WebAuthn: %w", err)
+	}
+	id, err := webauthn.AddVirtualAuthenticator(a.opts).Do(ctx)
+	if err != nil {
+		return fmt.Errorf("adding virtual authenticator: %w", err)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.pageIDs[s.TargetID()] = id
+	for _, c := range a.credentials {
+		if err := webauthn.AddCredential(id, c).Do(ctx); err != nil {
+			return fmt.Errorf("adding credential: %w", err)
+		}
+	}
+	return nil
+}
+
+// pageID returns the ID of the authenticator of the page.
+func (a *virtualAuthenticator) pageID(p *Page) (webauthn.AuthenticatorID, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	id, ok := a.pageIDs[p.targetID]
+	return id, ok
+}
+
+// parseCredential parses a WebAuthn credential from a JS object with the
+// credentialId, isResidentCredential, rpId, privateKey, userHandle, and
+// signCount fields. The binary fields are base64 encoded.
+func parseCredential(credential goja.Value) (*webauthn.Credential, error) {
+	if !gojaValueExists(credential) {
+		return nil, errors.New("credential is required")
+	}
+	buf, err := json.Marshal(credential.Export())
+	if err != nil {
+		return nil, fmt.Errorf("marshaling credential: %w", err)
+	}
+	var c webauthn.Credential
+	if err := json.Unmarshal(buf, &c); err != nil {
+		return nil, fmt.Errorf("unmarshaling credential: %w", err)
+	}
+	switch {
+	case c.CredentialID == "":
+		return nil, errors.New("credentialId is required")
+	case c.PrivateKey == "":
+		return nil, errors.New("privateKey is required")
+	case c.RpID == "":
+		return nil, errors.New("rpId is required")
+	}
+	return &c, nil
+}
@@ -0,0 +1,70 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/grafana/xk6-browser/k6ext/k6test"
+
+	"github.com/chromedp/cdproto/webauthn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVirtualAuthenticatorOptions(t *testing.T) {
+	vu := k6test.NewVU(t)
+
+	opts := NewVirtualAuthenticatorOptions()
+	require.NoError(t, opts.Parse(vu.Context(), nil))
+	assert.Equal(t, &webauthn.VirtualAuthenticatorOptions{
+		Protocol:                    webauthn.AuthenticatorProtocolCtap2,
+		Transport:                   webauthn.AuthenticatorTransportInternal,
+		AutomaticPresenceSimulation: true,
+	}, opts.toProtocol())
+
+	err := opts.Parse(vu.Context(), vu.ToGojaValue(map[string]interface{}{
+		"protocol":       "u2f",
+		"transport":      "usb",
+		"hasResidentKey": true,
+		"isUserVerified": true,
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, "u2f", opts.Protocol)
+	assert.Equal(t, "usb", opts.Transport)
+	assert.True(t, opts.HasResidentKey)
+	assert.True(t, opts.IsUserVerified)
+
+	err = NewVirtualAuthenticatorOptions().Parse(vu.Context(), vu.ToGojaValue(map[string]interface{}{
+		"protocol": "ctap3",
+	}))
+	assert.EqualError(t, err, `"ctap3" is not a valid protocol, must be one of: ctap2, u2f`)
+	err = NewVirtualAuthenticatorOptions().Parse(vu.Context(), vu.ToGojaValue(map[string]interface{}{
+		"transport": "wifi",
+	}))
+	assert.EqualError(t, err, `"wifi" is not a valid transport, must be one of: usb, nfc, ble, cable, internal`)
+}
+
+func TestParseCredential(t *testing.T) {
+	vu := k6test.NewVU(t)
+
+	c, err := parseCredential(vu.ToGojaValue(map[string]interface{}{
+		"credentialId":         "Y3JlZA==",
+		"isResidentCredential": true,
+		"rpId":                 "localhost",
+		"privateKey":           "a2V5",
+		"signCount":            2,
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, &webauthn.Credential{
+		CredentialID:         "Y3JlZA==",
+		IsResidentCredential: true,
+		RpID:                 "localhost",
+		PrivateKey:           "a2V5",
+		SignCount:            2,
+	}, c)
+
+	_, err = parseCredential(vu.ToGojaValue(map[string]interface{}{
+		"credentialId": "Y3JlZA==",
+		"privateKey":   "a2V5",
+	}))
+	assert.EqualError(t, err, "rpId is required")
+}
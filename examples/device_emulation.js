@@ -7,11 +7,8 @@ export default function() {
   });
 
   const device = devices['iPhone X'];
-  // The spread operator is currently unsupported by k6's Babel, so use
-  // Object.assign instead to merge browser context and device options.
-  // See https://github.com/grafana/k6/issues/2296
-  const options = Object.assign({ locale: 'es-ES' }, device);
-  const context = browser.newContext(options);
+  // The device emulation settings can be overridden by the other options.
+  const context = browser.newContext({ device: device, locale: 'es-ES' });
   const page = context.newPage();
 
   page.goto('https://k6.io/', { waitUntil: 'networkidle' });
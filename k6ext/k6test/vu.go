@@ -23,6 +23,8 @@ import (
 type VU struct {
 	*k6modulestest.VU
 	Loop *k6eventloop.EventLoop
+	// Samples receives the metric samples pushed by the VU.
+	Samples chan k6metrics.SampleContainer
 }
 
 // ToGojaValue is a convenience method for converting any value to a goja value.
@@ -70,6 +72,7 @@ func NewVU(tb testing.TB) *VU {
 			},
 			StateField: state,
 		},
+		Samples: samples,
 	}
 	ctx := k6ext.WithVU(context.Background(), vu)
 	vu.CtxField = ctx
@@ -9,6 +9,17 @@ type CustomMetrics struct {
 	BrowserFirstContentfulPaint *k6metrics.Metric
 	BrowserFirstMeaningfulPaint *k6metrics.Metric
 	BrowserLoaded               *k6metrics.Metric
+
+	BrowserNavigationDNS              *k6metrics.Metric
+	BrowserNavigationTCP              *k6metrics.Metric
+	BrowserNavigationTTFB             *k6metrics.Metric
+	BrowserNavigationDOMContentLoaded *k6metrics.Metric
+	BrowserNavigationLoad             *k6metrics.Metric
+
+	BrowserWebVitalCLS *k6metrics.Metric
+	BrowserWebVitalFID *k6metrics.Metric
+	BrowserWebVitalINP *k6metrics.Metric
+	BrowserWebVitalLCP *k6metrics.Metric
 }
 
 // RegisterCustomMetrics creates and registers our custom metrics with the k6
@@ -25,5 +36,23 @@ func RegisterCustomMetrics(registry *k6metrics.Registry) *CustomMetrics {
 			"browser_first_meaningful_paint", k6metrics.Trend, k6metrics.Time),
 		BrowserLoaded: registry.MustNewMetric(
 			"browser_loaded", k6metrics.Trend, k6metrics.Time),
+		BrowserNavigationDNS: registry.MustNewMetric(
+			"browser_navigation_dns", k6metrics.Trend, k6metrics.Time),
+		BrowserNavigationTCP: registry.MustNewMetric(
+			"browser_navigation_tcp", k6metrics.Trend, k6metrics.Time),
+		BrowserNavigationTTFB: registry.MustNewMetric(
+			"browser_navigation_ttfb", k6metrics.Trend, k6metrics.Time),
+		BrowserNavigationDOMContentLoaded: registry.MustNewMetric(
+			"browser_navigation_dom_content_loaded", k6metrics.Trend, k6metrics.Time),
+		BrowserNavigationLoad: registry.MustNewMetric(
+			"browser_navigation_load", k6metrics.Trend, k6metrics.Time),
+		BrowserWebVitalCLS: registry.MustNewMetric(
+			"browser_web_vital_cls", k6metrics.Trend),
+		BrowserWebVitalFID: registry.MustNewMetric(
+			"browser_web_vital_fid", k6metrics.Trend, k6metrics.Time),
+		BrowserWebVitalINP: registry.MustNewMetric(
+			"browser_web_vital_inp", k6metrics.Trend, k6metrics.Time),
+		BrowserWebVitalLCP: registry.MustNewMetric(
+			"browser_web_vital_lcp", k6metrics.Trend, k6metrics.Time),
 	}
 }
@@ -23,6 +23,7 @@ package log
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"regexp"
 	"runtime"
@@ -41,6 +42,31 @@ type Logger struct {
 	lastLogCall    int64
 	debugOverride  bool
 	categoryFilter *regexp.Regexp
+	categoryLevels map[string]logrus.Level
+	// verbose is the logger of the entries of the categories that are more
+	// verbose than the logger. It's derived from the logger on first use.
+	verbose *logrus.Logger
+
+	// out is the writer of the loggers derived from the logger, such as the
+	// JSON logger and the console serializer. It's shared by them so that
+	// their concurrent writes don't interleave.
+	outOnce sync.Once
+	out     io.Writer
+}
+
+// categories are the known log categories. A category is the part of the
+// category string of a log entry before the colon, such as "Page" for
+// "Page:Goto".
+//
+// It should list the categories that the extension logs with, which is
+// checked by TestLoggerCategories.
+var categories = []string{
+	"Browser", "BrowserContext", "BrowserType", "cdp", "CDPSession", "Clock",
+	"Connection", "Dialog", "Download", "ExecutionContext", "FileChooser",
+	"Frame", "FrameLocator", "FrameManager", "FrameSession", "harRecorder",
+	"IndexedDB", "Locator", "NetworkManager", "NewExecutionContext",
+	"NewFrame", "NewFrameSession", "Page", "Response", "Route", "Session",
+	"setHTTPCredentials", "Tracing", "WebStorage",
 }
 
 // NewNullLogger will create a logger where log lines will
@@ -86,12 +112,23 @@ func (l *Logger) Logf(level logrus.Level, category string, msg string, args ...i
 		return
 	}
 	// don't log if the current log level isn't in the required level.
-	if l.GetLevel() < level {
+	var verbose bool
+	if cl, ok := l.categoryLevel(category); ok {
+		if cl < level {
+			return
+		}
+		verbose = l.Logger != nil && l.GetLevel() < level
+	} else if l.GetLevel() < level {
 		return
 	}
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
+	logger := l.Logger
+	if verbose {
+		logger = l.verboseLogger()
+	}
+
 	now := time.Now().UnixNano() / 1000000
 	elapsed := now - l.lastLogCall
 	if now == elapsed {
@@ -104,17 +141,17 @@ func (l *Logger) Logf(level logrus.Level, category string, msg string, args ...i
 	if l.categoryFilter != nil && !l.categoryFilter.Match([]byte(category)) {
 		return
 	}
-	if l.Logger == nil {
+	if logger == nil {
 		magenta := color.New(color.FgMagenta).SprintFunc()
 		fmt.Printf("%s [%d]: %s - %s ms\n", magenta(category), goRoutineID(), string(msg), magenta(elapsed))
 		return
 	}
-	entry := l.WithFields(logrus.Fields{
+	entry := logger.WithFields(logrus.Fields{
 		"category":  category,
 		"elapsed":   fmt.Sprintf("%d ms", elapsed),
 		"goroutine": goRoutineID(),
 	})
-	if l.GetLevel() < level && l.debugOverride {
+	if logger.GetLevel() < level && l.debugOverride {
 		entry.Printf(msg, args...)
 		return
 	}
@@ -140,8 +177,101 @@ func (l *Logger) SetLevel(level string) error {
 	return nil
 }
 
-// DebugMode returns true if the logger level is set to Debug or higher.
+// SetCategoryLevels sets the log levels of the categories from a comma
+// separated list of category:level pairs, such as "cdp:debug,frame:info".
+// The category levels override the logger level for the log entries of the
+// categories, and the category names are case insensitive. The unknown
+// categories are ignored with a warning.
+//
+// It should be called before the logger is used.
+func (l *Logger) SetCategoryLevels(spec string) error {
+	known := make(map[string]bool, len(categories))
+	for _, c := range categories {
+		known[strings.ToLower(c)] = true
+	}
+	levels := make(map[string]logrus.Level)
+	warned := make(map[string]bool)
+	for _, cl := range strings.Split(spec, ",") {
+		cl = strings.TrimSpace(cl)
+		if cl == "" {
+			continue
+		}
+		i := strings.LastIndexByte(cl, ':')
+		if i < 0 {
+			return fmt.Errorf("invalid category log level %q, should be in the category:level format", cl)
+		}
+		category, level := strings.ToLower(strings.TrimSpace(cl[:i])), strings.TrimSpace(cl[i+1:])
+		pl, err := logrus.ParseLevel(level)
+		if err != nil {
+			return fmt.Errorf(
+				"invalid log level %q for category %q, should be one of: "+
+					"panic, fatal, error, warn, warning, info, debug, trace",
+				level, cl[:i],
+			)
+		}
+		if !known[category] {
+			if !warned[category] {
+				l.warnf("ignoring the log level of unknown log category %q", cl[:i])
+			}
+			warned[category] = true
+			continue
+		}
+		levels[category] = pl
+	}
+	l.categoryLevels = levels
+	return nil
+}
+
+// categoryLevel returns the log level of the category if it is set.
+func (l *Logger) categoryLevel(category string) (logrus.Level, bool) {
+	if len(l.categoryLevels) == 0 {
+		return 0, false
+	}
+	if i := strings.IndexByte(category, ':'); i >= 0 {
+		category = category[:i]
+	}
+	level, ok := l.categoryLevels[strings.ToLower(category)]
+	return level, ok
+}
+
+// warnf logs a warning about the configuration of the logger, which has no
+// category. It's printed out if there is no underlying logger.
+func (l *Logger) warnf(msg string, args ...interface{}) {
+	if l.Logger == nil {
+		fmt.Printf("warning: "+msg+"\n", args...)
+		return
+	}
+	l.Logger.Warnf(msg, args...)
+}
+
+// verboseLogger returns a copy of the underlying logger with the trace level,
+// which logs the entries of the categories that are more verbose than the
+// logger. The entries are already filtered by their category levels. The copy
+// is made once and shares the writer of the other derived loggers.
+//
+// It should be called with the mu lock held.
+func (l *Logger) verboseLogger() *logrus.Logger {
+	if l.verbose == nil {
+		l.verbose = &logrus.Logger{
+			Out:          l.writer(),
+			Hooks:        l.Hooks,
+			Formatter:    l.Formatter,
+			Level:        logrus.TraceLevel,
+			ReportCaller: l.Logger.ReportCaller,
+			ExitFunc:     l.ExitFunc,
+		}
+	}
+	return l.verbose
+}
+
+// DebugMode returns true if the logger level, or the level of any of
+// the categories, is set to Debug or higher.
 func (l *Logger) DebugMode() bool {
+	for _, level := range l.categoryLevels {
+		if level >= logrus.DebugLevel {
+			return true
+		}
+	}
 	return l.GetLevel() >= logrus.DebugLevel
 }
 
@@ -152,27 +282,104 @@ func (l *Logger) ReportCaller() {
 			return f.Func.Name(), fmt.Sprintf("%s:%d", f.File, f.Line)
 		}
 	}
-	l.SetFormatter(&logrus.TextFormatter{
-		CallerPrettyfier: caller(),
+	if _, ok := l.Formatter.(*logrus.JSONFormatter); ok {
+		l.SetFormatter(newJSONFormatter(caller()))
+	} else {
+		l.SetFormatter(&logrus.TextFormatter{
+			CallerPrettyfier: caller(),
+			FieldMap: logrus.FieldMap{
+				logrus.FieldKeyFile: "caller",
+			},
+		})
+	}
+	l.SetReportCaller(true)
+	l.resetVerboseLogger()
+}
+
+// SetFormat sets the format of the log entries from a format string.
+// Accepted values:
+//  - "text"
+//  - "json"
+//
+// The JSON format writes each log entry as a single JSON object per line
+// with the time, level, category, and message fields. The browser logs
+// are written with a logger of their own so that the format of the other
+// k6 logs stays the same, and it shares its writer with the console
+// serializer.
+func (l *Logger) SetFormat(format string) error {
+	switch format {
+	case "", "text":
+		return nil
+	case "json":
+		l.Logger = &logrus.Logger{
+			Out:          l.writer(),
+			Hooks:        l.Hooks,
+			Level:        l.GetLevel(),
+			ReportCaller: l.Logger.ReportCaller,
+			ExitFunc:     l.ExitFunc,
+			Formatter:    newJSONFormatter(nil),
+		}
+		l.resetVerboseLogger()
+		return nil
+	}
+	return fmt.Errorf("invalid log format %q, should be one of: text, json", format)
+}
+
+func newJSONFormatter(caller func(*runtime.Frame) (string, string)) *logrus.JSONFormatter {
+	return &logrus.JSONFormatter{
+		CallerPrettyfier: caller,
 		FieldMap: logrus.FieldMap{
+			logrus.FieldKeyMsg:  "message",
 			logrus.FieldKeyFile: "caller",
 		},
-	})
-	l.SetReportCaller(true)
+	}
+}
+
+// resetVerboseLogger makes the verbose logger be copied again from the
+// changed underlying logger.
+func (l *Logger) resetVerboseLogger() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.verbose = nil
 }
 
 // ConsoleLogFormatterSerializer creates a new logger that will
 // correctly serialize RemoteObject instances.
 func (l *Logger) ConsoleLogFormatterSerializer() *Logger {
+	out := l.writer()
 	return &Logger{
 		Logger: &logrus.Logger{
-			Out:       l.Out,
+			Out:       out,
+			Hooks:     l.Hooks,
 			Level:     l.Level,
 			Formatter: &consoleLogFormatter{l.Formatter},
 		},
+		out: out,
 	}
 }
 
+// writer returns the writer shared by the loggers derived from the logger.
+func (l *Logger) writer() io.Writer {
+	l.outOnce.Do(func() {
+		if l.out == nil {
+			l.out = &lockedWriter{w: l.Out}
+		}
+	})
+	return l.out
+}
+
+// lockedWriter serializes the writes to a writer.
+type lockedWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (w *lockedWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.w.Write(p)
+}
+
 func goRoutineID() int {
 	var buf [64]byte
 	n := runtime.Stack(buf[:], false)
@@ -21,6 +21,18 @@
 package log
 
 import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/sirupsen/logrus"
@@ -83,3 +95,185 @@ func TestConsoleLogFormatter(t *testing.T) {
 		assert.Equal(t, tc.expected, string(out))
 	}
 }
+
+func TestLoggerJSONFormat(t *testing.T) {
+	t.Parallel()
+
+	var (
+		buf bytes.Buffer
+		k6l = logrus.New()
+	)
+	k6l.SetOutput(&buf)
+	k6l.SetLevel(logrus.DebugLevel)
+
+	l := New(k6l, false, nil)
+	require.NoError(t, l.SetFormat("json"))
+	assert.IsType(t, &logrus.TextFormatter{}, k6l.Formatter, "should not change the k6 logger format")
+
+	const goroutines, lines = 10, 50
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < lines; j++ {
+				l.Debugf("test", "message %d-%d", i, j)
+			}
+		}(i)
+	}
+	// the console serializer should share the writer of the logger.
+	serializer := l.ConsoleLogFormatterSerializer()
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for j := 0; j < lines; j++ {
+			serializer.WithFields(logrus.Fields{
+				"category": "test",
+				"objects":  []interface{}{"message", j},
+			}).Debug()
+		}
+	}()
+	wg.Wait()
+
+	var n int
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var entry map[string]interface{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry), "line %q", scanner.Text())
+		assert.Equal(t, "debug", entry["level"])
+		assert.Equal(t, "test", entry["category"])
+		assert.Contains(t, entry["message"], "message")
+		assert.Contains(t, entry, "time")
+		n++
+	}
+	require.NoError(t, scanner.Err())
+	assert.Equal(t, (goroutines+1)*lines, n)
+
+	assert.EqualError(t, l.SetFormat("xml"), `invalid log format "xml", should be one of: text, json`)
+}
+
+func TestLoggerCategoryLevels(t *testing.T) {
+	t.Parallel()
+
+	var (
+		buf bytes.Buffer
+		k6l = logrus.New()
+	)
+	k6l.SetOutput(&buf)
+	k6l.SetLevel(logrus.InfoLevel)
+	k6l.SetFormatter(&logrus.JSONFormatter{})
+
+	l := New(k6l, false, nil)
+	require.NoError(t, l.SetCategoryLevels("cdp:debug, frame:warn,unknown:debug,unknown:info"))
+	assert.True(t, l.DebugMode())
+
+	var warnings []string
+	for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+		var entry map[string]interface{}
+		require.NoError(t, json.Unmarshal(line, &entry))
+		warnings = append(warnings, entry["msg"].(string))
+	}
+	assert.Equal(t, []string{`ignoring the log level of unknown log category "unknown"`}, warnings)
+	buf.Reset()
+
+	l.Debugf("cdp", "cdp debug")
+	l.Tracef("cdp:recv", "cdp trace")
+	l.Infof("Frame:Goto", "frame info")
+	l.Warnf("Frame:Goto", "frame warn")
+	l.Debugf("FrameManager:frameNavigated", "frame manager debug")
+	l.Infof("Page:Goto", "page info")
+
+	var messages []string
+	for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+		var entry map[string]interface{}
+		require.NoError(t, json.Unmarshal(line, &entry))
+		messages = append(messages, entry["msg"].(string))
+	}
+	assert.Equal(t, []string{"cdp debug", "frame warn", "page info"}, messages)
+	verbose := l.verbose
+	require.NotNil(t, verbose)
+	l.Debugf("cdp", "cdp debug")
+	assert.Same(t, verbose, l.verbose, "should reuse the verbose logger")
+
+	// the unknown categories should be warned about without a logger.
+	assert.NoError(t, New(nil, false, nil).SetCategoryLevels("unknown:debug"))
+
+	assert.EqualError(t, l.SetCategoryLevels("cdp"),
+		`invalid category log level "cdp", should be in the category:level format`)
+	assert.EqualError(t, l.SetCategoryLevels("cdp:loud"),
+		`invalid log level "loud" for category "cdp", should be one of: `+
+			`panic, fatal, error, warn, warning, info, debug, trace`)
+}
+
+// TestLoggerCategories checks that the known categories are the categories
+// of the log entries of the extension, by parsing the log calls in the
+// source files.
+func TestLoggerCategories(t *testing.T) {
+	t.Parallel()
+
+	logFuncs := map[string]bool{
+		"Tracef": true, "Debugf": true, "Infof": true, "Warnf": true, "Errorf": true, "Logf": true,
+	}
+	used := make(map[string]bool)
+	fset := token.NewFileSet()
+	err := filepath.Walk("..", func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() && (info.Name() == "tests" || info.Name() == "vendor") {
+			return filepath.SkipDir
+		}
+		if info.IsDir() || filepath.Ext(path) != ".go" || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+		f, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			return err
+		}
+		ast.Inspect(f, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(call.Args) < 2 {
+				return true
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok || !logFuncs[sel.Sel.Name] {
+				return true
+			}
+			// the other Errorf funcs, such as fmt.Errorf, don't have a category.
+			if id, ok := sel.X.(*ast.Ident); ok && (id.Name == "fmt" || id.Name == "errors") {
+				return true
+			}
+			lit, ok := call.Args[0].(*ast.BasicLit)
+			if !ok || lit.Kind != token.STRING {
+				return true
+			}
+			// the messages without a category have spaces.
+			category, err := strconv.Unquote(lit.Value)
+			if err != nil || category == "" || strings.Contains(category, " ") {
+				return true
+			}
+			if i := strings.IndexByte(category, ':'); i >= 0 {
+				category = category[:i]
+			}
+			used[strings.ToLower(category)] = true
+			return true
+		})
+		return nil
+	})
+	require.NoError(t, err)
+
+	known := make(map[string]bool, len(categories))
+	for _, c := range categories {
+		known[strings.ToLower(c)] = true
+	}
+	var missing []string
+	for c := range used {
+		if !known[c] {
+			missing = append(missing, c)
+		}
+		delete(known, c)
+	}
+	sort.Strings(missing)
+	assert.Empty(t, missing, "the categories should list all the log categories")
+	assert.Empty(t, known, "the categories should only list the log categories in use")
+}
@@ -23,7 +23,10 @@ package tests
 import (
 	_ "embed"
 	"encoding/json"
+	"fmt"
+	"net/http"
 	"testing"
+	"time"
 
 	"github.com/grafana/xk6-browser/common"
 
@@ -104,3 +107,37 @@ func TestBrowserContextOptionsExtraHTTPHeaders(t *testing.T) {
 	require.NotEmpty(t, h)
 	assert.Equal(t, "Some-Value", h[0])
 }
+
+func TestBrowserContextOptionsNetworkIdleIgnore(t *testing.T) {
+	tb := newTestBrowser(t, withHTTPServer())
+	tb.withHandler("/app", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `<script>
+			fetch("/poll");
+			setTimeout(() => fetch("/data").then(r => r.text()).then(t => document.title = t), 100);
+		</script>`)
+	})
+	tb.withHandler("/poll", func(_ http.ResponseWriter, r *http.Request) {
+		// a long poll that doesn't respond during the test.
+		select {
+		case <-r.Context().Done():
+		case <-time.After(10 * time.Second):
+		}
+	})
+	tb.withHandler("/data", func(w http.ResponseWriter, _ *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		fmt.Fprint(w, "loaded")
+	})
+	bctx := tb.NewContext(tb.toGojaValue(map[string]interface{}{
+		"networkIdleIgnore": []string{"**/poll"},
+	}))
+	t.Cleanup(bctx.Close)
+	p := bctx.NewPage()
+
+	start := time.Now()
+	require.NotNil(t, p.Goto(tb.URL("/app"), tb.toGojaValue(map[string]interface{}{
+		"waitUntil": "networkidle",
+		"timeout":   5000,
+	})))
+	assert.Less(t, time.Since(start), 5*time.Second, "the long poll should not keep the page busy")
+	assert.Equal(t, "loaded", p.Title(), "should wait for the requests made after load")
+}
@@ -0,0 +1,568 @@
+package tests
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/grafana/xk6-browser/api"
+
+	"github.com/dop251/goja"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBrowserContextRoute(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withFileServer())
+	tb.withHandler("/api", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, "original")
+	})
+
+	bctx := tb.NewContext(nil)
+	handler, err := tb.runJavaScript(`(route) => route.fulfill({ body: 'context' })`)
+	require.NoError(t, err)
+	bctx.Route(tb.toGojaValue("**/api"), handler)
+
+	p1 := bctx.NewPage()
+	require.NotNil(t, p1.Goto(tb.staticURL("empty.html"), nil))
+	p2 := bctx.NewPage()
+	require.NotNil(t, p2.Goto(tb.staticURL("empty.html"), nil))
+	fetchAPI1 := routedFetcher(tb, p1, "/api")
+	fetchAPI2 := routedFetcher(tb, p2, "/api")
+
+	assert.Equal(t, "context", fetchAPI1())
+	assert.Equal(t, "context", fetchAPI2())
+
+	// page routes take precedence over the browser context routes.
+	handler, err = tb.runJavaScript(`(route) => route.fulfill({ body: 'page' })`)
+	require.NoError(t, err)
+	p2.Route(tb.toGojaValue("**/api"), handler)
+	assert.Equal(t, "page", fetchAPI2())
+
+	bctx.Unroute(tb.toGojaValue("**/api"), nil)
+	assert.Equal(t, "original", fetchAPI1())
+	assert.Equal(t, "page", fetchAPI2())
+}
+
+func TestBrowserContextNewCDPSession(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t)
+	bctx := tb.NewContext(nil)
+	p := bctx.NewPage()
+	s := bctx.NewCDPSession(p)
+
+	res := s.Send("Runtime.evaluate", tb.toGojaValue(map[string]interface{}{
+		"expression":    "1 + 2",
+		"returnByValue": true,
+	}))
+	result, ok := res.Export().(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, map[string]interface{}{"type": "number", "value": float64(3), "description": "3"}, result["result"])
+
+	// The session is a dedicated one, so the domains of the page aren't
+	// enabled for it.
+	s.Send("Runtime.enable", nil)
+
+	var typ string
+	done := make(chan struct{})
+	s.On("Runtime.consoleAPICalled", func(_ goja.Value, args ...goja.Value) (goja.Value, error) {
+		params, _ := args[0].Export().(map[string]interface{})
+		typ = fmt.Sprint(params["type"])
+		close(done)
+		return goja.Undefined(), nil
+	})
+	p.Evaluate(tb.toGojaValue(`() => console.warn('hi')`))
+	tb.runLoopUntil(done)
+	assert.Equal(t, "warning", typ)
+
+	assertPanicErrorContains(t, func() (err interface{}) {
+		defer func() { err = recover() }()
+		s.Send("Nope.nope", nil)
+		return nil
+	}(), `sending "Nope.nope"`)
+
+	s.Detach()
+	assertPanicErrorContains(t, func() (err interface{}) {
+		defer func() { err = recover() }()
+		s.Send("Runtime.evaluate", nil)
+		return nil
+	}(), "session is detached")
+
+	// Detaching the session leaves the session of the page intact.
+	assert.Equal(t, int64(3), tb.asGojaValue(p.Evaluate(tb.toGojaValue(`() => 1 + 2`))).ToInteger())
+}
+
+func TestBrowserContextVirtualAuthenticator(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t)
+	bctx := tb.NewContext(nil)
+	bctx.NewPage()
+
+	id := bctx.AddVirtualAuthenticator(tb.toGojaValue(map[string]interface{}{
+		"protocol":       "ctap2",
+		"hasResidentKey": true,
+		"isUserVerified": true,
+	}))
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	bctx.AddCredential(id, tb.toGojaValue(map[string]interface{}{
+		"credentialId":         base64.StdEncoding.EncodeToString([]byte("cred-1")),
+		"isResidentCredential": false,
+		"rpId":                 "localhost",
+		"privateKey":           base64.StdEncoding.EncodeToString(pkcs8),
+		"signCount":            1,
+	}))
+
+	// the new pages get the credentials of the authenticator.
+	require.NotNil(t, bctx.NewPage())
+
+	credentials, ok := bctx.Credentials(id).Export().([]interface{})
+	require.True(t, ok)
+	require.Len(t, credentials, 1)
+	c, ok := credentials[0].(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("cred-1")), c["credentialId"])
+	assert.Equal(t, "localhost", c["rpId"])
+
+	// the credentials of the authenticator are kept without an open page.
+	for _, p := range bctx.Pages() {
+		p.Close(nil)
+	}
+	credentials, ok = bctx.Credentials(id).Export().([]interface{})
+	require.True(t, ok)
+	require.Len(t, credentials, 1)
+
+	bctx.RemoveVirtualAuthenticator(id)
+	assertPanicErrorContains(t, func() (err interface{}) {
+		defer func() { err = recover() }()
+		bctx.Credentials(id)
+		return nil
+	}(), `virtual authenticator "`+id+`" not found`)
+}
+
+func TestBrowserContextSetGeolocation(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withFileServer())
+	bctx := tb.NewContext(tb.toGojaValue(map[string]interface{}{
+		"permissions": []interface{}{"geolocation"},
+	}))
+	p := bctx.NewPage()
+	require.NotNil(t, p.Goto(tb.staticURL("empty.html"), nil))
+
+	bctx.SetGeolocation(tb.toGojaValue(map[string]interface{}{
+		"latitude":  51.5,
+		"longitude": -0.12,
+	}))
+
+	getPosition := `() => new Promise((resolve, reject) => {
+		navigator.geolocation.getCurrentPosition(
+			(pos) => resolve(pos.coords.latitude + ',' + pos.coords.longitude),
+			reject,
+		);
+	})`
+	assert.Equal(t, "51.5,-0.12", tb.asGojaValue(p.Evaluate(tb.toGojaValue(getPosition))).String())
+
+	assert.Panics(t, func() {
+		bctx.SetGeolocation(tb.toGojaValue(map[string]interface{}{
+			"latitude":  100,
+			"longitude": 0,
+		}))
+	}, "should not accept out of range latitudes")
+}
+
+func TestBrowserContextGrantPermissions(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withFileServer())
+	bctx := tb.NewContext(nil)
+	p := bctx.NewPage()
+	require.NotNil(t, p.Goto(tb.staticURL("empty.html"), nil))
+
+	state := func() string {
+		js := `() => navigator.permissions.query({ name: 'geolocation' }).then(r => r.state)`
+		return tb.asGojaValue(p.Evaluate(tb.toGojaValue(js))).String()
+	}
+	origin := func(url string) goja.Value {
+		return tb.toGojaValue(map[string]interface{}{"origin": url})
+	}
+
+	bctx.GrantPermissions([]string{"geolocation"}, origin("https://example.com"))
+	assert.Equal(t, "prompt", state(), "should not grant permissions to other origins")
+
+	bctx.GrantPermissions([]string{"geolocation"}, origin(tb.URL("")))
+	assert.Equal(t, "granted", state())
+
+	bctx.ClearPermissions()
+	assert.Equal(t, "prompt", state())
+
+	assertPanicErrorContains(t, func() (err interface{}) {
+		defer func() { err = recover() }()
+		bctx.GrantPermissions([]string{"teleportation"}, nil)
+		return nil
+	}(), `unknown permission "teleportation", valid permissions are: accelerometer,`)
+}
+
+func TestBrowserContextSetExtraHTTPHeaders(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withHTTPServer())
+	bctx := tb.NewContext(nil)
+	p1 := bctx.NewPage()
+
+	bctx.SetExtraHTTPHeaders(map[string]string{
+		"Some-Header":  "context",
+		"Other-Header": "context",
+	})
+	// the headers apply to the pages created after the call.
+	p2 := bctx.NewPage()
+	p2.SetExtraHTTPHeaders(map[string]string{"Some-Header": "page"})
+
+	headers := func(p api.Page) map[string][]string {
+		resp := p.Goto(tb.URL("/get"), nil)
+		require.NotNil(t, resp)
+		var body struct{ Headers map[string][]string }
+		require.NoError(t, json.Unmarshal(resp.Body().Bytes(), &body))
+		return body.Headers
+	}
+
+	h := headers(p1)
+	assert.Equal(t, []string{"context"}, h["Some-Header"])
+	assert.Equal(t, []string{"context"}, h["Other-Header"])
+
+	h = headers(p2)
+	assert.Equal(t, []string{"page"}, h["Some-Header"])
+	assert.Equal(t, []string{"context"}, h["Other-Header"])
+}
+
+func TestBrowserContextSetOffline(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withHTTPServer())
+	bctx := tb.NewContext(nil)
+	p1 := bctx.NewPage()
+	require.NotNil(t, p1.Goto(tb.URL("/get"), nil))
+
+	fetch := `() => fetch('/get').then(() => 'online', () => 'offline')`
+	onLine := func(p api.Page) string {
+		return tb.asGojaValue(p.Evaluate(tb.toGojaValue(fetch))).String()
+	}
+
+	bctx.SetOffline(true)
+	assert.Equal(t, "offline", onLine(p1))
+	assert.False(t, tb.asGojaBool(p1.Evaluate(tb.toGojaValue(`() => navigator.onLine`))))
+
+	// the pages created afterwards should also be offline.
+	p2 := bctx.NewPage()
+	assert.Panics(t, func() { p2.Goto(tb.URL("/get"), nil) })
+
+	// the pages of the other browser contexts should not be affected.
+	p3 := tb.NewContext(nil).NewPage()
+	require.NotNil(t, p3.Goto(tb.URL("/get"), nil))
+
+	bctx.SetOffline(false)
+	assert.Equal(t, "online", onLine(p1))
+	require.NotNil(t, p2.Goto(tb.URL("/get"), nil))
+}
+
+func TestBrowserContextSetNetworkConditions(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withHTTPServer())
+	bctx := tb.NewContext(nil)
+	p := bctx.NewPage()
+	require.NotNil(t, p.Goto(tb.URL("/get"), nil))
+
+	elapsed := func() float64 {
+		js := `() => { const start = performance.now(); return fetch('/get').then(() => performance.now() - start); }`
+		return tb.asGojaValue(p.Evaluate(tb.toGojaValue(js))).ToFloat()
+	}
+
+	bctx.SetNetworkConditions(tb.toGojaValue(map[string]interface{}{"latency": 500}))
+	assert.GreaterOrEqual(t, elapsed(), 500.0)
+
+	// the conditions should persist across navigations.
+	require.NotNil(t, p.Goto(tb.URL("/get"), nil))
+	assert.GreaterOrEqual(t, elapsed(), 500.0)
+
+	bctx.SetNetworkConditions(nil)
+	assert.Less(t, elapsed(), 500.0)
+
+	// the conditions without the offline key should keep the offline mode.
+	bctx.SetOffline(true)
+	bctx.SetNetworkConditions(tb.toGojaValue("Fast 3G"))
+	offline := `() => fetch('/get').then(() => false, () => true)`
+	assert.True(t, tb.asGojaBool(p.Evaluate(tb.toGojaValue(offline))))
+	bctx.SetNetworkConditions(tb.toGojaValue(map[string]interface{}{"offline": false}))
+	assert.False(t, tb.asGojaBool(p.Evaluate(tb.toGojaValue(offline))))
+
+	assertPanicErrorContains(t, func() (err interface{}) {
+		defer func() { err = recover() }()
+		bctx.SetNetworkConditions(tb.toGojaValue("Dial-up"))
+		return nil
+	}(), `unknown network conditions preset "Dial-up"`)
+}
+
+func TestBrowserContextRecordHAR(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withHTTPServer())
+	path := filepath.Join(t.TempDir(), "test.har")
+	bctx := tb.NewContext(tb.toGojaValue(map[string]interface{}{
+		"recordHar": map[string]interface{}{
+			"path":    path,
+			"content": "embed",
+		},
+	}))
+	p := bctx.NewPage()
+	require.NotNil(t, p.Goto(tb.URL("/redirect/1"), nil))
+	bctx.Close()
+
+	b, err := ioutil.ReadFile(filepath.Clean(path))
+	require.NoError(t, err)
+	var har struct {
+		Log struct {
+			Entries []struct {
+				Request struct {
+					URL string `json:"url"`
+				} `json:"request"`
+				Response struct {
+					Status      int    `json:"status"`
+					RedirectURL string `json:"redirectURL"`
+					Content     struct {
+						Text string `json:"text"`
+					} `json:"content"`
+				} `json:"response"`
+			} `json:"entries"`
+		} `json:"log"`
+	}
+	require.NoError(t, json.Unmarshal(b, &har))
+
+	entries := har.Log.Entries
+	require.GreaterOrEqual(t, len(entries), 2)
+	assert.Equal(t, tb.URL("/redirect/1"), entries[0].Request.URL)
+	assert.Equal(t, http.StatusFound, entries[0].Response.Status)
+	assert.NotEmpty(t, entries[0].Response.RedirectURL)
+	assert.Equal(t, tb.URL("/get"), entries[1].Request.URL)
+	assert.Equal(t, http.StatusOK, entries[1].Response.Status)
+	assert.Contains(t, entries[1].Response.Content.Text, `"url"`, "should embed the response body")
+}
+
+func TestBrowserContextRouteFromHAR(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withHTTPServer())
+	path := filepath.Join(t.TempDir(), "test.har")
+
+	// record the HAR with the update option.
+	bctx := tb.NewContext(nil)
+	bctx.RouteFromHAR(path, tb.toGojaValue(map[string]interface{}{"update": true}))
+	resp := bctx.NewPage().Goto(tb.URL("/get?recorded=1"), nil)
+	require.NotNil(t, resp)
+	recorded := string(resp.Body().Bytes())
+	bctx.Close()
+
+	fetch := func(p api.Page, url string) string {
+		js := fmt.Sprintf(`() => fetch(%q).then(r => r.text(), () => 'aborted')`, url)
+		return tb.asGojaValue(p.Evaluate(tb.toGojaValue(js))).String()
+	}
+
+	bctx = tb.NewContext(nil)
+	bctx.RouteFromHAR(path, tb.toGojaValue(map[string]interface{}{"query": "unordered"}))
+	p := bctx.NewPage()
+	resp = p.Goto(tb.URL("/get?recorded=1"), nil)
+	require.NotNil(t, resp)
+	assert.Equal(t, recorded, string(resp.Body().Bytes()), "should serve the recorded response")
+	assert.Equal(t, "aborted", fetch(p, tb.URL("/get?recorded=2")), "should abort the requests not in the HAR")
+
+	bctx = tb.NewContext(nil)
+	bctx.RouteFromHAR(path, tb.toGojaValue(map[string]interface{}{"query": "ignore", "notFound": "fallback"}))
+	p = bctx.NewPage()
+	require.NotNil(t, p.Goto(tb.URL("/get"), nil))
+	assert.Equal(t, recorded, fetch(p, tb.URL("/get?recorded=2")))
+	assert.Contains(t, fetch(p, tb.URL("/headers")), `"headers"`, "should fall back to the network")
+
+	assertPanicErrorContains(t, func() (err interface{}) {
+		defer func() { err = recover() }()
+		bctx.RouteFromHAR(path, tb.toGojaValue(map[string]interface{}{"notFound": "ignore"}))
+		return nil
+	}(), `invalid notFound "ignore"`)
+}
+
+func TestBrowserContextCookies(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withHTTPServer())
+	tb.withHandler("/set", func(w http.ResponseWriter, _ *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "root", Value: "1", Path: "/", HttpOnly: true})
+		http.SetCookie(w, &http.Cookie{Name: "app", Value: "2", Path: "/app", SameSite: http.SameSiteStrictMode})
+	})
+
+	bctx := tb.NewContext(nil)
+	assert.Empty(t, bctx.Cookies())
+
+	p := bctx.NewPage()
+	require.NotNil(t, p.Goto(tb.URL("/set"), nil))
+
+	cookies := bctx.Cookies()
+	require.Len(t, cookies, 2)
+	byName := make(map[string]*api.Cookie)
+	for _, c := range cookies {
+		byName[c.Name] = c
+	}
+	assert.Equal(t, &api.Cookie{
+		Name:     "root",
+		Value:    "1",
+		Domain:   "127.0.0.1",
+		Path:     "/",
+		Expires:  -1,
+		HTTPOnly: true,
+		SameSite: "Lax",
+	}, byName["root"])
+	assert.Equal(t, "Strict", byName["app"].SameSite)
+
+	cookies = bctx.Cookies(tb.URL("/"))
+	require.Len(t, cookies, 1)
+	assert.Equal(t, "root", cookies[0].Name)
+	assert.Len(t, bctx.Cookies(tb.URL("/app/page"), "http://example.com"), 2)
+
+	// the cookies of the other contexts are not included.
+	assert.Empty(t, tb.NewContext(nil).Cookies())
+}
+
+func TestBrowserContextAddCookies(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withHTTPServer())
+	tb.withHandler("/cookies", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, r.Header.Get("Cookie"))
+	})
+
+	bctx := tb.NewContext(nil)
+	bctx.AddCookies(tb.toGojaValue([]interface{}{
+		map[string]interface{}{"name": "session", "value": "token", "url": tb.URL("/")},
+		map[string]interface{}{"name": "other", "value": "1", "domain": "127.0.0.1", "path": "/other"},
+	}))
+
+	p := bctx.NewPage()
+	require.NotNil(t, p.Goto(tb.URL("/cookies"), nil))
+	assert.Equal(t, "session=token", p.InnerText("body", nil))
+	assert.Len(t, bctx.Cookies(), 2)
+
+	assertPanicErrorContains(t, func() (err interface{}) {
+		defer func() { err = recover() }()
+		bctx.AddCookies(tb.toGojaValue([]interface{}{
+			map[string]interface{}{"name": "session", "value": "token"},
+		}))
+		return nil
+	}(), `cookie #0: "session": either url or domain and path must be set`)
+}
+
+func TestBrowserContextClearCookies(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withHTTPServer())
+	bctx := tb.NewContext(nil)
+	addCookies := func() {
+		bctx.AddCookies(tb.toGojaValue([]interface{}{
+			map[string]interface{}{"name": "session", "value": "1", "domain": "127.0.0.1", "path": "/"},
+			map[string]interface{}{"name": "session", "value": "2", "domain": "localhost", "path": "/"},
+			map[string]interface{}{"name": "theme", "value": "dark", "domain": "127.0.0.1", "path": "/", "httpOnly": true},
+		}))
+	}
+	names := func() []string {
+		var names []string
+		for _, c := range bctx.Cookies() {
+			names = append(names, c.Name+"@"+c.Domain)
+		}
+		return names
+	}
+
+	addCookies()
+	bctx.ClearCookies(tb.toGojaValue(map[string]interface{}{"name": "session", "domain": "127.0.0.1"}))
+	assert.ElementsMatch(t, []string{"session@localhost", "theme@127.0.0.1"}, names())
+	for _, c := range bctx.Cookies() {
+		if c.Name == "theme" {
+			assert.True(t, c.HTTPOnly, "expected the kept cookies to keep their attributes")
+		}
+	}
+
+	bctx.ClearCookies(nil)
+	assert.Empty(t, bctx.Cookies())
+
+	addCookies()
+	bctx.ClearCookies(tb.toGojaValue(map[string]interface{}{"path": "/other"}))
+	assert.Len(t, bctx.Cookies(), 3)
+
+	// the matching cookies are deleted in a page of the context if it has one.
+	bctx.ClearCookies(nil)
+	bctx.NewPage()
+	addCookies()
+	bctx.ClearCookies(tb.toGojaValue(map[string]interface{}{"name": "session", "domain": "127.0.0.1"}))
+	assert.ElementsMatch(t, []string{"session@localhost", "theme@127.0.0.1"}, names())
+}
+
+func TestBrowserContextStorageState(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withHTTPServer())
+	tb.withHandler("/login", func(w http.ResponseWriter, _ *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "token", Path: "/"})
+		fmt.Fprint(w, `<script>localStorage.setItem("user", "k6")</script>`)
+	})
+	tb.withHandler("/page", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, r.Header.Get("Cookie"))
+	})
+
+	bctx := tb.NewContext(nil)
+	p := bctx.NewPage()
+	require.NotNil(t, p.Goto(tb.URL("/login"), nil))
+
+	path := filepath.Join(t.TempDir(), "state.json")
+	state := tb.asGojaValue(bctx.StorageState(tb.toGojaValue(map[string]interface{}{"path": path})))
+	exported, ok := state.Export().(map[string]interface{})
+	require.True(t, ok)
+	assert.Len(t, exported["cookies"], 1)
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{
+			"origin":       tb.URL(""),
+			"localStorage": []interface{}{map[string]interface{}{"name": "user", "value": "k6"}},
+		},
+	}, exported["origins"])
+	require.FileExists(t, path)
+
+	for _, storageState := range []interface{}{path, exported} {
+		bctx2 := tb.NewContext(tb.toGojaValue(map[string]interface{}{"storageState": storageState}))
+		p2 := bctx2.NewPage()
+		require.NotNil(t, p2.Goto(tb.URL("/page"), nil))
+		assert.Equal(t, "session=token", p2.InnerText("body", nil))
+		assert.Equal(t, "k6", tb.asGojaValue(p2.Evaluate(tb.toGojaValue(`() => localStorage.getItem("user")`))).String())
+
+		// the local storage is restored once, so that the changes of the
+		// pages are kept.
+		p2.Evaluate(tb.toGojaValue(`() => localStorage.setItem("user", "changed")`))
+		p2.Reload(nil)
+		assert.Equal(t, "changed", tb.asGojaValue(p2.Evaluate(tb.toGojaValue(`() => localStorage.getItem("user")`))).String())
+		assert.Equal(t, int64(1), tb.asGojaValue(p2.Evaluate(tb.toGojaValue(`() => localStorage.length`))).ToInteger())
+
+		// nor after the page clears it.
+		p2.Evaluate(tb.toGojaValue(`() => localStorage.clear()`))
+		p2.Reload(nil)
+		assert.Equal(t, int64(0), tb.asGojaValue(p2.Evaluate(tb.toGojaValue(`() => localStorage.length`))).ToInteger())
+	}
+}
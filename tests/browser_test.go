@@ -31,6 +31,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/xk6-browser/api"
 )
 
 func TestBrowserNewPage(t *testing.T) {
@@ -51,6 +53,25 @@ func TestBrowserNewPage(t *testing.T) {
 	assert.Equal(t, 0, l, "expected there to be 0 browser context after second page close, but found %d", l)
 }
 
+func TestBrowserContexts(t *testing.T) {
+	t.Parallel()
+
+	b := newTestBrowser(t)
+	require.Empty(t, b.Contexts())
+
+	bctx1 := b.NewContext(nil)
+	bctx2 := b.NewContext(nil)
+	p := bctx2.NewPage()
+	assert.ElementsMatch(t, []api.BrowserContext{bctx1, bctx2}, b.Contexts())
+	assert.Empty(t, bctx1.Pages())
+	assert.Equal(t, []api.Page{p}, bctx2.Pages())
+
+	for _, bctx := range b.Contexts() {
+		bctx.Close()
+	}
+	assert.Empty(t, b.Contexts())
+}
+
 func TestTmpDirCleanup(t *testing.T) {
 	tmpDirPath := "./"
 
@@ -143,8 +164,17 @@ func TestBrowserOn(t *testing.T) {
 func TestBrowserVersion(t *testing.T) {
 	const re = `^\d+\.\d+\.\d+\.\d+$`
 	r, _ := regexp.Compile(re)
-	ver := newTestBrowser(t).Version()
+	b := newTestBrowser(t)
+	ver := b.Version()
 	assert.Regexp(t, r, ver, "expected browser version to match regex %q, but found %q", re, ver)
+	assert.Equal(t, ver, b.Version(), "expected the browser version not to change")
+
+	info := b.VersionInfo()
+	assert.True(t, strings.HasSuffix(info["product"], "/"+ver), "unexpected product %q", info["product"])
+	assert.Regexp(t, `^\d+\.\d+$`, info["protocolVersion"])
+	assert.Regexp(t, `^\d+\.\d+`, info["jsVersion"])
+	assert.NotEmpty(t, info["revision"])
+	assert.Equal(t, b.UserAgent(), info["userAgent"])
 }
 
 // This only works for Chrome!
@@ -160,4 +190,5 @@ func TestBrowserUserAgent(t *testing.T) {
 		t.Errorf("UserAgent should start with %q, but got: %q", prefix, ua)
 	}
 	assert.Contains(t, ua, "Headless")
+	assert.Equal(t, ua, b.UserAgent(), "expected the user agent not to change")
 }
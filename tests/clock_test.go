@@ -0,0 +1,69 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/grafana/xk6-browser/common"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClock(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withHTTPServer())
+	tb.withHandler("/countdown", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `<div id="left">10</div><script>
+			let left = 10;
+			const id = setInterval(() => {
+				left--;
+				document.getElementById('left').textContent = left;
+				if (left === 0) {
+					clearInterval(id);
+				}
+			}, 1000);
+			setTimeout(() => { document.title = 'expired'; }, 5000);
+		</script>`)
+	})
+
+	p := tb.NewPage(nil)
+	cp, ok := p.(*common.Page)
+	require.True(t, ok)
+
+	const start = "2024-02-02T10:00:00Z"
+	cp.Clock.Install(tb.toGojaValue(map[string]interface{}{"time": start}))
+	cp.Clock.PauseAt(tb.toGojaValue(start))
+	require.NotNil(t, p.Goto(tb.URL("/countdown"), nil))
+
+	now := func() string {
+		return tb.asGojaValue(p.Evaluate(tb.toGojaValue(`() => new Date().toISOString()`))).String()
+	}
+	assert.Equal(t, "2024-02-02T10:00:00.000Z", now())
+
+	// each fast forward fires the interval once.
+	for i := 0; i < 3; i++ {
+		cp.Clock.FastForward(1000)
+	}
+	assert.Equal(t, "7", p.TextContent("#left", nil))
+	assert.Equal(t, "2024-02-02T10:00:03.000Z", now())
+	assert.Equal(t, "", p.Title())
+
+	cp.Clock.FastForward(2000)
+	assert.Equal(t, "expired", p.Title())
+
+	// the new documents continue from the state of the clock.
+	require.NotNil(t, p.Reload(nil))
+	assert.Equal(t, "2024-02-02T10:00:05.000Z", now())
+
+	cp.Clock.SetFixedTime(tb.toGojaValue("2030-01-01T00:00:00Z"))
+	assert.Equal(t, "2030-01-01T00:00:00.000Z", now())
+
+	assertPanicErrorContains(t, func() (err interface{}) {
+		defer func() { err = recover() }()
+		cp.Clock.Install(nil)
+		return nil
+	}(), "clock is already installed")
+}
@@ -5,6 +5,8 @@ import (
 	_ "embed"
 	"fmt"
 	"image/png"
+	"io/ioutil"
+	"path/filepath"
 	"testing"
 
 	"github.com/grafana/xk6-browser/api"
@@ -110,6 +112,70 @@ func TestElementHandleClick(t *testing.T) {
 	assert.Equal(t, res.String(), "Clicked")
 }
 
+func TestElementHandleDblclick(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t)
+	p := tb.NewPage(nil)
+	p.SetContent(`
+		<div id="editor" style="width: 200px; height: 100px">editor</div>
+		<script>
+			window.events = [];
+			const editor = document.getElementById("editor");
+			for (const type of ["mousedown", "mouseup", "click", "dblclick"]) {
+				editor.addEventListener(type, (e) => {
+					window.events.push(
+						[e.type, e.detail, e.button, e.shiftKey, e.offsetX, e.offsetY].join(":"));
+				});
+			}
+		</script>
+	`, nil)
+
+	p.Query("#editor").Dblclick(tb.toGojaValue(map[string]interface{}{
+		"modifiers": []string{"Shift"},
+		"position":  map[string]interface{}{"x": 10, "y": 20},
+	}))
+
+	got := tb.asGojaValue(p.Evaluate(tb.toGojaValue(`() => window.events`))).Export()
+	assert.Equal(t, []interface{}{
+		"mousedown:1:0:true:10:20",
+		"mouseup:1:0:true:10:20",
+		"click:1:0:true:10:20",
+		"mousedown:2:0:true:10:20",
+		"mouseup:2:0:true:10:20",
+		"click:2:0:true:10:20",
+		"dblclick:2:0:true:10:20",
+	}, got)
+	assert.False(t, tb.asGojaBool(p.Evaluate(tb.toGojaValue(`() => {
+		let shift = false;
+		document.addEventListener("click", (e) => shift = e.shiftKey, { once: true });
+		document.getElementById("editor").click();
+		return shift;
+	}`))), "expected the modifiers to be released")
+
+	// the events of the other buttons are dispatched with the same sequence.
+	p.Evaluate(tb.toGojaValue(`() => window.events = []`))
+	p.Locator("#editor", nil).Dblclick(tb.toGojaValue(map[string]interface{}{
+		"button":   "middle",
+		"position": map[string]interface{}{"x": 5, "y": 5},
+	}))
+	got = tb.asGojaValue(p.Evaluate(tb.toGojaValue(`() => window.events`))).Export()
+	assert.Equal(t, []interface{}{
+		"mousedown:1:1:false:5:5",
+		"mouseup:1:1:false:5:5",
+		"mousedown:2:1:false:5:5",
+		"mouseup:2:1:false:5:5",
+	}, got)
+
+	assertPanicErrorContains(t, func() (err interface{}) {
+		defer func() { err = recover() }()
+		p.Locator("#editor", nil).Dblclick(tb.toGojaValue(map[string]interface{}{
+			"modifiers": []string{"Hyper"},
+		}))
+		return nil
+	}(), `"Hyper" is not a valid modifier`)
+}
+
 func TestElementHandleClickWithNodeRemoved(t *testing.T) {
 	tb := newTestBrowser(t)
 	p := tb.NewPage(nil)
@@ -263,6 +329,134 @@ func TestElementHandleIsChecked(t *testing.T) {
 	element.Dispose()
 }
 
+func TestElementHandleSelectOption(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t)
+	p := tb.NewPage(nil)
+	p.SetContent(`
+		<select id="single">
+			<option value="a">A</option>
+			<option value="b">B</option>
+		</select>
+		<select id="multi" multiple>
+			<option value="a">A</option>
+			<option value="b" selected>B</option>
+			<option value="c">C</option>
+			<option value="d">D</option>
+		</select>
+		<div id="div"></div>
+		<script>
+			window.events = [];
+			for (const s of document.querySelectorAll('select')) {
+				s.addEventListener('input', () => window.events.push(s.id + ':input'));
+				s.addEventListener('change', () => window.events.push(s.id + ':change'));
+			}
+		</script>
+	`, nil)
+
+	single := p.Query("#single")
+	assert.Equal(t, []string{"b"}, single.SelectOption(tb.toGojaValue("B"), nil), "should select by label")
+	assert.Equal(t, []string{"a"}, single.SelectOption(tb.toGojaValue("a"), nil), "should select by value")
+
+	multi := p.Query("#multi")
+	option := p.Query("#multi option[value=d]")
+	selected := multi.SelectOption(tb.toGojaValue([]interface{}{
+		map[string]interface{}{"index": 0},
+		map[string]interface{}{"label": "C"},
+		option,
+	}), nil)
+	assert.Equal(t, []string{"a", "c", "d"}, selected, "should select all and deselect the others")
+	assert.Equal(t,
+		[]interface{}{"single:input", "single:change", "single:input", "single:change", "multi:input", "multi:change"},
+		p.Evaluate(tb.toGojaValue(`() => window.events`)))
+
+	assert.Empty(t, multi.SelectOption(tb.toGojaValue(nil), nil), "should deselect all")
+
+	assertPanicErrorContains(t, func() (err interface{}) {
+		defer func() { err = recover() }()
+		p.Query("#div").SelectOption(tb.toGojaValue("a"), nil)
+		return nil
+	}(), "element is not a <select> element")
+	assertPanicErrorContains(t, func() (err interface{}) {
+		defer func() { err = recover() }()
+		single.SelectOption(tb.toGojaValue("z"), nil)
+		return nil
+	}(), "options to select not found")
+}
+
+func TestElementHandleSetChecked(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t)
+	p := tb.NewPage(nil)
+	p.SetContent(`
+		<input id="a" type="radio" name="radio">
+		<input id="b" type="radio" name="radio">
+		<input id="async" type="checkbox">
+		<script>
+			// checks the checkbox a while after the click is reverted.
+			document.querySelector('#async').addEventListener('click', e => {
+				e.preventDefault();
+				setTimeout(() => { e.target.checked = true; }, 200);
+			});
+		</script>
+	`, nil)
+
+	a, b := p.Query("#a"), p.Query("#b")
+	a.SetChecked(true, nil)
+	assert.True(t, a.IsChecked())
+	b.SetChecked(true, nil)
+	assert.False(t, a.IsChecked())
+	assert.True(t, b.IsChecked())
+	b.SetChecked(true, nil)
+	assert.True(t, b.IsChecked(), "should not click a radio button in the desired state")
+
+	opts := tb.toGojaValue(jsFrameBaseOpts{Timeout: "500"})
+	assertPanicErrorContains(t, func() (err interface{}) {
+		defer func() { err = recover() }()
+		b.SetChecked(false, opts)
+		return nil
+	}(), "clicking the checkbox did not change its state")
+
+	p.Query("#async").SetChecked(true, nil)
+	assert.True(t, p.Query("#async").IsChecked(), "should wait for the state to change")
+}
+
+func TestElementHandleSetInputFiles(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t)
+	p := tb.NewPage(nil)
+	p.SetContent(`<input type="file" multiple><input type="text">`, nil)
+	element := p.Query("input[type=file]")
+
+	files := func() string {
+		js := `() => Array.from(document.querySelector('input').files).map(f => f.name).join(',')`
+		return tb.asGojaValue(p.Evaluate(tb.toGojaValue(js))).String()
+	}
+
+	path := filepath.Join(t.TempDir(), "report.txt")
+	require.NoError(t, ioutil.WriteFile(path, []byte("k6"), 0o600))
+	element.SetInputFiles(tb.toGojaValue(path), nil)
+	assert.Equal(t, "report.txt", files())
+
+	element.SetInputFiles(tb.toGojaValue([]interface{}{
+		map[string]interface{}{"name": "a.txt", "mimeType": "text/plain", "buffer": "a"},
+		map[string]interface{}{"name": "b.txt", "mimeType": "text/plain", "buffer": "b"},
+	}), nil)
+	assert.Equal(t, "a.txt,b.txt", files())
+
+	element.SetInputFiles(tb.toGojaValue([]interface{}{}), nil)
+	assert.Empty(t, files(), "should clear the files")
+
+	assertPanicErrorContains(t, func() (err interface{}) {
+		defer func() { err = recover() }()
+		p.Query("input[type=text]").SetInputFiles(tb.toGojaValue(path), nil)
+		return nil
+	}(), "element is not an <input type=file> element")
+}
+
 func TestElementHandleQueryAll(t *testing.T) {
 	const (
 		wantLiLen = 2
@@ -334,6 +528,52 @@ func TestElementHandleScreenshot(t *testing.T) {
 	assert.Equal(t, uint32(0), b)
 }
 
+func TestElementHandleScrollIntoViewIfNeeded(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t)
+	p := tb.NewPage(nil)
+	p.SetContent(`
+		<div style="height: 3000px">top</div>
+		<button id="bottom">bottom</button>
+		<button id="hidden" style="display: none">hidden</button>
+	`, nil)
+	scrollY := func() int64 {
+		return tb.asGojaValue(p.Evaluate(tb.toGojaValue(`() => window.scrollY`))).ToInteger()
+	}
+	require.Zero(t, scrollY())
+
+	button := p.Query("#bottom")
+	button.ScrollIntoViewIfNeeded(nil)
+	y := scrollY()
+	require.Positive(t, y)
+
+	// it shouldn't scroll if the element is already visible.
+	button.ScrollIntoViewIfNeeded(nil)
+	assert.Equal(t, y, scrollY())
+
+	p.Evaluate(tb.toGojaValue("button => button.remove()"), tb.toGojaValue(button))
+	assert.Panics(t, func() { button.ScrollIntoViewIfNeeded(tb.toGojaValue(map[string]interface{}{"timeout": 500})) })
+
+	assertPanicErrorContains(t, func() (err interface{}) {
+		defer func() { err = recover() }()
+		p.Locator("#hidden", nil).ScrollIntoViewIfNeeded(tb.toGojaValue(map[string]interface{}{"timeout": 500}))
+		return nil
+	}(), "timed out after")
+}
+
+func TestElementHandleScreenshotZeroSize(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t)
+	p := tb.NewPage(nil)
+	p.SetContent(`<div id="empty" style="width: 0px; height: 10px"></div>`, nil)
+
+	elem := p.Query("#empty")
+	opts := tb.toGojaValue(map[string]interface{}{"timeout": 500})
+	assert.Panics(t, func() { elem.Screenshot(opts) }, "should not take screenshots of empty elements")
+}
+
 func TestElementHandleWaitForSelector(t *testing.T) {
 	tb := newTestBrowser(t)
 	p := tb.NewPage(nil)
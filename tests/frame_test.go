@@ -0,0 +1,194 @@
+package tests
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grafana/xk6-browser/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFrameWaitForFunction(t *testing.T) {
+	t.Parallel()
+
+	script := `
+        frame.waitForFunction(%s, %s).then(ok => {
+            log('ok: '+ok.jsonValue());
+        }, err => {
+            log('err: '+err);
+        });`
+
+	t.Run("ok_frame_context", func(t *testing.T) {
+		t.Parallel()
+
+		tb := newTestBrowser(t, withFileServer())
+		p := tb.NewPage(nil)
+		require.NotNil(t, p.Goto(tb.staticURL("empty.html"), nil))
+		f := tb.attachFrame(p, "frame1", tb.staticURL("empty.html"))
+		require.NotNil(t, f)
+
+		require.NoError(t, tb.runtime().Set("frame", f))
+		var log []string
+		require.NoError(t, tb.runtime().Set("log", func(s string) { log = append(log, s) }))
+
+		// the flag of the main frame should not satisfy the predicate.
+		p.Evaluate(tb.toGojaValue(`() => window._ready = 'main'`))
+		f.Evaluate(tb.toGojaValue(`() => setTimeout(() => window._ready = 'frame', 500)`))
+
+		err := tb.vu.Loop.Start(func() error {
+			_, err := tb.runtime().RunString(fmt.Sprintf(script, `"window._ready"`, "{ polling: 100, timeout: 2000 }"))
+			return err
+		})
+		require.NoError(t, err)
+		assert.Contains(t, log, "ok: frame")
+	})
+
+	t.Run("err_page_default_timeout", func(t *testing.T) {
+		t.Parallel()
+
+		tb := newTestBrowser(t, withFileServer())
+		p := tb.NewPage(nil)
+		require.NotNil(t, p.Goto(tb.staticURL("empty.html"), nil))
+		f := tb.attachFrame(p, "frame1", tb.staticURL("empty.html"))
+		require.NotNil(t, f)
+		p.SetDefaultTimeout(300)
+
+		require.NoError(t, tb.runtime().Set("frame", f))
+		var log []string
+		require.NoError(t, tb.runtime().Set("log", func(s string) { log = append(log, s) }))
+
+		err := tb.vu.Loop.Start(func() error {
+			_, err := tb.runtime().RunString(fmt.Sprintf(script, "() => false", "{ polling: 'raf' }"))
+			return err
+		})
+		require.NoError(t, err)
+		require.Len(t, log, 1)
+		assert.Contains(t, log[0], "timed out after 300ms")
+	})
+}
+
+func TestFrameContent(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withFileServer())
+	p := tb.NewPage(nil)
+	require.NotNil(t, p.Goto(tb.staticURL("empty.html"), nil))
+	pageContent := p.Content()
+
+	f := tb.attachFrame(p, "frame1", tb.staticURL("empty.html"))
+	require.NotNil(t, f)
+
+	content := `<!DOCTYPE html><html><head></head><body><h1>Hello</h1></body></html>`
+	f.SetContent(content, tb.toGojaValue(map[string]interface{}{"waitUntil": "load"}))
+	assert.Equal(t, content, f.Content())
+	assert.Equal(t, "Hello", f.InnerText("h1", nil))
+	assert.NotContains(t, p.Content(), "<h1>Hello</h1>", "should not change the page's document")
+	assert.Contains(t, p.Content(), `id="frame1"`)
+	assert.NotEqual(t, pageContent, p.Content())
+
+	// the cross-origin frames are accessed through their own session.
+	crossOrigin := strings.Replace(tb.staticURL("empty.html"), "127.0.0.1", "localhost", 1)
+	cf := tb.attachFrame(p, "frame2", crossOrigin)
+	require.NotNil(t, cf)
+	cf.SetContent(content, nil)
+	assert.Equal(t, content, cf.Content())
+}
+
+func TestFrameTree(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withFileServer())
+	p := tb.NewPage(nil)
+	require.NotNil(t, p.Goto(tb.staticURL("empty.html"), nil))
+
+	f1 := tb.attachFrame(p, "frame1", tb.staticURL("empty.html"))
+	require.NotNil(t, f1)
+	f2 := tb.attachFrame(p, "frame2", tb.staticURL("empty.html"))
+	require.NotNil(t, f2)
+
+	// attach a frame to the first frame.
+	attach := `async (url) => {
+		const frame = document.createElement('iframe');
+		frame.src = url;
+		frame.name = 'nested';
+		document.body.appendChild(frame);
+		await new Promise(x => frame.onload = x);
+		return frame;
+	}`
+	nested := f1.EvaluateHandle(tb.toGojaValue(attach), tb.toGojaValue(tb.staticURL("empty.html"))).
+		AsElement().
+		ContentFrame()
+	require.NotNil(t, nested)
+	assert.Equal(t, "nested", nested.Name())
+	assert.Equal(t, tb.staticURL("empty.html"), nested.URL())
+
+	main := p.MainFrame()
+	assert.Nil(t, main.ParentFrame())
+	assert.Equal(t, f1.ID(), nested.ParentFrame().ID())
+	assert.Equal(t, main.ID(), f1.ParentFrame().ID())
+
+	ids := func(frames []api.Frame) []string {
+		var ids []string
+		for _, f := range frames {
+			ids = append(ids, f.ID())
+		}
+		return ids
+	}
+	assert.Equal(t, []string{f1.ID(), f2.ID()}, ids(main.ChildFrames()))
+	assert.Equal(t, []string{nested.ID()}, ids(f1.ChildFrames()))
+	assert.Equal(t, []string{main.ID(), f1.ID(), nested.ID(), f2.ID()}, ids(p.Frames()),
+		"should list the frames in the tree order")
+
+	p.Evaluate(tb.toGojaValue(`() => document.getElementById('frame1').remove()`))
+	assert.Eventually(t, func() bool {
+		return assert.ObjectsAreEqual([]string{main.ID(), f2.ID()}, ids(p.Frames()))
+	}, 5*time.Second, 50*time.Millisecond, "should not list the detached frames")
+}
+
+func TestFrameElement(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withFileServer())
+	p := tb.NewPage(nil)
+	require.NotNil(t, p.Goto(tb.staticURL("empty.html"), nil))
+
+	crossOrigin := strings.Replace(tb.staticURL("empty.html"), "127.0.0.1", "localhost", 1)
+	for id, url := range map[string]string{"frame1": tb.staticURL("empty.html"), "frame2": crossOrigin} {
+		f := tb.attachFrame(p, id, url)
+		require.NotNil(t, f)
+		el := f.FrameElement()
+		require.NotNil(t, el)
+		assert.Equal(t, id, el.GetAttribute("id").String())
+		assert.Equal(t, f.ID(), el.ContentFrame().ID())
+	}
+
+	// the nested frames are owned by an element of their parent frame.
+	parent := tb.attachFrame(p, "frame3", tb.staticURL("empty.html"))
+	require.NotNil(t, parent)
+	attach := `async (url) => {
+		const frame = document.createElement('iframe');
+		frame.src = url;
+		frame.id = 'nested';
+		document.body.appendChild(frame);
+		await new Promise(x => frame.onload = x);
+		return frame;
+	}`
+	nested := parent.EvaluateHandle(tb.toGojaValue(attach), tb.toGojaValue(tb.staticURL("empty.html"))).
+		AsElement().
+		ContentFrame()
+	require.NotNil(t, nested)
+	el := nested.FrameElement()
+	require.NotNil(t, el)
+	assert.Equal(t, "nested", el.GetAttribute("id").String())
+	assert.Equal(t, parent.ID(), el.OwnerFrame().ID())
+
+	assertPanicErrorContains(t, func() (err interface{}) {
+		defer func() { err = recover() }()
+		p.MainFrame().FrameElement()
+		return nil
+	}(), "main frame has no frame element")
+}
@@ -46,8 +46,30 @@ func TestKeyboardPress(t *testing.T) {
 		assert.Equal(t, "Hello World", el.InputValue(nil))
 	})
 
+	t.Run("insert_text", func(t *testing.T) {
+		p := tb.NewPage(nil)
+		cp, ok := p.(*common.Page)
+		require.True(t, ok)
+		kb := cp.Keyboard
+
+		p.SetContent(`<input>`, nil)
+		p.Evaluate(tb.toGojaValue(`() => {
+			window.events = [];
+			const input = document.querySelector('input');
+			for (const e of ['keydown', 'keypress', 'keyup', 'input']) {
+				input.addEventListener(e, () => window.events.push(e));
+			}
+		}`))
+		el := p.Query("input")
+		p.Focus("input", nil)
+
+		kb.InsertText("Hello 世界")
+		assert.Equal(t, "Hello 世界", el.InputValue(nil))
+		events := p.Evaluate(tb.toGojaValue(`() => window.events.join(',')`))
+		assert.Equal(t, "input", tb.asGojaValue(events).String(), "should not dispatch key events")
+	})
+
 	t.Run("combo", func(t *testing.T) {
-		t.Skip("FIXME") // See https://github.com/grafana/xk6-browser/issues/285
 		p := tb.NewPage(nil)
 		cp, ok := p.(*common.Page)
 		require.True(t, ok)
@@ -72,6 +94,34 @@ func TestKeyboardPress(t *testing.T) {
 		assert.Equal(t, "", el.InputValue(nil))
 	})
 
+	t.Run("chord", func(t *testing.T) {
+		p := tb.NewPage(nil)
+		cp, ok := p.(*common.Page)
+		require.True(t, ok)
+		kb := cp.Keyboard
+
+		p.SetContent(`<input>`, nil)
+		p.Evaluate(tb.toGojaValue(`() => {
+			window.keys = [];
+			const input = document.querySelector('input');
+			input.addEventListener('keydown', e => window.keys.push('down:' + e.key));
+			input.addEventListener('keyup', e => window.keys.push('up:' + e.key));
+		}`))
+		p.Focus("input", nil)
+
+		kb.Press("Control+Shift+KeyK", nil)
+		keys := p.Evaluate(tb.toGojaValue(`() => window.keys.join(',')`))
+		assert.Equal(t,
+			"down:Control,down:Shift,down:K,up:K,up:Shift,up:Control",
+			tb.asGojaValue(keys).String())
+
+		assertPanicErrorContains(t, func() (err interface{}) {
+			defer func() { err = recover() }()
+			kb.Press("Control+Unknown", nil)
+			return nil
+		}(), `"Unknown" is not a valid key for layout "us"`)
+	})
+
 	t.Run("newline", func(t *testing.T) {
 		p := tb.NewPage(nil)
 		cp, ok := p.(*common.Page)
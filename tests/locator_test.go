@@ -1,9 +1,15 @@
 package tests
 
 import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/grafana/xk6-browser/api"
+	"github.com/grafana/xk6-browser/common"
 
 	"github.com/dop251/goja"
 	"github.com/stretchr/testify/assert"
@@ -24,6 +30,28 @@ func TestLocator(t *testing.T) {
 		name string
 		do   func(*testBrowser, api.Page)
 	}{
+		{
+			"All", func(tb *testBrowser, p api.Page) {
+				locators := p.Locator("div > span", nil).All()
+				require.Len(t, locators, 2)
+				require.Equal(t, "hello", locators[0].TextContent(nil))
+				require.Equal(t, "bye", locators[1].TextContent(nil))
+				// all returns an empty list when nothing matches.
+				require.Empty(t, p.Locator("#doesNotExist", nil).All())
+			},
+		},
+		{
+			"AllInnerTexts", func(tb *testBrowser, p api.Page) {
+				require.Equal(t, []string{"hello", "bye"}, p.Locator("div > span", nil).AllInnerTexts())
+				require.Empty(t, p.Locator("#doesNotExist", nil).AllInnerTexts())
+			},
+		},
+		{
+			"AllTextContents", func(tb *testBrowser, p api.Page) {
+				require.Equal(t, []string{"hello", "bye"}, p.Locator("div > span", nil).AllTextContents())
+				require.Empty(t, p.Locator("#doesNotExist", nil).AllTextContents())
+			},
+		},
 		{
 			"Check", func(tb *testBrowser, p api.Page) {
 				t.Run("check", func(t *testing.T) {
@@ -47,6 +75,26 @@ func TestLocator(t *testing.T) {
 				})
 			},
 		},
+		{
+			"Clear", func(tb *testBrowser, p api.Page) {
+				p.Evaluate(tb.toGojaValue(`() => {
+					window.inputs = 0;
+					document.querySelector('#inputText').addEventListener('input', () => window.inputs++);
+				}`))
+				l := p.Locator("#inputText", nil)
+				l.Clear(nil)
+				require.Empty(t, l.InputValue(nil))
+				v := p.Evaluate(tb.toGojaValue(`() => window.inputs`))
+				require.Equal(t, int64(1), tb.asGojaValue(v).ToInteger(), "should fire an input event")
+
+				p.Locator("textarea", nil).Clear(nil)
+				require.Empty(t, p.Locator("textarea", nil).InputValue(nil))
+
+				assert.Panics(t, func() { p.Locator("#divHello", nil).Clear(nil) }, "should not clear a div")
+				p.Evaluate(tb.toGojaValue(`() => document.querySelector('#inputText').readOnly = true`))
+				assert.Panics(t, func() { l.Clear(nil) }, "should not clear a read-only input")
+			},
+		},
 		{
 			"Click", func(tb *testBrowser, p api.Page) {
 				p.Locator("#link", nil).Click(nil)
@@ -54,6 +102,12 @@ func TestLocator(t *testing.T) {
 				require.True(t, tb.asGojaBool(v), "cannot not click the link")
 			},
 		},
+		{
+			"Count", func(tb *testBrowser, p api.Page) {
+				require.Equal(t, 2, p.Locator("div > span", nil).Count())
+				require.Equal(t, 0, p.Locator("#doesNotExist", nil).Count())
+			},
+		},
 		{
 			"DblClick", func(tb *testBrowser, p api.Page) {
 				p.Locator("#link", nil).Dblclick(nil)
@@ -61,6 +115,45 @@ func TestLocator(t *testing.T) {
 				require.True(t, tb.asGojaBool(v), "cannot not double click the link")
 			},
 		},
+		{
+			"ElementHandle", func(tb *testBrowser, p api.Page) {
+				h := p.Locator("#inputText", nil).ElementHandle(nil)
+				require.NotNil(t, h)
+				defer h.Dispose()
+				require.Equal(t, "something", h.InputValue(nil))
+				require.Panics(t, func() { p.Locator("a", nil).ElementHandle(nil) },
+					"should not get a handle with multiple elements in strict mode")
+			},
+		},
+		{
+			"ElementHandles", func(tb *testBrowser, p api.Page) {
+				handles := p.Locator("div > span", nil).ElementHandles()
+				require.Len(t, handles, 2)
+				for _, h := range handles {
+					defer h.Dispose()
+				}
+				require.Equal(t, "bye", handles[1].TextContent())
+				require.Empty(t, p.Locator("#doesNotExist", nil).ElementHandles())
+			},
+		},
+		{
+			"Evaluate", func(tb *testBrowser, p api.Page) {
+				l := p.Locator("#inputText", nil)
+				v := l.Evaluate(tb.toGojaValue(`(el, suffix) => el.value + suffix`), tb.toGojaValue("!"))
+				require.Equal(t, "something!", tb.asGojaValue(v).String())
+				require.Panics(t, func() { p.Locator("a", nil).Evaluate(tb.toGojaValue(`el => el.id`), nil) },
+					"should not evaluate with multiple elements in strict mode")
+			},
+		},
+		{
+			"EvaluateAll", func(tb *testBrowser, p api.Page) {
+				l := p.Locator("div > span", nil)
+				v := l.EvaluateAll(tb.toGojaValue(`(els, sep) => els.map(el => el.textContent).join(sep)`), tb.toGojaValue(","))
+				require.Equal(t, "hello,bye", tb.asGojaValue(v).String())
+				v = p.Locator("#doesNotExist", nil).EvaluateAll(tb.toGojaValue(`els => els.length`), nil)
+				require.Equal(t, int64(0), tb.asGojaValue(v).ToInteger())
+			},
+		},
 		{
 			"DispatchEvent", func(tb *testBrowser, p api.Page) {
 				result := func() bool {
@@ -79,6 +172,43 @@ func TestLocator(t *testing.T) {
 				require.Equal(t, value, p.InputValue("#inputText", nil))
 			},
 		},
+		{
+			"Filter", func(tb *testBrowser, p api.Page) {
+				divs := p.Locator("div", nil)
+				t.Run("has_text", func(t *testing.T) {
+					opts := tb.toGojaValue(map[string]interface{}{"hasText": "BYE"})
+					require.Equal(t, "bye", divs.Filter(opts).TextContent(nil))
+				})
+				t.Run("has_text_regexp", func(t *testing.T) {
+					re, err := tb.runJavaScript(`/^hel+o$/`)
+					require.NoError(t, err)
+					opts := tb.toGojaValue(map[string]interface{}{"hasText": re})
+					require.Equal(t, "hello", divs.Filter(opts).TextContent(nil))
+				})
+				t.Run("has_text_regexp_quotes", func(t *testing.T) {
+					re, err := tb.runJavaScript("/^\"?hel+o'?`?$/u")
+					require.NoError(t, err)
+					opts := tb.toGojaValue(map[string]interface{}{"hasText": re})
+					require.Equal(t, "hello", divs.Filter(opts).TextContent(nil))
+				})
+				t.Run("has", func(t *testing.T) {
+					opts := tb.toGojaValue(map[string]interface{}{"has": p.Locator("span", nil)})
+					require.Equal(t, 2, divs.Filter(opts).Count())
+				})
+				t.Run("has_and_has_text", func(t *testing.T) {
+					opts := tb.toGojaValue(map[string]interface{}{
+						"has":     p.Locator("span", nil),
+						"hasText": "hello",
+					})
+					require.Equal(t, 1, divs.Filter(opts).Count())
+				})
+			},
+		},
+		{
+			"First", func(tb *testBrowser, p api.Page) {
+				require.Equal(t, "hello", p.Locator("div > span", nil).First().TextContent(nil))
+			},
+		},
 		{
 			"Focus", func(tb *testBrowser, p api.Page) {
 				focused := func() bool {
@@ -99,6 +229,9 @@ func TestLocator(t *testing.T) {
 				v := l.GetAttribute("value", nil)
 				require.NotNil(t, v)
 				require.Equal(t, "something", v.ToString().String())
+				// an absent attribute is null, not an empty string.
+				v = l.GetAttribute("doesNotExist", nil)
+				require.True(t, goja.IsNull(v), "should return null for an absent attribute")
 			},
 		},
 		{
@@ -133,6 +266,33 @@ func TestLocator(t *testing.T) {
 				t.Run("select", func(t *testing.T) {
 					require.Equal(t, "option text", p.Locator("#selectElement", nil).InputValue(nil))
 				})
+				t.Run("live_value", func(t *testing.T) {
+					l := p.Locator("#inputText", nil)
+					l.Fill("changed", nil)
+					require.Equal(t, "changed", l.InputValue(nil))
+					require.Equal(t, "something", l.GetAttribute("value", nil).String(), "should not change the attribute")
+				})
+				t.Run("not_form_control", func(t *testing.T) {
+					assertPanicErrorContains(t, func() (err interface{}) {
+						defer func() { err = recover() }()
+						p.Locator("#divHello", nil).InputValue(nil)
+						return nil
+					}(), "node is not an HTMLInputElement or HTMLTextAreaElement or HTMLSelectElement")
+				})
+			},
+		},
+		{
+			"Last", func(tb *testBrowser, p api.Page) {
+				require.Equal(t, "bye", p.Locator("div > span", nil).Last().TextContent(nil))
+			},
+		},
+		{
+			"Nth", func(tb *testBrowser, p api.Page) {
+				l := p.Locator("input[type=checkbox]", nil)
+				l.Nth(0).Check(nil)
+				require.True(t, p.Locator("#inputCheckbox", nil).IsChecked(nil))
+				require.False(t, l.Nth(1).IsChecked(nil))
+				require.Panics(t, func() { l.Nth(-1) }, "should not accept negative indices")
 			},
 		},
 		{
@@ -141,6 +301,28 @@ func TestLocator(t *testing.T) {
 				require.Equal(t, "xsomething", p.InputValue("#inputText", nil))
 			},
 		},
+		{
+			"PressSequentially", func(tb *testBrowser, p api.Page) {
+				p.Evaluate(tb.toGojaValue(`() => {
+					window.keys = [];
+					const input = document.querySelector('#inputText');
+					input.value = '';
+					input.addEventListener('keydown', e => window.keys.push(e.key));
+				}`))
+				start := time.Now()
+				p.Locator("#inputText", nil).PressSequentially("aB", tb.toGojaValue(map[string]interface{}{"delay": 100}))
+				require.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond, "should wait between the characters")
+				require.Equal(t, "aB", p.InputValue("#inputText", nil))
+				keys := p.Evaluate(tb.toGojaValue(`() => window.keys.join(',')`))
+				require.Equal(t, "a,Shift,B", tb.asGojaValue(keys).String())
+			},
+		},
+		{
+			"Screenshot", func(tb *testBrowser, p api.Page) {
+				buf := p.Locator("#inputText", nil).Screenshot(nil)
+				require.True(t, bytes.HasPrefix(buf.Bytes(), []byte("\x89PNG")), "should be a png image")
+			},
+		},
 		{
 			"SelectOption", func(tb *testBrowser, p api.Page) {
 				l := p.Locator("#selectElement", nil)
@@ -150,7 +332,41 @@ func TestLocator(t *testing.T) {
 			},
 		},
 		{
-			"Tap", func(tb *testBrowser, p api.Page) {
+			"SetChecked", func(tb *testBrowser, p api.Page) {
+				check := func() bool {
+					v := p.Evaluate(tb.toGojaValue(`() => window.check`))
+					return tb.asGojaBool(v)
+				}
+				l := p.Locator("#inputCheckbox", nil)
+				l.SetChecked(true, nil)
+				require.True(t, check(), "cannot check the input box")
+				l.SetChecked(true, nil)
+				require.True(t, l.IsChecked(nil), "should not toggle a checked input box")
+				l.SetChecked(false, nil)
+				require.False(t, check(), "cannot uncheck the input box")
+				l.SetChecked(false, nil)
+				require.False(t, l.IsChecked(nil), "should not toggle an unchecked input box")
+
+				assert.Panics(t, func() {
+					p.Locator("#inputText", nil).SetChecked(true, tb.toGojaValue(jsFrameBaseOpts{Timeout: "100"}))
+				}, "should not check a text input")
+			},
+		},
+		{
+			"SetInputFiles", func(tb *testBrowser, p api.Page) {
+				l := p.Locator("#inputFile", nil)
+				l.SetInputFiles(tb.toGojaValue(map[string]interface{}{
+					"name": "a.txt", "mimeType": "text/plain", "buffer": "a",
+				}), nil)
+				v := p.Evaluate(tb.toGojaValue(`() => document.querySelector('#inputFile').files[0].name`))
+				require.Equal(t, "a.txt", tb.asGojaValue(v).String())
+			},
+		},
+		{
+			"Tap", func(tb *testBrowser, _ api.Page) {
+				bctx := tb.NewContext(tb.toGojaValue(map[string]interface{}{"hasTouch": true}))
+				p := bctx.NewPage()
+				require.NotNil(t, p.Goto(tb.staticURL("locators.html"), nil))
 				result := func() bool {
 					v := p.Evaluate(tb.toGojaValue(`() => window.result`))
 					return tb.asGojaBool(v)
@@ -199,6 +415,9 @@ func TestLocator(t *testing.T) {
 		{
 			"Check", func(l api.Locator, tb *testBrowser) { l.Check(timeout(tb)) },
 		},
+		{
+			"Clear", func(l api.Locator, tb *testBrowser) { l.Clear(timeout(tb)) },
+		},
 		{
 			"Click", func(l api.Locator, tb *testBrowser) { l.Click(timeout(tb)) },
 		},
@@ -210,6 +429,9 @@ func TestLocator(t *testing.T) {
 				l.DispatchEvent("click", tb.toGojaValue("mouseevent"), timeout(tb))
 			},
 		},
+		{
+			"ElementHandle", func(l api.Locator, tb *testBrowser) { l.ElementHandle(timeout(tb)) },
+		},
 		{
 			"Focus", func(l api.Locator, tb *testBrowser) { l.Focus(timeout(tb)) },
 		},
@@ -234,9 +456,26 @@ func TestLocator(t *testing.T) {
 		{
 			"Press", func(l api.Locator, tb *testBrowser) { l.Press("a", timeout(tb)) },
 		},
+		{
+			"PressSequentially", func(l api.Locator, tb *testBrowser) { l.PressSequentially("a", timeout(tb)) },
+		},
+		{
+			"Screenshot", func(l api.Locator, tb *testBrowser) { l.Screenshot(timeout(tb)) },
+		},
+		{
+			"ScrollIntoViewIfNeeded", func(l api.Locator, tb *testBrowser) { l.ScrollIntoViewIfNeeded(timeout(tb)) },
+		},
 		{
 			"SelectOption", func(l api.Locator, tb *testBrowser) { l.SelectOption(tb.toGojaValue(""), timeout(tb)) },
 		},
+		{
+			"SetChecked", func(l api.Locator, tb *testBrowser) { l.SetChecked(true, timeout(tb)) },
+		},
+		{
+			"SetInputFiles", func(l api.Locator, tb *testBrowser) {
+				l.SetInputFiles(tb.toGojaValue([]string{}), timeout(tb))
+			},
+		},
 		{
 			"Tap", func(l api.Locator, tb *testBrowser) { l.Tap(timeout(tb)) },
 		},
@@ -352,7 +591,11 @@ func TestLocatorElementState(t *testing.T) {
 		},
 	}
 	for _, tt := range sanityTests {
-		t.Run("timeout/"+tt.name, func(t *testing.T) {
+		// the visibility of a missing element is reported without an error.
+		if tt.name == "IsVisible" || tt.name == "IsHidden" {
+			continue
+		}
+		t.Run("notexist/"+tt.name, func(t *testing.T) {
 			t.Parallel()
 
 			tb := newTestBrowser(t)
@@ -361,6 +604,29 @@ func TestLocatorElementState(t *testing.T) {
 			assert.Panics(t, func() { tt.do(p.Locator("NOTEXIST", nil), tb) })
 		})
 	}
+	t.Run("notexist/IsVisible_IsHidden", func(t *testing.T) {
+		t.Parallel()
+
+		tb := newTestBrowser(t)
+		p := tb.NewPage(nil)
+		p.SetContent("<html></html>", nil)
+		l := p.Locator("NOTEXIST", nil)
+		start := time.Now()
+		assert.False(t, l.IsVisible(nil))
+		assert.True(t, l.IsHidden(nil))
+		assert.Less(t, time.Since(start), time.Second, "should not wait for the element")
+	})
+	t.Run("timeout/frame", func(t *testing.T) {
+		t.Parallel()
+
+		tb := newTestBrowser(t)
+		p := tb.NewPage(nil)
+		p.SetContent("<html></html>", nil)
+		l := p.FrameLocator("NOTEXIST").Locator("input", nil)
+		start := time.Now()
+		assert.Panics(t, func() { l.IsVisible(timeout(tb)) })
+		assert.Less(t, time.Since(start), 5*time.Second, "should honor the timeout")
+	})
 
 	tb := newTestBrowser(t, withFileServer())
 	p := tb.NewPage(nil)
@@ -371,3 +637,286 @@ func TestLocatorElementState(t *testing.T) {
 		})
 	}
 }
+
+func TestLocatorBoundingBox(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withFileServer())
+	p := tb.NewPage(nil)
+	p.SetContent(`
+		<style>body { margin: 0 }</style>
+		<header style="position: sticky; top: 0; height: 50px">header</header>
+		<div style="height: 3000px"></div>
+		<p style="display: none">hidden</p>
+	`, nil)
+
+	p.Evaluate(tb.toGojaValue(`() => window.scrollTo(0, 1000)`))
+	box := p.Locator("header", nil).BoundingBox(nil)
+	require.NotNil(t, box)
+	assert.Equal(t, 0.0, box.Y, "should stay at the top of the viewport")
+	assert.Equal(t, 50.0, box.Height)
+
+	assert.Nil(t, p.Locator("p", nil).BoundingBox(nil), "should not have a box when not rendered")
+
+	// the boxes of the elements in frames are in page coordinates.
+	p = tb.NewPage(nil)
+	require.NotNil(t, p.Goto(tb.staticURL("empty.html"), nil))
+	p.Evaluate(tb.toGojaValue(`() => {
+		document.body.style.margin = '0';
+		document.body.style.paddingTop = '100px';
+	}`))
+	crossOrigin := strings.Replace(tb.staticURL("empty.html"), "127.0.0.1", "localhost", 1)
+	for _, url := range []string{tb.staticURL("empty.html"), crossOrigin} {
+		p.Evaluate(tb.toGojaValue(`() => document.querySelectorAll('iframe').forEach(f => f.remove())`))
+		f := tb.attachFrame(p, "frame1", url)
+		require.NotNil(t, f)
+		p.Evaluate(tb.toGojaValue(`() => {
+			const f = document.getElementById('frame1');
+			f.style.border = '5px solid';
+			f.style.padding = '0';
+			f.style.marginLeft = '20px';
+		}`))
+		f.SetContent(`<style>body { margin: 0 }</style><div style="margin: 10px; height: 10px"></div>`, nil)
+
+		box := f.Locator("div", nil).BoundingBox(nil)
+		require.NotNil(t, box, url)
+		assert.Equal(t, 35.0, box.X, url)
+		assert.Equal(t, 115.0, box.Y, url)
+	}
+}
+
+func TestLocatorHover(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t)
+	p := tb.NewPage(nil)
+	p.SetContent(`
+		<style>
+			body { margin: 0 }
+			#menu { display: none }
+			nav:hover #menu { display: block }
+		</style>
+		<div style="height: 2000px"></div>
+		<nav style="width: 100px; height: 50px">
+			<span>menu</span>
+			<ul id="menu"><li>item</li></ul>
+		</nav>
+		<script>
+			window.moves = [];
+			document.querySelector('nav').addEventListener('mousemove', e => {
+				const r = e.currentTarget.getBoundingClientRect();
+				window.moves.push((e.clientX - r.left) + ',' + (e.clientY - r.top) + ':' + e.shiftKey);
+			});
+		</script>
+	`, nil)
+	moves := func() string {
+		return tb.asGojaValue(p.Evaluate(tb.toGojaValue(`() => window.moves.join(';')`))).String()
+	}
+
+	nav := p.Locator("nav", nil)
+	require.False(t, p.Locator("#menu", nil).IsVisible(nil), "should not show the menu first")
+	nav.Hover(tb.toGojaValue(map[string]interface{}{
+		"position":  map[string]interface{}{"x": 10, "y": 20},
+		"modifiers": []string{"Shift"},
+	}))
+	assert.True(t, p.Locator("#menu", nil).IsVisible(nil), "should activate :hover")
+	assert.Equal(t, "10,20:true", moves(), "should move to the position with the modifiers")
+
+	cp, ok := p.(*common.Page)
+	require.True(t, ok)
+	cp.Mouse.Move(0, 0, nil)
+	nav.Hover(nil)
+	assert.True(t, strings.HasSuffix(moves(), ";50,25:false"),
+		"should move to the center without the modifiers: %s", moves())
+
+	// the hover is retried until the element is stable, unless it's forced.
+	p.Evaluate(tb.toGojaValue(`() => {
+		const nav = document.querySelector('nav');
+		nav.style.position = 'relative';
+		window.moving = setInterval(() => { nav.style.left = (Math.random() * 100) + 'px' }, 10);
+	}`))
+	assertPanicErrorContains(t, func() (err interface{}) {
+		defer func() { err = recover() }()
+		nav.Hover(tb.toGojaValue(jsFrameBaseOpts{Timeout: "500"}))
+		return nil
+	}(), "timed out")
+	require.NotPanics(t, func() {
+		nav.Hover(tb.toGojaValue(map[string]interface{}{"force": true}))
+	})
+}
+
+func TestLocatorTap(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t)
+	bctx := tb.NewContext(tb.toGojaValue(map[string]interface{}{"hasTouch": true}))
+	p := bctx.NewPage()
+	p.SetContent(`
+		<style>body { margin: 0 }</style>
+		<div style="height: 2000px"></div>
+		<button style="width: 100px; height: 50px">tap</button>
+		<script>
+			window.taps = [];
+			document.querySelector('button').addEventListener('touchstart', e => {
+				const r = e.target.getBoundingClientRect();
+				const t = e.touches[0];
+				window.taps.push((t.clientX - r.left) + ',' + (t.clientY - r.top) + ':' + e.shiftKey);
+			});
+		</script>
+	`, nil)
+	taps := func() string {
+		return tb.asGojaValue(p.Evaluate(tb.toGojaValue(`() => window.taps.join(';')`))).String()
+	}
+
+	l := p.Locator("button", nil)
+	l.Tap(nil)
+	assert.Equal(t, "50,25:false", taps(), "should scroll into view and tap the center")
+
+	l.Tap(tb.toGojaValue(map[string]interface{}{
+		"position":  map[string]interface{}{"x": 10, "y": 20},
+		"modifiers": []string{"Shift"},
+	}))
+	assert.Equal(t, "50,25:false;10,20:true", taps(), "should tap the position with the modifiers")
+
+	l.Tap(tb.toGojaValue(map[string]interface{}{
+		"position": map[string]interface{}{"x": 10, "y": 20},
+	}))
+	assert.Equal(t, "50,25:false;10,20:true;10,20:false", taps(), "should release the modifiers")
+
+	t.Run("err_no_touch", func(t *testing.T) {
+		t.Parallel()
+
+		tb := newTestBrowser(t)
+		p := tb.NewPage(nil)
+		p.SetContent(`<button>tap</button>`, nil)
+		assertPanicErrorContains(t, func() (err interface{}) {
+			defer func() { err = recover() }()
+			p.Locator("button", nil).Tap(nil)
+			return nil
+		}(), "touch emulation is not enabled, set the hasTouch browser context option")
+	})
+}
+
+func TestLocatorAriaSnapshot(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t)
+	p := tb.NewPage(nil)
+	p.SetContent(`
+		<main>
+			<h1>The
+				Title</h1>
+			<ul>
+				<li>one</li>
+				<li>two</li>
+			</ul>
+			<label><input type="checkbox" checked> remember me</label>
+			<button disabled>OK</button>
+		</main>
+		<p style="display: none">hidden</p>
+	`, nil)
+
+	want := `- main:
+  - heading "The Title" [level=1]
+  - list:
+    - listitem: one
+    - listitem: two
+  - checkbox "remember me" [checked]
+  - text: remember me
+  - button "OK" [disabled]`
+	assert.Equal(t, want, p.Locator("main", nil).AriaSnapshot(nil))
+	assert.Equal(t, `- heading "The Title" [level=1]`, p.Locator("h1", nil).AriaSnapshot(nil))
+	assert.Empty(t, p.Locator("p", nil).AriaSnapshot(nil), "should be empty when not rendered")
+}
+
+func TestFrameLocator(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withHTTPServer())
+	serve := func(path, body string) {
+		tb.withHandler(path, func(w http.ResponseWriter, _ *http.Request) {
+			fmt.Fprint(w, body)
+		})
+	}
+	serve("/outer", `<button>outer</button><iframe id="inner" src="/inner"></iframe>`)
+	serve("/inner", `<button>inner</button><iframe id="leaf" src="/leaf"></iframe>`)
+	serve("/leaf", `<button>leaf</button><input placeholder="name">`)
+	serve("/lazy", `<script>
+		setTimeout(() => {
+			const f = document.createElement('iframe');
+			f.src = '/leaf';
+			document.body.appendChild(f);
+		}, 200);
+	</script>`)
+
+	p := tb.NewPage(nil)
+	require.NotNil(t, p.Goto(tb.URL("/outer"), nil))
+
+	inner := p.FrameLocator("#inner")
+	assert.Equal(t, "inner", inner.GetByRole("button", nil).InnerText(nil))
+	assert.Equal(t, 1, inner.Locator("button", nil).Count())
+
+	// the nested frame locators enter each of the iframes.
+	leaf := inner.FrameLocator("#leaf")
+	assert.Equal(t, "leaf", leaf.Locator("button", nil).TextContent(nil))
+	input := leaf.GetByPlaceholder(tb.toGojaValue("name"), nil)
+	input.Fill("k6", nil)
+	assert.Equal(t, "k6", input.InputValue(nil))
+	assert.Equal(t, "leaf", p.Locator("#inner", nil).FrameLocator("#leaf").GetByText(tb.toGojaValue("leaf"), nil).InnerText(nil))
+
+	// the frame locators wait for the iframes to be attached.
+	require.NotNil(t, p.Goto(tb.URL("/lazy"), nil))
+	assert.Equal(t, "leaf", p.FrameLocator("iframe").Locator("button", nil).InnerText(nil))
+
+	assertPanicErrorContains(t, func() (err interface{}) {
+		defer func() { err = recover() }()
+		p.FrameLocator("button").Locator("button", nil).InnerText(nil)
+		return nil
+	}(), `selector "button" does not match an iframe`)
+}
+
+func TestLocatorAndOr(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t)
+	p := tb.NewPage(nil)
+	p.SetContent(`
+		<button title="save">Save</button>
+		<button title="cancel">Cancel</button>
+		<a href="#" title="save">Save link</a>
+		<div role="dialog">Confirm</div>
+	`, nil)
+
+	and := p.Locator("button", nil).And(p.Locator("[title=save]", nil))
+	assert.Equal(t, 1, and.Count())
+	assert.Equal(t, "Save", and.TextContent(nil))
+
+	or := p.Locator("[role=dialog]", nil).Or(p.Locator("button", nil))
+	require.Equal(t, 3, or.Count())
+	assert.Equal(t, "Save", or.First().TextContent(nil), "should be in the document order")
+	assert.Equal(t, "Confirm", or.Last().TextContent(nil))
+
+	both := p.Locator("[title]", nil).Or(p.Locator("a", nil))
+	assert.Equal(t, 3, both.Count(), "should include the elements matching both sides once")
+
+	assert.Equal(t, 0, p.Locator("a", nil).And(p.Locator("button", nil)).Count())
+
+	assertPanicErrorContains(t, func() (err interface{}) {
+		defer func() { err = recover() }()
+		f := tb.attachFrame(p, "frame1", "about:blank")
+		p.Locator("button", nil).And(f.Locator("button", nil))
+		return nil
+	}(), "locators must belong to the same frame")
+}
+
+func TestLocatorHighlightHeadless(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t)
+	p := tb.NewPage(nil)
+	p.SetContent(`<button>one</button><button>two</button>`, nil)
+
+	assert.NotPanics(t, func() { p.Locator("button", nil).Highlight() })
+	marked := tb.asGojaValue(p.Evaluate(tb.toGojaValue(`() => document.querySelectorAll("[data-k6-browser-highlight]").length`)))
+	assert.Equal(t, int64(0), marked.ToInteger(), "should be a no-op in headless mode")
+}
@@ -0,0 +1,40 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/xk6-browser/common"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMouseWheel(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withFileServer())
+	p := tb.NewPage(nil)
+	require.NotNil(t, p.Goto(tb.staticURL("wheel.html"), nil))
+	cp, ok := p.(*common.Page)
+	require.True(t, ok)
+
+	// the wheel event is dispatched at the current mouse position.
+	cp.Mouse.Move(50, 50, nil)
+	cp.Mouse.Wheel(20, 100)
+
+	wheel := func() string {
+		return tb.asGojaValue(p.Evaluate(tb.toGojaValue(`() => window.wheel.join(';')`))).String()
+	}
+	assert.Equal(t, "20,100", wheel())
+
+	scrolled := func() string {
+		return tb.asGojaValue(p.Evaluate(tb.toGojaValue(`() => window.scrolled`))).String()
+	}
+	assert.Eventually(t, func() bool { return scrolled() == "20,100" }, time.Second, 50*time.Millisecond)
+
+	// moving outside of the list should not scroll it.
+	cp.Mouse.Move(500, 500, nil)
+	cp.Mouse.Wheel(0, 100)
+	assert.Equal(t, "20,100", wheel())
+}
@@ -28,9 +28,18 @@ import (
 	"errors"
 	"fmt"
 	"image/png"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/grafana/xk6-browser/api"
+	"github.com/grafana/xk6-browser/common"
 
 	"github.com/dop251/goja"
+	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -74,6 +83,41 @@ func TestPageEmulateMedia(t *testing.T) {
 	assert.True(t, res.ToBoolean(), "expected reduced motion setting to be 'reduce'")
 }
 
+func TestPageEmulateMediaReset(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withFileServer())
+	p := tb.NewPage(nil)
+
+	matches := func(query string) bool {
+		js := fmt.Sprintf("() => matchMedia(%q).matches", query)
+		return tb.asGojaBool(p.Evaluate(tb.toGojaValue(js)))
+	}
+
+	p.EmulateMedia(tb.toGojaValue(map[string]interface{}{
+		"colorScheme":  "dark",
+		"forcedColors": "active",
+	}))
+	assert.True(t, matches("(prefers-color-scheme: dark)"))
+	assert.True(t, matches("(forced-colors: active)"))
+
+	// the emulation persists across navigations.
+	require.NotNil(t, p.Goto(tb.staticURL("empty.html"), nil))
+	assert.True(t, matches("(prefers-color-scheme: dark)"))
+	assert.True(t, matches("(forced-colors: active)"))
+
+	// null resets the emulation while the other options are kept.
+	p.EmulateMedia(tb.toGojaValue(map[string]interface{}{
+		"forcedColors": nil,
+	}))
+	assert.True(t, matches("(prefers-color-scheme: dark)"))
+	assert.False(t, matches("(forced-colors: active)"))
+
+	assert.Panics(t, func() {
+		p.EmulateMedia(tb.toGojaValue(map[string]interface{}{"media": "tv"}))
+	})
+}
+
 func TestPageContent(t *testing.T) {
 	t.Parallel()
 
@@ -86,6 +130,65 @@ func TestPageContent(t *testing.T) {
 	assert.Equal(t, content, p.Content())
 }
 
+func TestPageSetContentWaitUntil(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withHTTPServer())
+	tb.withHandler("/style.css", func(w http.ResponseWriter, _ *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.Header().Set("Content-Type", "text/css")
+		fmt.Fprint(w, "h1 { color: rgb(255, 0, 0); }")
+	})
+	p := tb.NewPage(nil)
+
+	content := `<html><head><link rel="stylesheet" href="style.css"></head><body><h1>Hello</h1></body></html>`
+	p.SetContent(content, tb.toGojaValue(map[string]interface{}{
+		"baseURL":   tb.URL("/"),
+		"waitUntil": "load",
+	}))
+
+	color := `() => getComputedStyle(document.querySelector('h1')).color`
+	assert.Equal(t, "rgb(255, 0, 0)", tb.asGojaValue(p.Evaluate(tb.toGojaValue(color))).String(),
+		"should resolve the stylesheet against the base URL and wait for it to load")
+	assert.Equal(t, "complete", tb.asGojaValue(p.Evaluate(tb.toGojaValue(`() => document.readyState`))).String())
+
+	p.SetContent(`<p>hi</p>`, tb.toGojaValue(map[string]interface{}{"waitUntil": "domcontentloaded"}))
+	assert.Equal(t, "hi", p.InnerText("p", nil))
+}
+
+func TestPageEvalOnSelector(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t)
+	p := tb.NewPage(nil)
+	p.SetContent(`
+		<ul>
+			<li id="a" data-n="1">A</li>
+			<li id="b" data-n="2">B</li>
+		</ul>
+	`, nil)
+
+	got := p.EvalOnSelector("li", tb.toGojaValue(`(e, suffix) => e.id + suffix`), tb.toGojaValue("!"))
+	assert.Equal(t, "a!", tb.asGojaValue(got).String())
+
+	got = p.EvalOnSelectorAll("li", tb.toGojaValue(`(es) => es.map(e => e.dataset.n).join(",")`), nil)
+	assert.Equal(t, "1,2", tb.asGojaValue(got).String())
+
+	got = p.EvalOnSelectorAll("p", tb.toGojaValue(`(es) => es.length`), nil)
+	assert.Equal(t, int64(0), tb.asGojaValue(got).ToInteger(), "should not wait for the elements")
+
+	assertPanicErrorContains(t, func() (err interface{}) {
+		defer func() { err = recover() }()
+		p.EvalOnSelector("p", tb.toGojaValue(`(e) => e.id`), nil)
+		return nil
+	}(), `no element matches selector "p"`)
+
+	require.NoError(t, tb.runtime().Set("page", p))
+	v, err := tb.runJavaScript(`page.$eval("#b", e => e.textContent) + page.$$eval("li", es => es.length)`)
+	require.NoError(t, err)
+	assert.Equal(t, "B2", v.String())
+}
+
 func TestPageEvaluate(t *testing.T) {
 	t.Parallel()
 
@@ -425,6 +528,77 @@ func TestPageScreenshotFullpage(t *testing.T) {
 	assert.Greater(t, b, uint32(128))
 }
 
+func TestPageViewportSize(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t)
+	p := tb.NewPage(nil)
+
+	// the size is available before navigating.
+	assert.Equal(t, map[string]float64{"width": 1280, "height": 720}, p.ViewportSize())
+
+	p.SetViewportSize(tb.toGojaValue(struct {
+		Width  float64 `js:"width"`
+		Height float64 `js:"height"`
+	}{Width: 640, Height: 480}))
+	assert.Equal(t, map[string]float64{"width": 640, "height": 480}, p.ViewportSize())
+
+	p = tb.NewContext(tb.toGojaValue(map[string]interface{}{
+		"viewport": nil,
+	})).NewPage()
+	assert.Equal(t, map[string]float64{"width": 1280, "height": 720}, p.ViewportSize())
+}
+
+func TestPageScreenshotScale(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t)
+	bctx := tb.NewContext(tb.toGojaValue(map[string]interface{}{
+		"deviceScaleFactor": 2,
+		"viewport":          map[string]interface{}{"width": 200, "height": 100},
+	}))
+	p := bctx.NewPage()
+
+	size := func(scale string) (int, int) {
+		buf := p.Screenshot(tb.toGojaValue(map[string]interface{}{"scale": scale}))
+		img, err := png.Decode(bytes.NewReader(buf.Bytes()))
+		require.NoError(t, err)
+		return img.Bounds().Max.X, img.Bounds().Max.Y
+	}
+
+	w, h := size("device")
+	assert.Equal(t, 400, w)
+	assert.Equal(t, 200, h)
+
+	w, h = size("css")
+	assert.Equal(t, 200, w)
+	assert.Equal(t, 100, h)
+}
+
+func TestPageScreenshotAnimations(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t)
+	p := tb.NewPage(nil)
+	p.SetContent(`
+		<style>
+			@keyframes grow { from { width: 0px; } to { width: 100px; } }
+			#bar { height: 10px; background: red; animation: grow 60s forwards; }
+		</style>
+		<div id="bar"></div>
+	`, nil)
+
+	p.Screenshot(tb.toGojaValue(map[string]interface{}{"animations": "disabled"}))
+
+	// the finite animations are finished before taking the screenshot.
+	width := p.Evaluate(tb.toGojaValue(`() => getComputedStyle(document.getElementById('bar')).width`))
+	assert.Equal(t, "100px", width)
+
+	// the animation style is removed after taking the screenshot.
+	styles := p.Evaluate(tb.toGojaValue(`() => document.querySelectorAll('style').length`))
+	assert.EqualValues(t, 1, styles)
+}
+
 func TestPageTitle(t *testing.T) {
 	p := newTestBrowser(t).NewPage(nil)
 	p.SetContent(`<html><head><title>Some title</title></head></html>`, nil)
@@ -452,12 +626,33 @@ func TestPageSetExtraHTTPHeaders(t *testing.T) {
 	assert.Equal(t, "Some-Value", h[0])
 }
 
+func TestPageSetExtraHTTPHeadersMerge(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withHTTPServer())
+	bctx := tb.NewContext(tb.toGojaValue(map[string]interface{}{
+		"extraHTTPHeaders": map[string]interface{}{
+			"Some-Header":  "context",
+			"Other-Header": "context",
+		},
+	}))
+	p := bctx.NewPage()
+	p.SetExtraHTTPHeaders(map[string]string{"some-header": "page"})
+
+	resp := p.Goto(tb.URL("/get"), nil)
+	require.NotNil(t, resp)
+	var body struct{ Headers map[string][]string }
+	require.NoError(t, json.Unmarshal(resp.Body().Bytes(), &body))
+	assert.Equal(t, []string{"page"}, body.Headers["Some-Header"])
+	assert.Equal(t, []string{"context"}, body.Headers["Other-Header"])
+}
+
 func TestPageWaitForFunction(t *testing.T) {
 	t.Parallel()
 
 	script := `
         page.waitForFunction(%s, %s, %s).then(ok => {
-            log('ok: '+ok);
+            log('ok: '+ok.jsonValue());
         }, err => {
             log('err: '+err);
         });`
@@ -484,7 +679,7 @@ func TestPageWaitForFunction(t *testing.T) {
 			return err
 		})
 		require.NoError(t, err)
-		assert.Contains(t, log, "ok: null")
+		assert.Contains(t, log, "ok: true")
 	})
 
 	t.Run("ok_func_raf_default_arg", func(t *testing.T) {
@@ -508,7 +703,7 @@ func TestPageWaitForFunction(t *testing.T) {
 			return err
 		})
 		require.NoError(t, err)
-		assert.Contains(t, log, "ok: null")
+		assert.Contains(t, log, "ok: true")
 
 		argEvalJS := p.Evaluate(tb.toGojaValue("() => window._arg"))
 		argEval, ok := argEvalJS.(goja.Value)
@@ -542,7 +737,7 @@ func TestPageWaitForFunction(t *testing.T) {
 			return err
 		})
 		require.NoError(t, err)
-		assert.Contains(t, log, "ok: null")
+		assert.Contains(t, log, "ok: true")
 
 		argEvalJS := p.Evaluate(tb.toGojaValue("() => window._args"))
 		argEval, ok := argEvalJS.(goja.Value)
@@ -650,14 +845,363 @@ func TestPageWaitForFunction(t *testing.T) {
 			return err
 		})
 		require.NoError(t, err)
-		assert.Contains(t, log, "ok: null")
+		assert.Contains(t, log, "ok: true")
+	})
+
+	t.Run("ok_func_value_handle", func(t *testing.T) {
+		t.Parallel()
+
+		tb := newTestBrowser(t)
+		p := tb.NewPage(nil)
+		require.NoError(t, tb.runtime().Set("page", p))
+		var log []string
+		require.NoError(t, tb.runtime().Set("log", func(s string) { log = append(log, s) }))
+
+		err := tb.vu.Loop.Start(func() error {
+			_, err := tb.runtime().RunString(fmt.Sprintf(script, "() => 42", "{ polling: 50 }", "null"))
+			return err
+		})
+		require.NoError(t, err)
+		assert.Contains(t, log, "ok: 42")
+	})
+
+	t.Run("ok_func_element_handle_arg", func(t *testing.T) {
+		t.Parallel()
+
+		tb := newTestBrowser(t)
+		p := tb.NewPage(nil)
+		p.SetContent(`<div id="status">loading</div>`, nil)
+		require.NoError(t, tb.runtime().Set("page", p))
+		require.NoError(t, tb.runtime().Set("el", p.Query("#status")))
+		var log []string
+		require.NoError(t, tb.runtime().Set("log", func(s string) { log = append(log, s) }))
+
+		p.Evaluate(tb.toGojaValue(`() => {
+			setTimeout(() => document.querySelector('#status').innerHTML = 'ready', 500);
+		}`))
+
+		err := tb.vu.Loop.Start(func() error {
+			s := fmt.Sprintf(script, "el => el.innerHTML === 'ready'", "{ polling: 'mutation', timeout: 2000 }", "el")
+			_, err := tb.runtime().RunString(s)
+			return err
+		})
+		require.NoError(t, err)
+		assert.Contains(t, log, "ok: true")
+	})
+}
+
+func TestPageWaitForEvent(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ok/predicate", func(t *testing.T) {
+		t.Parallel()
+
+		tb := newTestBrowser(t)
+		p := tb.NewPage(nil)
+		p.Evaluate(tb.toGojaValue(`() => {
+			setTimeout(() => console.log("first"), 100);
+			setTimeout(() => console.log("second"), 200);
+		}`))
+
+		predicate, err := tb.runJavaScript(`(msg) => msg.text() === "second"`)
+		require.NoError(t, err)
+		msg, ok := p.WaitForEvent("console", predicate).(api.ConsoleMessage)
+		require.True(t, ok)
+		assert.Equal(t, "second", msg.Text())
+	})
+
+	t.Run("ok/dialog", func(t *testing.T) {
+		t.Parallel()
+
+		tb := newTestBrowser(t)
+		p := tb.NewPage(nil)
+		p.Evaluate(tb.toGojaValue(`() => {
+			setTimeout(() => { window.answer = prompt("name?"); }, 100);
+		}`))
+
+		dialog, ok := p.WaitForEvent("dialog", nil).(api.Dialog)
+		require.True(t, ok)
+		assert.Equal(t, "name?", dialog.Message())
+		dialog.Accept("k6")
+		assert.Equal(t, "k6", tb.asGojaValue(p.Evaluate(tb.toGojaValue(`() => window.answer`))).String(),
+			"should not dismiss the waited dialog")
+	})
+
+	t.Run("err_timeout", func(t *testing.T) {
+		t.Parallel()
+
+		tb := newTestBrowser(t)
+		p := tb.NewPage(nil)
+		opts := tb.toGojaValue(map[string]interface{}{"timeout": 100})
+
+		assertPanicErrorContains(t, func() (err interface{}) {
+			defer func() { err = recover() }()
+			p.WaitForEvent("console", opts)
+			return nil
+		}(), `waiting for "console" event: timed out after 100ms`)
+	})
+
+	t.Run("err_unknown_event", func(t *testing.T) {
+		t.Parallel()
+
+		p := newTestBrowser(t).NewPage(nil)
+		assertPanicErrorContains(t, func() (err interface{}) {
+			defer func() { err = recover() }()
+			p.WaitForEvent("none", nil)
+			return nil
+		}(), `unknown page event: "none"`)
+	})
+}
+
+func TestPageWaitForPopup(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ok", func(t *testing.T) {
+		t.Parallel()
+
+		tb := newTestBrowser(t, withFileServer())
+		p := tb.NewPage(nil)
+		p.SetContent(fmt.Sprintf(
+			`<button onclick="window.open('%s')">open</button>`, tb.staticURL("empty.html"),
+		), nil)
+
+		// the popup can be opened before waiting for it.
+		p.Click("button", nil)
+		popup := p.WaitForPopup(nil)
+		require.NotNil(t, popup)
+		assert.Equal(t, p, popup.Opener())
+		popup.WaitForLoadState("load", nil)
+		assert.Equal(t, tb.staticURL("empty.html"), popup.URL())
+	})
+
+	t.Run("closed_popup", func(t *testing.T) {
+		t.Parallel()
+
+		tb := newTestBrowser(t, withFileServer())
+		bctx := tb.NewContext(nil)
+		p := bctx.NewPage()
+		p.SetContent(fmt.Sprintf(
+			`<button onclick="window.open('%s')">open</button>`, tb.staticURL("empty.html"),
+		), nil)
+
+		// a popup that is closed before waiting for it isn't returned.
+		p.Click("button", nil)
+		require.Eventually(t, func() bool { return len(bctx.Pages()) == 2 }, 5*time.Second, 10*time.Millisecond)
+		popup := bctx.Pages()[1]
+		if popup == p {
+			popup = bctx.Pages()[0]
+		}
+		popup.Close(nil)
+		require.Eventually(t, popup.IsClosed, 5*time.Second, 10*time.Millisecond)
+
+		opts := tb.toGojaValue(map[string]interface{}{"timeout": 100})
+		assertPanicErrorContains(t, func() (err interface{}) {
+			defer func() { err = recover() }()
+			p.WaitForPopup(opts)
+			return nil
+		}(), "timed out after 100ms, no popup was opened")
+	})
+
+	t.Run("err_timeout", func(t *testing.T) {
+		t.Parallel()
+
+		tb := newTestBrowser(t)
+		p := tb.NewPage(nil)
+		opts := tb.toGojaValue(map[string]interface{}{"timeout": 100})
+
+		assertPanicErrorContains(t, func() (err interface{}) {
+			defer func() { err = recover() }()
+			p.WaitForPopup(opts)
+			return nil
+		}(), "timed out after 100ms, no popup was opened")
+	})
+}
+
+func TestPageIndexedDB(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withFileServer())
+	p := tb.NewPage(nil)
+	require.NotNil(t, p.Goto(tb.staticURL("empty.html"), nil))
+
+	p.Evaluate(tb.toGojaValue(`() => new Promise((resolve, reject) => {
+		const req = indexedDB.open("app", 2);
+		req.onupgradeneeded = () => {
+			const store = req.result.createObjectStore("todos", { keyPath: "id" });
+			store.createIndex("by_done", "done");
+			for (let id = 1; id <= 3; id++) {
+				store.put({ id, title: "todo " + id, done: id === 1, data: new Uint8Array([id]) });
+			}
+		};
+		req.onsuccess = () => { req.result.close(); resolve(); };
+		req.onerror = () => reject(req.error);
+	})`))
+
+	idb := p.IndexedDB()
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{
+			"name":    "app",
+			"version": float64(2),
+			"objectStores": []interface{}{
+				map[string]interface{}{
+					"name":          "todos",
+					"keyPath":       "id",
+					"autoIncrement": false,
+					"indexes": []interface{}{
+						map[string]interface{}{"name": "by_done", "keyPath": "done", "unique": false, "multiEntry": false},
+					},
+				},
+			},
+		},
+	}, idb.Databases().Export())
+
+	records, ok := idb.Records("app", "todos", tb.toGojaValue(map[string]interface{}{"limit": 2})).
+		Export().(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, float64(2), records["version"])
+	assert.Equal(t, float64(3), records["total"])
+	assert.Equal(t, true, records["truncated"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{
+			"key": float64(1),
+			"value": map[string]interface{}{
+				"id": float64(1), "title": "todo 1", "done": true, "data": []interface{}{float64(1)},
+			},
+		},
+		map[string]interface{}{
+			"key": float64(2),
+			"value": map[string]interface{}{
+				"id": float64(2), "title": "todo 2", "done": false, "data": []interface{}{float64(2)},
+			},
+		},
+	}, records["records"])
+
+	assertPanicErrorContains(t, func() (err interface{}) {
+		defer func() { err = recover() }()
+		idb.Records("missing", "todos", nil)
+		return nil
+	}(), `database "missing" not found`)
+	assertPanicErrorContains(t, func() (err interface{}) {
+		defer func() { err = recover() }()
+		idb.Records("app", "missing", nil)
+		return nil
+	}(), `object store "missing" not found in database "app"`)
+
+	// the missing database isn't created by reading it.
+	assert.Len(t, idb.Databases().Export(), 1)
+}
+
+func TestPageWebStorage(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ok", func(t *testing.T) {
+		t.Parallel()
+
+		tb := newTestBrowser(t, withFileServer())
+		p := tb.NewPage(nil)
+		require.NotNil(t, p.Goto(tb.staticURL("empty.html"), nil))
+
+		for _, tt := range []struct {
+			kind    string
+			storage api.WebStorage
+		}{
+			{"localStorage", p.LocalStorage()},
+			{"sessionStorage", p.SessionStorage()},
+		} {
+			s := tt.storage
+			assert.True(t, goja.IsNull(s.GetItem("a")), "%s: expected a missing item to be null", tt.kind)
+
+			s.SetItem("a", "1")
+			s.SetItem("b", `{"json": true}`)
+			assert.Equal(t, "1", s.GetItem("a").String(), tt.kind)
+			assert.Equal(t, `{"json": true}`, s.GetItem("b").String(), tt.kind)
+			got := tb.asGojaValue(p.Evaluate(tb.toGojaValue(
+				fmt.Sprintf(`() => %s.getItem("a")`, tt.kind)))).String()
+			assert.Equal(t, "1", got, tt.kind)
+
+			s.RemoveItem("a")
+			assert.True(t, goja.IsNull(s.GetItem("a")), tt.kind)
+			assert.Equal(t, `{"json": true}`, s.GetItem("b").String(), tt.kind)
+
+			s.Clear()
+			assert.True(t, goja.IsNull(s.GetItem("b")), tt.kind)
+		}
+	})
+
+	t.Run("err_opaque_origin", func(t *testing.T) {
+		t.Parallel()
+
+		tb := newTestBrowser(t)
+		p := tb.NewPage(nil)
+
+		assertPanicErrorContains(t, func() (err interface{}) {
+			defer func() { err = recover() }()
+			p.LocalStorage().GetItem("a")
+			return nil
+		}(), `localStorage of "about:blank" is not accessible: SecurityError`)
 	})
 }
 
 func TestPageWaitForLoadState(t *testing.T) {
 	t.Parallel()
 
-	// TODO: Add happy path tests once WaitForLoadState is not racy.
+	t.Run("ok/already_reached", func(t *testing.T) {
+		t.Parallel()
+
+		tb := newTestBrowser(t, withFileServer())
+		p := tb.NewPage(nil)
+		require.NotNil(t, p.Goto(tb.staticURL("empty.html"), nil))
+
+		start := time.Now()
+		p.WaitForLoadState("load", nil)
+		p.WaitForLoadState("domcontentloaded", nil)
+		assert.Less(t, time.Since(start), time.Second, "should not wait for a reached state")
+	})
+
+	t.Run("ok/networkidle", func(t *testing.T) {
+		t.Parallel()
+
+		tb := newTestBrowser(t, withHTTPServer())
+		tb.withHandler("/app", func(w http.ResponseWriter, _ *http.Request) {
+			fmt.Fprint(w, `<script>
+				window.addEventListener('load', () => {
+					fetch("/data").then(r => r.text()).then(t => document.title = t);
+				});
+			</script>`)
+		})
+		tb.withHandler("/data", func(w http.ResponseWriter, _ *http.Request) {
+			time.Sleep(300 * time.Millisecond)
+			fmt.Fprint(w, "loaded")
+		})
+		p := tb.NewPage(nil)
+		require.NotNil(t, p.Goto(tb.URL("/app"), nil))
+
+		p.WaitForLoadState("networkidle", nil)
+		assert.Equal(t, "loaded", p.Title(), "should wait for the requests made after load")
+	})
+
+	t.Run("err_timeout", func(t *testing.T) {
+		t.Parallel()
+
+		tb := newTestBrowser(t, withHTTPServer())
+		tb.withHandler("/app", func(w http.ResponseWriter, _ *http.Request) {
+			fmt.Fprint(w, `<script>fetch("/poll");</script>`)
+		})
+		tb.withHandler("/poll", func(_ http.ResponseWriter, r *http.Request) {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(10 * time.Second):
+			}
+		})
+		p := tb.NewPage(nil)
+		require.NotNil(t, p.Goto(tb.URL("/app"), nil))
+
+		assertPanicErrorContains(t, func() (err interface{}) {
+			defer func() { err = recover() }()
+			p.WaitForLoadState("networkidle", tb.toGojaValue(map[string]interface{}{"timeout": 500}))
+			return nil
+		}(), "timed out after 500ms")
+	})
 
 	t.Run("err_wrong_event", func(t *testing.T) {
 		t.Parallel()
@@ -665,7 +1209,7 @@ func TestPageWaitForLoadState(t *testing.T) {
 		defer func() {
 			assertPanicErrorContains(t, recover(),
 				`invalid lifecycle event: "none"; `+
-					`must be one of: load, domcontentloaded, networkidle`)
+					`must be one of: load, domcontentloaded, networkidle, commit`)
 		}()
 
 		p := newTestBrowser(t).NewPage(nil)
@@ -683,15 +1227,1215 @@ func TestPageWaitForNavigationShouldNotPanic(t *testing.T) {
 	require.NotPanics(t, func() { p.WaitForNavigation(nil) })
 }
 
-func assertPanicErrorContains(t *testing.T, err interface{}, expErrMsg string) {
-	t.Helper()
+func TestPageRoute(t *testing.T) {
+	t.Parallel()
 
-	require.NotNil(t, err)
-	require.IsType(t, &goja.Object{}, err)
-	gotObj, _ := err.(*goja.Object)
-	got := gotObj.Export()
-	expErr := errors.New(expErrMsg)
-	gotErr, ok := got.(error)
-	require.True(t, ok)
-	assert.Contains(t, gotErr.Error(), expErr.Error())
+	newPage := func(t *testing.T) (*testBrowser, api.Page) {
+		t.Helper()
+
+		tb := newTestBrowser(t, withFileServer())
+		tb.withHandler("/api", func(w http.ResponseWriter, _ *http.Request) {
+			fmt.Fprint(w, "original")
+		})
+		p := tb.NewPage(nil)
+		require.NotNil(t, p.Goto(tb.staticURL("empty.html"), nil))
+
+		return tb, p
+	}
+	fetchAPI := func(tb *testBrowser, p api.Page) string {
+		return routedFetcher(tb, p, "/api")()
+	}
+	handler := func(t *testing.T, tb *testBrowser, js string) goja.Value {
+		t.Helper()
+
+		fn, err := tb.runJavaScript(js)
+		require.NoError(t, err)
+		return fn
+	}
+
+	t.Run("fulfill", func(t *testing.T) {
+		t.Parallel()
+
+		tb, p := newPage(t)
+		p.Route(tb.toGojaValue("**/api"), handler(t, tb, `
+			(route) => route.fulfill({ body: 'mocked', contentType: 'text/plain' })
+		`))
+		assert.Equal(t, "mocked", fetchAPI(tb, p))
+	})
+	t.Run("fulfill_content_type", func(t *testing.T) {
+		t.Parallel()
+
+		tb, p := newPage(t)
+		p.Route(tb.toGojaValue("**/api"), handler(t, tb, `(route) => route.fulfill({
+			body: 'mocked',
+			contentType: 'text/plain',
+			headers: { 'Content-Type': 'text/html', 'X-Mocked': 'yes' },
+		})`))
+		var text string
+		done := make(chan struct{})
+		p.On("console", func(_ goja.Value, args ...goja.Value) (goja.Value, error) {
+			m, ok := args[0].Export().(api.ConsoleMessage)
+			require.True(t, ok)
+			text = m.Text()
+			close(done)
+			return goja.Undefined(), nil
+		})
+		p.Evaluate(tb.toGojaValue(`() => {
+			fetch('/api').then(r => console.log(
+				r.headers.get('content-type') + ' ' + r.headers.get('x-mocked')));
+		}`))
+		tb.runLoopUntil(done)
+		assert.Equal(t, "text/plain yes", text)
+	})
+	t.Run("abort", func(t *testing.T) {
+		t.Parallel()
+
+		tb, p := newPage(t)
+		p.Route(tb.toGojaValue("**/api"), handler(t, tb, `(route) => route.abort()`))
+		assert.Equal(t, "failed", fetchAPI(tb, p))
+	})
+	t.Run("reverse_order", func(t *testing.T) {
+		t.Parallel()
+
+		tb, p := newPage(t)
+		p.Route(tb.toGojaValue("**/*"), handler(t, tb, `(route) => route.fulfill({ body: 'first' })`))
+		p.Route(tb.toGojaValue("**/api"), handler(t, tb, `(route) => route.fulfill({ body: 'second' })`))
+		assert.Equal(t, "second", fetchAPI(tb, p))
+	})
+	t.Run("fallthrough", func(t *testing.T) {
+		t.Parallel()
+
+		tb, p := newPage(t)
+		p.Route(tb.toGojaValue("**/api"), handler(t, tb, `() => {}`))
+		assert.Equal(t, "original", fetchAPI(tb, p))
+	})
+	t.Run("unroute", func(t *testing.T) {
+		t.Parallel()
+
+		tb, p := newPage(t)
+		url := tb.toGojaValue("**/api")
+		p.Route(url, handler(t, tb, `(route) => route.fulfill({ body: 'mocked' })`))
+		p.Unroute(url, nil)
+		assert.Equal(t, "original", fetchAPI(tb, p))
+	})
+}
+
+// routedFetcher returns a function that fetches the url from the page and
+// returns the response text, or "failed" if the request fails. The route
+// handlers are called on the event loop, so it runs the event loop until
+// the page logs the response to the console.
+func routedFetcher(tb *testBrowser, p api.Page, url string) func() string {
+	var (
+		text string
+		done chan struct{}
+	)
+	p.On("console", func(_ goja.Value, args ...goja.Value) (goja.Value, error) {
+		m, ok := args[0].Export().(api.ConsoleMessage)
+		require.True(tb.t, ok)
+		text = m.Text()
+		close(done)
+		return goja.Undefined(), nil
+	})
+
+	return func() string {
+		tb.t.Helper()
+
+		done = make(chan struct{})
+		p.Evaluate(tb.toGojaValue(`(url) => {
+			fetch(url).then(r => r.text()).catch(() => 'failed').then(t => console.log(t));
+		}`), tb.toGojaValue(url))
+		tb.runLoopUntil(done)
+
+		return text
+	}
+}
+
+func TestPageWaitForResponse(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withFileServer())
+	tb.withHandler("/api", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, "ok")
+	})
+	p := tb.NewPage(nil)
+	require.NotNil(t, p.Goto(tb.staticURL("empty.html"), nil))
+
+	fetchLater := tb.toGojaValue(`() => { setTimeout(() => fetch('/api'), 100) }`)
+
+	p.Evaluate(fetchLater)
+	resp := p.WaitForResponse(tb.toGojaValue("**/api"), nil)
+	require.NotNil(t, resp)
+	assert.Equal(t, tb.URL("/api"), resp.URL())
+
+	p.Evaluate(fetchLater)
+	predicate, err := tb.runJavaScript(`(resp) => resp.url().endsWith('/api') && resp.status() === 200`)
+	require.NoError(t, err)
+	resp = p.WaitForResponse(predicate, nil)
+	require.NotNil(t, resp)
+	assert.Equal(t, int64(200), resp.Status())
+
+	opts := tb.toGojaValue(struct {
+		Timeout int64 `js:"timeout"`
+	}{Timeout: 100})
+	assertPanicErrorContains(t, func() (err interface{}) {
+		defer func() { err = recover() }()
+		p.WaitForResponse(tb.toGojaValue("**/missing"), opts)
+		return nil
+	}(), "timed out after 100ms")
+}
+
+func TestPageWaitForRequest(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withFileServer())
+	tb.withHandler("/cached", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Cache-Control", "max-age=3600")
+		fmt.Fprint(w, "ok")
+	})
+	p := tb.NewPage(nil)
+	require.NotNil(t, p.Goto(tb.staticURL("empty.html"), nil))
+
+	fetchLater := tb.toGojaValue(`() => { setTimeout(() => fetch('/cached'), 100) }`)
+
+	p.Evaluate(fetchLater)
+	req := p.WaitForRequest(tb.toGojaValue("**/cached"), nil)
+	require.NotNil(t, req)
+	assert.Equal(t, tb.URL("/cached"), req.URL())
+
+	// the second request is served from the cache.
+	p.Evaluate(fetchLater)
+	predicate, err := tb.runJavaScript(`(req) => req.url().endsWith('/cached') && req.method() === 'GET'`)
+	require.NoError(t, err)
+	req = p.WaitForRequest(predicate, nil)
+	require.NotNil(t, req)
+	assert.Equal(t, "GET", req.Method())
+
+	opts := tb.toGojaValue(struct {
+		Timeout int64 `js:"timeout"`
+	}{Timeout: 100})
+	assertPanicErrorContains(t, func() (err interface{}) {
+		defer func() { err = recover() }()
+		p.WaitForRequest(tb.toGojaValue("**/missing"), opts)
+		return nil
+	}(), "timed out after 100ms")
+}
+
+func TestPageRequestPostData(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withHTTPServer())
+	p := tb.NewPage(nil)
+	require.NotNil(t, p.Goto(tb.URL("/get"), nil))
+
+	post := func(js string) api.Request {
+		p.Evaluate(tb.toGojaValue(fmt.Sprintf(`() => { setTimeout(() => %s, 100) }`, js)))
+		req := p.WaitForRequest(tb.toGojaValue("**/post"), nil)
+		require.NotNil(t, req)
+		return req
+	}
+
+	req := post(`navigator.sendBeacon('/post', JSON.stringify({ event: 'click' }))`)
+	assert.Equal(t, `{"event":"click"}`, req.PostData().String())
+	assert.Equal(t, map[string]interface{}{"event": "click"}, req.PostDataJSON().Export())
+
+	// the long bodies are not sent with the request event.
+	req = post(`fetch('/post', { method: 'POST', body: JSON.stringify({ data: 'x'.repeat(1 << 20) }) })`)
+	assert.Len(t, req.PostData().String(), 1<<20+len(`{"data":""}`))
+
+	req = post(`fetch('/post', { method: 'POST', body: new URLSearchParams({ a: '1', b: '2' }) })`)
+	assert.Equal(t, "a=1&b=2", req.PostData().String())
+	assert.Equal(t, map[string]string{"a": "1", "b": "2"}, req.PostDataJSON().Export())
+
+	p.Evaluate(tb.toGojaValue(`() => { setTimeout(() => fetch('/get'), 100) }`))
+	req = p.WaitForRequest(tb.toGojaValue("**/get"), nil)
+	require.NotNil(t, req)
+	assert.True(t, goja.IsNull(req.PostData()), "should be null without a body")
+}
+
+func TestPageRequestRedirects(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withHTTPServer())
+	p := tb.NewPage(nil)
+	resp := p.Goto(tb.URL("/redirect/2"), nil)
+	require.NotNil(t, resp)
+
+	req := resp.Request()
+	assert.Equal(t, tb.URL("/get"), req.URL())
+	assert.Nil(t, req.RedirectedTo())
+
+	var chain []api.Request
+	for r := req.RedirectedFrom(); r != nil; r = r.RedirectedFrom() {
+		chain = append([]api.Request{r}, chain...)
+	}
+	require.Len(t, chain, 2)
+	assert.Equal(t, tb.URL("/redirect/2"), chain[0].URL())
+	for i, r := range chain {
+		hop := r.Response()
+		require.NotNil(t, hop)
+		assert.Equal(t, int64(http.StatusFound), hop.Status())
+		assert.NotEmpty(t, hop.HeaderValue("location").String())
+		if i < len(chain)-1 {
+			assert.Same(t, chain[i+1], r.RedirectedTo())
+		} else {
+			assert.Same(t, req, r.RedirectedTo())
+		}
+	}
+}
+
+func TestPageResponseBody(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withHTTPServer())
+	p := tb.NewPage(nil)
+	resp := p.Goto(tb.URL("/get"), nil)
+	require.NotNil(t, resp)
+
+	text := resp.Text()
+	assert.Contains(t, text, `"url"`)
+	assert.Equal(t, text, string(resp.Body().Bytes()))
+
+	body, ok := resp.JSON().Export().(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, tb.URL("/get"), body["url"])
+	body["url"] = "changed"
+	body, ok = resp.JSON().Export().(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, tb.URL("/get"), body["url"], "should not share the parsed values")
+
+	resp = p.Goto(tb.URL("/html"), nil)
+	require.NotNil(t, resp)
+	assertPanicErrorContains(t, func() (err interface{}) {
+		defer func() { err = recover() }()
+		resp.JSON()
+		return nil
+	}(), "unmarshalling response body to JSON")
+}
+
+func TestPageResponseSecurityDetails(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withHTTPServer())
+	p := tb.NewContext(tb.toGojaValue(map[string]interface{}{"ignoreHTTPSErrors": true})).NewPage()
+
+	resp := p.Goto(tb.http.ServerHTTPS.URL+"/get", nil)
+	require.NotNil(t, resp)
+	details, ok := resp.SecurityDetails().Export().(*common.SecurityDetails)
+	require.True(t, ok)
+	assert.NotEmpty(t, details.Protocol)
+	assert.NotEmpty(t, details.Cipher)
+	assert.NotEmpty(t, details.Issuer)
+	assert.Less(t, details.ValidFrom, time.Now().Unix())
+	assert.Greater(t, details.ValidTo, details.ValidFrom)
+
+	resp = p.Goto(tb.URL("/get"), nil)
+	require.NotNil(t, resp)
+	assert.True(t, goja.IsNull(resp.SecurityDetails()), "should be null over plain HTTP")
+}
+
+func TestPageResponseServerAddr(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withHTTPServer())
+	tb.withHandler("/cached", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Cache-Control", "max-age=3600")
+		fmt.Fprint(w, "ok")
+	})
+	p := tb.NewPage(nil)
+
+	resp := p.Goto(tb.URL("/cached"), nil)
+	require.NotNil(t, resp)
+	addr, ok := resp.ServerAddr().Export().(*common.RemoteAddress)
+	require.True(t, ok)
+	assert.Equal(t, "127.0.0.1", addr.IPAddress)
+	assert.Equal(t, tb.URL(""), fmt.Sprintf("http://%s:%d", addr.IPAddress, addr.Port))
+
+	// the response of the second navigation is served from the cache.
+	resp = p.Goto(tb.URL("/cached"), nil)
+	require.NotNil(t, resp)
+	assert.True(t, goja.IsNull(resp.ServerAddr()), "should be null when served from the cache")
+}
+
+func TestPageResponseFinished(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withFileServer())
+	tb.withHandler("/slow", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Length", "4")
+		fmt.Fprint(w, "sl")
+		w.(http.Flusher).Flush() //nolint:forcetypeassert
+		time.Sleep(500 * time.Millisecond)
+		fmt.Fprint(w, "ow")
+	})
+	tb.withHandler("/broken", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Length", "100")
+		fmt.Fprint(w, "partial")
+		w.(http.Flusher).Flush()                   //nolint:forcetypeassert
+		conn, _, err := w.(http.Hijacker).Hijack() //nolint:forcetypeassert
+		if err == nil {
+			_ = conn.Close()
+		}
+	})
+	p := tb.NewPage(nil)
+	require.NotNil(t, p.Goto(tb.staticURL("empty.html"), nil))
+
+	fetch := func(path string) api.Response {
+		js := fmt.Sprintf(`() => { setTimeout(() => fetch(%q).then(r => r.text()).catch(() => {}), 100) }`, path)
+		p.Evaluate(tb.toGojaValue(js))
+		resp := p.WaitForResponse(tb.toGojaValue("**"+path), nil)
+		require.NotNil(t, resp)
+		return resp
+	}
+
+	resp := fetch("/slow")
+	assert.True(t, goja.IsNull(resp.Finished()))
+	assert.Equal(t, "slow", resp.Text(), "should read the complete body")
+
+	resp = fetch("/broken")
+	assert.Contains(t, resp.Finished().String(), "loading failed")
+}
+
+func TestPageGetByRole(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t)
+	p := tb.NewPage(nil)
+	p.SetContent(`
+		<button>Submit</button>
+		<button aria-label="Cancel order" disabled>X</button>
+		<label><input type="checkbox" checked> Accept</label>
+		<input type="checkbox" aria-label="Subscribe">
+		<h1>Title</h1>
+		<a href="#">Home</a>
+		<button style="display:none">Hidden</button>
+	`, nil)
+
+	count := func(role string, opts interface{}) int {
+		if opts == nil {
+			return p.GetByRole(role, nil).Count()
+		}
+		return p.GetByRole(role, tb.toGojaValue(opts)).Count()
+	}
+	type opts = map[string]interface{}
+
+	assert.Equal(t, 2, count("button", nil))
+	assert.Equal(t, 3, count("button", opts{"includeHidden": true}))
+	assert.Equal(t, 1, count("button", opts{"name": "submit"}))
+	assert.Equal(t, 0, count("button", opts{"name": "submit", "exact": true}))
+	assert.Equal(t, 1, count("button", opts{"name": "Cancel order", "exact": true}))
+	assert.Equal(t, 1, count("button", opts{"disabled": true}))
+	assert.Equal(t, 1, count("checkbox", opts{"checked": true}))
+	assert.Equal(t, 1, count("checkbox", opts{"checked": false}))
+	assert.Equal(t, 1, count("checkbox", opts{"name": "Accept"}))
+	assert.Equal(t, 1, count("heading", nil))
+	assert.Equal(t, "Home", p.GetByRole("link", nil).TextContent(nil))
+
+	name, err := tb.runJavaScript(`/^cancel/i`)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count("button", opts{"name": name}))
+}
+
+func TestPageGetByText(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t)
+	p := tb.NewPage(nil)
+	p.SetContent(`
+		<div><span>  Hello   world </span></div>
+		<label for="email">Email address</label><input id="email" placeholder="you@example.com">
+		<input aria-label="Search" placeholder="Search terms">
+		<button data-testid="submit">Go</button>
+	`, nil)
+
+	exact := tb.toGojaValue(map[string]interface{}{"exact": true})
+
+	assert.Equal(t, 1, p.GetByText(tb.toGojaValue("hello"), nil).Count())
+	assert.Equal(t, "  Hello   world ", p.GetByText(tb.toGojaValue("hello"), nil).InnerHTML(nil))
+	assert.Equal(t, 0, p.GetByText(tb.toGojaValue("hello"), exact).Count())
+	assert.Equal(t, 1, p.GetByText(tb.toGojaValue("Hello world"), exact).Count())
+
+	assert.Equal(t, "email", p.GetByLabel(tb.toGojaValue("email"), nil).GetAttribute("id", nil).String())
+	assert.Equal(t, 1, p.GetByLabel(tb.toGojaValue("Search"), exact).Count())
+
+	assert.Equal(t, "email", p.GetByPlaceholder(tb.toGojaValue("@example"), nil).GetAttribute("id", nil).String())
+	assert.Equal(t, 0, p.GetByPlaceholder(tb.toGojaValue("search"), exact).Count())
+
+	assert.Equal(t, "Go", p.GetByTestId(tb.toGojaValue("submit")).TextContent(nil))
+	assert.Equal(t, 0, p.GetByTestId(tb.toGojaValue("sub")).Count())
+}
+
+func TestPageGetByTestIdAttribute(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t)
+	bctx := tb.NewContext(tb.toGojaValue(map[string]interface{}{"testIdAttribute": "data-qa"}))
+	p := bctx.NewPage()
+	p.SetContent(`<button data-testid="submit">A</button><button data-qa="submit">B</button>`, nil)
+
+	assert.Equal(t, "B", p.GetByTestId(tb.toGojaValue("submit")).TextContent(nil))
+}
+
+func TestPageSetDefaultTimeout(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t)
+	bctx := tb.NewContext(nil)
+	bctx.SetDefaultTimeout(1000)
+	p := bctx.NewPage()
+
+	waitMissing := func(opts goja.Value) time.Duration {
+		start := time.Now()
+		func() {
+			defer func() { require.NotNil(t, recover()) }()
+			p.WaitForSelector("#missing", opts)
+		}()
+		return time.Since(start)
+	}
+
+	// the context's timeout is used when the page has none.
+	elapsed := waitMissing(nil)
+	assert.GreaterOrEqual(t, elapsed, time.Second)
+	assert.Less(t, elapsed, 10*time.Second)
+
+	// the page's timeout takes precedence over the context's.
+	p.SetDefaultTimeout(100)
+	elapsed = waitMissing(nil)
+	assert.GreaterOrEqual(t, elapsed, 100*time.Millisecond)
+	assert.Less(t, elapsed, time.Second)
+
+	// the timeout option takes precedence over the page's.
+	opts := tb.toGojaValue(struct {
+		Timeout int64 `js:"timeout"`
+	}{Timeout: 300})
+	elapsed = waitMissing(opts)
+	assert.GreaterOrEqual(t, elapsed, 300*time.Millisecond)
+	assert.Less(t, elapsed, time.Second)
+}
+
+func TestPagePdf(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t)
+	p := tb.NewPage(nil)
+	p.SetContent(`<h1>Report</h1>`, nil)
+
+	path := filepath.Join(t.TempDir(), "report.pdf")
+	opts := tb.toGojaValue(map[string]interface{}{
+		"format": "A4",
+		"path":   path,
+	})
+	buf := p.Pdf(opts)
+	assert.True(t, bytes.HasPrefix(buf.Bytes(), []byte("%PDF-")))
+
+	saved, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, buf.Bytes(), saved)
+}
+
+// evaluateExposed evaluates the page function, which calls the exposed
+// functions, without waiting for it, as the exposed functions are called on
+// the event loop. It returns the result of the page function once the n
+// calls are done.
+func evaluateExposed(
+	tb *testBrowser, e interface {
+		Evaluate(goja.Value, ...goja.Value) interface{}
+	}, js string, calls <-chan struct{}, n int,
+) goja.Value {
+	tb.t.Helper()
+
+	e.Evaluate(tb.toGojaValue(`() => { window.result = (` + js + `)() }`))
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < n; i++ {
+			<-calls
+		}
+		close(done)
+	}()
+	tb.runLoopUntil(done)
+
+	return tb.asGojaValue(e.Evaluate(tb.toGojaValue(`() => window.result`)))
+}
+
+// exposedCallback returns the JS function as a callback for the exposed
+// functions, which reports its calls on the returned channel.
+func exposedCallback(tb *testBrowser, js string) (goja.Callable, <-chan struct{}) {
+	tb.t.Helper()
+
+	v, err := tb.runJavaScript(js)
+	require.NoError(tb.t, err)
+	fn, ok := goja.AssertFunction(v)
+	require.True(tb.t, ok)
+
+	calls := make(chan struct{}, 10)
+	return func(this goja.Value, args ...goja.Value) (goja.Value, error) {
+		defer func() { calls <- struct{}{} }()
+		return fn(this, args...)
+	}, calls
+}
+
+func TestPageExposeFunction(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withFileServer())
+	p := tb.NewPage(nil)
+
+	add, addCalls := exposedCallback(tb, `(a, b) => a + b`)
+	fail, failCalls := exposedCallback(tb, `() => { throw new Error('oops') }`)
+	p.ExposeFunction("add", add)
+	p.ExposeFunction("fail", fail)
+
+	assert.Equal(t, int64(5), evaluateExposed(tb, p, `() => window.add(2, 3)`, addCalls, 1).ToInteger())
+
+	// concurrent calls should resolve to their own results.
+	addAll := `() => Promise.all([1, 2, 3].map(n => window.add(n, n)))`
+	var got []int64
+	require.NoError(t, tb.runtime().ExportTo(evaluateExposed(tb, p, addAll, addCalls, 3), &got))
+	assert.Equal(t, []int64{2, 4, 6}, got)
+
+	failed := `() => window.fail().catch(err => err.message)`
+	assert.Contains(t, evaluateExposed(tb, p, failed, failCalls, 1).String(), "oops")
+
+	// the function should survive navigations.
+	require.NotNil(t, p.Goto(tb.staticURL("empty.html"), nil))
+	assert.Equal(t, int64(5), evaluateExposed(tb, p, `() => window.add(2, 3)`, addCalls, 1).ToInteger())
+
+	assertPanicErrorContains(t, func() (err interface{}) {
+		defer func() { err = recover() }()
+		p.ExposeFunction("add", add)
+		return nil
+	}(), `function "add" has been already registered`)
+}
+
+func TestPageExposeBinding(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withFileServer())
+	p := tb.NewPage(nil)
+	require.NotNil(t, p.Goto(tb.staticURL("empty.html"), nil))
+	f := tb.attachFrame(p, "frame1", tb.staticURL("empty.html?frame"))
+	require.NotNil(t, f)
+
+	whoamiFn, whoamiCalls := exposedCallback(tb, `(source, arg) => source.frame.url() + ' ' + arg`)
+	htmlFn, htmlCalls := exposedCallback(tb, `(source, el) => el.innerHTML()`)
+	p.ExposeBinding("whoami", whoamiFn, nil)
+	p.ExposeBinding("html", htmlFn, tb.toGojaValue(map[string]interface{}{"handle": true}))
+
+	whoami := `() => window.whoami('hi')`
+	assert.Equal(t, tb.staticURL("empty.html")+" hi", evaluateExposed(tb, p, whoami, whoamiCalls, 1).String())
+	assert.Equal(t, tb.staticURL("empty.html?frame")+" hi", evaluateExposed(tb, f, whoami, whoamiCalls, 1).String())
+
+	html := `() => {
+		document.body.innerHTML = '<b>bold</b>';
+		return window.html(document.body);
+	}`
+	assert.Equal(t, "<b>bold</b>", evaluateExposed(tb, p, html, htmlCalls, 1).String())
+
+	// the call is rejected by the page, so it doesn't wait for the callback.
+	tooMany := `() => window.html(document.body, document.body).catch(err => err.message)`
+	assert.Contains(t, tb.asGojaValue(p.Evaluate(tb.toGojaValue(tooMany))).String(),
+		`exposed function "html" with the handle option accepts a single argument`)
+
+	// the bindings should be installed in the new documents.
+	require.NotNil(t, p.Goto(tb.staticURL("empty.html?reloaded"), nil))
+	assert.Equal(t, tb.staticURL("empty.html?reloaded")+" hi", evaluateExposed(tb, p, whoami, whoamiCalls, 1).String())
+}
+
+func TestPageAddInitScript(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withHTTPServer())
+	tb.withHandler("/init", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `<script>window.seenByPage = window.order.slice()</script>`)
+	})
+
+	bctx := tb.NewContext(nil)
+	bctx.AddInitScript(tb.toGojaValue(`window.order = ['context']`), nil)
+	p := bctx.NewPage()
+
+	script, err := tb.runJavaScript(`(name) => window.order.push(name)`)
+	require.NoError(t, err)
+	p.AddInitScript(script, tb.toGojaValue("page1"))
+	p.AddInitScript(script, tb.toGojaValue("page2"))
+	stub, err := tb.runJavaScript(`(now) => { Date.now = () => now }`)
+	require.NoError(t, err)
+	p.AddInitScript(stub, tb.toGojaValue(42))
+
+	require.NotNil(t, p.Goto(tb.URL("/init"), nil))
+
+	var seen []string
+	require.NoError(t, tb.runtime().ExportTo(
+		tb.asGojaValue(p.Evaluate(tb.toGojaValue(`() => window.seenByPage`))), &seen))
+	assert.Equal(t, []string{"context", "page1", "page2"}, seen)
+	assert.Equal(t, int64(42), tb.asGojaValue(p.Evaluate(tb.toGojaValue(`() => Date.now()`))).ToInteger())
+}
+
+func TestPageOnConsole(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t)
+	p := tb.NewPage(nil)
+
+	var msgs []api.ConsoleMessage
+	done := make(chan struct{})
+	collect := func(_ goja.Value, args ...goja.Value) (goja.Value, error) {
+		m, ok := args[0].Export().(api.ConsoleMessage)
+		require.True(t, ok)
+		if msgs = append(msgs, m); len(msgs) == 2 {
+			close(done)
+		}
+		return goja.Undefined(), nil
+	}
+	p.On("console", collect)
+
+	p.Evaluate(tb.toGojaValue(`() => {
+		console.log('hello', 42, { a: 1 });
+		console.error('oops');
+	}`))
+	tb.runLoopUntil(done)
+
+	assert.Equal(t, "log", msgs[0].Type())
+	assert.Equal(t, "hello 42 JSHandle@object", msgs[0].Text())
+	require.Len(t, msgs[0].Args(), 3)
+	assert.Equal(t, "hello", msgs[0].Args()[0].JSONValue().String())
+	assert.Equal(t, int64(1), msgs[0].Args()[2].JSONValue().ToObject(tb.runtime()).Get("a").ToInteger())
+	assert.Equal(t, "error", msgs[1].Type())
+	assert.Equal(t, "oops", msgs[1].Text())
+	assert.Equal(t, int64(2), msgs[1].Location().LineNumber)
+
+	assertPanicErrorContains(t, func() (err interface{}) {
+		defer func() { err = recover() }()
+		p.On("nope", collect)
+		return nil
+	}(), `unknown page event: "nope"`)
+}
+
+func TestPageOnPageError(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withHTTPServer())
+	tb.withHandler("/throw", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `<script>throw new TypeError('boom')</script>`)
+	})
+	p := tb.NewPage(nil)
+
+	var got map[string]string
+	done := make(chan struct{})
+	handler := func(_ goja.Value, args ...goja.Value) (goja.Value, error) {
+		obj := args[0].ToObject(tb.runtime())
+		got = map[string]string{
+			"name":    obj.Get("name").String(),
+			"message": obj.Get("message").String(),
+			"stack":   obj.Get("stack").String(),
+		}
+		close(done)
+		return goja.Undefined(), nil
+	}
+	p.On("pageerror", handler)
+
+	// the error is thrown while the page is navigating.
+	require.NotNil(t, p.Goto(tb.URL("/throw"), nil))
+	tb.runLoopUntil(done)
+
+	assert.Equal(t, "TypeError", got["name"])
+	assert.Equal(t, "boom", got["message"])
+	assert.Contains(t, got["stack"], "TypeError: boom\n    at ")
+}
+
+func TestPageOnDialog(t *testing.T) {
+	t.Parallel()
+
+	t.Run("default", func(t *testing.T) {
+		t.Parallel()
+
+		tb := newTestBrowser(t)
+		p := tb.NewPage(nil)
+
+		got := p.Evaluate(tb.toGojaValue(`() => { alert('hi'); return confirm('sure?') }`))
+		assert.False(t, tb.asGojaBool(got), "should dismiss the dialogs without handlers")
+	})
+
+	t.Run("handler", func(t *testing.T) {
+		t.Parallel()
+
+		tb := newTestBrowser(t)
+		p := tb.NewPage(nil)
+
+		var dialogs []string
+		done := make(chan struct{})
+		handler := func(_ goja.Value, args ...goja.Value) (goja.Value, error) {
+			d, ok := args[0].Export().(api.Dialog)
+			require.True(t, ok)
+			dialogs = append(dialogs, d.Type()+":"+d.Message()+":"+d.DefaultValue())
+			switch d.Type() {
+			case "confirm":
+				d.Accept("")
+			case "prompt":
+				d.Accept("k6")
+			case "alert":
+				close(done)
+			}
+			return goja.Undefined(), nil
+		}
+		p.On("dialog", handler)
+
+		// the dialogs are opened after the evaluation, as the page is blocked
+		// until the handlers are called on the event loop.
+		p.Evaluate(tb.toGojaValue(`() => setTimeout(() => {
+			window.results = [confirm('sure?'), prompt('name?', 'guest')];
+			// the alert is not handled by the handler, and should not block the page.
+			alert('hi');
+			window.results.push('done');
+		})`))
+		tb.runLoopUntil(done)
+
+		var results []interface{}
+		require.NoError(t, tb.runtime().ExportTo(
+			tb.asGojaValue(p.Evaluate(tb.toGojaValue(`() => window.results`))), &results))
+		assert.Equal(t, []interface{}{true, "k6", "done"}, results)
+		assert.Equal(t, []string{"confirm:sure?:", "prompt:name?:guest", "alert:hi:"}, dialogs)
+	})
+}
+
+func TestPageOnDownload(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withHTTPServer())
+	tb.withHandler("/download", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Disposition", `attachment; filename="report.txt"`)
+		fmt.Fprint(w, "k6 report")
+	})
+	tb.withHandler("/links", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `<a href="/download">download</a>`)
+	})
+
+	bctx := tb.NewContext(tb.toGojaValue(map[string]interface{}{"acceptDownloads": true}))
+	p := bctx.NewPage()
+
+	var d api.Download
+	done := make(chan struct{})
+	handler := func(_ goja.Value, args ...goja.Value) (goja.Value, error) {
+		var ok bool
+		d, ok = args[0].Export().(api.Download)
+		require.True(t, ok)
+		close(done)
+		return goja.Undefined(), nil
+	}
+	p.On("download", handler)
+
+	require.NotNil(t, p.Goto(tb.URL("/links"), nil))
+	p.Evaluate(tb.toGojaValue(`() => document.querySelector('a').click()`))
+	tb.runLoopUntil(done)
+
+	assert.Equal(t, "report.txt", d.SuggestedFilename())
+	assert.Equal(t, tb.URL("/download"), d.URL())
+	assert.Equal(t, p, d.Page())
+
+	path := d.Path()
+	b, err := ioutil.ReadFile(path) //nolint:gosec
+	require.NoError(t, err)
+	assert.Equal(t, "k6 report", string(b))
+	assert.Empty(t, d.Failure())
+
+	saveAs := filepath.Join(t.TempDir(), "reports", d.SuggestedFilename())
+	d.SaveAs(saveAs)
+	b, err = ioutil.ReadFile(saveAs) //nolint:gosec
+	require.NoError(t, err)
+	assert.Equal(t, "k6 report", string(b))
+
+	d.Delete()
+	assert.NoFileExists(t, path)
+
+	bctx.Close()
+	assert.NoDirExists(t, filepath.Dir(path), "should remove the downloads on close")
+}
+
+func TestPageOnFileChooser(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withHTTPServer())
+	tb.withHandler("/upload", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `<input type="file" multiple>`)
+	})
+	p := tb.NewPage(nil)
+	require.NotNil(t, p.Goto(tb.URL("/upload"), nil))
+
+	var multiple bool
+	done := make(chan struct{})
+	handler := func(_ goja.Value, args ...goja.Value) (goja.Value, error) {
+		fc, ok := args[0].Export().(api.FileChooser)
+		require.True(t, ok)
+		fc.SetFiles(tb.toGojaValue([]interface{}{
+			map[string]interface{}{"name": "a.txt", "mimeType": "text/plain", "buffer": "aa"},
+			map[string]interface{}{"name": "b.json", "buffer": "b"},
+		}), nil)
+		multiple = fc.IsMultiple()
+		close(done)
+		return goja.Undefined(), nil
+	}
+	p.On("filechooser", handler)
+
+	p.Click("input", nil)
+	tb.runLoopUntil(done)
+	assert.True(t, multiple)
+
+	files := `() => Array.from(document.querySelector('input').files)
+		.map(f => f.name + ':' + f.type + ':' + f.size).join(',')`
+	assert.Equal(t, "a.txt:text/plain:2,b.json:application/json:1", tb.asGojaValue(p.Evaluate(tb.toGojaValue(files))).String())
+}
+
+func TestPageDragAndDrop(t *testing.T) {
+	t.Parallel()
+
+	const html = `
+	<div id="source" draggable="true" style="width: 50px; height: 50px">drag me</div>
+	<div id="target" style="width: 100px; height: 100px; margin-top: 100px"></div>
+	<script>
+		window.events = [];
+		window.moves = 0;
+		const source = document.querySelector('#source');
+		const target = document.querySelector('#target');
+		source.addEventListener('dragstart', e => {
+			e.dataTransfer.setData('text/plain', 'k6');
+			events.push('dragstart');
+		});
+		document.addEventListener('dragover', () => moves++);
+		target.addEventListener('dragover', e => e.preventDefault());
+		target.addEventListener('drop', e => {
+			e.preventDefault();
+			events.push('drop:' + e.dataTransfer.getData('text/plain'));
+		});
+	</script>`
+
+	t.Run("page", func(t *testing.T) {
+		t.Parallel()
+
+		tb := newTestBrowser(t)
+		p := tb.NewPage(nil)
+		p.SetContent(html, nil)
+
+		p.DragAndDrop("#source", "#target", tb.toGojaValue(map[string]interface{}{"steps": 10}))
+
+		events := p.Evaluate(tb.toGojaValue(`() => events.join(',')`))
+		assert.Equal(t, "dragstart,drop:k6", tb.asGojaValue(events).String())
+		moves := p.Evaluate(tb.toGojaValue(`() => moves`))
+		assert.Greater(t, tb.asGojaValue(moves).ToInteger(), int64(1), "should move in steps")
+	})
+
+	t.Run("locator", func(t *testing.T) {
+		t.Parallel()
+
+		tb := newTestBrowser(t)
+		p := tb.NewPage(nil)
+		p.SetContent(html, nil)
+
+		p.Locator("#source", nil).DragTo(p.Locator("#target", nil), tb.toGojaValue(map[string]interface{}{
+			"targetPosition": map[string]interface{}{"x": 10, "y": 10},
+		}))
+
+		events := p.Evaluate(tb.toGojaValue(`() => events.join(',')`))
+		assert.Equal(t, "dragstart,drop:k6", tb.asGojaValue(events).String())
+	})
+}
+
+func assertPanicErrorContains(t *testing.T, err interface{}, expErrMsg string) {
+	t.Helper()
+
+	require.NotNil(t, err)
+	require.IsType(t, &goja.Object{}, err)
+	gotObj, _ := err.(*goja.Object)
+	got := gotObj.Export()
+	expErr := errors.New(expErrMsg)
+	gotErr, ok := got.(error)
+	require.True(t, ok)
+	assert.Contains(t, gotErr.Error(), expErr.Error())
+}
+
+func TestPageThrottleCPU(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withFileServer())
+	p := tb.NewPage(nil)
+	require.NotNil(t, p.Goto(tb.staticURL("empty.html"), nil))
+
+	busy := `() => {
+		const start = performance.now();
+		let x = 0;
+		for (let i = 0; i < 1e7; i++) { x += Math.sqrt(i); }
+		return performance.now() - start;
+	}`
+	unthrottled := tb.asGojaValue(p.Evaluate(tb.toGojaValue(busy))).ToFloat()
+
+	p.ThrottleCPU(4)
+	throttled := tb.asGojaValue(p.Evaluate(tb.toGojaValue(busy))).ToFloat()
+	assert.Greater(t, throttled, unthrottled*2)
+
+	p.ThrottleCPU(1)
+
+	assertPanicErrorContains(t, func() (err interface{}) {
+		defer func() { err = recover() }()
+		p.ThrottleCPU(0.5)
+		return nil
+	}(), `invalid CPU throttling rate "0.50"`)
+}
+
+func TestPageGotoNavigationTiming(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withFileServer())
+	p := tb.NewContext(tb.toGojaValue(map[string]interface{}{
+		"navigationTiming": true,
+	})).NewPage()
+
+	url := tb.staticURL("empty.html")
+	require.NotNil(t, p.Goto(url, nil))
+
+	got := make(map[string]map[string]string)
+	for len(tb.vu.Samples) > 0 {
+		for _, s := range (<-tb.vu.Samples).GetSamples() {
+			if strings.HasPrefix(s.Metric.Name, "browser_navigation_") {
+				got[s.Metric.Name] = s.Tags.CloneTags()
+			}
+		}
+	}
+	for _, name := range []string{
+		"browser_navigation_dns",
+		"browser_navigation_tcp",
+		"browser_navigation_ttfb",
+		"browser_navigation_dom_content_loaded",
+		"browser_navigation_load",
+	} {
+		require.Contains(t, got, name)
+		assert.Equal(t, url, got[name]["url"])
+		assert.Equal(t, "200", got[name]["status"])
+	}
+}
+
+func TestPageMetrics(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withFileServer())
+	p := tb.NewPage(nil)
+	require.NotNil(t, p.Goto(tb.staticURL("empty.html"), nil))
+
+	m := p.Metrics()
+	for _, name := range []string{"Documents", "Nodes", "JSHeapUsedSize", "LayoutCount"} {
+		assert.Contains(t, m, name)
+	}
+
+	p.Evaluate(tb.toGojaValue(`() => {
+		for (let i = 0; i < 100; i++) {
+			document.body.appendChild(document.createElement('div'));
+		}
+	}`))
+	assert.GreaterOrEqual(t, p.Metrics()["Nodes"], m["Nodes"]+100)
+}
+
+func TestPageAccessibilitySnapshot(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t)
+	p := tb.NewPage(nil)
+	p.SetContent(`
+		<title>a11y</title>
+		<div>
+			<h1>Title</h1>
+			<button disabled>OK</button>
+		</div>
+		<form aria-label="login">
+			<input type="checkbox" aria-label="remember" checked>
+		</form>
+	`, nil)
+	pp, ok := p.(*common.Page)
+	require.True(t, ok)
+
+	snapshot := pp.Accessibility.Snapshot(nil)
+	require.NotNil(t, snapshot)
+	assert.Equal(t, "RootWebArea", snapshot["role"])
+	assert.Equal(t, "a11y", snapshot["name"])
+	children, ok := snapshot["children"].([]map[string]interface{})
+	require.True(t, ok)
+	require.Len(t, children, 3, "should prune the nodes without useful semantics")
+	assert.Equal(t, map[string]interface{}{"role": "heading", "name": "Title", "level": 1.0}, children[0])
+	assert.Equal(t, map[string]interface{}{"role": "button", "name": "OK", "disabled": true}, children[1])
+	assert.Equal(t, map[string]interface{}{"role": "checkbox", "name": "remember", "checked": true}, children[2])
+
+	form := p.Query("form")
+	snapshot = pp.Accessibility.Snapshot(tb.toGojaValue(map[string]interface{}{
+		"root":            form,
+		"interestingOnly": false,
+	}))
+	require.NotNil(t, snapshot)
+	assert.Equal(t, "form", snapshot["role"])
+	assert.Equal(t, "login", snapshot["name"])
+
+	// the root isn't interesting, so it's pruned.
+	snapshot = pp.Accessibility.Snapshot(tb.toGojaValue(map[string]interface{}{"root": p.Query("div")}))
+	assert.Nil(t, snapshot)
+}
+
+func TestPageGoBackForward(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withHTTPServer())
+	for _, path := range []string{"/first", "/second"} {
+		path := path
+		tb.withHandler(path, func(w http.ResponseWriter, _ *http.Request) {
+			fmt.Fprintf(w, "<title>%s</title>", path)
+		})
+	}
+	p := tb.NewPage(nil)
+
+	assert.Nil(t, p.GoBack(nil), "should be null without a previous page")
+
+	require.NotNil(t, p.Goto(tb.URL("/first"), nil))
+	require.NotNil(t, p.Goto(tb.URL("/second"), nil))
+
+	resp := p.GoBack(nil)
+	require.NotNil(t, resp)
+	assert.Equal(t, tb.URL("/first"), resp.URL())
+	assert.Equal(t, tb.URL("/first"), p.URL())
+
+	resp = p.GoForward(tb.toGojaValue(map[string]interface{}{"waitUntil": "domcontentloaded"}))
+	require.NotNil(t, resp)
+	assert.Equal(t, tb.URL("/second"), resp.URL())
+	assert.Nil(t, p.GoForward(nil), "should be null without a next page")
+
+	// the navigations within the same document have no response.
+	p.Evaluate(tb.toGojaValue(`() => history.pushState({}, "", "/second#pushed")`))
+	assert.Nil(t, p.GoBack(nil))
+	assert.Equal(t, tb.URL("/second"), p.URL())
+}
+
+func TestPageReload(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withHTTPServer())
+	tb.withHandler("/cache", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "max-age=3600")
+		fmt.Fprint(w, r.Header.Get("Cache-Control"))
+	})
+	p := tb.NewPage(nil)
+	require.NotNil(t, p.Goto(tb.URL("/cache"), nil))
+
+	resp := p.Reload(tb.toGojaValue(map[string]interface{}{"waitUntil": "networkidle"}))
+	require.NotNil(t, resp)
+	assert.Equal(t, tb.URL("/cache"), resp.URL())
+	assert.Equal(t, "max-age=0", resp.Text(), "should revalidate the cached page")
+
+	resp = p.Reload(tb.toGojaValue(map[string]interface{}{"ignoreCache": true}))
+	require.NotNil(t, resp)
+	assert.Equal(t, "no-cache", resp.Text(), "should bypass the cache")
+
+	assertPanicErrorContains(t, func() (err interface{}) {
+		defer func() { err = recover() }()
+		p.Reload(tb.toGojaValue(map[string]interface{}{"waitUntil": "commit!"}))
+		return nil
+	}(), `"commit!" is not a valid lifecycle`)
+}
+
+func TestPageWaitForURL(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withHTTPServer())
+	tb.withHandler("/first", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `<a href="/second">second</a>`)
+	})
+	tb.withHandler("/second", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, "second")
+	})
+	p := tb.NewPage(nil)
+	require.NotNil(t, p.Goto(tb.URL("/first"), nil))
+
+	// the current URL matches already.
+	p.WaitForURL(tb.toGojaValue("**/first"), nil)
+
+	p.Evaluate(tb.toGojaValue(`() => setTimeout(() => document.querySelector("a").click(), 100)`))
+	p.WaitForURL(tb.toGojaValue("**/second"), tb.toGojaValue(map[string]interface{}{"waitUntil": "domcontentloaded"}))
+	assert.Equal(t, tb.URL("/second"), p.URL())
+
+	re, err := tb.runJavaScript(`/\/route\/\d+$/`)
+	require.NoError(t, err)
+	p.Evaluate(tb.toGojaValue(`() => setTimeout(() => history.pushState({}, "", "/route/1"), 100)`))
+	p.WaitForURL(re, nil)
+	assert.Equal(t, tb.URL("/route/1"), p.URL())
+
+	predicate, err := tb.runJavaScript(`(url) => url.endsWith("#done")`)
+	require.NoError(t, err)
+	p.Evaluate(tb.toGojaValue(`() => setTimeout(() => { location.hash = "done" }, 100)`))
+	p.WaitForURL(predicate, nil)
+
+	assertPanicErrorContains(t, func() (err interface{}) {
+		defer func() { err = recover() }()
+		p.WaitForURL(tb.toGojaValue("**/never"), tb.toGojaValue(map[string]interface{}{"timeout": 200}))
+		return nil
+	}(), "timed out after 200ms")
+}
+
+func TestPageGotoWaitUntilCommit(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withHTTPServer())
+	tb.withHandler("/slow", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, "<html><body>start")
+		w.(http.Flusher).Flush() //nolint:forcetypeassert
+		time.Sleep(2 * time.Second)
+		fmt.Fprint(w, "end</body></html>")
+	})
+	p := tb.NewPage(nil)
+
+	start := time.Now()
+	resp := p.Goto(tb.URL("/slow"), tb.toGojaValue(map[string]interface{}{"waitUntil": "commit"}))
+	require.NotNil(t, resp)
+	assert.Equal(t, http.StatusOK, int(resp.Status()))
+	assert.Less(t, time.Since(start), 2*time.Second, "should not wait for the content")
+}
+
+func TestPageBringToFront(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t)
+	bctx := tb.NewContext(nil)
+	t.Cleanup(bctx.Close)
+	p1 := bctx.NewPage()
+	p2 := bctx.NewPage()
+
+	p2.BringToFront()
+	p1.BringToFront()
+	state := tb.asGojaValue(p1.Evaluate(tb.toGojaValue(`() => document.visibilityState`)))
+	assert.Equal(t, "visible", state.String())
+}
+
+func TestPageLogBrowserConsole(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withLogCache())
+	bctx := tb.NewContext(tb.toGojaValue(map[string]interface{}{
+		"logBrowserConsoleIgnore": []string{"debug"},
+	}))
+	t.Cleanup(bctx.Close)
+	p := bctx.NewPage()
+	p.SetContent(`<script>console.warn("careful", 1); console.debug("noisy");</script>`, nil)
+
+	var warn, debug *logrus.Entry
+	require.Eventually(t, func() bool {
+		tb.logCache.mu.RLock()
+		defer tb.logCache.mu.RUnlock()
+		for i, e := range tb.logCache.entries {
+			if e.Data["source"] != "browser-console-api" {
+				continue
+			}
+			switch e.Level {
+			case logrus.WarnLevel:
+				warn = &tb.logCache.entries[i]
+			case logrus.DebugLevel:
+				debug = &tb.logCache.entries[i]
+			}
+		}
+		return warn != nil
+	}, 5*time.Second, 50*time.Millisecond)
+
+	assert.Equal(t, []interface{}{"careful", float64(1)}, warn.Data["objects"])
+	assert.Equal(t, p.URL(), warn.Data["url"])
+	assert.Contains(t, warn.Data["location"], ":1:")
+	assert.Nil(t, debug, "should not log the ignored levels")
 }
@@ -27,6 +27,7 @@ import (
 	"os"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/grafana/xk6-browser/api"
 	"github.com/grafana/xk6-browser/chromium"
@@ -261,6 +262,31 @@ func (b *testBrowser) await(fn func() error) error {
 	return b.vu.RunLoop(fn)
 }
 
+// runLoopUntil runs the event loop until done is closed, so that the queued
+// callbacks, such as the page event handlers, are called. It fails the test
+// if done isn't closed in time.
+func (b *testBrowser) runLoopUntil(done <-chan struct{}) {
+	b.t.Helper()
+	var timedOut bool
+	err := b.await(func() error {
+		cb := b.vu.RegisterCallback()
+		go func() {
+			select {
+			case <-done:
+				cb(func() error { return nil })
+			case <-time.After(5 * time.Second):
+				cb(func() error {
+					timedOut = true
+					return nil
+				})
+			}
+		}()
+		return nil
+	})
+	require.NoError(b.t, err)
+	require.False(b.t, timedOut, "timed out waiting for the callbacks")
+}
+
 // launchOptions provides a way to customize browser type
 // launch options in tests.
 type launchOptions struct {
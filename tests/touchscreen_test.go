@@ -0,0 +1,86 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/grafana/xk6-browser/common"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const touchEventsHTML = `
+<div id="area" style="width: 300px; height: 300px"></div>
+<script>
+	window.touches = [];
+	const area = document.querySelector('#area');
+	for (const e of ['touchstart', 'touchmove', 'touchend']) {
+		area.addEventListener(e, ev => {
+			const list = ev.touches.length ? ev.touches : ev.changedTouches;
+			const points = Array.from(list).map(t => Math.round(t.clientX) + ',' + Math.round(t.clientY));
+			window.touches.push(e + ':' + ev.touches.length + ':' + points.join(' '));
+		});
+	}
+</script>`
+
+func TestTouchscreenSwipe(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t)
+	bctx := tb.NewContext(tb.toGojaValue(map[string]interface{}{"hasTouch": true}))
+	p := bctx.NewPage()
+	p.SetContent(touchEventsHTML, nil)
+	cp, ok := p.(*common.Page)
+	require.True(t, ok)
+
+	cp.Touchscreen.Swipe(
+		tb.toGojaValue(map[string]interface{}{"x": 10, "y": 200}),
+		tb.toGojaValue(map[string]interface{}{"x": 210, "y": 200}),
+		tb.toGojaValue(map[string]interface{}{"steps": 4, "duration": 40}),
+	)
+
+	touches := p.Evaluate(tb.toGojaValue(`() => window.touches.join(';')`))
+	assert.Equal(t,
+		"touchstart:1:10,200;touchmove:1:60,200;touchmove:1:110,200;touchmove:1:160,200;"+
+			"touchmove:1:210,200;touchend:0:210,200",
+		tb.asGojaValue(touches).String())
+
+	t.Run("err_no_touch", func(t *testing.T) {
+		t.Parallel()
+
+		tb := newTestBrowser(t)
+		p := tb.NewPage(nil)
+		cp, ok := p.(*common.Page)
+		require.True(t, ok)
+
+		point := tb.toGojaValue(map[string]interface{}{"x": 0, "y": 0})
+		assertPanicErrorContains(t, func() (err interface{}) {
+			defer func() { err = recover() }()
+			cp.Touchscreen.Swipe(point, point, nil)
+			return nil
+		}(), "touch emulation is not enabled, set the hasTouch browser context option")
+	})
+}
+
+func TestTouchscreenPinch(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t)
+	bctx := tb.NewContext(tb.toGojaValue(map[string]interface{}{"hasTouch": true}))
+	p := bctx.NewPage()
+	p.SetContent(touchEventsHTML, nil)
+	cp, ok := p.(*common.Page)
+	require.True(t, ok)
+
+	cp.Touchscreen.Pinch(
+		tb.toGojaValue(map[string]interface{}{"x": 150, "y": 150}),
+		2,
+		tb.toGojaValue(map[string]interface{}{"distance": 100, "steps": 2}),
+	)
+
+	touches := p.Evaluate(tb.toGojaValue(`() => window.touches.join(';')`))
+	assert.Equal(t,
+		"touchstart:2:100,150 200,150;touchmove:2:75,150 225,150;touchmove:2:50,150 250,150;"+
+			"touchend:0:50,150 250,150",
+		tb.asGojaValue(touches).String())
+}
@@ -0,0 +1,114 @@
+package tests
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/grafana/xk6-browser/common"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracing(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withFileServer())
+	p := tb.NewPage(nil)
+
+	path := filepath.Join(t.TempDir(), "trace.zip")
+	tb.StartTracing(tb.toGojaValue(map[string]interface{}{
+		"path":        path,
+		"screenshots": true,
+		"snapshots":   true,
+		"title":       "tracing",
+	}))
+	assertPanicErrorContains(t, func() (err interface{}) {
+		defer func() { err = recover() }()
+		tb.StartTracing(nil)
+		return nil
+	}(), "tracing has already been started")
+
+	require.NotNil(t, p.Goto(tb.staticURL("locators.html"), nil))
+	// the tracing is browser-wide, so it also captures the other contexts.
+	require.NotNil(t, tb.NewPage(nil).Goto(tb.staticURL("locators.html"), nil))
+
+	buf := tb.StopTracing().Bytes()
+	saved, err := ioutil.ReadFile(filepath.Clean(path))
+	require.NoError(t, err)
+	assert.Equal(t, buf, saved)
+
+	zr, err := zip.NewReader(bytes.NewReader(buf), int64(len(buf)))
+	require.NoError(t, err)
+
+	var names []string
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+	}
+	require.Contains(t, names, "trace.json")
+	assert.Contains(t, names, "snapshots/page-1.html")
+	assert.Contains(t, names, "snapshots/page-2.html")
+
+	f, err := zr.Open("trace.json")
+	require.NoError(t, err)
+	var trace struct {
+		TraceEvents []map[string]interface{} `json:"traceEvents"`
+		Metadata    map[string]string        `json:"metadata"`
+	}
+	require.NoError(t, json.NewDecoder(f).Decode(&trace))
+	assert.NotEmpty(t, trace.TraceEvents)
+	assert.Equal(t, "tracing", trace.Metadata["title"])
+
+	assertPanicErrorContains(t, func() (err interface{}) {
+		defer func() { err = recover() }()
+		tb.StopTracing()
+		return nil
+	}(), "tracing has not been started")
+}
+
+func TestBrowserContextTracing(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withFileServer())
+	bctx, ok := tb.NewContext(nil).(*common.BrowserContext)
+	require.True(t, ok)
+	p := bctx.NewPage()
+
+	require.NoError(t, bctx.Tracing.Start(tb.toGojaValue(map[string]interface{}{
+		"snapshots": true,
+		"title":     "tracing",
+	})))
+	assert.ErrorContains(t, bctx.Tracing.Start(nil), "tracing has already been started")
+
+	require.NotNil(t, p.Goto(tb.staticURL("locators.html"), nil))
+
+	path := filepath.Join(t.TempDir(), "trace.zip")
+	require.NoError(t, bctx.Tracing.Stop(tb.toGojaValue(map[string]interface{}{"path": path})))
+
+	zr, err := zip.OpenReader(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = zr.Close() })
+
+	var names []string
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+	}
+	require.Contains(t, names, "trace.json")
+	assert.Contains(t, names, "snapshots/page-1.html")
+
+	f, err := zr.Open("trace.json")
+	require.NoError(t, err)
+	var trace struct {
+		TraceEvents []map[string]interface{} `json:"traceEvents"`
+		Metadata    map[string]string        `json:"metadata"`
+	}
+	require.NoError(t, json.NewDecoder(f).Decode(&trace))
+	assert.NotEmpty(t, trace.TraceEvents)
+	assert.Equal(t, "tracing", trace.Metadata["title"])
+
+	assert.ErrorContains(t, bctx.Tracing.Stop(nil), "tracing has not been started")
+}
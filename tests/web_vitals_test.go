@@ -0,0 +1,42 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebVitalMetrics(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withFileServer())
+	p := tb.NewPage(nil)
+
+	url := tb.staticURL("web_vitals.html")
+	require.NotNil(t, p.Goto(url, nil))
+	// navigating away hides the page, which reports its web vitals.
+	require.NotNil(t, p.Goto(tb.staticURL("empty.html"), nil))
+
+	want := []string{"browser_web_vital_lcp", "browser_web_vital_cls"}
+	got := make(map[string]string)
+	timeout := time.After(5 * time.Second)
+	for len(got) < len(want) {
+		select {
+		case sc := <-tb.vu.Samples:
+			for _, s := range sc.GetSamples() {
+				for _, name := range want {
+					if s.Metric.Name == name {
+						got[name], _ = s.Tags.Get("url")
+					}
+				}
+			}
+		case <-timeout:
+			require.FailNow(t, "timed out waiting for the web vitals", "got: %v", got)
+		}
+	}
+	for _, name := range want {
+		assert.Equal(t, url, got[name], "%s should be tagged with the page URL", name)
+	}
+}